@@ -5,16 +5,186 @@
 CREATE SCHEMA IF NOT EXISTS test AUTHORIZATION postgres;
 DROP TABLE IF EXISTS customers;
 
+-- schema_version records the migrations applied to this database. Bump it together with store.SchemaVersion
+-- and add the statements that upgrade an existing database to database/migrations.
+CREATE TABLE IF NOT EXISTS schema_version(
+                    version int PRIMARY KEY,
+                    applied_at timestamp NOT NULL DEFAULT now()
+);
+
+INSERT INTO schema_version(version) VALUES(1), (2), (3), (4), (5), (6), (7), (8), (9), (10), (11) ON CONFLICT DO NOTHING;
+
 CREATE TABLE customer(
                     id SERIAL PRIMARY KEY,
-                    name varchar(20) NOT NULL UNIQUE,
-                    age int,
-                    salary float
+                    -- tenant_id is the tenant of the API key that created the customer. Every query is scoped by it.
+                    tenant_id varchar(64) NOT NULL,
+                    -- name is NULL when the name is encrypted in name_enc, and name_index is its blind index.
+                    name varchar(20),
+                    name_enc bytea,
+                    name_index bytea,
+                    email varchar(254),
+                    phone varchar(16),
+                    date_of_birth date,
+                    -- salary_minor is the salary in the minor unit of salary_currency, e.g. paise for INR.
+                    salary_minor bigint CHECK (salary_minor >= 0),
+                    salary_currency char(3),
+                    -- salary_enc holds the encrypted salary instead of salary_minor and salary_currency.
+                    salary_enc bytea,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    updated_at timestamptz NOT NULL DEFAULT now(),
+                    -- status only changes through the transitions allowed by models.Status, recorded in customer_transition.
+                    status varchar(16) NOT NULL DEFAULT 'prospect' CHECK (status IN ('prospect', 'active', 'suspended', 'closed')),
+                    -- deleted_at is set when the customer is merged into merged_into. Every query skips deleted customers.
+                    deleted_at timestamptz,
+                    merged_into int REFERENCES customer(id) ON DELETE SET NULL,
+                    -- attributes holds the custom attributes defined for the tenant in attribute_definition.
+                    attributes jsonb NOT NULL DEFAULT '{}',
+                    -- erased_at is set when the personal data of the customer is erased. The row is kept for statistics.
+                    erased_at timestamptz,
+                    CHECK ((salary_minor IS NULL) = (salary_currency IS NULL)),
+                    CHECK ((name IS NULL) <> (name_enc IS NULL)),
+                    CHECK ((name_enc IS NULL) = (name_index IS NULL)),
+                    CHECK (salary_enc IS NULL OR salary_minor IS NULL)
+);
+
+-- Names and emails are unique among the customers of a tenant that are not deleted.
+CREATE UNIQUE INDEX customer_tenant_id_name_key ON customer(tenant_id, name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX customer_tenant_id_name_index_key ON customer(tenant_id, name_index) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX customer_tenant_id_email_key ON customer(tenant_id, email) WHERE deleted_at IS NULL;
+CREATE INDEX customer_attributes ON customer USING GIN (attributes jsonb_path_ops);
+
+-- attribute_definition holds the custom attributes of the customers of a tenant. enum lists the values a
+-- string attribute may take.
+CREATE TABLE attribute_definition(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean', 'date')),
+                    required boolean NOT NULL DEFAULT false,
+                    enum jsonb,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    UNIQUE (tenant_id, name)
+);
+
+INSERT INTO customer(tenant_id, name, email, phone, date_of_birth, salary_minor, salary_currency, status) VALUES('default', 'Divya', 'divya@example.com', '+919876543210', '2000-03-14', 3000000, 'INR', 'active');
+INSERT INTO customer(tenant_id, name, email, phone, date_of_birth, salary_minor, salary_currency, status) VALUES('default', 'Jay', 'jay@example.com', '+919876543211', '2001-07-02', 3000000, 'INR', 'active');
+INSERT INTO customer(tenant_id, name, email, phone, date_of_birth, salary_minor, salary_currency, status) VALUES('default', 'Karan', 'karan@example.com', '+919876543212', '2000-11-23', 3000000, 'INR', 'active');
+
+CREATE TABLE address(
+                    id SERIAL PRIMARY KEY,
+                    customer_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    type varchar(8) NOT NULL CHECK (type IN ('billing', 'shipping')),
+                    street varchar(200) NOT NULL DEFAULT '',
+                    city varchar(100) NOT NULL DEFAULT '',
+                    country char(2) NOT NULL,
+                    postal_code varchar(16) NOT NULL
+);
+
+CREATE INDEX address_customer_id ON address(customer_id);
+
+CREATE TABLE customer_transition(
+                    id SERIAL PRIMARY KEY,
+                    customer_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    from_status varchar(16) NOT NULL,
+                    to_status varchar(16) NOT NULL,
+                    reason varchar(500) NOT NULL,
+                    caller varchar(64) NOT NULL DEFAULT '',
+                    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX customer_transition_customer_id ON customer_transition(customer_id);
+
+-- tag holds the tags of a tenant. customer_tag links them to customers.
+CREATE TABLE tag(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE customer_tag(
+                    customer_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    tag_id int NOT NULL REFERENCES tag(id) ON DELETE CASCADE,
+                    PRIMARY KEY (customer_id, tag_id)
+);
+
+CREATE INDEX customer_tag_tag_id ON customer_tag(tag_id);
+
+-- segment holds named filters over the customers of a tenant, as the query string of GET /customer.
+CREATE TABLE segment(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    filter varchar(2000) NOT NULL,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    updated_at timestamptz NOT NULL DEFAULT now(),
+                    UNIQUE (tenant_id, name)
+);
+
+-- customer_relationship holds typed, directed edges between the customers of a tenant, e.g. a company and its
+-- parent. Parent and referral edges never form a cycle.
+CREATE TABLE customer_relationship(
+                    id SERIAL PRIMARY KEY,
+                    type varchar(16) NOT NULL CHECK (type IN ('parent', 'referral', 'household')),
+                    from_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    to_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    UNIQUE (from_id, to_id, type),
+                    CHECK (from_id <> to_id)
+);
+
+CREATE INDEX customer_relationship_to_id ON customer_relationship(to_id);
+
+-- audit_log records changes made to customers beyond their own fields, e.g. merges, with the API key that made them.
+CREATE TABLE audit_log(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    action varchar(32) NOT NULL,
+                    customer_id int NOT NULL,
+                    detail jsonb NOT NULL,
+                    caller varchar(64) NOT NULL DEFAULT '',
+                    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_customer_id ON audit_log(customer_id);
+
+-- erasure_log records every erasure of a customer. Each entry holds the hash of the previous entry of its
+-- tenant, so that changing or removing one breaks the chain. Entries can only be appended.
+CREATE TABLE erasure_log(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    customer_id int NOT NULL,
+                    caller varchar(64) NOT NULL DEFAULT '',
+                    erased_at timestamptz NOT NULL,
+                    prev_hash char(64) NOT NULL,
+                    hash char(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX erasure_log_tenant_id ON erasure_log(tenant_id, id);
+
+CREATE FUNCTION erasure_log_append_only() RETURNS trigger AS $$
+    BEGIN
+        RAISE EXCEPTION 'erasure_log is append-only';
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER erasure_log_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON erasure_log
+    FOR EACH STATEMENT EXECUTE FUNCTION erasure_log_append_only();
+
+-- data_key holds the keys that encrypt the customers of a tenant, wrapped by the master key master_key_id.
+-- New values are encrypted with the latest data key; older ones still open the values encrypted before a
+-- rotation. The index key of a tenant computes the blind indexes of names and is never rotated.
+CREATE TABLE data_key(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    purpose varchar(8) NOT NULL CHECK (purpose IN ('data', 'index')),
+                    master_key_id varchar(100) NOT NULL,
+                    wrapped bytea NOT NULL,
+                    created_at timestamptz NOT NULL DEFAULT now()
 );
 
-INSERT INTO customer VALUES(1,'Divya', 22, 30000);
-INSERT INTO customer VALUES(2,'Jay', 21, 30000);
-INSERT INTO customer VALUES(3,'Karan', 22, 30000);
+CREATE INDEX data_key_tenant_id ON data_key(tenant_id, purpose, id);
+CREATE UNIQUE INDEX data_key_index_key ON data_key(tenant_id) WHERE purpose = 'index';
 
 CREATE TABLE DELETED_USER(
     id int,
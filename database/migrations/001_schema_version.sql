@@ -0,0 +1,12 @@
+-- Records that a database created before schema_version existed is at version 1: the customer table with
+-- id, name, age and salary. Apply it first; every later migration adds its version to schema_version.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS schema_version(
+                    version int PRIMARY KEY,
+                    applied_at timestamp NOT NULL DEFAULT now()
+);
+
+INSERT INTO schema_version(version) VALUES(1) ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Migrates a version 1 database to version 2: contact details, timestamps and addresses.
+-- Ages are no longer stored; they are computed from date_of_birth, which starts out unknown.
+BEGIN;
+
+ALTER TABLE customer
+    ADD COLUMN email varchar(254) UNIQUE,
+    ADD COLUMN phone varchar(16),
+    ADD COLUMN date_of_birth date,
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now(),
+    DROP COLUMN age;
+
+CREATE TABLE address(
+                    id SERIAL PRIMARY KEY,
+                    customer_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    type varchar(8) NOT NULL CHECK (type IN ('billing', 'shipping')),
+                    street varchar(200) NOT NULL DEFAULT '',
+                    city varchar(100) NOT NULL DEFAULT '',
+                    country char(2) NOT NULL,
+                    postal_code varchar(16) NOT NULL
+);
+
+CREATE INDEX address_customer_id ON address(customer_id);
+
+INSERT INTO schema_version(version) VALUES(2);
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Migrates a version 2 database to version 3: salaries are stored in minor units with their currency.
+-- Every existing salary was entered in rupees, so they are rounded to the nearest paisa as INR.
+BEGIN;
+
+ALTER TABLE customer
+    ADD COLUMN salary_minor bigint CHECK (salary_minor >= 0),
+    ADD COLUMN salary_currency char(3),
+    ADD CHECK ((salary_minor IS NULL) = (salary_currency IS NULL));
+
+UPDATE customer SET salary_minor = round(salary::numeric * 100), salary_currency = 'INR' WHERE salary IS NOT NULL;
+
+ALTER TABLE customer DROP COLUMN salary;
+
+INSERT INTO schema_version(version) VALUES(3);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Migrates a version 3 database to version 4: customers belong to a tenant.
+-- Existing customers belong to the default tenant, which the existing API key is mapped to in API_KEYS.
+-- Names and emails only need to be unique within a tenant.
+BEGIN;
+
+ALTER TABLE customer ADD COLUMN tenant_id varchar(64) NOT NULL DEFAULT 'default';
+ALTER TABLE customer ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE customer
+    DROP CONSTRAINT customer_name_key,
+    DROP CONSTRAINT customer_email_key,
+    ADD UNIQUE (tenant_id, name),
+    ADD UNIQUE (tenant_id, email);
+
+INSERT INTO schema_version(version) VALUES(4);
+
+COMMIT;
@@ -0,0 +1,30 @@
+-- Migrates a version 4 database to version 5: customers can be merged into another one.
+-- Merged customers are soft-deleted and keep the id of the customer they were merged into. Names and emails
+-- only need to be unique among the customers that are not deleted, so the constraints become partial indexes.
+-- Every merge is recorded in audit_log, which outlives the customers it refers to.
+BEGIN;
+
+ALTER TABLE customer
+    ADD COLUMN deleted_at timestamptz,
+    ADD COLUMN merged_into int REFERENCES customer(id) ON DELETE SET NULL,
+    DROP CONSTRAINT customer_tenant_id_name_key,
+    DROP CONSTRAINT customer_tenant_id_email_key;
+
+CREATE UNIQUE INDEX customer_tenant_id_name_key ON customer(tenant_id, name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX customer_tenant_id_email_key ON customer(tenant_id, email) WHERE deleted_at IS NULL;
+
+CREATE TABLE audit_log(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    action varchar(32) NOT NULL,
+                    customer_id int NOT NULL,
+                    detail jsonb NOT NULL,
+                    caller varchar(64) NOT NULL DEFAULT '',
+                    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_customer_id ON audit_log(customer_id);
+
+INSERT INTO schema_version(version) VALUES(5);
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- Migrates a version 5 database to version 6: customers have a lifecycle status.
+-- Existing customers were all treated as active. New customers start as prospects.
+-- Every change of status is recorded in customer_transition.
+BEGIN;
+
+ALTER TABLE customer ADD COLUMN status varchar(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('prospect', 'active', 'suspended', 'closed'));
+ALTER TABLE customer ALTER COLUMN status SET DEFAULT 'prospect';
+
+CREATE TABLE customer_transition(
+                    id SERIAL PRIMARY KEY,
+                    customer_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    from_status varchar(16) NOT NULL,
+                    to_status varchar(16) NOT NULL,
+                    reason varchar(500) NOT NULL,
+                    caller varchar(64) NOT NULL DEFAULT '',
+                    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX customer_transition_customer_id ON customer_transition(customer_id);
+
+INSERT INTO schema_version(version) VALUES(6);
+
+COMMIT;
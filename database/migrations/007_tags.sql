@@ -0,0 +1,32 @@
+-- Migrates a version 6 database to version 7: customers can be tagged, and filters over them saved as segments.
+-- Tags are per tenant and created on first use. A segment keeps its filter as the query string of a listing.
+BEGIN;
+
+CREATE TABLE tag(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE customer_tag(
+                    customer_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    tag_id int NOT NULL REFERENCES tag(id) ON DELETE CASCADE,
+                    PRIMARY KEY (customer_id, tag_id)
+);
+
+CREATE INDEX customer_tag_tag_id ON customer_tag(tag_id);
+
+CREATE TABLE segment(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    filter varchar(2000) NOT NULL,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    updated_at timestamptz NOT NULL DEFAULT now(),
+                    UNIQUE (tenant_id, name)
+);
+
+INSERT INTO schema_version(version) VALUES(7);
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- Migrates a version 7 database to version 8: customers have custom attributes.
+-- Each tenant defines its attributes in attribute_definition. Values are kept in a JSONB object per customer,
+-- whose GIN index serves the containment queries of attribute filters.
+BEGIN;
+
+ALTER TABLE customer ADD COLUMN attributes jsonb NOT NULL DEFAULT '{}';
+
+CREATE INDEX customer_attributes ON customer USING GIN (attributes jsonb_path_ops);
+
+CREATE TABLE attribute_definition(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean', 'date')),
+                    required boolean NOT NULL DEFAULT false,
+                    enum jsonb,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    UNIQUE (tenant_id, name)
+);
+
+INSERT INTO schema_version(version) VALUES(8);
+
+COMMIT;
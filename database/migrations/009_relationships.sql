@@ -0,0 +1,20 @@
+-- Migrates a version 8 database to version 9: customers can be related to each other.
+-- Relationships are directed edges between two customers of the same tenant. Graph walks follow them in both
+-- directions, so both ends are indexed.
+BEGIN;
+
+CREATE TABLE customer_relationship(
+                    id SERIAL PRIMARY KEY,
+                    type varchar(16) NOT NULL CHECK (type IN ('parent', 'referral', 'household')),
+                    from_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    to_id int NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+                    created_at timestamptz NOT NULL DEFAULT now(),
+                    UNIQUE (from_id, to_id, type),
+                    CHECK (from_id <> to_id)
+);
+
+CREATE INDEX customer_relationship_to_id ON customer_relationship(to_id);
+
+INSERT INTO schema_version(version) VALUES(9);
+
+COMMIT;
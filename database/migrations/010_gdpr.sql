@@ -0,0 +1,32 @@
+-- Migrates a version 9 database to version 10: customers can be erased.
+-- An erased customer keeps its row, so that relationships, tags and statistics stay intact, but loses its
+-- personal data. Every erasure is recorded in erasure_log, whose entries are hash-chained per tenant and can
+-- only be appended.
+BEGIN;
+
+ALTER TABLE customer ADD COLUMN erased_at timestamptz;
+
+CREATE TABLE erasure_log(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    customer_id int NOT NULL,
+                    caller varchar(64) NOT NULL DEFAULT '',
+                    erased_at timestamptz NOT NULL,
+                    prev_hash char(64) NOT NULL,
+                    hash char(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX erasure_log_tenant_id ON erasure_log(tenant_id, id);
+
+CREATE FUNCTION erasure_log_append_only() RETURNS trigger AS $$
+    BEGIN
+        RAISE EXCEPTION 'erasure_log is append-only';
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER erasure_log_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON erasure_log
+    FOR EACH STATEMENT EXECUTE FUNCTION erasure_log_append_only();
+
+INSERT INTO schema_version(version) VALUES(10);
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- Migrates a version 10 database to version 11: names and salaries can be encrypted.
+-- With a master key configured, names and salaries are encrypted with a data key of their tenant, which
+-- data_key holds wrapped by the master key, and names are looked up by their blind index. Customers written
+-- before keep their plaintext columns until the re-encryption job encrypts them.
+BEGIN;
+
+CREATE TABLE data_key(
+                    id SERIAL PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    purpose varchar(8) NOT NULL CHECK (purpose IN ('data', 'index')),
+                    master_key_id varchar(100) NOT NULL,
+                    wrapped bytea NOT NULL,
+                    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX data_key_tenant_id ON data_key(tenant_id, purpose, id);
+CREATE UNIQUE INDEX data_key_index_key ON data_key(tenant_id) WHERE purpose = 'index';
+
+ALTER TABLE customer
+    ALTER COLUMN name DROP NOT NULL,
+    ADD COLUMN name_enc bytea,
+    ADD COLUMN name_index bytea,
+    ADD COLUMN salary_enc bytea,
+    ADD CHECK ((name IS NULL) <> (name_enc IS NULL)),
+    ADD CHECK ((name_enc IS NULL) = (name_index IS NULL)),
+    ADD CHECK (salary_enc IS NULL OR salary_minor IS NULL);
+
+CREATE UNIQUE INDEX customer_tenant_id_name_index_key ON customer(tenant_id, name_index) WHERE deleted_at IS NULL;
+
+INSERT INTO schema_version(version) VALUES(11);
+
+COMMIT;
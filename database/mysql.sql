@@ -0,0 +1,57 @@
+-- The tables of the customers and their attribute schema on MySQL 8, for DB_DIALECT=mysql. They hold the same
+-- columns as database/database.sql at the schema_version below. Everything else is kept in Postgres only, see
+-- store.Dialect.
+
+-- schema_version records the version of database/database.sql these tables match. Bump it together with
+-- store.SchemaVersion.
+CREATE TABLE IF NOT EXISTS schema_version(
+                    version int PRIMARY KEY,
+                    applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT IGNORE INTO schema_version(version) VALUES(11);
+
+CREATE TABLE customer(
+                    id int AUTO_INCREMENT PRIMARY KEY,
+                    -- tenant_id is the tenant of the API key that created the customer. Every query is scoped by it.
+                    tenant_id varchar(64) NOT NULL,
+                    -- Names and emails compare case-sensitively, as they do in Postgres.
+                    name varchar(20) COLLATE utf8mb4_bin NOT NULL,
+                    email varchar(254) COLLATE utf8mb4_bin,
+                    phone varchar(16),
+                    date_of_birth date,
+                    -- salary_minor is the salary in the minor unit of salary_currency, e.g. paise for INR.
+                    salary_minor bigint CHECK (salary_minor >= 0),
+                    salary_currency char(3),
+                    -- Timestamps keep microseconds, so that every write changes updated_at.
+                    created_at datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+                    updated_at datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+                    status varchar(16) NOT NULL DEFAULT 'prospect' CHECK (status IN ('prospect', 'active', 'suspended', 'closed')),
+                    deleted_at datetime(6),
+                    -- attributes holds the custom attributes defined for the tenant in attribute_definition.
+                    attributes json NOT NULL DEFAULT (JSON_OBJECT()),
+                    -- MySQL has no partial indexes, so names and emails are unique through columns that are NULL for
+                    -- deleted customers. ON DUPLICATE KEY UPDATE finds the customer of the same name by them.
+                    active_name varchar(20) COLLATE utf8mb4_bin AS (IF(deleted_at IS NULL, name, NULL)) STORED,
+                    active_email varchar(254) COLLATE utf8mb4_bin AS (IF(deleted_at IS NULL, email, NULL)) STORED,
+                    CHECK ((salary_minor IS NULL) = (salary_currency IS NULL)),
+                    UNIQUE KEY customer_tenant_id_name_key (tenant_id, active_name),
+                    UNIQUE KEY customer_tenant_id_email_key (tenant_id, active_email)
+);
+
+-- attribute_definition holds the custom attributes of the customers of a tenant. enum lists the values a
+-- string attribute may take.
+CREATE TABLE attribute_definition(
+                    id int AUTO_INCREMENT PRIMARY KEY,
+                    tenant_id varchar(64) NOT NULL,
+                    name varchar(64) NOT NULL,
+                    type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean', 'date')),
+                    required boolean NOT NULL DEFAULT false,
+                    enum json,
+                    created_at datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+                    UNIQUE KEY attribute_definition_tenant_id_name_key (tenant_id, name)
+);
+
+INSERT INTO customer(tenant_id, name, email, phone, date_of_birth, salary_minor, salary_currency, status) VALUES('default', 'Divya', 'divya@example.com', '+919876543210', '2000-03-14', 3000000, 'INR', 'active');
+INSERT INTO customer(tenant_id, name, email, phone, date_of_birth, salary_minor, salary_currency, status) VALUES('default', 'Jay', 'jay@example.com', '+919876543211', '2001-07-02', 3000000, 'INR', 'active');
+INSERT INTO customer(tenant_id, name, email, phone, date_of_birth, salary_minor, salary_currency, status) VALUES('default', 'Karan', 'karan@example.com', '+919876543212', '2000-11-23', 3000000, 'INR', 'active');
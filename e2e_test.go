@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"customer/internal/e2e"
+	"customer/models"
+)
+
+func TestMain(m *testing.M) {
+	e2e.Main(m, setup)
+}
+
+// call sends body as JSON with the test API key and decodes the data of the response into res, when given. It
+// returns the status of the response.
+func call(t *testing.T, method, path string, body, res interface{}) int {
+	t.Helper()
+
+	code, err := send(e2e.URL(t)+path, method, body, res)
+	if err != nil {
+		t.Fatalf("%v %v: %v", method, path, err)
+	}
+
+	return code
+}
+
+// send is call for goroutines, which must not stop the test.
+func send(url, method string, body, res interface{}) (int, error) {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return 0, err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, &payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", e2e.Key)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if res != nil && resp.StatusCode < http.StatusMultipleChoices {
+		envelope := struct {
+			Data interface{} `json:"data"`
+		}{Data: res}
+
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			return 0, fmt.Errorf("failed to decode the response: %v", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+func TestE2E_CustomerLifecycle(t *testing.T) {
+	var created models.Customer
+
+	code := call(t, http.MethodPost, "/customer", models.Customer{Name: "E2E Lifecycle"}, &created)
+	if code != http.StatusCreated {
+		t.Fatalf("Expected %v\nGot %v", http.StatusCreated, code)
+	}
+
+	path := "/customer/" + strconv.Itoa(created.ID)
+
+	var got models.Customer
+	if code := call(t, http.MethodGet, path, nil, &got); code != http.StatusOK || got.Name != "E2E Lifecycle" {
+		t.Errorf("Expected %v, %v\nGot %v, %+v", http.StatusOK, created, code, got)
+	}
+
+	patch := map[string]string{"email": "lifecycle@example.com"}
+	if code := call(t, http.MethodPatch, path, patch, nil); code != http.StatusOK {
+		t.Errorf("Expected %v\nGot %v", http.StatusOK, code)
+	}
+
+	call(t, http.MethodGet, path, nil, &got)
+
+	if got.Name != "E2E Lifecycle" || got.Email != "lifecycle@example.com" {
+		t.Errorf("Expected the patch to keep the name and set the email\nGot %+v", got)
+	}
+
+	if code := call(t, http.MethodDelete, path, nil, nil); code != http.StatusNoContent {
+		t.Errorf("Expected %v\nGot %v", http.StatusNoContent, code)
+	}
+
+	if code := call(t, http.MethodGet, path, nil, nil); code != http.StatusNotFound {
+		t.Errorf("Expected %v\nGot %v", http.StatusNotFound, code)
+	}
+}
+
+func TestE2E_Upsert(t *testing.T) {
+	tests := []struct {
+		desc   string
+		status int
+	}{
+		{"created", http.StatusCreated},
+		{"replaced", http.StatusOK},
+	}
+
+	var ids []int
+
+	for i, tc := range tests {
+		var res models.Customer
+
+		code := call(t, http.MethodPut, "/customer/by-name/E2E%20Upsert", models.Customer{Email: "upsert@example.com"}, &res)
+		if code != tc.status {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i, tc.desc, tc.status, code)
+		}
+
+		ids = append(ids, res.ID)
+	}
+
+	if ids[0] == 0 || ids[0] != ids[1] {
+		t.Errorf("Expected the customer to be replaced in place\nGot ids %v", ids)
+	}
+}
+
+func TestE2E_Unauthorized(t *testing.T) {
+	res, err := http.Get(e2e.URL(t) + "/customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected %v\nGot %v", http.StatusUnauthorized, res.StatusCode)
+	}
+}
+
+func TestE2E_ConcurrentCreates(t *testing.T) {
+	const n = 20
+
+	var (
+		url = e2e.URL(t) + "/customer"
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids = map[int]bool{}
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			var res models.Customer
+
+			code, err := send(url, http.MethodPost, models.Customer{Name: fmt.Sprintf("E2E Concurrent %d", i)}, &res)
+			if err != nil || code != http.StatusCreated {
+				t.Errorf("Expected %v\nGot %v, %v", http.StatusCreated, code, err)
+				return
+			}
+
+			mu.Lock()
+			ids[res.ID] = true
+			mu.Unlock()
+		}(i)
+	}
+
+	wg.Wait()
+
+	if len(ids) != n {
+		t.Errorf("Expected %v distinct ids\nGot %v", n, len(ids))
+	}
+}
+
+// The SQL-only routes are refused clearly when another store than the postgres one keeps the customers, since
+// they are joined to the customers in the database.
+func TestE2E_DatabaseOnly(t *testing.T) {
+	for _, path := range []string{"/segments", "/customer/1/addresses", "/customer/1/tags", "/customer/1/transitions",
+		"/customer/1/relationships", "/customer/1/gdpr-export", "/erasures"} {
+		req, _ := http.NewRequest(http.MethodGet, e2e.URL(t)+path, nil)
+		req.Header.Set("x-api-key", e2e.Key)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body struct {
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		}
+
+		err = json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusNotImplemented || err != nil || len(body.Errors) != 1 ||
+			!strings.Contains(body.Errors[0].Reason, "postgres store, not memory") {
+			t.Errorf("%v: Expected %v for the memory store\nGot %v, %+v, %v", path, http.StatusNotImplemented,
+				res.StatusCode, body, err)
+		}
+	}
+}
+
+func TestE2E_Stream(t *testing.T) {
+	const n = 3
+
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("E2E Stream %d", i)
+		if code := call(t, http.MethodPost, "/customer", models.Customer{Name: name}, nil); code != http.StatusCreated {
+			t.Fatalf("Expected %v\nGot %v", http.StatusCreated, code)
+		}
+	}
+
+	tests := []struct {
+		desc   string
+		accept string
+		// names reads the names of the customers in a streamed body.
+		names func(t *testing.T, body *bufio.Reader) []string
+	}{
+		{"ndjson", "application/x-ndjson", func(t *testing.T, body *bufio.Reader) (names []string) {
+			for {
+				line, err := body.ReadBytes('\n')
+				if len(line) > 0 {
+					var c models.Customer
+					if err := json.Unmarshal(line, &c); err != nil {
+						t.Fatalf("Expected one customer per line\nGot %q: %v", line, err)
+					}
+
+					names = append(names, c.Name)
+				}
+
+				if err != nil {
+					return names
+				}
+			}
+		}},
+		{"csv", "text/csv", func(t *testing.T, body *bufio.Reader) (names []string) {
+			rows, err := csv.NewReader(body).ReadAll()
+			if err != nil || len(rows) == 0 || rows[0][1] != "name" {
+				t.Fatalf("Expected a header and one row per customer\nGot %v, %v", rows, err)
+			}
+
+			for _, row := range rows[1:] {
+				names = append(names, row[1])
+			}
+
+			return names
+		}},
+	}
+
+	for i, tc := range tests {
+		req, _ := http.NewRequest(http.MethodGet, e2e.URL(t)+"/customer?fields=id,name", nil)
+		req.Header.Set("x-api-key", e2e.Key)
+		req.Header.Set("Accept", tc.accept)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), tc.accept) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, http.StatusOK, tc.accept,
+				res.StatusCode, res.Header.Get("Content-Type"))
+		}
+
+		streamed := 0
+
+		for _, name := range tc.names(t, bufio.NewReader(res.Body)) {
+			if strings.HasPrefix(name, "E2E Stream ") {
+				streamed++
+			}
+		}
+
+		res.Body.Close()
+
+		if streamed != n {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v streamed customers\nGot %v", i+1, tc.desc, n, streamed)
+		}
+	}
+}
+
+// Without a database there is nothing the service waits for, so it is ready from the in-memory store.
+func TestE2E_Ready(t *testing.T) {
+	res, err := http.Get(e2e.URL(t) + "/health/ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Data struct {
+			Status     string                     `json:"status"`
+			Components map[string]json.RawMessage `json:"components"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := body.Data.Components["database"]; res.StatusCode != http.StatusOK || ok {
+		t.Errorf("Expected %v without a database check\nGot %v, %+v", http.StatusOK, res.StatusCode, body.Data)
+	}
+}
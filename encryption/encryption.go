@@ -0,0 +1,212 @@
+// Package encryption implements the envelope encryption of customer fields. Fields are encrypted with data
+// keys, which are only stored wrapped by a master key, and looked up by blind indexes.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the size of master keys, data keys and index keys in bytes: keys are AES-256 keys.
+const KeySize = 32
+
+// prefixSize is the size of the data key id that starts every ciphertext.
+const prefixSize = 4
+
+// ErrCiphertext is returned for a ciphertext that is too short, or that does not open with its key.
+var ErrCiphertext = errors.New("invalid ciphertext")
+
+// MasterKey wraps the keys that encrypt customer fields, so that they are never stored in the clear. A key
+// management service is used through an implementation of it that calls the service; NewLocalKey keeps the
+// master key in memory.
+type MasterKey interface {
+	// ID names the master key, so that the keys wrapped with it are told apart from the keys wrapped with
+	// the master key that replaces it.
+	ID() string
+	// Wrap encrypts key, bound to aad.
+	Wrap(ctx context.Context, key, aad []byte) ([]byte, error)
+	// Unwrap decrypts a key wrapped with the same aad.
+	Unwrap(ctx context.Context, wrapped, aad []byte) ([]byte, error)
+}
+
+type localKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewLocalKey returns a master key held in memory. Its id is derived from the key, so that the same key has
+// the same id on every instance.
+func NewLocalKey(key []byte) (MasterKey, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(key)
+
+	return localKey{id: "local:" + hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+func (k localKey) ID() string {
+	return k.id
+}
+
+func (k localKey) Wrap(_ context.Context, key, aad []byte) ([]byte, error) {
+	return seal(k.aead, nil, key, aad)
+}
+
+func (k localKey) Unwrap(_ context.Context, wrapped, aad []byte) ([]byte, error) {
+	return open(k.aead, nil, wrapped, aad)
+}
+
+// LoadMasterKey returns the local master key encoded in base64 by value, or held in the file at path when
+// value is empty. It returns nil when neither is set, which leaves customer fields unencrypted.
+func LoadMasterKey(value, path string) (MasterKey, error) {
+	if value == "" && path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		value = string(b)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("master key is not base64: %w", err)
+	}
+
+	return NewLocalKey(key)
+}
+
+// GenerateKey returns a new random key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// DataKey encrypts fields with AES-256-GCM. Every ciphertext starts with the id of its data key, so that it
+// is opened with that key after newer ones replaced it.
+type DataKey struct {
+	ID   int
+	aead cipher.AEAD
+}
+
+func NewDataKey(id int, key []byte) (DataKey, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	return DataKey{ID: id, aead: aead}, nil
+}
+
+// Prefix returns the bytes every ciphertext of the key starts with.
+func (k DataKey) Prefix() []byte {
+	prefix := make([]byte, prefixSize)
+	binary.BigEndian.PutUint32(prefix, uint32(k.ID))
+
+	return prefix
+}
+
+// Seal encrypts plaintext, bound to aad so that it cannot be opened as another field.
+func (k DataKey) Seal(plaintext, aad []byte) ([]byte, error) {
+	return seal(k.aead, k.Prefix(), plaintext, aad)
+}
+
+// Open decrypts a ciphertext sealed by the key with the same aad.
+func (k DataKey) Open(ciphertext, aad []byte) ([]byte, error) {
+	id, err := KeyID(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if id != k.ID {
+		return nil, fmt.Errorf("ciphertext of data key %v opened with data key %v: %w", id, k.ID, ErrCiphertext)
+	}
+
+	return open(k.aead, ciphertext[:prefixSize], ciphertext[prefixSize:], aad)
+}
+
+// KeyID returns the id of the data key that sealed ciphertext.
+func KeyID(ciphertext []byte) (int, error) {
+	if len(ciphertext) < prefixSize {
+		return 0, ErrCiphertext
+	}
+
+	return int(binary.BigEndian.Uint32(ciphertext)), nil
+}
+
+// IndexKey computes blind indexes: keyed hashes of values, which find equal values without revealing them.
+type IndexKey []byte
+
+// Index returns the blind index of the value of field. The same value has different indexes in different
+// fields.
+func (k IndexKey) Index(field, value string) []byte {
+	mac := hmac.New(sha256.New, k)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+
+	return mac.Sum(nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key is %v bytes, not %v", len(key), KeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal appends a random nonce and the ciphertext of plaintext to prefix, which is authenticated too.
+func seal(aead cipher.AEAD, prefix, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(append([]byte{}, prefix...), nonce...)
+
+	return aead.Seal(out, nonce, plaintext, append(append([]byte{}, prefix...), aad...)), nil
+}
+
+// open decrypts what seal appended to prefix.
+func open(aead cipher.AEAD, prefix, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():],
+		append(append([]byte{}, prefix...), aad...))
+	if err != nil {
+		return nil, ErrCiphertext
+	}
+
+	return plaintext, nil
+}
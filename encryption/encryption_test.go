@@ -0,0 +1,156 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testKey returns a key whose bytes all are b.
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestDataKey_Seal(t *testing.T) {
+	key, err := NewDataKey(7, testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewDataKey(8, testKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := key.Seal([]byte("Divya"), []byte("zopsmart|customer.name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _ := KeyID(sealed); id != 7 {
+		t.Errorf("Expected key id 7\nGot %v", id)
+	}
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		desc       string
+		key        DataKey
+		ciphertext []byte
+		aad        string
+		expected   []byte
+		err        error
+	}{
+		{"opens", key, sealed, "zopsmart|customer.name", []byte("Divya"), nil},
+		{"other tenant", key, sealed, "acme|customer.name", nil, ErrCiphertext},
+		{"other field", key, sealed, "zopsmart|customer.salary", nil, ErrCiphertext},
+		{"tampered", key, tampered, "zopsmart|customer.name", nil, ErrCiphertext},
+		{"other key", other, sealed, "zopsmart|customer.name", nil, ErrCiphertext},
+		{"too short", key, sealed[:3], "zopsmart|customer.name", nil, ErrCiphertext},
+	}
+
+	for i, tc := range tests {
+		res, err := tc.key.Open(tc.ciphertext, []byte(tc.aad))
+		if !errors.Is(err, tc.err) || !bytes.Equal(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %q, %v\nGot %q, %v", i, tc.desc, tc.expected, tc.err, res, err)
+		}
+	}
+}
+
+func TestDataKey_SealIsRandomised(t *testing.T) {
+	key, _ := NewDataKey(1, testKey(1))
+
+	a, _ := key.Seal([]byte("Divya"), nil)
+	b, _ := key.Seal([]byte("Divya"), nil)
+
+	if bytes.Equal(a, b) {
+		t.Errorf("Expected different ciphertexts of the same plaintext\nGot %x twice", a)
+	}
+}
+
+func TestLocalKey_Wrap(t *testing.T) {
+	ctx := context.Background()
+	master, _ := NewLocalKey(testKey(3))
+
+	wrapped, err := master.Wrap(ctx, testKey(4), []byte("zopsmart|data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key, err := master.Unwrap(ctx, wrapped, []byte("zopsmart|data")); err != nil || !bytes.Equal(key, testKey(4)) {
+		t.Errorf("Expected %x\nGot %x, %v", testKey(4), key, err)
+	}
+
+	if _, err := master.Unwrap(ctx, wrapped, []byte("acme|data")); err != ErrCiphertext {
+		t.Errorf("Expected %v\nGot %v", ErrCiphertext, err)
+	}
+
+	other, _ := NewLocalKey(testKey(5))
+	if master.ID() == other.ID() {
+		t.Errorf("Expected different ids for different keys\nGot %v twice", master.ID())
+	}
+}
+
+func TestLoadMasterKey(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testKey(3))
+	expected, _ := NewLocalKey(testKey(3))
+
+	path := filepath.Join(t.TempDir(), "master.key")
+	if err := os.WriteFile(path, []byte(encoded+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc  string
+		value string
+		path  string
+		id    string
+		err   bool
+	}{
+		{"env var", encoded, "", expected.ID(), false},
+		{"keyfile", "", path, expected.ID(), false},
+		{"env var wins", encoded, "missing", expected.ID(), false},
+		{"none", "", "", "", false},
+		{"missing keyfile", "", filepath.Join(t.TempDir(), "missing"), "", true},
+		{"not base64", "not a key", "", "", true},
+		{"wrong size", base64.StdEncoding.EncodeToString([]byte("short")), "", "", true},
+	}
+
+	for i, tc := range tests {
+		key, err := LoadMasterKey(tc.value, tc.path)
+
+		var id string
+		if key != nil {
+			id = key.ID()
+		}
+
+		if (err != nil) != tc.err || id != tc.id {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %q, error %v\nGot %q, %v", i, tc.desc, tc.id, tc.err, id, err)
+		}
+	}
+}
+
+func TestIndexKey_Index(t *testing.T) {
+	key := IndexKey(testKey(6))
+
+	if !bytes.Equal(key.Index("name", "Divya"), key.Index("name", "Divya")) {
+		t.Errorf("Expected the same index for the same value")
+	}
+
+	if bytes.Equal(key.Index("name", "Divya"), key.Index("name", "divya")) {
+		t.Errorf("Expected different indexes for different values")
+	}
+
+	if bytes.Equal(key.Index("name", "Divya"), key.Index("email", "Divya")) {
+		t.Errorf("Expected different indexes in different fields")
+	}
+
+	if bytes.Equal(key.Index("name", "Divya"), IndexKey(testKey(7)).Index("name", "Divya")) {
+		t.Errorf("Expected different indexes with different keys")
+	}
+}
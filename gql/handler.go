@@ -0,0 +1,50 @@
+package gql
+
+import (
+	"context"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
+	"github.com/graph-gophers/graphql-go"
+
+	"customer/models"
+	"customer/service"
+)
+
+// batchWait is how long the loader waits for sibling fields to request customers before querying the store.
+const batchWait = 2 * time.Millisecond
+
+// Handler serves the /graphql endpoint. It is registered as a regular gofr route,
+// so it sits behind the same Auth middleware as the REST endpoints.
+type Handler struct {
+	schema  *graphql.Schema
+	service service.HandlerIn
+}
+
+type payload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func New(s service.HandlerIn) Handler {
+	return Handler{schema: graphql.MustParseSchema(schema, &resolver{service: s}), service: s}
+}
+
+func (h Handler) Serve(ctx *gofr.Context) (interface{}, error) {
+	var req payload
+	if err := ctx.Bind(&req); err != nil || req.Query == "" {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	l := newLoader(batchWait, func(ids []int) ([]models.Customer, error) {
+		return h.service.GetByIDs(ctx, ids)
+	})
+
+	c := context.WithValue(ctx.Context, gofrKey, ctx)
+	c = context.WithValue(c, loaderKey, l)
+
+	return types.Raw{Data: h.schema.Exec(c, req.Query, req.OperationName, req.Variables)}, nil
+}
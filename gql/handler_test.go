@@ -0,0 +1,171 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"customer/mocks"
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func connect(body string) *gofr.Context {
+	r := httptest.NewRequest(http.MethodPost, "http://customer/graphql", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+	ctx.Context = r.Context()
+
+	return ctx
+}
+
+func TestHandler_Serve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockHandlerIn(ctrl)
+	h := New(m)
+
+	batched := func(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+		sort.Ints(ids)
+
+		if !reflect.DeepEqual(ids, []int{1, 2}) {
+			t.Errorf("Expected a single batch for ids [1 2]\nGot %v", ids)
+		}
+
+		return []models.Customer{{ID: 1, Name: "Divya"}, {ID: 2, Name: "Jay"}}, nil
+	}
+
+	dob := models.NewDate(2000, time.March, 14)
+	salary := &models.Money{Minor: 10050, Currency: "INR"}
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected string
+		mock     []*gomock.Call
+	}{
+		{"batched lookups", `{"query":"{a: customer(id: \"1\") {name} b: customer(id: \"2\") {name} c: customer(id: \"1\") {id}}"}`,
+			`{"data":{"a":{"name":"Divya"},"b":{"name":"Jay"},"c":{"id":"1"}}}`,
+			[]*gomock.Call{m.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).DoAndReturn(batched).Times(1)}},
+		{"unknown customer", `{"query":"{customer(id: \"5\") {name}}"}`, `{"data":{"customer":null}}`,
+			[]*gomock.Call{m.EXPECT().GetByIDs(gomock.Any(), []int{5}).Return(nil, nil)}},
+		{"filtered listing", `{"query":"{customers(filter: {minAge: 21}, page: {limit: 1}) {id age}}"}`,
+			`{"data":{"customers":[{"id":"1","age":22}]}}`,
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{MinAge: 21, Limit: 1}).
+				Return([]models.Customer{{ID: 1, Name: "Divya", Age: 22}}, nil)}},
+		{"create", `{"query":"mutation {createCustomer(input: {name: \"Karan\", dateOfBirth: \"2000-03-14\"}) {name dateOfBirth age salary {amount}}}"}`,
+			`{"data":{"createCustomer":{"name":"Karan","dateOfBirth":"2000-03-14","age":22,"salary":null}}}`,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), models.Customer{Name: "Karan", DateOfBirth: &dob}).
+				Return(models.Customer{Name: "Karan", DateOfBirth: &dob, Age: 22}, nil)}},
+		{"invalid date of birth", `{"query":"mutation {createCustomer(input: {name: \"Karan\", dateOfBirth: \"14-03-2000\"}) {name}}"}`,
+			`{"errors":[{"message":"Incorrect value for parameter: dateOfBirth","path":["createCustomer"]}],"data":null}`, nil},
+		{"update", `{"query":"mutation {updateCustomer(id: \"3\", input: {name: \"Karan\", salary: {amount: \"100.50\", currency: \"INR\"}}) {id salary {amount currency}}}"}`,
+			`{"data":{"updateCustomer":{"id":"3","salary":{"amount":"100.50","currency":"INR"}}}}`,
+			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), models.Customer{ID: 3, Name: "Karan", Salary: salary}).
+				Return(models.Customer{ID: 3, Name: "Karan", Salary: salary}, nil)}},
+		{"attributes", `{"query":"mutation {updateCustomer(id: \"3\", input: {name: \"Karan\", attributes: \"{\\\"plan\\\":\\\"gold\\\"}\"}) {id attributes}}"}`,
+			`{"data":{"updateCustomer":{"id":"3","attributes":"{\"plan\":\"gold\"}"}}}`,
+			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), models.Customer{ID: 3, Name: "Karan", Attributes: models.Attributes{"plan": "gold"}}).
+				Return(models.Customer{ID: 3, Name: "Karan", Attributes: models.Attributes{"plan": "gold"}}, nil)}},
+		{"invalid attributes", `{"query":"mutation {updateCustomer(id: \"3\", input: {name: \"Karan\", attributes: \"[]\"}) {id}}"}`,
+			`{"errors":[{"message":"Incorrect value for parameter: attributes","path":["updateCustomer"]}],"data":null}`, nil},
+		{"delete", `{"query":"mutation {deleteCustomer(id: \"3\")}"}`, `{"data":{"deleteCustomer":true}}`,
+			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), 3).Return(nil)}},
+		{"delete error", `{"query":"mutation {deleteCustomer(id: \"3\")}"}`,
+			`{"errors":[{"message":"DB Error: db error","path":["deleteCustomer"]}],"data":null}`,
+			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), 3).Return(errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.Serve(connect(tc.body))
+			if err != nil {
+				t.Fatalf("TEST[%d], failed.\n%s\nunexpected error %v", i+1, tc.desc, err)
+			}
+
+			body, _ := json.Marshal(resp.(types.Raw).Data)
+			if string(body) != tc.expected {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestHandler_ServeInvalidBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := New(mocks.NewMockHandlerIn(ctrl))
+
+	for i, body := range []string{``, `{"query":""}`} {
+		_, err := h.Serve(connect(body))
+		if !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"body"}}) {
+			t.Errorf("TEST[%d], failed.\nExpected %v\nGot %v", i+1, errors.InvalidParam{Param: []string{"body"}}, err)
+		}
+	}
+}
+
+// TestHandler_ServeConcurrently resolves sibling fields through the traced service. Run with -race, it checks
+// that the resolvers, which run concurrently, never write to the request context they share.
+func TestHandler_ServeConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider("customer", exporter)
+	otel.SetTracerProvider(tp)
+
+	m := mocks.NewMockServiceIn(ctrl)
+	h := New(service.New(m, mocks.NewMockAttributeServiceIn(ctrl)))
+
+	m.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+		res := make([]models.Customer, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, models.Customer{ID: id, Name: "Divya"})
+		}
+
+		return res, nil
+	}).AnyTimes()
+	m.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]models.Customer{{ID: 4, Name: "Jay"}}, nil).AnyTimes()
+
+	ctx := connect(`{"query":"{a: customer(id: \"1\") {name} b: customer(id: \"2\") {name} c: customer(id: \"3\") {name} d: customers {id}}"}`)
+	parent := ctx.Context
+
+	res, err := h.Serve(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error\nGot %v", err)
+	}
+
+	expected := `{"data":{"a":{"name":"Divya"},"b":{"name":"Divya"},"c":{"name":"Divya"},"d":[{"id":"4"}]}}`
+
+	if body, _ := json.Marshal(res.(types.Raw).Data); string(body) != expected {
+		t.Errorf("Expected %v\nGot %v", expected, string(body))
+	}
+
+	if ctx.Context != parent {
+		t.Errorf("Expected the request context to be left alone")
+	}
+
+	_ = tp.ForceFlush(context.Background())
+
+	for _, span := range exporter.GetSpans() {
+		if span.Parent.IsValid() {
+			t.Errorf("Expected %v to have no parent, as the request has no span\nGot parent %v", span.Name, span.Parent.SpanID())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package gql
+
+import (
+	"sync"
+	"time"
+
+	"customer/models"
+)
+
+// loader collects the ids requested while a query is being resolved and fetches them in a single batch.
+type loader struct {
+	mu    sync.Mutex
+	wait  time.Duration
+	fetch func(ids []int) ([]models.Customer, error)
+	batch *batch
+	cache map[int]*batch
+}
+
+type batch struct {
+	ids  []int
+	done chan struct{}
+	res  map[int]models.Customer
+	err  error
+}
+
+func newLoader(wait time.Duration, fetch func(ids []int) ([]models.Customer, error)) *loader {
+	return &loader{wait: wait, fetch: fetch, cache: make(map[int]*batch)}
+}
+
+// Load returns the customer with the given id, and false if it does not exist.
+func (l *loader) Load(id int) (models.Customer, bool, error) {
+	l.mu.Lock()
+
+	b, ok := l.cache[id]
+	if !ok {
+		if l.batch == nil {
+			l.batch = &batch{done: make(chan struct{})}
+			time.AfterFunc(l.wait, l.dispatch)
+		}
+
+		b = l.batch
+		b.ids = append(b.ids, id)
+		l.cache[id] = b
+	}
+
+	l.mu.Unlock()
+
+	<-b.done
+
+	if b.err != nil {
+		return models.Customer{}, false, b.err
+	}
+
+	c, ok := b.res[id]
+
+	return c, ok, nil
+}
+
+func (l *loader) dispatch() {
+	l.mu.Lock()
+	b := l.batch
+	l.batch = nil
+	l.mu.Unlock()
+
+	res, err := l.fetch(b.ids)
+
+	b.res = make(map[int]models.Customer, len(res))
+	for i := range res {
+		b.res[res[i].ID] = res[i]
+	}
+
+	b.err = err
+	close(b.done)
+}
@@ -0,0 +1,310 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/graph-gophers/graphql-go"
+
+	"customer/models"
+	"customer/service"
+)
+
+type ctxKey int
+
+const (
+	gofrKey ctxKey = iota
+	loaderKey
+)
+
+type resolver struct {
+	service service.HandlerIn
+}
+
+type filterInput struct {
+	Name   *string
+	MinAge *int32
+	MaxAge *int32
+}
+
+type pageInput struct {
+	Limit  *int32
+	Offset *int32
+}
+
+type customerInput struct {
+	Name        string
+	Email       *string
+	Phone       *string
+	DateOfBirth *string
+	Salary      *moneyInput
+	Attributes  *string
+}
+
+type moneyInput struct {
+	Amount   string
+	Currency string
+}
+
+func (r *resolver) Customer(ctx context.Context, args struct{ ID graphql.ID }) (*customerResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok, err := ctx.Value(loaderKey).(*loader).Load(id)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &customerResolver{c: c}, nil
+}
+
+func (r *resolver) Customers(ctx context.Context, args struct {
+	Filter *filterInput
+	Page   *pageInput
+}) ([]*customerResolver, error) {
+	var filter models.Filter
+
+	if f := args.Filter; f != nil {
+		filter.Name = stringValue(f.Name)
+		filter.MinAge = intValue(f.MinAge)
+		filter.MaxAge = intValue(f.MaxAge)
+	}
+
+	if p := args.Page; p != nil {
+		filter.Limit = intValue(p.Limit)
+		filter.Offset = intValue(p.Offset)
+	}
+
+	res, err := r.service.Get(gofrContext(ctx), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	customers := make([]*customerResolver, len(res))
+	for i := range res {
+		customers[i] = &customerResolver{c: res[i]}
+	}
+
+	return customers, nil
+}
+
+func (r *resolver) CreateCustomer(ctx context.Context, args struct{ Input customerInput }) (*customerResolver, error) {
+	customer, err := args.Input.customer()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.service.Create(gofrContext(ctx), customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerResolver{c: res}, nil
+}
+
+func (r *resolver) UpdateCustomer(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input customerInput
+}) (*customerResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := args.Input.customer()
+	if err != nil {
+		return nil, err
+	}
+
+	customer.ID = id
+
+	res, err := r.service.Update(gofrContext(ctx), customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerResolver{c: res}, nil
+}
+
+func (r *resolver) DeleteCustomer(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.Delete(gofrContext(ctx), id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type customerResolver struct {
+	c models.Customer
+}
+
+func (r *customerResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(r.c.ID))
+}
+
+func (r *customerResolver) Name() string {
+	return r.c.Name
+}
+
+func (r *customerResolver) Email() *string {
+	return stringPtr(r.c.Email)
+}
+
+func (r *customerResolver) Phone() *string {
+	return stringPtr(r.c.Phone)
+}
+
+func (r *customerResolver) DateOfBirth() *string {
+	if r.c.DateOfBirth == nil {
+		return nil
+	}
+
+	return stringPtr(r.c.DateOfBirth.String())
+}
+
+func (r *customerResolver) CreatedAt() *string {
+	return timePtr(r.c.CreatedAt)
+}
+
+func (r *customerResolver) UpdatedAt() *string {
+	return timePtr(r.c.UpdatedAt)
+}
+
+func (r *customerResolver) Age() *int32 {
+	return int32Ptr(r.c.Age)
+}
+
+func (r *customerResolver) Status() *string {
+	return stringPtr(string(r.c.Status))
+}
+
+func (r *customerResolver) Salary() *moneyResolver {
+	if r.c.Salary == nil {
+		return nil
+	}
+
+	return &moneyResolver{m: *r.c.Salary}
+}
+
+func (r *customerResolver) Attributes() *string {
+	if r.c.Attributes == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(r.c.Attributes)
+	if err != nil {
+		return nil
+	}
+
+	return stringPtr(string(b))
+}
+
+type moneyResolver struct {
+	m models.Money
+}
+
+func (r *moneyResolver) Amount() string {
+	return r.m.Amount()
+}
+
+func (r *moneyResolver) Currency() string {
+	return r.m.Currency
+}
+
+func (i customerInput) customer() (models.Customer, error) {
+	c := models.Customer{Name: i.Name, Email: stringValue(i.Email), Phone: stringValue(i.Phone)}
+
+	if i.DateOfBirth != nil {
+		dob, err := models.ParseDate(*i.DateOfBirth)
+		if err != nil {
+			return models.Customer{}, errors.InvalidParam{Param: []string{"dateOfBirth"}}
+		}
+
+		c.DateOfBirth = &dob
+	}
+
+	if i.Salary != nil {
+		salary, err := models.ParseMoney(i.Salary.Amount, i.Salary.Currency)
+		if err != nil {
+			return models.Customer{}, errors.InvalidParam{Param: []string{"salary"}}
+		}
+
+		c.Salary = &salary
+	}
+
+	if i.Attributes != nil {
+		if err := json.Unmarshal([]byte(*i.Attributes), &c.Attributes); err != nil {
+			return models.Customer{}, errors.InvalidParam{Param: []string{"attributes"}}
+		}
+	}
+
+	return c, nil
+}
+
+func gofrContext(ctx context.Context) *gofr.Context {
+	return ctx.Value(gofrKey).(*gofr.Context)
+}
+
+func parseID(id graphql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return n, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func intValue(n *int32) int {
+	if n == nil {
+		return 0
+	}
+
+	return int(*n)
+}
+
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+func timePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	s := t.Format(time.RFC3339)
+
+	return &s
+}
+
+func int32Ptr(n int) *int32 {
+	if n == 0 {
+		return nil
+	}
+
+	v := int32(n)
+
+	return &v
+}
@@ -0,0 +1,74 @@
+package gql
+
+const schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	customer(id: ID!): Customer
+	customers(filter: CustomerFilter, page: Page): [Customer!]!
+}
+
+type Mutation {
+	createCustomer(input: CustomerInput!): Customer!
+	updateCustomer(id: ID!, input: CustomerInput!): Customer!
+	deleteCustomer(id: ID!): Boolean!
+}
+
+type Customer {
+	id: ID!
+	name: String!
+	email: String
+	phone: String
+	"YYYY-MM-DD"
+	dateOfBirth: String
+	"Computed from dateOfBirth."
+	age: Int
+	salary: Money
+	"prospect, active, suspended or closed. Changed through POST /customer/{id}/transitions."
+	status: String
+	"RFC 3339"
+	createdAt: String
+	"RFC 3339"
+	updatedAt: String
+	"The custom attributes defined through /attributes, as a JSON object."
+	attributes: String
+}
+
+input CustomerFilter {
+	name: String
+	minAge: Int
+	maxAge: Int
+}
+
+input Page {
+	limit: Int
+	offset: Int
+}
+
+input CustomerInput {
+	name: String!
+	email: String
+	phone: String
+	"YYYY-MM-DD"
+	dateOfBirth: String
+	salary: MoneyInput
+	"A JSON object. An update without attributes keeps the stored ones."
+	attributes: String
+}
+
+"An amount in a currency, e.g. 30000.00 INR."
+type Money {
+	"Decimal string with as many decimals as the minor unit of the currency."
+	amount: String!
+	"ISO 4217"
+	currency: String!
+}
+
+input MoneyInput {
+	amount: String!
+	currency: String!
+}
+`
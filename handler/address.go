@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"strconv"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+// Address serves the addresses of a customer under /customer/{id}/addresses.
+type Address struct {
+	service service.AddressHandlerIn
+}
+
+func NewAddress(a service.AddressHandlerIn) Address {
+	return Address{service: a}
+}
+
+func (a Address) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Address.Get")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+	return a.service.List(ctx, customerID)
+}
+
+func (a Address) GetByID(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Address.GetByID")
+	defer span.End()
+
+	customerID, id, err := addressIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.service.Get(ctx, customerID, id)
+}
+
+func (a Address) Create(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Address.Create")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	var address models.Address
+	if err := ctx.Bind(&address); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	if address.ID != 0 {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+	address.CustomerID = customerID
+	return a.service.Create(ctx, address)
+}
+
+func (a Address) Update(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Address.Update")
+	defer span.End()
+
+	customerID, id, err := addressIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var address models.Address
+	if err := ctx.Bind(&address); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	address.ID, address.CustomerID = id, customerID
+	return a.service.Update(ctx, address)
+}
+
+func (a Address) Delete(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Address.Delete")
+	defer span.End()
+
+	customerID, id, err := addressIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nil, a.service.Delete(ctx, customerID, id)
+}
+
+// addressIDs reads the customer and address ids from the path.
+func addressIDs(ctx *gofr.Context) (customerID, id int, err error) {
+	customerID, err = pathID(ctx, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	id, err = pathID(ctx, "addressId")
+	if err != nil {
+		return 0, 0, err
+	}
+	return customerID, id, nil
+}
+
+func pathID(ctx *gofr.Context, name string) (int, error) {
+	value := ctx.PathParam(name)
+	if value == "" {
+		return 0, errors.MissingParam{Param: []string{name}}
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.InvalidParam{Param: []string{name}}
+	}
+	return id, nil
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAddress_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAddressHandlerIn(ctrl)
+	h := NewAddress(m)
+
+	addresses := []models.Address{{ID: 1, CustomerID: 1, Type: models.AddressBilling, Country: "IN", PostalCode: "560001"}}
+
+	tests := []struct {
+		desc     string
+		ID       string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", "1", addresses, nil, []*gomock.Call{m.EXPECT().List(gomock.Any(), 1).Return(addresses, nil)}},
+		{"missing ID", "", nil, errors.MissingParam{Param: []string{"id"}}, nil},
+		{"invalid ID", "s", nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodGet, "http://customer", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": tc.ID})
+
+			resp, err := h.Get(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAddress_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAddressHandlerIn(ctrl)
+	h := NewAddress(m)
+
+	input := models.Address{CustomerID: 1, Type: models.AddressShipping, Country: "IN", PostalCode: "560001"}
+	created := input
+	created.ID = 7
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", `{"type":"shipping","country":"IN","postalCode":"560001"}`, created, nil,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), input).Return(created, nil)}},
+		{"id in body", `{"id":3,"type":"shipping","country":"IN","postalCode":"560001"}`, nil,
+			errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"invalid body", `{"type":`, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPost, "http://customer", bytes.NewReader([]byte(tc.body))))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "1"})
+
+			resp, err := h.Create(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAddress_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAddressHandlerIn(ctrl)
+	h := NewAddress(m)
+
+	updated := models.Address{ID: 7, CustomerID: 1, Type: models.AddressBilling, Country: "IN", PostalCode: "560002"}
+
+	tests := []struct {
+		desc      string
+		addressID string
+		expected  interface{}
+		err       error
+		mock      []*gomock.Call
+	}{
+		{"success", "7", updated, nil, []*gomock.Call{m.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)}},
+		{"missing address ID", "", nil, errors.MissingParam{Param: []string{"addressId"}}, nil},
+		{"not found", "7", models.Address{}, errors.EntityNotFound{Entity: "address", ID: "7"}, []*gomock.Call{
+			m.EXPECT().Update(gomock.Any(), updated).Return(models.Address{}, errors.EntityNotFound{Entity: "address", ID: "7"})}},
+	}
+
+	for i, tc := range tests {
+		body := []byte(`{"type":"billing","country":"IN","postalCode":"560002"}`)
+		ctx := connect(httptest.NewRequest(http.MethodPut, "http://customer", bytes.NewReader(body)))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "1", "addressId": tc.addressID})
+
+			resp, err := h.Update(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAddress_GetByIDAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAddressHandlerIn(ctrl)
+	h := NewAddress(m)
+
+	address := models.Address{ID: 7, CustomerID: 1, Type: models.AddressBilling, Country: "IN", PostalCode: "560002"}
+
+	m.EXPECT().Get(gomock.Any(), 1, 7).Return(address, nil)
+	m.EXPECT().Delete(gomock.Any(), 1, 7).Return(nil)
+
+	ctx := connect(httptest.NewRequest(http.MethodGet, "http://customer", nil))
+	ctx.SetPathParams(map[string]string{"id": "1", "addressId": "7"})
+
+	if resp, err := h.GetByID(ctx); err != nil || !reflect.DeepEqual(resp, address) {
+		t.Errorf("Expected %v\nGot %v, %v", address, resp, err)
+	}
+
+	if resp, err := h.Delete(ctx); err != nil || resp != nil {
+		t.Errorf("Expected no content\nGot %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+// Attribute serves the custom attribute schema of the tenant under /attributes.
+type Attribute struct {
+	service service.AttributeHandlerIn
+}
+
+func NewAttribute(a service.AttributeHandlerIn) Attribute {
+	return Attribute{service: a}
+}
+
+func (a Attribute) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Attribute.Get")
+	defer span.End()
+
+	return a.service.List(ctx)
+}
+
+func (a Attribute) Create(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Attribute.Create")
+	defer span.End()
+
+	var d models.AttributeDefinition
+	if err := ctx.Bind(&d); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	if d.ID != 0 {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+	return a.service.Create(ctx, d)
+}
+
+// Delete removes the attribute in the path from the schema and from every customer of the tenant.
+func (a Attribute) Delete(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Attribute.Delete")
+	defer span.End()
+
+	name := ctx.PathParam("name")
+	if name == "" {
+		return nil, errors.MissingParam{Param: []string{"name"}}
+	}
+	return nil, a.service.Delete(ctx, name)
+}
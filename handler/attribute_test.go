@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAttribute_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAttributeHandlerIn(ctrl)
+	h := NewAttribute(m)
+
+	plan := models.AttributeDefinition{Name: "plan", Type: models.AttributeString, Required: true, Enum: []string{"gold", "silver"}}
+	created := plan
+	created.ID = 1
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", `{"name":"plan","type":"string","required":true,"enum":["gold","silver"]}`, created, nil,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), plan).Return(created, nil)}},
+		{"id in body", `{"id":1,"name":"plan","type":"string"}`, nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"invalid body", `{"name":`, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPost, "http://customer", bytes.NewReader([]byte(tc.body))))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.Create(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAttribute_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAttributeHandlerIn(ctrl)
+	h := NewAttribute(m)
+
+	tests := []struct {
+		desc   string
+		params map[string]string
+		err    error
+		mock   []*gomock.Call
+	}{
+		{"success", map[string]string{"name": "plan"}, nil, []*gomock.Call{m.EXPECT().Delete(gomock.Any(), "plan").Return(nil)}},
+		{"forbidden", map[string]string{"name": "plan"}, errors.ForbiddenRequest{URL: "attributes"},
+			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), "plan").Return(errors.ForbiddenRequest{URL: "attributes"})}},
+		{"missing name", map[string]string{}, errors.MissingParam{Param: []string{"name"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodDelete, "http://customer", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(tc.params)
+
+			resp, err := h.Delete(ctx)
+			if resp != nil {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, nil, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
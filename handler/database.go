@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// HasDatabase reports whether app is connected to the SQL database.
+func HasDatabase(app *gofr.Gofr) bool {
+	db := app.DB()
+	return db != nil && db.DB != nil
+}
+
+// WithDatabase returns the handlers of the routes whose data is only kept in the SQL database, such as addresses
+// and segments. Without a database, e.g. with STORE_BACKEND=memory and no DB_HOST, they answer 501 instead of
+// failing on their first query.
+func WithDatabase(app *gofr.Gofr) func(h gofr.Handler) gofr.Handler {
+	if HasDatabase(app) {
+		return func(h gofr.Handler) gofr.Handler { return h }
+	}
+
+	return Unsupported("DATABASE_NOT_CONFIGURED", "this route is served from the SQL database, which is not configured")
+}
+
+// Unsupported returns the handlers of routes the configured stores cannot serve. They answer 501 with code and
+// reason instead.
+func Unsupported(code, reason string) func(h gofr.Handler) gofr.Handler {
+	return func(gofr.Handler) gofr.Handler {
+		return func(*gofr.Context) (interface{}, error) {
+			return nil, &errors.Response{StatusCode: http.StatusNotImplemented, Code: code, Reason: reason}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWithDatabase(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	served := func(*gofr.Context) (interface{}, error) { return "served", nil }
+	refused := &errors.Response{StatusCode: http.StatusNotImplemented, Code: "DATABASE_NOT_CONFIGURED",
+		Reason: "this route is served from the SQL database, which is not configured"}
+
+	tests := []struct {
+		desc     string
+		app      *gofr.Gofr
+		expected interface{}
+		err      error
+	}{
+		{"database", &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}}, "served", nil},
+		{"no database", &gofr.Gofr{}, nil, refused},
+	}
+
+	for i, tc := range tests {
+		res, err := WithDatabase(tc.app)(served)(gofr.NewContext(nil, nil, tc.app))
+		if !reflect.DeepEqual(res, tc.expected) || !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, tc.expected, tc.err, res, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/template"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+const zipType = "application/zip"
+
+// GDPR serves the access and erasure requests of customers under /customer/{id}/gdpr-export and
+// /customer/{id}/erase, and the erasure log under /erasures.
+type GDPR struct {
+	service service.GDPRHandlerIn
+}
+
+func NewGDPR(g service.GDPRHandlerIn) GDPR {
+	return GDPR{service: g}
+}
+
+// Export returns everything kept about the customer as JSON, or with format=zip as a ZIP archive holding one
+// JSON file per part of the export.
+func (g GDPR) Export(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.GDPR.Export")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	format := ctx.Param("format")
+	if format != "" && format != "json" && format != "zip" {
+		return nil, errors.InvalidParam{Param: []string{"format"}}
+	}
+
+	res, err := g.service.Export(ctx, customerID)
+	if err != nil || format != "zip" {
+		return res, err
+	}
+
+	archive, err := exportArchive(res)
+	if err != nil {
+		return nil, err
+	}
+	return template.File{Content: archive, ContentType: zipType}, nil
+}
+
+// Erase irreversibly removes the personal data of the customer and returns the entry recorded in the erasure log.
+func (g GDPR) Erase(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.GDPR.Erase")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+	return g.service.Erase(ctx, customerID)
+}
+
+// Erasures returns the erasure log of the tenant and whether it is intact.
+func (g GDPR) Erasures(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.GDPR.Erasures")
+	defer span.End()
+
+	return g.service.Erasures(ctx)
+}
+
+// exportArchive writes an export as a ZIP archive of indented JSON files.
+func exportArchive(e models.Export) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"customer.json", e.Customer},
+		{"merged.json", e.Merged},
+		{"addresses.json", e.Addresses},
+		{"transitions.json", e.Transitions},
+		{"tags.json", e.Tags},
+		{"relationships.json", e.Relationships},
+		{"audit.json", e.Audit},
+	}
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for _, f := range files {
+		b, err := json.MarshalIndent(f.data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: e.ExportedAt})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := fw.Write(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/template"
+	"github.com/golang/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGDPR_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockGDPRHandlerIn(ctrl)
+	h := NewGDPR(m)
+
+	export := models.Export{Customer: models.Customer{ID: 1, Name: "Divya"}, Tags: []string{"vip"}}
+
+	tests := []struct {
+		desc     string
+		target   string
+		id       string
+		expected interface{}
+		err      error
+		mock     *gomock.Call
+	}{
+		{"json", "/customer/1/gdpr-export", "1", export, nil, m.EXPECT().Export(gomock.Any(), 1).Return(export, nil)},
+		{"json format", "/customer/1/gdpr-export?format=json", "1", export, nil,
+			m.EXPECT().Export(gomock.Any(), 1).Return(export, nil)},
+		{"unknown format", "/customer/1/gdpr-export?format=xml", "1", nil, errors.InvalidParam{Param: []string{"format"}}, nil},
+		{"invalid id", "/customer/abc/gdpr-export", "abc", nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"forbidden", "/customer/1/gdpr-export?format=zip", "1", models.Export{}, errors.ForbiddenRequest{URL: "gdpr"},
+			m.EXPECT().Export(gomock.Any(), 1).Return(models.Export{}, errors.ForbiddenRequest{URL: "gdpr"})},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": tc.id})
+
+			resp, err := h.Export(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestGDPR_ExportZIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockGDPRHandlerIn(ctrl)
+	h := NewGDPR(m)
+
+	m.EXPECT().Export(gomock.Any(), 1).Return(models.Export{Customer: models.Customer{ID: 1, Name: "Divya"},
+		Tags: []string{"vip"}}, nil)
+
+	ctx := connect(httptest.NewRequest(http.MethodGet, "/customer/1/gdpr-export?format=zip", nil))
+	ctx.SetPathParams(map[string]string{"id": "1"})
+
+	resp, err := h.Export(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error\nGot %v", err)
+	}
+
+	file, ok := resp.(template.File)
+	if !ok || file.ContentType != zipType {
+		t.Fatalf("Expected a %v file\nGot %v", zipType, resp)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(file.Content), int64(len(file.Content)))
+	if err != nil {
+		t.Fatalf("Expected a ZIP archive\nGot %v", err)
+	}
+
+	expected := map[string]string{
+		"customer.json": "{\n  \"id\": 1,\n  \"name\": \"Divya\"\n}",
+		"tags.json":     "[\n  \"vip\"\n]",
+		"merged.json":   "null",
+	}
+	got := map[string]string{}
+
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("Expected %v to open\nGot %v", f.Name, err)
+		}
+
+		b, _ := io.ReadAll(r)
+		r.Close()
+
+		got[f.Name] = string(b)
+	}
+
+	if len(got) != 7 {
+		t.Errorf("Expected 7 files\nGot %v", len(got))
+	}
+
+	for name, content := range expected {
+		if got[name] != content {
+			t.Errorf("Expected %v to hold %v\nGot %v", name, content, got[name])
+		}
+	}
+}
+
+func TestGDPR_Erase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockGDPRHandlerIn(ctrl)
+	h := NewGDPR(m)
+
+	erasure := models.Erasure{ID: 1, CustomerID: 1, PrevHash: models.GenesisHash, Hash: "ab"}
+
+	tests := []struct {
+		desc     string
+		id       string
+		expected interface{}
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", "1", erasure, nil, m.EXPECT().Erase(gomock.Any(), 1).Return(erasure, nil)},
+		{"not found", "2", models.Erasure{}, errors.EntityNotFound{Entity: "customer", ID: "2"},
+			m.EXPECT().Erase(gomock.Any(), 2).Return(models.Erasure{}, errors.EntityNotFound{Entity: "customer", ID: "2"})},
+		{"invalid id", "abc", nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPost, "/customer/"+tc.id+"/erase", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": tc.id})
+
+			resp, err := h.Erase(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
@@ -3,13 +3,17 @@ package handler
 import (
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
 	"strconv"
 
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 
+	"customer/metrics"
+	"customer/middleware"
 	"customer/models"
 	"customer/service"
+	"customer/tracing"
 
 	jsonpatch "github.com/evanphx/json-patch"
 )
@@ -23,10 +27,30 @@ func New(h service.HandlerIn) Handler {
 }
 
 func (h Handler) Get(ctx *gofr.Context) (interface{}, error) {
-	return h.service.Get(ctx)
+	ctx, span := tracing.Start(ctx, "handler.Get")
+	defer span.End()
+
+	filter, err := getFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Fields, err = getFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.service.Get(ctx, filter)
+	if err != nil || filter.Fields == nil {
+		return res, err
+	}
+	return models.ProjectAll(res, filter.Fields), nil
 }
 
 func (h Handler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.GetByID")
+	defer span.End()
+
 	id := ctx.PathParam("id")
 	if id == "" {
 		return nil, errors.MissingParam{Param: []string{"id"}}
@@ -35,11 +59,24 @@ func (h Handler) GetByID(ctx *gofr.Context) (interface{}, error) {
 	if err != nil {
 		return nil, errors.InvalidParam{Param: []string{"id"}}
 	}
-	return h.service.GetByID(ctx, uid)
+	tracing.SetCustomerID(ctx, uid)
+
+	fields, err := getFields(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	res, err := h.service.GetByID(ctx, uid, fields...)
+	if err != nil || fields == nil {
+		return res, err
+	}
+	return res.Project(fields), nil
 }
 
 func (h Handler) Create(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Create")
+	defer span.End()
+
 	var customer models.Customer
 	if err := ctx.Bind(&customer); err != nil {
 		return nil, errors.InvalidParam{Param: []string{"body"}}
@@ -48,6 +85,9 @@ func (h Handler) Create(ctx *gofr.Context) (interface{}, error) {
 }
 
 func (h Handler) Update(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Update")
+	defer span.End()
+
 	id := ctx.PathParam("id")
 	if id == "" {
 		return nil, errors.MissingParam{Param: []string{"id"}}
@@ -56,6 +96,7 @@ func (h Handler) Update(ctx *gofr.Context) (interface{}, error) {
 	if err != nil {
 		return nil, errors.InvalidParam{Param: []string{"id"}}
 	}
+	tracing.SetCustomerID(ctx, uid)
 	var customer models.Customer
 	if err := ctx.Bind(&customer); err != nil {
 		return nil, errors.InvalidParam{Param: []string{"body"}}
@@ -64,7 +105,47 @@ func (h Handler) Update(ctx *gofr.Context) (interface{}, error) {
 	return h.service.Update(ctx, customer)
 }
 
+// Upsert creates or replaces the customer named in the path, answering 201 when it was created and 200 when it
+// was replaced. A name in the body must match the one in the path.
+func (h Handler) Upsert(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Upsert")
+	defer span.End()
+
+	name := ctx.PathParam("name")
+	if name == "" {
+		return nil, errors.MissingParam{Param: []string{"name"}}
+	}
+
+	var customer models.Customer
+	if err := ctx.Bind(&customer); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	if customer.ID != 0 {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	if customer.Name != "" && customer.Name != name {
+		return nil, errors.InvalidParam{Param: []string{"name"}}
+	}
+
+	customer.Name = name
+
+	res, created, err := h.service.Upsert(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		middleware.SetStatus(ctx, http.StatusCreated)
+	}
+	return res, nil
+}
+
 func (h Handler) Delete(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Delete")
+	defer span.End()
+
 	id := ctx.PathParam("id")
 	if id == "" {
 		return nil, errors.MissingParam{Param: []string{"id"}}
@@ -73,11 +154,15 @@ func (h Handler) Delete(ctx *gofr.Context) (interface{}, error) {
 	if err != nil {
 		return nil, errors.InvalidParam{Param: []string{"id"}}
 	}
+	tracing.SetCustomerID(ctx, uid)
 	err = h.service.Delete(ctx, uid)
 	return nil, err
 }
 
 func (h Handler) Patch(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Patch")
+	defer span.End()
+
 	id := ctx.PathParam("id")
 	if id == "" {
 		return nil, errors.MissingParam{Param: []string{"id"}}
@@ -87,6 +172,7 @@ func (h Handler) Patch(ctx *gofr.Context) (interface{}, error) {
 	if err != nil {
 		return nil, errors.InvalidParam{Param: []string{"id"}}
 	}
+	tracing.SetCustomerID(ctx, uid)
 
 	body, err1 := ioutil.ReadAll(ctx.Request().Body)
 	if err1 != nil {
@@ -106,8 +192,39 @@ func (h Handler) Patch(ctx *gofr.Context) (interface{}, error) {
 		return nil, errors.Error("unmarshal error")
 	}
 
+	// MergePatch prunes the nulls of nested objects, so the attributes are read from the body itself: a null
+	// attribute is removed from the customer.
+	var raw struct {
+		Attributes models.Attributes `json:"attributes"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, errors.Error("unmarshal error")
+	}
+	customer.Attributes = raw.Attributes
+
 	if customer.ID != 0 {
 		return nil, errors.InvalidParam{Param: []string{"id"}}
 	}
+
+	// The status only changes through POST /customer/{id}/transitions.
+	if customer.Status != "" {
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
 	return h.service.Patch(ctx, uid, customer)
 }
+
+// getFilter reads the listing filters and pagination from the query parameters.
+func getFilter(ctx *gofr.Context) (models.Filter, error) {
+	filter, err := models.ParseFilter(ctx.Request().URL.Query())
+	metrics.CountValidation(err)
+
+	return filter, err
+}
+
+// getFields reads the fields the customers returned are projected to.
+func getFields(ctx *gofr.Context) ([]string, error) {
+	fields, err := models.ParseFields(ctx.Param("fields"))
+	metrics.CountValidation(err)
+
+	return fields, err
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"customer/middleware"
 	"customer/mocks"
 	"customer/models"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
@@ -21,6 +22,8 @@ func connect(r *http.Request) *gofr.Context {
 	req := request.NewHTTPRequest(r)
 	res := responder.NewContextualResponder(w, r)
 	ctx := gofr.NewContext(res, req, app)
+	// gofr hands the request context to the handlers it routes.
+	ctx.Context = r.Context()
 	return ctx
 }
 
@@ -32,25 +35,45 @@ func TestHandler_Get(t *testing.T) {
 	h := New(m)
 
 	customer1 := []models.Customer{{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", Age: 22, Salary: &models.Money{Minor: 3000000, Currency: "INR"},
 	}}
 
 	tests := []struct {
 		desc     string
+		query    string
 		mocks    []*gomock.Call
 		expected interface{}
 		err      error
 	}{
-		{"get all", []*gomock.Call{m.EXPECT().Get(gomock.Any()).Return(customer1, nil)},
+		{"get all", "", []*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{}).Return(customer1, nil)},
 			customer1, nil},
-		{"internal server error",
-			[]*gomock.Call{m.EXPECT().Get(gomock.Any()).Return([]models.Customer{}, errors.DB{Err: errors.Error("db error")})},
+		{"filtered", "?name=Divya&minAge=20&maxAge=30&limit=10&offset=5",
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{Name: "Divya", MinAge: 20, MaxAge: 30, Limit: 10, Offset: 5}).
+				Return(customer1, nil)},
+			customer1, nil},
+		{"projected", "?fields=id,name",
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{Fields: []string{"id", "name"}}).Return(customer1, nil)},
+			[]map[string]interface{}{{"id": 1, "name": "Divya"}}, nil},
+		{"unknown field", "?fields=id,tenant", nil, nil, errors.InvalidParam{Param: []string{"fields"}}},
+		{"invalid filter", "?minAge=abc", nil, nil, errors.InvalidParam{Param: []string{"minAge"}}},
+		{"negative limit", "?limit=-1", nil, nil, errors.InvalidParam{Param: []string{"limit"}}},
+		{"by status", "?status=suspended",
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{Status: models.StatusSuspended}).Return(customer1, nil)},
+			customer1, nil},
+		{"unknown status", "?status=churned", nil, nil, errors.InvalidParam{Param: []string{"status"}}},
+		{"by tags", "?tag=vip&tag=!churned",
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{Tags: []string{"vip"}, ExcludedTags: []string{"churned"}}).
+				Return(customer1, nil)},
+			customer1, nil},
+		{"invalid tag", "?tag=Big%20Spender", nil, nil, errors.InvalidParam{Param: []string{"tag"}}},
+		{"internal server error", "",
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{}).Return([]models.Customer{}, errors.DB{Err: errors.Error("db error")})},
 			[]models.Customer{}, errors.DB{Err: errors.Error("db error")}},
 	}
 
 	for i, tc := range tests {
 
-		r := httptest.NewRequest(http.MethodGet, "http://customer", nil)
+		r := httptest.NewRequest(http.MethodGet, "http://customer"+tc.query, nil)
 		ctx := connect(r)
 
 		t.Run(tc.desc, func(t *testing.T) {
@@ -74,7 +97,7 @@ func TestHandler_GetByID(t *testing.T) {
 	h := New(m)
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", Age: 22, Salary: &models.Money{Minor: 3000000, Currency: "INR"},
 	}
 
 	tests := []struct {
@@ -116,6 +139,50 @@ func TestHandler_GetByID(t *testing.T) {
 	}
 }
 
+func TestHandler_GetByIDFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockHandlerIn(ctrl)
+	h := New(m)
+
+	customer1 := models.Customer{ID: 1, Name: "Divya"}
+
+	tests := []struct {
+		desc     string
+		query    string
+		expected interface{}
+		err      error
+		mocks    []*gomock.Call
+	}{
+		{"projected", "?fields=name,email", map[string]interface{}{"name": "Divya", "email": ""}, nil,
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1, "name", "email").Return(customer1, nil)}},
+		{"repeated field", "?fields=id,id", map[string]interface{}{"id": 1}, nil,
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1, "id").Return(customer1, nil)}},
+		{"unknown field", "?fields=password", nil, errors.InvalidParam{Param: []string{"fields"}}, nil},
+		{"not found", "?fields=name", models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1, "name").
+				Return(models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "1"})}},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://customer/1"+tc.query, nil)
+		ctx := connect(r)
+		ctx.SetPathParams(map[string]string{"id": "1"})
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.GetByID(ctx)
+
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
 func TestHandler_Create(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -124,13 +191,14 @@ func TestHandler_Create(t *testing.T) {
 	h := New(m)
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", Age: 22, Salary: &models.Money{Minor: 3000000, Currency: "INR"},
 	}
 
-	c1 := []byte(`{"id":1, "name": "divya", "age": 22, "salary": 30000}`)
+	c1 := []byte(`{"id":1, "name": "divya", "age": 22, "salary": {"amount": "30000.00", "currency": "INR"}}`)
 	c2 := []byte(``)
-	c3 := []byte(`{"id":1, "name": "", "age": 22, "salary": 30000}`)
-	c4 := []byte(`{"id":1, "age": 22, "salary": 30000}`)
+	c3 := []byte(`{"id":1, "name": "", "age": 22, "salary": {"amount": "30000.00", "currency": "INR"}}`)
+	c4 := []byte(`{"id":1, "age": 22, "salary": {"amount": "30000.00", "currency": "INR"}}`)
+	c5 := []byte(`{"name": "divya", "salary": 30000}`)
 
 	tests := []struct {
 		desc     string
@@ -146,6 +214,7 @@ func TestHandler_Create(t *testing.T) {
 			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.InvalidParam{Param: []string{"body"}})}},
 		{"invalid body 3", c4, models.Customer{}, errors.InvalidParam{Param: []string{"body"}},
 			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.InvalidParam{Param: []string{"body"}})}},
+		{"salary without currency", c5, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
 		{"internal server error", c1, models.Customer{}, errors.DB{Err: errors.Error("db err")},
 			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.DB{Err: errors.Error("db err")})}},
 	}
@@ -176,11 +245,11 @@ func TestHandler_Update(t *testing.T) {
 	h := New(m)
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", Age: 22, Salary: &models.Money{Minor: 3000000, Currency: "INR"},
 	}
-	c1 := []byte(`{"id":1, "name": "divya", "age": 22, "salary": 30000}`)
+	c1 := []byte(`{"id":1, "name": "divya", "age": 22, "salary": {"amount": "30000.00", "currency": "INR"}}`)
 	c2 := []byte(``)
-	c3 := []byte(`{"id":1, "age":21, "salary": 30000}`)
+	c3 := []byte(`{"id":1, "age":21, "salary": {"amount": "30000.00", "currency": "INR"}}`)
 
 	tests := []struct {
 		desc     string
@@ -226,6 +295,72 @@ func TestHandler_Update(t *testing.T) {
 	}
 }
 
+func TestHandler_Upsert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockHandlerIn(ctrl)
+	h := New(m)
+
+	divya := models.Customer{ID: 1, Name: "Divya", Email: "divya@example.com"}
+
+	tests := []struct {
+		desc     string
+		name     string
+		body     string
+		expected interface{}
+		status   int
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"created", "Divya", `{"email": "divya@example.com"}`, divya, http.StatusCreated, nil,
+			[]*gomock.Call{m.EXPECT().Upsert(gomock.Any(), models.Customer{Name: "Divya", Email: "divya@example.com"}).
+				Return(divya, true, nil)}},
+		{"replaced", "Divya", `{"name": "Divya", "email": "divya@example.com"}`, divya, http.StatusOK, nil,
+			[]*gomock.Call{m.EXPECT().Upsert(gomock.Any(), models.Customer{Name: "Divya", Email: "divya@example.com"}).
+				Return(divya, false, nil)}},
+		{"other name in body", "Divya", `{"name": "Jay"}`, nil, http.StatusOK, errors.InvalidParam{Param: []string{"name"}}, nil},
+		{"id in body", "Divya", `{"id": 2}`, nil, http.StatusOK, errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"invalid body", "Divya", `{`, nil, http.StatusOK, errors.InvalidParam{Param: []string{"body"}}, nil},
+		{"missing name", "", `{}`, nil, http.StatusOK, errors.MissingParam{Param: []string{"name"}}, nil},
+		{"internal server error", "Divya", `{}`, nil, http.StatusOK, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().Upsert(gomock.Any(), models.Customer{Name: "Divya"}).
+				Return(models.Customer{}, false, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var (
+				resp interface{}
+				err  error
+			)
+
+			// The status set by the handler is applied by middleware.Status, as gofr writes its 200.
+			served := middleware.Status(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := connect(r)
+				ctx.SetPathParams(map[string]string{"name": tc.name})
+
+				resp, err = h.Upsert(ctx)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			served.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "http://customer/by-name/"+tc.name,
+				bytes.NewReader([]byte(tc.body))))
+
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if w.Code != tc.status {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.status, w.Code)
+			}
+		})
+	}
+}
+
 func TestHandler_Delete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -275,11 +410,11 @@ func TestHandler_Patch(t *testing.T) {
 	h := New(m)
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", Age: 22, Salary: &models.Money{Minor: 3000000, Currency: "INR"},
 	}
 	c1 := []byte(`{"name": "divya"}`)
 	c2 := []byte(`{"divya"}`)
-	c3 := []byte(`{"id":1, "age":21, "salary": 30000}`)
+	c3 := []byte(`{"id":1, "age":21, "salary": {"amount": "30000.00", "currency": "INR"}}`)
 
 	tests := []struct {
 		desc     string
@@ -299,6 +434,11 @@ func TestHandler_Patch(t *testing.T) {
 		//{"unmarshall error", "1", []byte(`"name":"divya":`), nil, errors.Error("unmarshal error"), nil},
 		{"restricted field ID", "1", c3, nil, errors.InvalidParam{Param: []string{"id"}},
 			nil},
+		{"restricted field status", "1", []byte(`{"status": "active"}`), nil, errors.InvalidParam{Param: []string{"status"}},
+			nil},
+		{"null attribute", "1", []byte(`{"attributes": {"plan": "gold", "seats": null}}`), customer1, nil,
+			[]*gomock.Call{m.EXPECT().Patch(gomock.Any(), 1,
+				models.Customer{Attributes: models.Attributes{"plan": "gold", "seats": nil}}).Return(customer1, nil)}},
 	}
 
 	for i, tc := range tests {
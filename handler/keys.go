@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/service"
+	"customer/tracing"
+)
+
+// Keys serves the rotation of the data key of the tenant under /keys/rotate.
+type Keys struct {
+	service service.KeysHandlerIn
+}
+
+func NewKeys(k service.KeysHandlerIn) Keys {
+	return Keys{service: k}
+}
+
+// Rotate starts encrypting the customers of the tenant with a new data key, and returns it.
+func (k Keys) Rotate(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Keys.Rotate")
+	defer span.End()
+
+	return k.service.Rotate(ctx)
+}
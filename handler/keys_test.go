@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestKeys_Rotate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockKeysHandlerIn(ctrl)
+	h := NewKeys(m)
+
+	tests := []struct {
+		desc     string
+		expected interface{}
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", models.DataKey{ID: 2}, nil, m.EXPECT().Rotate(gomock.Any()).Return(models.DataKey{ID: 2}, nil)},
+		{"forbidden", models.DataKey{}, errors.ForbiddenRequest{URL: "keys"},
+			m.EXPECT().Rotate(gomock.Any()).Return(models.DataKey{}, errors.ForbiddenRequest{URL: "keys"})},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPost, "/keys/rotate", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.Rotate(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+// Merge serves /customer/duplicates and /customer/merge.
+type Merge struct {
+	service service.MergeHandlerIn
+}
+
+func NewMerge(m service.MergeHandlerIn) Merge {
+	return Merge{service: m}
+}
+
+// Duplicates lists the pairs of customers that are likely the same person, best first, with their score.
+func (m Merge) Duplicates(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Merge.Duplicates")
+	defer span.End()
+
+	return m.service.Duplicates(ctx)
+}
+
+// Merge merges the customers with loserIds into the one with survivorId and returns the survivor.
+func (m Merge) Merge(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Merge")
+	defer span.End()
+
+	var req models.MergeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	tracing.SetCustomerID(ctx, req.SurvivorID)
+
+	return m.service.Merge(ctx, req)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMerge_Duplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockMergeHandlerIn(ctrl)
+	h := NewMerge(m)
+
+	duplicates := []models.Duplicate{{Customers: [2]models.Customer{{ID: 1, Name: "Jay"}, {ID: 2, Name: "jay"}}, Score: 1}}
+
+	tests := []struct {
+		desc     string
+		expected interface{}
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", duplicates, nil, m.EXPECT().Duplicates(gomock.Any()).Return(duplicates, nil)},
+		{"internal server error", []models.Duplicate(nil), errors.DB{Err: errors.Error("db error")},
+			m.EXPECT().Duplicates(gomock.Any()).Return(nil, errors.DB{Err: errors.Error("db error")})},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/customer/duplicates", nil)
+		ctx := connect(r)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.Duplicates(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMerge_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockMergeHandlerIn(ctrl)
+	h := NewMerge(m)
+
+	survivor := models.Customer{ID: 1, Name: "Jay", Phone: "+919876543211"}
+	req := models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2, 3}}
+	notFound := errors.EntityNotFound{Entity: "customer", ID: "3"}
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected interface{}
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", `{"survivorId": 1, "loserIds": [2, 3]}`, survivor, nil,
+			m.EXPECT().Merge(gomock.Any(), req).Return(survivor, nil)},
+		{"loser not found", `{"survivorId": 1, "loserIds": [2, 3]}`, models.Customer{}, notFound,
+			m.EXPECT().Merge(gomock.Any(), req).Return(models.Customer{}, notFound)},
+		{"invalid body", `{"survivorId": "1"}`, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/customer/merge", bytes.NewReader([]byte(tc.body)))
+		ctx := connect(r)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.Merge(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"strconv"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+// Relationship serves the relationships of a customer under /customer/{id}/relationships, and the graph around
+// it under /customer/{id}/graph.
+type Relationship struct {
+	service service.RelationshipHandlerIn
+}
+
+func NewRelationship(r service.RelationshipHandlerIn) Relationship {
+	return Relationship{service: r}
+}
+
+func (r Relationship) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Relationship.Get")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+	return r.service.List(ctx, customerID)
+}
+
+// Create relates the customer in the path to the customer in "toId".
+func (r Relationship) Create(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Relationship.Create")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	var relationship models.Relationship
+	if err := ctx.Bind(&relationship); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	if relationship.ID != 0 {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+	if relationship.FromID != 0 && relationship.FromID != customerID {
+		return nil, errors.InvalidParam{Param: []string{"fromId"}}
+	}
+	relationship.FromID = customerID
+	return r.service.Create(ctx, relationship)
+}
+
+// Graph returns the customers within "depth" relationships of the customer, one by default.
+func (r Relationship) Graph(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Relationship.Graph")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	depth := 1
+	if v := ctx.Param("depth"); v != "" {
+		if depth, err = strconv.Atoi(v); err != nil {
+			return nil, errors.InvalidParam{Param: []string{"depth"}}
+		}
+	}
+	return r.service.Graph(ctx, customerID, depth)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRelationship_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockRelationshipHandlerIn(ctrl)
+	h := NewRelationship(m)
+
+	parent := models.Relationship{Type: models.RelationshipParent, FromID: 2, ToID: 1}
+	created := parent
+	created.ID = 1
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", `{"type":"parent","toId":1}`, created, nil,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), parent).Return(created, nil)}},
+		{"matching fromId", `{"type":"parent","fromId":2,"toId":1}`, created, nil,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), parent).Return(created, nil)}},
+		{"other fromId", `{"type":"parent","fromId":3,"toId":1}`, nil, errors.InvalidParam{Param: []string{"fromId"}}, nil},
+		{"id in body", `{"id":1,"type":"parent","toId":1}`, nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"invalid body", `{"type":`, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPost, "http://customer", bytes.NewReader([]byte(tc.body))))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "2"})
+
+			resp, err := h.Create(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestRelationship_Graph(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockRelationshipHandlerIn(ctrl)
+	h := NewRelationship(m)
+
+	graph := models.Graph{Customers: []models.Customer{{ID: 2, Name: "Acme India"}}, Relationships: []models.Relationship{}}
+
+	tests := []struct {
+		desc     string
+		target   string
+		params   map[string]string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"default depth", "/customer/2/graph", map[string]string{"id": "2"}, graph, nil,
+			[]*gomock.Call{m.EXPECT().Graph(gomock.Any(), 2, 1).Return(graph, nil)}},
+		{"depth", "/customer/2/graph?depth=3", map[string]string{"id": "2"}, graph, nil,
+			[]*gomock.Call{m.EXPECT().Graph(gomock.Any(), 2, 3).Return(graph, nil)}},
+		{"invalid depth", "/customer/2/graph?depth=all", map[string]string{"id": "2"}, nil,
+			errors.InvalidParam{Param: []string{"depth"}}, nil},
+		{"invalid id", "/customer/abc/graph", map[string]string{"id": "abc"}, nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(tc.params)
+
+			resp, err := h.Graph(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+// Segment serves the saved segments under /segments, and their customers under /segments/{id}/customers.
+type Segment struct {
+	service service.SegmentHandlerIn
+}
+
+func NewSegment(s service.SegmentHandlerIn) Segment {
+	return Segment{service: s}
+}
+
+func (s Segment) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Segment.Get")
+	defer span.End()
+
+	return s.service.List(ctx)
+}
+
+func (s Segment) GetByID(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Segment.GetByID")
+	defer span.End()
+
+	id, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	return s.service.Get(ctx, id)
+}
+
+func (s Segment) Create(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Segment.Create")
+	defer span.End()
+
+	var segment models.Segment
+	if err := ctx.Bind(&segment); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	if segment.ID != 0 {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+	return s.service.Create(ctx, segment)
+}
+
+func (s Segment) Update(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Segment.Update")
+	defer span.End()
+
+	id, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var segment models.Segment
+	if err := ctx.Bind(&segment); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	if segment.ID != 0 && segment.ID != id {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+	segment.ID = id
+	return s.service.Update(ctx, segment)
+}
+
+func (s Segment) Delete(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Segment.Delete")
+	defer span.End()
+
+	id, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	return nil, s.service.Delete(ctx, id)
+}
+
+// Customers evaluates the segment, paged by limit and offset and projected by fields. Other filters in the
+// query are ignored, since the segment has its own.
+func (s Segment) Customers(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Segment.Customers")
+	defer span.End()
+
+	id, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := getFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Fields, err = getFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.service.Customers(ctx, id, page)
+	if err != nil || page.Fields == nil {
+		return res, err
+	}
+	return models.ProjectAll(res, page.Fields), nil
+}
+
+// Stream exports GET /segments/{id}/customers as Handler.Stream does for GET /customer, e.g. as CSV when the
+// client accepts text/csv. It must run after middleware.Auth.
+func (s Segment) Stream(app *gofr.Gofr) func(http.Handler) http.Handler {
+	return streaming(app, func(path string) streamFunc {
+		id, ok := segmentCustomersID(path)
+		if !ok {
+			return nil
+		}
+
+		return func(ctx *gofr.Context, page models.Filter, fn func(models.Customer) error) error {
+			return s.service.Stream(ctx, id, page, fn)
+		}
+	})
+}
+
+// segmentCustomersID returns the segment id of a /segments/{id}/customers path.
+func segmentCustomersID(path string) (int, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "segments" || parts[2] != "customers" {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(parts[1])
+
+	return id, err == nil
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSegment_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockSegmentHandlerIn(ctrl)
+	h := NewSegment(m)
+
+	vip := models.Segment{ID: 1, Name: "VIPs", Filter: "tag=vip"}
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", `{"name":"VIPs","filter":"tag=vip"}`, vip, nil,
+			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), vip).Return(vip, nil)}},
+		{"matching id in body", `{"id":1,"name":"VIPs","filter":"tag=vip"}`, vip, nil,
+			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), vip).Return(vip, nil)}},
+		{"other id in body", `{"id":2,"name":"VIPs","filter":"tag=vip"}`, nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"invalid body", `{"name":`, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPut, "http://customer", bytes.NewReader([]byte(tc.body))))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "1"})
+
+			resp, err := h.Update(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSegment_Customers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockSegmentHandlerIn(ctrl)
+	h := NewSegment(m)
+
+	customers := []models.Customer{{ID: 1, Name: "Divya", Age: 22}}
+
+	tests := []struct {
+		desc     string
+		query    string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", "?limit=10", customers, nil,
+			[]*gomock.Call{m.EXPECT().Customers(gomock.Any(), 1, models.Filter{Limit: 10}).Return(customers, nil)}},
+		{"projected", "?fields=id,name", []map[string]interface{}{{"id": 1, "name": "Divya"}}, nil,
+			[]*gomock.Call{m.EXPECT().Customers(gomock.Any(), 1, models.Filter{Fields: []string{"id", "name"}}).
+				Return(customers, nil)}},
+		{"invalid limit", "?limit=-1", nil, errors.InvalidParam{Param: []string{"limit"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodGet, "http://customer"+tc.query, nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "1"})
+
+			resp, err := h.Customers(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSegment_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockSegmentHandlerIn(ctrl)
+	h := NewSegment(m)
+
+	customers := []models.Customer{{ID: 1, Name: "Divya, R", Age: 22}, {ID: 2, Name: "Jay"}}
+	notFound := errors.EntityNotFound{Entity: "segment", ID: "7"}
+
+	// segmentStreamed is streamed for the segment methods, which take the segment id as well.
+	segmentStreamed := func(customers []models.Customer, err error) func(*gofr.Context, int, models.Filter,
+		func(models.Customer) error) error {
+		return func(ctx *gofr.Context, _ int, filter models.Filter, fn func(models.Customer) error) error {
+			return streamed(customers, err)(ctx, filter, fn)
+		}
+	}
+
+	tests := []struct {
+		desc        string
+		target      string
+		accept      string
+		status      int
+		contentType string
+		body        string
+		mock        *gomock.Call
+	}{
+		{"csv", "/segments/1/customers?fields=id,name,age", csvType, http.StatusOK, csvType,
+			"id,name,age\n1,\"Divya, R\",22\n2,Jay,\n",
+			m.EXPECT().Stream(gomock.Any(), 1, models.Filter{Fields: []string{"id", "name", "age"}}, gomock.Any()).
+				DoAndReturn(segmentStreamed(customers, nil))},
+		{"ndjson", "/segments/1/customers", ndjsonType, http.StatusOK, ndjsonType,
+			`{"id":1,"name":"Divya, R","age":22}` + "\n",
+			m.EXPECT().Stream(gomock.Any(), 1, models.Filter{}, gomock.Any()).DoAndReturn(segmentStreamed(customers[:1], nil))},
+		{"segment not found", "/segments/7/customers", csvType, http.StatusNotFound, "", "",
+			m.EXPECT().Stream(gomock.Any(), 7, models.Filter{}, gomock.Any()).DoAndReturn(segmentStreamed(nil, notFound))},
+		{"not streamed", "/segments/1/customers", "", http.StatusTeapot, "", "", nil},
+		{"other route", "/segments/1", csvType, http.StatusTeapot, "", "", nil},
+		{"invalid id", "/segments/abc/customers", csvType, http.StatusTeapot, "", "", nil},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			r.Header.Set("Accept", tc.accept)
+
+			w := httptest.NewRecorder()
+			h.Stream(gofr.New())(next).ServeHTTP(w, r)
+
+			if w.Code != tc.status {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.status, w.Code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.contentType, ct)
+			}
+
+			if w.Body.String() != tc.body {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/service"
+	"customer/tracing"
+)
+
+// maxAgeBuckets bounds the age histogram so that a single request cannot ask for an arbitrarily large one.
+const maxAgeBuckets = 20
+
+// defaultAgeBuckets are the lower bounds of the age histogram when the buckets parameter is not given.
+var defaultAgeBuckets = []int{18, 25, 35, 45, 55, 65}
+
+// Stats serves /customer/stats.
+type Stats struct {
+	service service.StatsHandlerIn
+}
+
+func NewStats(s service.StatsHandlerIn) Stats {
+	return Stats{service: s}
+}
+
+// Get summarises the customers matching the listing filters. The age histogram is bucketed by the ascending,
+// comma separated lower bounds in the buckets parameter, e.g. buckets=18,30,60.
+func (s Stats) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Stats.Get")
+	defer span.End()
+
+	filter, err := getFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := ageBuckets(ctx.Param("buckets"))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.service.Get(ctx, filter, buckets)
+}
+
+func ageBuckets(param string) ([]int, error) {
+	if param == "" {
+		return defaultAgeBuckets, nil
+	}
+
+	bounds := strings.Split(param, ",")
+	if len(bounds) > maxAgeBuckets {
+		return nil, errors.InvalidParam{Param: []string{"buckets"}}
+	}
+
+	buckets := make([]int, len(bounds))
+
+	for i, b := range bounds {
+		n, err := strconv.Atoi(strings.TrimSpace(b))
+		if err != nil || n <= 0 || (i > 0 && n <= buckets[i-1]) {
+			return nil, errors.InvalidParam{Param: []string{"buckets"}}
+		}
+
+		buckets[i] = n
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStats_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockStatsHandlerIn(ctrl)
+	h := NewStats(m)
+
+	stats := models.Stats{Count: 3}
+
+	tests := []struct {
+		desc     string
+		query    string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"default buckets", "", stats, nil,
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{}, defaultAgeBuckets).Return(stats, nil)}},
+		{"filters and buckets", "?minAge=18&name=Divya&buckets=18,%2030,60", stats, nil,
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{Name: "Divya", MinAge: 18}, []int{18, 30, 60}).Return(stats, nil)}},
+		{"invalid filter", "?maxAge=old", nil, errors.InvalidParam{Param: []string{"maxAge"}}, nil},
+		{"buckets not ascending", "?buckets=30,18", nil, errors.InvalidParam{Param: []string{"buckets"}}, nil},
+		{"invalid bucket", "?buckets=18,x", nil, errors.InvalidParam{Param: []string{"buckets"}}, nil},
+		{"too many buckets", "?buckets=1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21", nil,
+			errors.InvalidParam{Param: []string{"buckets"}}, nil},
+		{"internal server error", "", nil, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(models.Stats{}, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodGet, "http://customer/customer/stats"+tc.query, nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.Get(ctx)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if tc.err == nil && !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+		})
+	}
+}
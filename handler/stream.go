@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/tracing"
+)
+
+const (
+	ndjsonType = "application/x-ndjson"
+	csvType    = "text/csv"
+	// flushEvery is the number of customers written between two flushes of a streamed listing.
+	flushEvery = 100
+)
+
+// format is the encoding of a streamed listing.
+type format int
+
+const (
+	formatJSON format = iota
+	formatNDJSON
+	formatCSV
+)
+
+// streamFunc passes the customers matching a filter to fn as they are read.
+type streamFunc func(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error
+
+// Stream serves GET /customer as a stream when the client accepts application/x-ndjson, one customer per line,
+// or text/csv, one customer per row after a header of the field names, or passes stream=true, as a chunked
+// JSON array in the usual data envelope. Customers are written as they are read, so a listing of any size uses
+// constant memory, and the query is cancelled when the client goes away.
+//
+// gofr handlers cannot write to the response themselves, so streaming is a middleware. It must run after
+// middleware.Auth. Every other request, and streamed ones with invalid parameters, go on to the regular handlers.
+func (h Handler) Stream(app *gofr.Gofr) func(http.Handler) http.Handler {
+	return streaming(app, func(path string) streamFunc {
+		if path != "/customer" {
+			return nil
+		}
+
+		return h.service.Stream
+	})
+}
+
+// streaming streams the GET requests for which route returns a streamFunc, as described for Handler.Stream.
+func streaming(app *gofr.Gofr, route func(path string) streamFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f, ok := streamFormat(r)
+			if !ok || r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			serve := route(r.URL.Path)
+			if serve == nil {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+			// gofr only hands the request context to the handlers it routes, and it holds the tenant, scopes, request
+			// id and span set by the middlewares before this one, as well as the cancellation of the request.
+			ctx.Context = r.Context()
+
+			// An invalid filter is left to the handler, which reports and counts it.
+			filter, err := models.ParseFilter(r.URL.Query())
+			if err == nil {
+				filter.Fields, err = models.ParseFields(ctx.Param("fields"))
+			}
+
+			if err != nil {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			stream(ctx, &streamWriter{w: w, format: f, fields: filter.Fields}, filter, serve)
+		})
+	}
+}
+
+// streamFormat returns the format a request asks to be streamed in, if any.
+func streamFormat(r *http.Request) (format, bool) {
+	accept := r.Header.Get("Accept")
+
+	switch {
+	case strings.Contains(accept, ndjsonType):
+		return formatNDJSON, true
+	case strings.Contains(accept, csvType):
+		return formatCSV, true
+	case r.URL.Query().Get("stream") == "true":
+		return formatJSON, true
+	default:
+		return 0, false
+	}
+}
+
+func stream(ctx *gofr.Context, s *streamWriter, filter models.Filter, serve streamFunc) {
+	ctx, span := tracing.Start(ctx, "handler.Stream")
+	defer span.End()
+
+	err := serve(ctx, filter, func(c models.Customer) error {
+		// A client that went away stops the stream even when the store does not notice it between two rows.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if filter.Fields != nil {
+			return s.write(c.Project(filter.Fields))
+		}
+
+		return s.write(c)
+	})
+
+	// Nothing is left to tell a client that went away.
+	if err != nil && ctx.Err() == nil {
+		ctx.Logger.Errorf("request_id=%v handler.Stream: %v", middleware.RequestID(ctx), err)
+	}
+
+	s.close(err)
+}
+
+// streamWriter writes customers as NDJSON lines, CSV rows, or the elements of a JSON array in gofr's data
+// envelope.
+type streamWriter struct {
+	w       http.ResponseWriter
+	format  format
+	written int
+	// fields are the columns of a CSV stream. Every customer field is written when it is empty.
+	fields []string
+}
+
+func (s *streamWriter) write(v interface{}) error {
+	if s.written == 0 {
+		s.start()
+	}
+
+	var err error
+	if s.format == formatCSV {
+		err = s.writeRow(v)
+	} else {
+		err = s.writeJSON(v)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.written++
+	if s.written%flushEvery == 0 {
+		s.flush()
+	}
+
+	return nil
+}
+
+func (s *streamWriter) writeJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if s.format == formatNDJSON {
+		b = append(b, '\n')
+	} else if s.written > 0 {
+		b = append([]byte(","), b...)
+	}
+
+	_, err = s.w.Write(b)
+
+	return err
+}
+
+// writeRow writes a customer as a CSV row of its fields, in the order of the header.
+func (s *streamWriter) writeRow(v interface{}) error {
+	projected, ok := v.(map[string]interface{})
+	if !ok {
+		projected = v.(models.Customer).Project(s.columns())
+	}
+
+	row := make([]string, 0, len(projected))
+	for _, name := range s.columns() {
+		row = append(row, csvValue(projected[name]))
+	}
+
+	w := csv.NewWriter(s.w)
+	if err := w.Write(row); err != nil {
+		return err
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+func (s *streamWriter) columns() []string {
+	if s.fields != nil {
+		return s.fields
+	}
+
+	return models.CustomerFields
+}
+
+func (s *streamWriter) start() {
+	switch s.format {
+	case formatNDJSON:
+		s.w.Header().Set("Content-Type", ndjsonType)
+		s.w.WriteHeader(http.StatusOK)
+	case formatCSV:
+		s.w.Header().Set("Content-Type", csvType)
+		s.w.WriteHeader(http.StatusOK)
+
+		w := csv.NewWriter(s.w)
+		_ = w.Write(s.columns())
+		w.Flush()
+	default:
+		s.w.Header().Set("Content-Type", "application/json")
+		s.w.WriteHeader(http.StatusOK)
+		_, _ = s.w.Write([]byte(`{"data":[`))
+	}
+}
+
+// close ends the stream. A failure before the first customer is reported with a status code. After it the
+// status is already sent, so the JSON array is left unterminated for the client to notice; NDJSON and CSV
+// streams simply end early.
+func (s *streamWriter) close(err error) {
+	if err != nil {
+		if s.written == 0 {
+			s.w.WriteHeader(streamStatus(err))
+		}
+
+		return
+	}
+
+	if s.written == 0 {
+		s.start()
+	}
+
+	if s.format == formatJSON {
+		_, _ = s.w.Write([]byte("]}"))
+	}
+
+	s.flush()
+}
+
+func (s *streamWriter) flush() {
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// streamStatus is the status of a stream that failed before its first customer.
+func streamStatus(err error) int {
+	if _, ok := err.(errors.EntityNotFound); ok {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// csvValue formats a customer field for a CSV cell. Unset fields are left empty.
+func csvValue(v interface{}) string {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return ""
+	}
+
+	switch v := v.(type) {
+	case *time.Time:
+		return v.Format(time.RFC3339)
+	case models.Attributes:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// streamed hands the customers to the callback of a mocked Stream, then fails with err.
+func streamed(customers []models.Customer, err error) func(*gofr.Context, models.Filter, func(models.Customer) error) error {
+	return func(_ *gofr.Context, _ models.Filter, fn func(models.Customer) error) error {
+		for i := range customers {
+			if err := fn(customers[i]); err != nil {
+				return err
+			}
+		}
+
+		return err
+	}
+}
+
+func TestHandler_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockHandlerIn(ctrl)
+	h := New(m)
+
+	customers := []models.Customer{{ID: 1, Name: "Divya", Age: 22}, {ID: 2, Name: "Jay", Age: 21}}
+	withAttributes := []models.Customer{{ID: 1, Name: "Divya", Attributes: models.Attributes{"plan": "gold"}}}
+	dbError := errors.DB{Err: errors.Error("db error")}
+
+	tests := []struct {
+		desc        string
+		target      string
+		accept      string
+		status      int
+		contentType string
+		body        string
+		mock        *gomock.Call
+	}{
+		{"ndjson", "/customer?minAge=20", ndjsonType, http.StatusOK, ndjsonType,
+			`{"id":1,"name":"Divya","age":22}` + "\n" + `{"id":2,"name":"Jay","age":21}` + "\n",
+			m.EXPECT().Stream(gomock.Any(), models.Filter{MinAge: 20}, gomock.Any()).DoAndReturn(streamed(customers, nil))},
+		{"csv", "/customer", csvType, http.StatusOK, csvType,
+			"id,name,email,phone,dateOfBirth,age,salary,attributes,createdAt,updatedAt,status\n" +
+				"1,Divya,,,,22,,,,,\n2,Jay,,,,21,,,,,\n",
+			m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(streamed(customers, nil))},
+		{"csv attributes", "/customer?fields=id,attributes", csvType, http.StatusOK, csvType,
+			"id,attributes\n" + `1,"{""plan"":""gold""}"` + "\n",
+			m.EXPECT().Stream(gomock.Any(), models.Filter{Fields: []string{"id", "attributes"}}, gomock.Any()).
+				DoAndReturn(streamed(withAttributes, nil))},
+		{"json array", "/customer?stream=true&fields=id,name", "", http.StatusOK, "application/json",
+			`{"data":[{"id":1,"name":"Divya"},{"id":2,"name":"Jay"}]}`,
+			m.EXPECT().Stream(gomock.Any(), models.Filter{Fields: []string{"id", "name"}}, gomock.Any()).
+				DoAndReturn(streamed(customers, nil))},
+		{"no customers", "/customer?stream=true", "", http.StatusOK, "application/json", `{"data":[]}`,
+			m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(streamed(nil, nil))},
+		{"failure before the first customer", "/customer?stream=true", "", http.StatusInternalServerError, "", "",
+			m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(streamed(nil, dbError))},
+		{"failure after the first customer", "/customer?stream=true", "", http.StatusOK, "application/json",
+			`{"data":[{"id":1,"name":"Divya","age":22}`,
+			m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(streamed(customers[:1], dbError))},
+		{"not streamed", "/customer", "", http.StatusTeapot, "", "", nil},
+		{"other route", "/customer/1", ndjsonType, http.StatusTeapot, "", "", nil},
+		{"invalid parameter", "/customer?stream=true&limit=-1", "", http.StatusTeapot, "", "", nil},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			r.Header.Set("Accept", tc.accept)
+
+			w := httptest.NewRecorder()
+			h.Stream(gofr.New())(next).ServeHTTP(w, r)
+
+			if w.Code != tc.status {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.status, w.Code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.contentType, ct)
+			}
+
+			if w.Body.String() != tc.body {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_StreamRequestContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockHandlerIn(ctrl)
+	h := New(m)
+
+	requestCtx, cancel := context.WithCancel(middleware.WithTenant(context.Background(), "zopsmart"))
+	defer cancel()
+
+	var second error
+
+	// The client goes away after the first customer, so the second one must not be written.
+	m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(
+		func(ctx *gofr.Context, _ models.Filter, fn func(models.Customer) error) error {
+			if tenant := middleware.Tenant(ctx); tenant != "zopsmart" {
+				t.Errorf("Expected the stream to be scoped to zopsmart\nGot %q", tenant)
+			}
+
+			if err := fn(models.Customer{ID: 1}); err != nil {
+				return err
+			}
+
+			cancel()
+			second = fn(models.Customer{ID: 2})
+
+			return second
+		})
+
+	r := httptest.NewRequest(http.MethodGet, "/customer", nil).WithContext(requestCtx)
+	r.Header.Set("Accept", ndjsonType)
+
+	w := httptest.NewRecorder()
+	h.Stream(gofr.New())(nil).ServeHTTP(w, r)
+
+	if second != context.Canceled {
+		t.Errorf("Expected the row callback to stop with %v\nGot %v", context.Canceled, second)
+	}
+
+	if body := w.Body.String(); body != `{"id":1}`+"\n" {
+		t.Errorf("Expected only the first customer\nGot %q", body)
+	}
+}
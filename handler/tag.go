@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/service"
+	"customer/tracing"
+)
+
+// Tag serves the tags of a customer under /customer/{id}/tags.
+type Tag struct {
+	service service.TagHandlerIn
+}
+
+func NewTag(t service.TagHandlerIn) Tag {
+	return Tag{service: t}
+}
+
+func (t Tag) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Tag.Get")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+	return t.service.List(ctx, customerID)
+}
+
+// Add tags the customer with the tag in the path and returns all of its tags.
+func (t Tag) Add(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Tag.Add")
+	defer span.End()
+
+	customerID, name, err := tagPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.service.Add(ctx, customerID, name)
+}
+
+func (t Tag) Remove(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Tag.Remove")
+	defer span.End()
+
+	customerID, name, err := tagPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nil, t.service.Remove(ctx, customerID, name)
+}
+
+// tagPath reads the customer id and the tag name from the path.
+func tagPath(ctx *gofr.Context) (customerID int, name string, err error) {
+	customerID, err = pathID(ctx, "id")
+	if err != nil {
+		return 0, "", err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	name = ctx.PathParam("tag")
+	if name == "" {
+		return 0, "", errors.MissingParam{Param: []string{"tag"}}
+	}
+	return customerID, name, nil
+}
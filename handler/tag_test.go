@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"customer/mocks"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTag_Add(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockTagHandlerIn(ctrl)
+	h := NewTag(m)
+
+	tests := []struct {
+		desc     string
+		params   map[string]string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", map[string]string{"id": "1", "tag": "vip"}, []string{"b2b", "vip"}, nil,
+			[]*gomock.Call{m.EXPECT().Add(gomock.Any(), 1, "vip").Return([]string{"b2b", "vip"}, nil)}},
+		{"invalid id", map[string]string{"id": "abc", "tag": "vip"}, nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"missing tag", map[string]string{"id": "1"}, nil, errors.MissingParam{Param: []string{"tag"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPut, "http://customer", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(tc.params)
+
+			resp, err := h.Add(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestTag_Remove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockTagHandlerIn(ctrl)
+	h := NewTag(m)
+
+	notTagged := errors.EntityNotFound{Entity: "tag", ID: "vip"}
+
+	tests := []struct {
+		desc string
+		err  error
+		mock []*gomock.Call
+	}{
+		{"success", nil, []*gomock.Call{m.EXPECT().Remove(gomock.Any(), 1, "vip").Return(nil)}},
+		{"not tagged", notTagged, []*gomock.Call{m.EXPECT().Remove(gomock.Any(), 1, "vip").Return(notTagged)}},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodDelete, "http://customer", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "1", "tag": "vip"})
+
+			if _, err := h.Remove(ctx); !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/service"
+	"customer/tracing"
+)
+
+// Transition serves the status history of a customer under /customer/{id}/transitions.
+type Transition struct {
+	service service.TransitionHandlerIn
+}
+
+func NewTransition(t service.TransitionHandlerIn) Transition {
+	return Transition{service: t}
+}
+
+func (t Transition) Get(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Transition.Get")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+	return t.service.List(ctx, customerID)
+}
+
+// Create moves the customer to the status in "to", giving "reason". The status it leaves is filled in.
+func (t Transition) Create(ctx *gofr.Context) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "handler.Transition.Create")
+	defer span.End()
+
+	customerID, err := pathID(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetCustomerID(ctx, customerID)
+
+	var transition models.Transition
+	if err := ctx.Bind(&transition); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+	if transition.ID != 0 {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+	transition.CustomerID, transition.From, transition.Caller = customerID, "", ""
+	return t.service.Create(ctx, transition)
+}
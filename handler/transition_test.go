@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTransition_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockTransitionHandlerIn(ctrl)
+	h := NewTransition(m)
+
+	history := []models.Transition{{ID: 1, CustomerID: 1, From: models.StatusProspect, To: models.StatusActive, Reason: "signed"}}
+
+	tests := []struct {
+		desc     string
+		id       string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", "1", history, nil, []*gomock.Call{m.EXPECT().List(gomock.Any(), 1).Return(history, nil)}},
+		{"invalid id", "abc", nil, errors.InvalidParam{Param: []string{"id"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodGet, "http://customer", nil))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": tc.id})
+
+			resp, err := h.Get(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestTransition_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockTransitionHandlerIn(ctrl)
+	h := NewTransition(m)
+
+	input := models.Transition{CustomerID: 1, To: models.StatusSuspended, Reason: "unpaid invoices"}
+	created := input
+	created.ID, created.From = 3, models.StatusActive
+
+	tests := []struct {
+		desc     string
+		body     string
+		expected interface{}
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", `{"to":"suspended","reason":"unpaid invoices"}`, created, nil,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), input).Return(created, nil)}},
+		{"from and caller are ignored", `{"from":"closed","to":"suspended","reason":"unpaid invoices","caller":"me"}`,
+			created, nil, []*gomock.Call{m.EXPECT().Create(gomock.Any(), input).Return(created, nil)}},
+		{"id in body", `{"id":3,"to":"suspended","reason":"unpaid invoices"}`, nil,
+			errors.InvalidParam{Param: []string{"id"}}, nil},
+		{"invalid body", `{"to":`, nil, errors.InvalidParam{Param: []string{"body"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := connect(httptest.NewRequest(http.MethodPost, "http://customer", bytes.NewReader([]byte(tc.body))))
+
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx.SetPathParams(map[string]string{"id": "1"})
+
+			resp, err := h.Create(ctx)
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
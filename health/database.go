@@ -0,0 +1,65 @@
+package health
+
+import (
+	"fmt"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/gocql/gocql"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Database pings the database and confirms that the customer table can be read.
+func Database(ctx *gofr.Context) (string, error) {
+	db := ctx.DB()
+	if db == nil || db.DB == nil {
+		return "", errors.Error("database is not configured")
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		return "", err
+	}
+
+	if _, err := db.ExecContext(ctx, "SELECT 1 FROM customer LIMIT 1"); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+// Schema confirms that the latest applied migration is the one the store is written against.
+func Schema(expected int) Check {
+	return func(ctx *gofr.Context) (string, error) {
+		db := ctx.DB()
+		if db == nil || db.DB == nil {
+			return "", errors.Error("database is not configured")
+		}
+
+		var version int
+		if err := db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_version").Scan(&version); err != nil {
+			return "", err
+		}
+
+		detail := fmt.Sprintf("version %d", version)
+		if version != expected {
+			return detail, errors.Error(fmt.Sprintf("expected schema version %d", expected))
+		}
+
+		return detail, nil
+	}
+}
+
+// Cassandra queries the Cassandra cluster that stores the customers when STORE_BACKEND is cassandra.
+func Cassandra(session *gocql.Session) Check {
+	return func(ctx *gofr.Context) (string, error) {
+		return "", session.Query("SELECT release_version FROM system.local").WithContext(ctx).Exec()
+	}
+}
+
+// Mongo pings the MongoDB deployment that stores the customers when STORE_BACKEND is mongo.
+func Mongo(client *mongo.Client) Check {
+	return func(ctx *gofr.Context) (string, error) {
+		return "", client.Ping(ctx, readpref.Primary())
+	}
+}
@@ -0,0 +1,161 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Check reports whether a dependency can serve requests. The returned detail is shown in the report either way.
+type Check func(ctx *gofr.Context) (detail string, err error)
+
+type Component struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status     string               `json:"status"`
+	Draining   bool                 `json:"draining,omitempty"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// Health serves the liveness and readiness probes.
+type Health struct {
+	timeout  time.Duration
+	draining int32
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// New returns a Health that gives every readiness check at most timeout to answer.
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout, checks: map[string]Check{}}
+}
+
+// Absent is the check of a component the service does without. It is always up and states why in the report, so
+// that nobody waits on a component that is not there.
+func Absent(reason string) Check {
+	return func(*gofr.Context) (string, error) {
+		return reason, nil
+	}
+}
+
+// Register adds a check to the readiness report under name.
+func (h *Health) Register(name string, check Check) {
+	h.mu.Lock()
+	h.checks[name] = check
+	h.mu.Unlock()
+}
+
+// Drain makes readiness fail so that no new traffic is routed to the instance.
+func (h *Health) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+func (h *Health) Draining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+// Live reports that the process is up. It does not look at any dependency.
+func (h *Health) Live(ctx *gofr.Context) (interface{}, error) {
+	return Report{Status: StatusUp}, nil
+}
+
+// Ready runs every registered check and fails with 503 when one of them fails or the instance is draining.
+func (h *Health) Ready(ctx *gofr.Context) (interface{}, error) {
+	report := h.Report(ctx)
+	if report.Status == StatusUp {
+		return report, nil
+	}
+
+	return nil, &errors.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       "SERVICE_UNAVAILABLE",
+		Reason:     "the service is not ready",
+		Detail:     report,
+	}
+}
+
+// Report runs the checks concurrently.
+func (h *Health) Report(ctx *gofr.Context) Report {
+	h.mu.RLock()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+
+	h.mu.RUnlock()
+
+	parent := ctx.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	results := make([]Component, len(names))
+
+	var wg sync.WaitGroup
+
+	for i := range checks {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = h.run(ctx, parent, checks[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Draining: h.Draining(), Components: map[string]Component{}}
+	if report.Draining {
+		report.Status = StatusDown
+	}
+
+	for i, name := range names {
+		report.Components[name] = results[i]
+
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (h *Health) run(ctx *gofr.Context, parent context.Context, check Check) Component {
+	c, cancel := context.WithTimeout(parent, h.timeout)
+	defer cancel()
+
+	checkCtx := gofr.NewContext(nil, nil, ctx.Gofr)
+	checkCtx.Context = c
+
+	detail, err := check(checkCtx)
+	if err != nil {
+		return Component{Status: StatusDown, Detail: detail, Error: err.Error()}
+	}
+
+	return Component{Status: StatusUp, Detail: detail}
+}
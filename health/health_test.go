@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func initializeDb(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *gofr.Context) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.Background()
+
+	return db, mock, ctx
+}
+
+func TestHealth_Ready(t *testing.T) {
+	db, mock, ctx := initializeDb(t)
+	defer db.Close()
+
+	version := "SELECT MAX(version) FROM schema_version"
+
+	tests := []struct {
+		desc     string
+		draining bool
+		expected Report
+		mock     []interface{}
+	}{
+		{"ready", false, Report{Status: StatusUp, Components: map[string]Component{
+			"database": {Status: StatusUp},
+			"schema":   {Status: StatusUp, Detail: "version 1"},
+			"outbox":   {Status: StatusUp, Detail: "none"},
+		}}, []interface{}{
+			mock.ExpectPing(),
+			mock.ExpectExec("SELECT 1 FROM customer LIMIT 1").WillReturnResult(sqlmock.NewResult(0, 1)),
+			mock.ExpectQuery(version).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(1)),
+		}},
+		{"database down", false, Report{Status: StatusDown, Components: map[string]Component{
+			"database": {Status: StatusDown, Error: "connection refused"},
+			"schema":   {Status: StatusUp, Detail: "version 1"},
+			"outbox":   {Status: StatusUp, Detail: "none"},
+		}}, []interface{}{
+			mock.ExpectPing().WillReturnError(errors.Error("connection refused")),
+			mock.ExpectQuery(version).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(1)),
+		}},
+		{"schema behind", false, Report{Status: StatusDown, Components: map[string]Component{
+			"database": {Status: StatusUp},
+			"schema":   {Status: StatusDown, Detail: "version 0", Error: "expected schema version 1"},
+			"outbox":   {Status: StatusUp, Detail: "none"},
+		}}, []interface{}{
+			mock.ExpectPing(),
+			mock.ExpectExec("SELECT 1 FROM customer LIMIT 1").WillReturnResult(sqlmock.NewResult(0, 1)),
+			mock.ExpectQuery(version).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(0)),
+		}},
+		{"draining", true, Report{Status: StatusDown, Draining: true, Components: map[string]Component{
+			"database": {Status: StatusUp},
+			"schema":   {Status: StatusUp, Detail: "version 1"},
+			"outbox":   {Status: StatusUp, Detail: "none"},
+		}}, []interface{}{
+			mock.ExpectPing(),
+			mock.ExpectExec("SELECT 1 FROM customer LIMIT 1").WillReturnResult(sqlmock.NewResult(0, 1)),
+			mock.ExpectQuery(version).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(1)),
+		}},
+	}
+
+	mock.MatchExpectationsInOrder(false)
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			h := New(time.Second)
+			h.Register("database", Database)
+			h.Register("schema", Schema(1))
+			h.Register("outbox", Absent("none"))
+
+			if tc.draining {
+				h.Drain()
+			}
+
+			res, err := h.Ready(ctx)
+
+			if tc.expected.Status == StatusUp {
+				if err != nil || !reflect.DeepEqual(res, tc.expected) {
+					t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v, %v", i+1, tc.desc, tc.expected, res, err)
+				}
+
+				return
+			}
+
+			e, ok := err.(*errors.Response)
+			if !ok || e.StatusCode != http.StatusServiceUnavailable || !reflect.DeepEqual(e.Detail, tc.expected) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestHealth_Live(t *testing.T) {
+	h := New(time.Second)
+	h.Register("database", func(*gofr.Context) (string, error) { return "", errors.Error("down") })
+	h.Drain()
+
+	res, err := h.Live(gofr.NewContext(nil, nil, gofr.New()))
+	if err != nil || !reflect.DeepEqual(res, Report{Status: StatusUp}) {
+		t.Errorf("Expected %v\nGot %v, %v", Report{Status: StatusUp}, res, err)
+	}
+}
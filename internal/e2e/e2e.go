@@ -0,0 +1,197 @@
+// Package e2e boots the service for the end-to-end tests. The test binary serves the service from a copy of
+// itself, so that the tests reach it over HTTP like a client and it is shut down like a deployment shuts it
+// down.
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/lifecycle"
+	"customer/middleware"
+)
+
+// Key is the API key of the tenant the end-to-end tests act as. It is granted every scope the service served
+// from the in-memory store needs.
+const Key = "e2e-key"
+
+// serveEnv is set for the copy of the test binary that serves the service.
+const serveEnv = "E2E_SERVE"
+
+// Setup wires the service into app the way main does, and returns the function that starts its background
+// workers, which the end-to-end tests do not start.
+type Setup func(app *gofr.Gofr, manager *lifecycle.Manager) (start func(), err error)
+
+var (
+	serveOnce sync.Once
+	serveURL  string
+	served    *child
+	serveErr  error
+)
+
+// child is the copy of the test binary that serves the service.
+type child struct {
+	cmd *exec.Cmd
+	// exited is closed when the child exits, and err is then what it exited with.
+	exited chan struct{}
+	err    error
+}
+
+// Main is the TestMain of the end-to-end tests. In the copy of the test binary started by URL it serves the
+// service wired by setup until SIGTERM. Otherwise it runs the tests, then stops the service and fails when it
+// does not stop cleanly.
+func Main(m *testing.M, setup Setup) {
+	if os.Getenv(serveEnv) != "" {
+		os.Exit(serve(setup))
+	}
+
+	code := m.Run()
+
+	if err := stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "stopping the service: %v\n", err)
+		code = lifecycle.ExitFailure
+	}
+
+	os.Exit(code)
+}
+
+// serve runs the service until SIGTERM, without its background workers, so tests keep apart by naming their
+// customers after themselves.
+func serve(setup Setup) int {
+	app := gofr.New()
+	manager := lifecycle.New(app.Logger, time.Second)
+
+	if _, err := setup(app, manager); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return lifecycle.ExitFailure
+	}
+
+	return manager.Run(app.Start, syscall.SIGTERM)
+}
+
+// URL returns the base URL of the service, served over HTTP from the in-memory store and without a database. It
+// is started once per test binary, and stopped by Main after the tests.
+func URL(t *testing.T) string {
+	t.Helper()
+
+	serveOnce.Do(func() {
+		for attempt := 0; attempt < 5; attempt++ {
+			if serveURL, served, serveErr = start(); serveErr != errPortTaken {
+				return
+			}
+		}
+	})
+
+	if serveErr != nil {
+		t.Fatalf("failed to start the service: %v", serveErr)
+	}
+
+	return serveURL
+}
+
+// errPortTaken is returned by start when the service could not listen on the port it was given, which another
+// process may take between it being picked and the service listening on it.
+var errPortTaken = errors.New("the service stopped before it answered")
+
+func start() (string, *child, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The environment wins over configs/.env. Without DB_HOST, gofr connects to no database.
+	cmd := exec.Command(exe)
+	cmd.Env = append(os.Environ(), serveEnv+"=1", "STORE_BACKEND=memory", "HTTP_PORT="+strconv.Itoa(port), "DB_HOST=",
+		"MASTER_KEY=", "DRAIN_PERIOD=0s", "API_KEYS="+Key+"=e2e",
+		"API_KEY_SCOPES="+Key+"=customer:salary:read customer:salary:write customer:attributes:admin")
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return "", nil, err
+	}
+
+	c := &child{cmd: cmd, exited: make(chan struct{})}
+
+	go func() {
+		c.err = cmd.Wait()
+		close(c.exited)
+	}()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d", port)
+	if err := waitLive(url, c.exited); err != nil {
+		_ = cmd.Process.Kill()
+		<-c.exited
+
+		return "", nil, err
+	}
+
+	return url, c, nil
+}
+
+// waitLive waits until the service at url answers its liveness probe. The probe carries a request id of its own,
+// which only the service echoes, so that another process listening on the port is not taken for it.
+func waitLive(url string, exited <-chan struct{}) error {
+	id := fmt.Sprintf("e2e-%d-%d", os.Getpid(), time.Now().UnixNano())
+
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		select {
+		case <-exited:
+			return errPortTaken
+		default:
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, url+"/health/live", nil)
+		req.Header.Set(middleware.RequestIDHeader, id)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			continue
+		}
+
+		res.Body.Close()
+
+		if res.StatusCode == http.StatusOK && res.Header.Get(middleware.RequestIDHeader) == id {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%v/health/live did not answer in time", url)
+}
+
+// stop shuts the service down like a deployment does, and fails when it does not stop cleanly.
+func stop() error {
+	if served == nil {
+		return nil
+	}
+
+	if err := served.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return err
+	}
+
+	select {
+	case <-served.exited:
+		return served.err
+	case <-time.After(10 * time.Second):
+		_ = served.cmd.Process.Kill()
+		return errors.New("the service did not stop in time")
+	}
+}
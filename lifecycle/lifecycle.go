@@ -0,0 +1,218 @@
+package lifecycle
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/log"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager runs the background workers of the service and shuts it down in order on a signal:
+// the OnSignal callbacks run, new requests are refused, in-flight requests and workers are waited
+// for until the deadline and finally the OnStop hooks run in reverse order of registration.
+type Manager struct {
+	logger   log.Logger
+	deadline time.Duration
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	// stopping is closed once the shutdown has begun to refuse requests.
+	stopping chan struct{}
+
+	mu       sync.Mutex
+	inFlight int
+	idle     chan struct{}
+	failed   bool
+	onSignal []func()
+	hooks    []hook
+}
+
+// New returns a Manager that gives in-flight requests, workers and hooks together at most deadline to finish.
+func New(logger log.Logger, deadline time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{logger: logger, deadline: deadline, ctx: ctx, cancel: cancel, stopping: make(chan struct{})}
+}
+
+// Go runs worker until the shutdown cancels its context. A worker that returns early with an error fails the exit code.
+func (m *Manager) Go(name string, worker func(ctx context.Context) error) {
+	m.workers.Add(1)
+
+	go func() {
+		defer m.workers.Done()
+
+		if err := worker(m.ctx); err != nil && m.ctx.Err() == nil {
+			m.fail("worker %v stopped: %v", name, err)
+		}
+	}()
+}
+
+// OnSignal registers fn to run as soon as a shutdown signal arrives, while requests are still accepted.
+func (m *Manager) OnSignal(fn func()) {
+	m.mu.Lock()
+	m.onSignal = append(m.onSignal, fn)
+	m.mu.Unlock()
+}
+
+// OnStop registers fn to run once requests and workers are done, e.g. to flush writes or close pools.
+func (m *Manager) OnStop(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+	m.mu.Unlock()
+}
+
+// Track counts the requests being served and refuses new ones with 503 once the shutdown has begun.
+func (m *Manager) Track(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		select {
+		case <-m.stopping:
+			m.mu.Unlock()
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		default:
+		}
+		m.inFlight++
+		m.mu.Unlock()
+
+		defer m.release()
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Run starts serve and blocks until one of sigs arrives or serve returns, then shuts down and
+// returns the exit code. serve returning on its own is a failure.
+func (m *Manager) Run(serve func(), sigs ...os.Signal) int {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	defer signal.Stop(ch)
+
+	served := make(chan struct{})
+
+	go func() {
+		serve()
+		close(served)
+	}()
+
+	select {
+	case sig := <-ch:
+		m.logger.Infof("received %v, shutting down", sig)
+	case <-served:
+		m.fail("server stopped unexpectedly")
+	}
+
+	return m.Shutdown()
+}
+
+// Shutdown stops the service as described on Manager and returns the exit code.
+func (m *Manager) Shutdown() int {
+	m.mu.Lock()
+	onSignal := m.onSignal
+	m.mu.Unlock()
+
+	for _, fn := range onSignal {
+		fn()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.deadline)
+	defer cancel()
+
+	select {
+	case <-m.stop():
+	case <-ctx.Done():
+		m.fail("in-flight requests did not finish within %v", m.deadline)
+	}
+
+	m.cancel()
+
+	workers := make(chan struct{})
+
+	go func() {
+		m.workers.Wait()
+		close(workers)
+	}()
+
+	select {
+	case <-workers:
+	case <-ctx.Done():
+		m.fail("workers did not stop within %v", m.deadline)
+	}
+
+	m.mu.Lock()
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil {
+			m.fail("stopping %v: %v", hooks[i].name, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failed {
+		return ExitFailure
+	}
+
+	return ExitOK
+}
+
+// stop refuses new requests and returns a channel that is closed once the in-flight ones are done.
+func (m *Manager) stop() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	select {
+	case <-m.stopping:
+	default:
+		close(m.stopping)
+	}
+
+	m.idle = make(chan struct{})
+
+	if m.inFlight == 0 {
+		close(m.idle)
+	}
+
+	return m.idle
+}
+
+func (m *Manager) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	if m.inFlight == 0 && m.idle != nil {
+		close(m.idle)
+	}
+}
+
+func (m *Manager) fail(format string, args ...interface{}) {
+	m.logger.Errorf(format, args...)
+
+	m.mu.Lock()
+	m.failed = true
+	m.mu.Unlock()
+}
@@ -0,0 +1,150 @@
+package lifecycle
+
+import (
+	"context"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/log"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// serve starts a test server behind m.Track whose handler holds every request until release is closed.
+func serve(m *Manager, started chan<- struct{}, release <-chan struct{}) *httptest.Server {
+	return httptest.NewServer(m.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})))
+}
+
+func TestManager_Run(t *testing.T) {
+	tests := []struct {
+		desc     string
+		deadline time.Duration
+		hold     time.Duration
+		hookErr  error
+		status   int
+		code     int
+	}{
+		{"in-flight request drains", time.Second, 50 * time.Millisecond, nil, http.StatusOK, ExitOK},
+		{"deadline exceeded", 50 * time.Millisecond, 300 * time.Millisecond, nil, http.StatusOK, ExitFailure},
+		{"hook fails", time.Second, 0, errors.Error("flush failed"), http.StatusOK, ExitFailure},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			m := New(log.NewMockLogger(io.Discard), tc.deadline)
+
+			started, release := make(chan struct{}, 1), make(chan struct{})
+			srv := serve(m, started, release)
+
+			defer srv.Close()
+
+			var order []string
+
+			workerStopped := make(chan struct{})
+
+			m.Go("worker", func(ctx context.Context) error {
+				<-ctx.Done()
+				close(workerStopped)
+
+				return nil
+			})
+			m.OnSignal(func() { order = append(order, "signal") })
+			m.OnStop("database", func(context.Context) error {
+				order = append(order, "database")
+				return nil
+			})
+			m.OnStop("outbox", func(context.Context) error {
+				order = append(order, "outbox")
+				return tc.hookErr
+			})
+
+			status := make(chan int, 1)
+
+			go func() {
+				res, err := http.Get(srv.URL)
+				if err != nil {
+					status <- 0
+					return
+				}
+
+				res.Body.Close()
+				status <- res.StatusCode
+			}()
+
+			<-started
+
+			code, serving := make(chan int, 1), make(chan struct{})
+
+			// Run only calls serve once it listens for the signal.
+			go func() {
+				code <- m.Run(func() {
+					close(serving)
+					select {}
+				}, syscall.SIGTERM)
+			}()
+
+			<-serving
+
+			if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+				t.Fatal(err)
+			}
+
+			// New requests are refused while the first one is still being served.
+			<-m.stopping
+
+			res, err := http.Get(srv.URL)
+			if err != nil || res.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v %v", i+1, tc.desc, http.StatusServiceUnavailable, res, err)
+			} else {
+				res.Body.Close()
+			}
+
+			time.AfterFunc(tc.hold, func() { close(release) })
+
+			if got := <-code; got != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected exit code %v\nGot %v", i+1, tc.desc, tc.code, got)
+			}
+
+			if got := <-status; got != tc.status {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.status, got)
+			}
+
+			select {
+			case <-workerStopped:
+			default:
+				t.Errorf("TEST[%d], failed.\n%s\nExpected the worker to be stopped", i+1, tc.desc)
+			}
+
+			if expected := []string{"signal", "outbox", "database"}; !reflect.DeepEqual(order, expected) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, expected, order)
+			}
+		})
+	}
+}
+
+func TestManager_RunServerStops(t *testing.T) {
+	m := New(log.NewMockLogger(io.Discard), time.Second)
+
+	if code := m.Run(func() {}, syscall.SIGTERM); code != ExitFailure {
+		t.Errorf("Expected exit code %v\nGot %v", ExitFailure, code)
+	}
+}
+
+func TestManager_WorkerFails(t *testing.T) {
+	m := New(log.NewMockLogger(io.Discard), time.Second)
+	m.Go("relay", func(context.Context) error { return errors.Error("connection lost") })
+
+	// The worker fails on its own, before the shutdown cancels it.
+	m.workers.Wait()
+
+	if code := m.Shutdown(); code != ExitFailure {
+		t.Errorf("Expected exit code %v\nGot %v", ExitFailure, code)
+	}
+}
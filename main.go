@@ -1,26 +1,424 @@
 package main
 
 import (
+	"context"
+	"customer/encryption"
+	"customer/gql"
 	"customer/handler"
+	"customer/health"
+	"customer/lifecycle"
+	"customer/metrics"
 	"customer/middleware"
+	"customer/models"
+	"customer/openapi"
+	"customer/rpc"
 	"customer/service"
 	"customer/store"
+	"customer/tracing"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"fmt"
+	"github.com/gocql/gocql"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
 func main() {
 	app := gofr.New()
 
-	app.Server.UseMiddleware(middleware.OauthMiddleware)
-	store := store.New()
-	service := service.New(store)
-	handler := handler.New(service)
-
-	app.GET("/customer", handler.Get)
-	app.GET("/customer/{id}", handler.GetByID)
-	app.POST("/customer", handler.Create)
-	app.PUT("/customer/{id}", handler.Update)
-	app.DELETE("/customer/{id}", handler.Delete)
-	app.PATCH("/customer/{id}", handler.Patch)
-	app.Start()
+	deadline, err := time.ParseDuration(app.Config.GetOrDefault("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil {
+		app.Logger.Errorf("invalid SHUTDOWN_TIMEOUT: %v", err)
+		os.Exit(lifecycle.ExitFailure)
+	}
+
+	manager := lifecycle.New(app.Logger, deadline)
+	manager.OnStop("database", func(context.Context) error {
+		if db := app.DB(); db != nil && db.DB != nil {
+			return db.Close()
+		}
+
+		return nil
+	})
+
+	shutdown, err := tracing.Init(context.Background(), app.Config.GetOrDefault("APP_NAME", "customer"),
+		app.Config.Get("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	if err != nil {
+		app.Logger.Errorf("tracing disabled: %v", err)
+	} else {
+		manager.OnStop("tracing", shutdown)
+	}
+
+	start, err := setup(app, manager)
+	if err != nil {
+		app.Logger.Errorf("%v", err)
+		os.Exit(lifecycle.ExitFailure)
+	}
+
+	start()
+
+	os.Exit(manager.Run(app.Start, syscall.SIGINT, syscall.SIGTERM))
+}
+
+// setup wires the stores, services, handlers and middlewares of the service into app as configured, and
+// returns the function that starts its background workers under manager. The end-to-end tests serve app
+// without starting them.
+func setup(app *gofr.Gofr, manager *lifecycle.Manager) (start func(), err error) {
+	tenants, err := middleware.ParseTenants(app.Config.GetOrDefault("API_KEYS", "divya-zs=default"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid API_KEYS: %v", err)
+	}
+
+	scopes, err := middleware.ParseScopes(app.Config.GetOrDefault("API_KEY_SCOPES",
+		"divya-zs=customer:salary:read customer:salary:write customer:attributes:admin customer:gdpr:admin customer:keys:admin"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid API_KEY_SCOPES: %v", err)
+	}
+
+	app.Server.UseMiddleware(manager.Track)
+	app.Server.UseMiddleware(middleware.Tracing)
+	app.Server.UseMiddleware(middleware.RequestLogger(os.Stdout))
+	app.Server.UseMiddleware(middleware.Auth(tenants, scopes))
+	app.Server.UseMiddleware(middleware.Status)
+
+	probes := health.New(2 * time.Second)
+	// Without a database, e.g. with STORE_BACKEND=memory and no DB_HOST, there is none to be ready.
+	if handler.HasDatabase(app) {
+		probes.Register("database", health.Database)
+		probes.Register("schema", health.Schema(store.SchemaVersion))
+	}
+
+	probes.Register("outbox", health.Absent("none: the service publishes no events, so there is no outbox relay"))
+
+	statsTTL, err := time.ParseDuration(app.Config.GetOrDefault("STATS_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STATS_CACHE_TTL: %v", err)
+	}
+
+	drain, err := time.ParseDuration(app.Config.GetOrDefault("DRAIN_PERIOD", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DRAIN_PERIOD: %v", err)
+	}
+
+	rules, err := models.ParseMergeRules(app.Config.Get("MERGE_RULES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MERGE_RULES: %v", err)
+	}
+
+	threshold, err := strconv.ParseFloat(app.Config.GetOrDefault("DUPLICATE_THRESHOLD", "0.8"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DUPLICATE_THRESHOLD: %v", err)
+	}
+
+	duplicateScan, err := time.ParseDuration(app.Config.GetOrDefault("DUPLICATE_SCAN_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DUPLICATE_SCAN_INTERVAL: %v", err)
+	}
+
+	master, err := encryption.LoadMasterKey(app.Config.Get("MASTER_KEY"), app.Config.Get("MASTER_KEY_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MASTER_KEY: %v", err)
+	}
+
+	// Without a master key, names and salaries are stored in plaintext as they always were.
+	var keyring *store.Keyring
+	if master != nil {
+		keyring = store.NewKeyring(master, time.Minute)
+		probes.Register("keyring", keyring.Check)
+	} else {
+		app.Logger.Warn("MASTER_KEY is not set: customer names and salaries are stored in plaintext")
+	}
+
+	backend := app.Config.GetOrDefault("STORE_BACKEND", "postgres")
+
+	dialect, err := store.ParseDialect(app.Config.GetOrDefault("DB_DIALECT", string(store.Postgres)))
+	if err != nil {
+		return nil, err
+	}
+
+	customerStore, attributeStore, err := openStore(app, manager, probes, keyring, backend, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("customer store: %v", err)
+	}
+
+	reencryptBatch, err := strconv.Atoi(app.Config.GetOrDefault("REENCRYPT_BATCH_SIZE", "500"))
+	if err != nil || reencryptBatch < 1 {
+		return nil, fmt.Errorf("invalid REENCRYPT_BATCH_SIZE: %v", app.Config.Get("REENCRYPT_BATCH_SIZE"))
+	}
+
+	reencryptInterval, err := time.ParseDuration(app.Config.GetOrDefault("REENCRYPT_INTERVAL", "10m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REENCRYPT_INTERVAL: %v", err)
+	}
+
+	customers := metrics.NewStore(customerStore)
+	addresses := store.NewAddress()
+	transitions := store.NewTransition()
+	tags := store.NewTag()
+	segments := store.NewSegment()
+	relationships := store.NewRelationship()
+	gdprStore := store.NewGDPR(keyring)
+	keyStore := store.NewKeys(keyring)
+
+	// Segments and graphs read their customers through the customer service, so it is built first.
+	customerService := metrics.NewService(service.New(customers, attributeStore))
+	addressService := service.NewAddress(addresses, customers)
+	statsService := service.NewStats(customers, attributeStore, statsTTL)
+	probes.Register("stats_cache", statsService.Check)
+	mergeService := service.NewMerge(customers, rules, threshold)
+	transitionService := service.NewTransitions(transitions, customers)
+	tagService := service.NewTag(tags, customers)
+	segmentService := service.NewSegments(segments, customerService)
+	attributeService := service.NewAttributes(attributeStore)
+	relationshipService := service.NewRelationships(relationships, customerService)
+	gdprService := service.NewGDPR(gdprStore, customers, addresses, transitions, tags, relationships)
+	keyService := service.NewKeys(keyStore, reencryptBatch)
+
+	customer := handler.New(customerService)
+	address := handler.NewAddress(addressService)
+	stats := handler.NewStats(statsService)
+	merge := handler.NewMerge(mergeService)
+	transition := handler.NewTransition(transitionService)
+	tag := handler.NewTag(tagService)
+	segment := handler.NewSegment(segmentService)
+	attribute := handler.NewAttribute(attributeService)
+	relationship := handler.NewRelationship(relationshipService)
+	gdpr := handler.NewGDPR(gdprService)
+	keys := handler.NewKeys(keyService)
+
+	for _, status := range models.Statuses {
+		transitionService.OnEnter(status, metrics.CountTransition)
+	}
+
+	// The streamed listing writes to the response itself, so it is served by a middleware behind Auth.
+	app.Server.UseMiddleware(customer.Stream(app))
+	if handler.HasDatabase(app) && backend == "postgres" && dialect == store.Postgres {
+		app.Server.UseMiddleware(segment.Stream(app))
+	}
+
+	// Addresses, transitions, tags, relationships, segments, erasures and data keys are kept in the SQL database
+	// next to the customers and joined to them, so they are only served when the postgres store keeps the
+	// customers on Postgres.
+	sqlOnly := handler.WithDatabase(app)
+
+	switch {
+	case backend != "postgres":
+		sqlOnly = handler.Unsupported("BACKEND_NOT_SUPPORTED", "this route is only served by the postgres store, not "+backend)
+	case dialect != store.Postgres:
+		sqlOnly = handler.Unsupported("DIALECT_NOT_SUPPORTED", "this route is only served from Postgres, not "+string(dialect))
+	}
+
+	// Statistics and merges are written for Postgres only, see store.Dialect, so they are refused for the customers
+	// kept in MySQL.
+	postgresOnly := func(h gofr.Handler) gofr.Handler { return h }
+	if backend == "postgres" && dialect != store.Postgres {
+		postgresOnly = sqlOnly
+	}
+
+	app.GET("/customer", customer.Get)
+	// Registered before /customer/{id} so that "stats" and "duplicates" are not taken for an id.
+	app.GET("/customer/stats", postgresOnly(stats.Get))
+	app.GET("/customer/duplicates", merge.Duplicates)
+	app.GET("/customer/{id}", customer.GetByID)
+	app.POST("/customer", customer.Create)
+	app.PUT("/customer/{id}", customer.Update)
+	app.DELETE("/customer/{id}", customer.Delete)
+	app.PATCH("/customer/{id}", customer.Patch)
+	app.PUT("/customer/by-name/{name}", customer.Upsert)
+	app.POST("/customer/merge", postgresOnly(merge.Merge))
+
+	app.GET("/customer/{id}/addresses", sqlOnly(address.Get))
+	app.GET("/customer/{id}/addresses/{addressId}", sqlOnly(address.GetByID))
+	app.POST("/customer/{id}/addresses", sqlOnly(address.Create))
+	app.PUT("/customer/{id}/addresses/{addressId}", sqlOnly(address.Update))
+	app.DELETE("/customer/{id}/addresses/{addressId}", sqlOnly(address.Delete))
+
+	app.GET("/customer/{id}/transitions", sqlOnly(transition.Get))
+	app.POST("/customer/{id}/transitions", sqlOnly(transition.Create))
+
+	app.GET("/customer/{id}/tags", sqlOnly(tag.Get))
+	app.PUT("/customer/{id}/tags/{tag}", sqlOnly(tag.Add))
+	app.DELETE("/customer/{id}/tags/{tag}", sqlOnly(tag.Remove))
+
+	app.GET("/customer/{id}/relationships", sqlOnly(relationship.Get))
+	app.POST("/customer/{id}/relationships", sqlOnly(relationship.Create))
+	app.GET("/customer/{id}/graph", sqlOnly(relationship.Graph))
+
+	app.GET("/customer/{id}/gdpr-export", sqlOnly(gdpr.Export))
+	app.POST("/customer/{id}/erase", sqlOnly(gdpr.Erase))
+	app.GET("/erasures", sqlOnly(gdpr.Erasures))
+
+	app.POST("/keys/rotate", sqlOnly(keys.Rotate))
+
+	app.GET("/segments", sqlOnly(segment.Get))
+	app.POST("/segments", sqlOnly(segment.Create))
+	app.GET("/segments/{id}", sqlOnly(segment.GetByID))
+	app.PUT("/segments/{id}", sqlOnly(segment.Update))
+	app.DELETE("/segments/{id}", sqlOnly(segment.Delete))
+	app.GET("/segments/{id}/customers", sqlOnly(segment.Customers))
+
+	app.GET("/attributes", attribute.Get)
+	app.POST("/attributes", attribute.Create)
+	app.DELETE("/attributes/{name}", attribute.Delete)
+
+	graphql := gql.New(customerService)
+	app.POST("/graphql", graphql.Serve)
+
+	app.GET("/openapi.json", openapi.Serve)
+	app.GET("/docs", openapi.UI)
+
+	app.GET("/health/live", probes.Live)
+	app.GET("/health/ready", probes.Ready)
+
+	manager.OnSignal(func() {
+		probes.Drain()
+		time.Sleep(drain)
+	})
+
+	return func() {
+		gauges := metrics.NewGauges(app, customers, tenants.Names(), time.Minute)
+		manager.Go("gauges", func(ctx context.Context) error {
+			gauges.Run(ctx)
+			return nil
+		})
+
+		manager.Go("duplicates", func(ctx context.Context) error {
+			mergeService.Run(ctx, app, tenants.Names(), duplicateScan)
+			return nil
+		})
+
+		manager.Go("reencrypt", func(ctx context.Context) error {
+			keyService.Run(ctx, app, tenants.Names(), reencryptInterval)
+			return nil
+		})
+
+		manager.Go("grpc", func(ctx context.Context) error {
+			return rpc.ListenAndServe(ctx, app, customerService, tenants, scopes, app.Config.GetOrDefault("GRPC_PORT", "9090"))
+		})
+	}, nil
+}
+
+// openStore returns the customer store selected by STORE_BACKEND, and the store of the attribute schema every
+// customer write is validated against. postgres, the default, also serves YugabyteDB through its
+// Postgres-compatible YSQL API. memory keeps the customers and their attribute schema in memory, so that they
+// are served without a database and lost on restart. mongo keeps the customers in the MONGO_DATABASE database
+// at MONGO_URI. Everything else stays in the SQL database of dialect, and only the postgres store on Postgres
+// encrypts customers. On MySQL it keeps the customers and their attribute schema only, as store.Dialect
+// describes. cassandra keeps the customers in the CASSANDRA_KEYSPACE keyspace of the cluster at CASSANDRA_HOSTS.
+// The mongo and cassandra stores keep the attribute schema in the SQL database, so they need DB_HOST.
+func openStore(app *gofr.Gofr, manager *lifecycle.Manager, probes *health.Health, keys *store.Keyring,
+	backend string, dialect store.Dialect) (store.ServiceIn, store.AttributeServiceIn, error) {
+	if backend != "postgres" && keys != nil {
+		return nil, nil, fmt.Errorf("MASTER_KEY is set, but the %v store does not encrypt customers", backend)
+	}
+
+	switch backend {
+	case "postgres":
+		if dialect == store.MySQL {
+			if keys != nil {
+				return nil, nil, fmt.Errorf("MASTER_KEY is set, but customers are only encrypted on Postgres")
+			}
+
+			return store.NewMySQL(), store.NewMySQLAttribute(), nil
+		}
+
+		return store.New(keys), store.NewAttribute(), nil
+	case "memory":
+		app.Logger.Warn("STORE_BACKEND is memory: customers are lost when the service stops")
+
+		customers := store.NewMemory()
+
+		return customers, customers.Attributes(), nil
+	case "mongo", "cassandra":
+		// The attribute schema stays in the SQL database.
+		if !handler.HasDatabase(app) {
+			return nil, nil, fmt.Errorf("STORE_BACKEND is %v, but DB_HOST is not set", backend)
+		}
+
+		attributes := store.AttributeServiceIn(store.NewAttribute())
+		if dialect == store.MySQL {
+			attributes = store.NewMySQLAttribute()
+		}
+
+		if backend == "cassandra" {
+			customers, err := openCassandra(app, manager, probes)
+			return customers, attributes, err
+		}
+
+		customers, err := openMongo(app, manager, probes)
+		return customers, attributes, err
+	}
+
+	return nil, nil, fmt.Errorf("unknown STORE_BACKEND %q: use postgres, memory, mongo or cassandra", backend)
+}
+
+// openMongo connects to MONGO_URI and creates the indexes of the customer collection.
+func openMongo(app *gofr.Gofr, manager *lifecycle.Manager, probes *health.Health) (store.ServiceIn, error) {
+	uri := app.Config.Get("MONGO_URI")
+	if uri == "" {
+		return nil, fmt.Errorf("STORE_BACKEND is mongo, but MONGO_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	manager.OnStop("mongo", client.Disconnect)
+
+	s := store.NewMongo(client.Database(app.Config.GetOrDefault("MONGO_DATABASE", "customer")))
+	if err := s.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	probes.Register("mongo", health.Mongo(client))
+
+	return s, nil
+}
+
+// openCassandra connects to the comma-separated CASSANDRA_HOSTS and creates the tables of the customers in
+// CASSANDRA_KEYSPACE, which must exist.
+func openCassandra(app *gofr.Gofr, manager *lifecycle.Manager, probes *health.Health) (store.ServiceIn, error) {
+	hosts := app.Config.Get("CASSANDRA_HOSTS")
+	if hosts == "" {
+		return nil, fmt.Errorf("STORE_BACKEND is cassandra, but CASSANDRA_HOSTS is not set")
+	}
+
+	cluster := gocql.NewCluster(strings.Split(hosts, ",")...)
+	cluster.Keyspace = app.Config.GetOrDefault("CASSANDRA_KEYSPACE", "customer")
+	cluster.Consistency = gocql.Quorum
+	cluster.Timeout = 10 * time.Second
+
+	if user := app.Config.Get("CASSANDRA_USER"); user != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: user, Password: app.Config.Get("CASSANDRA_PASSWORD")}
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, err
+	}
+
+	manager.OnStop("cassandra", func(context.Context) error {
+		session.Close()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s := store.NewCassandra(session)
+	if err := s.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	probes.Register("cassandra", health.Cassandra(session))
+
+	return s, nil
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"customer/lifecycle"
+	"customer/openapi"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/base64"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRoutesDocumented fails when a route is registered in main.go without a matching OpenAPI operation.
+func TestRoutesDocumented(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "main.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse main.go: %v", err)
+	}
+
+	spec := openapi.Document()
+	methods := map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+	routes := 0
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !methods[sel.Sel.Name] {
+			return true
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+
+		path, _ := strconv.Unquote(lit.Value)
+		routes++
+
+		if _, ok := spec.Paths[path][strings.ToLower(sel.Sel.Name)]; !ok {
+			t.Errorf("route %v %v has no entry in the OpenAPI document", sel.Sel.Name, path)
+		}
+
+		return true
+	})
+
+	if routes == 0 {
+		t.Errorf("no routes found in main.go")
+	}
+}
+
+// TestSetupInvalidDurations fails when a malformed duration is only logged instead of stopping the start.
+func TestSetupInvalidDurations(t *testing.T) {
+	for _, key := range []string{"STATS_CACHE_TTL", "DRAIN_PERIOD"} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv("STORE_BACKEND", "memory")
+			t.Setenv("DB_HOST", "")
+			t.Setenv("MASTER_KEY", "")
+			t.Setenv(key, "soon")
+
+			app := gofr.New()
+
+			_, err := setup(app, lifecycle.New(app.Logger, time.Second))
+			if err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("Expected setup to fail on an invalid %v\nGot %v", key, err)
+			}
+		})
+	}
+}
+
+// TestSetupDialect fails for a DB_DIALECT the SQL stores are not written for, and for encryption on MySQL.
+func TestSetupDialect(t *testing.T) {
+	tests := []struct {
+		desc      string
+		dialect   string
+		masterKey string
+		expected  string
+	}{
+		{"unknown dialect", "sqlite", "", "DB_DIALECT"},
+		{"encryption on mysql", "mysql", base64.StdEncoding.EncodeToString(make([]byte, 32)), "MASTER_KEY"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Setenv("STORE_BACKEND", "postgres")
+			t.Setenv("DB_DIALECT", tc.dialect)
+			t.Setenv("DB_HOST", "")
+			t.Setenv("MASTER_KEY", tc.masterKey)
+
+			app := gofr.New()
+
+			_, err := setup(app, lifecycle.New(app.Logger, time.Second))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected setup to fail on %v\nGot %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// TestSetupUnknownBackend fails when a backend that has no store is accepted as STORE_BACKEND.
+func TestSetupUnknownBackend(t *testing.T) {
+	t.Setenv("STORE_BACKEND", "redis")
+	t.Setenv("MASTER_KEY", "")
+
+	app := gofr.New()
+
+	_, err := setup(app, lifecycle.New(app.Logger, time.Second))
+	if err == nil || !strings.Contains(err.Error(), `unknown STORE_BACKEND "redis"`) {
+		t.Errorf("Expected setup to refuse STORE_BACKEND redis\nGot %v", err)
+	}
+}
+
+// TestSetupWithoutDatabase fails at startup for the backends that keep the attribute schema in the SQL database
+// when there is none, instead of failing every customer write.
+func TestSetupWithoutDatabase(t *testing.T) {
+	for _, backend := range []string{"mongo", "cassandra"} {
+		t.Run(backend, func(t *testing.T) {
+			t.Setenv("STORE_BACKEND", backend)
+			t.Setenv("DB_HOST", "")
+			t.Setenv("MASTER_KEY", "")
+
+			app := gofr.New()
+
+			_, err := setup(app, lifecycle.New(app.Logger, time.Second))
+			if expected := "STORE_BACKEND is " + backend + ", but DB_HOST is not set"; err == nil ||
+				!strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected setup to fail with %q\nGot %v", expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/store"
+)
+
+// Gauges periodically refreshes the number of customers of all the tenants.
+type Gauges struct {
+	app      *gofr.Gofr
+	store    store.ServiceIn
+	tenants  []string
+	interval time.Duration
+}
+
+func NewGauges(app *gofr.Gofr, s store.ServiceIn, tenants []string, interval time.Duration) *Gauges {
+	return &Gauges{app: app, store: s, tenants: tenants, interval: interval}
+}
+
+// Run refreshes the gauges every interval until ctx is cancelled.
+func (g *Gauges) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.Refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh counts the customers once, in total and in every status. The store only counts within a tenant,
+// so every tenant is counted on its own.
+func (g *Gauges) Refresh(ctx context.Context) {
+	counts := map[string]int{}
+
+	for _, tenant := range g.tenants {
+		c := gofr.NewContext(nil, nil, g.app)
+		c.Context = middleware.WithTenant(ctx, tenant)
+
+		for _, status := range append([]models.Status{""}, models.Statuses...) {
+			count, err := g.store.Count(c, models.Filter{Status: status})
+			if err != nil {
+				g.app.Logger.Errorf("refreshing customer gauges of tenant %v: %v", tenant, err)
+				return
+			}
+
+			counts[state(status)] += count
+		}
+	}
+
+	for state, count := range counts {
+		customers.WithLabelValues(state).Set(float64(count))
+	}
+}
+
+// state is the gauge label of the customers in status, or of all of them for no status.
+func state(status models.Status) string {
+	if status == "" {
+		return "total"
+	}
+
+	return string(status)
+}
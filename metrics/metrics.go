@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The collectors are registered on the default registry, which gofr exposes at /metrics.
+var (
+	mutations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "customer_mutations_total",
+		Help: "Customers created, updated, patched and deleted, by API key.",
+	}, []string{"operation", "api_key"})
+
+	validationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "customer_validation_failures_total",
+		Help: "Requests rejected because of an invalid or missing field, by field.",
+	}, []string{"field"})
+
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "customer_store_query_duration_seconds",
+		Help:    "Latency of the store methods.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "result"})
+
+	customers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "customers",
+		Help: "Number of customers, in total and by status, refreshed periodically.",
+	}, []string{"state"})
+
+	transitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "customer_transitions_total",
+		Help: "Changes of customer status, by the status left and the status entered.",
+	}, []string{"from", "to"})
+)
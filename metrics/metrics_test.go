@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"testing"
+	"time"
+)
+
+func sampleCount(method, result string) uint64 {
+	var m dto.Metric
+	_ = queryDuration.WithLabelValues(method, result).(prometheus.Metric).Write(&m)
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestStoreMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewStore(m)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.Background()
+
+	m.EXPECT().Get(gomock.Any(), models.Filter{}).Return(nil, nil)
+	m.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{}, errors.DB{Err: errors.Error("db error")})
+
+	before := [2]uint64{sampleCount("Get", "ok"), sampleCount("GetByID", "error")}
+
+	_, _ = s.Get(ctx, models.Filter{})
+	_, _ = s.GetByID(ctx, 1)
+
+	after := [2]uint64{sampleCount("Get", "ok"), sampleCount("GetByID", "error")}
+
+	if after[0] != before[0]+1 || after[1] != before[1]+1 {
+		t.Errorf("Expected one observation per call\nGot %v -> %v", before, after)
+	}
+}
+
+func TestServiceMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockHandlerIn(ctrl)
+	s := NewService(m)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.Background()
+
+	tests := []struct {
+		desc    string
+		call    func()
+		counter prometheus.Counter
+		mock    *gomock.Call
+	}{
+		{"create", func() { _, _ = s.Create(ctx, models.Customer{Name: "Divya"}) }, mutations.WithLabelValues("create", ""),
+			m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{Name: "Divya"}, nil)},
+		{"update", func() { _, _ = s.Update(ctx, models.Customer{ID: 1}) }, mutations.WithLabelValues("update", ""),
+			m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(models.Customer{ID: 1}, nil)},
+		{"patch", func() { _, _ = s.Patch(ctx, 1, models.Customer{Age: 3}) }, mutations.WithLabelValues("patch", ""),
+			m.EXPECT().Patch(gomock.Any(), 1, gomock.Any()).Return(models.Customer{ID: 1}, nil)},
+		{"delete", func() { _ = s.Delete(ctx, 1) }, mutations.WithLabelValues("delete", ""),
+			m.EXPECT().Delete(gomock.Any(), 1).Return(nil)},
+		{"validation failure", func() { _, _ = s.Create(ctx, models.Customer{}) }, validationFailures.WithLabelValues("name"),
+			m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.InvalidParam{Param: []string{"name"}})},
+		{"validation failure in a handler", func() { CountValidation(errors.InvalidParam{Param: []string{"fields"}}) },
+			validationFailures.WithLabelValues("fields"), nil},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			before := testutil.ToFloat64(tc.counter)
+			tc.call()
+
+			if after := testutil.ToFloat64(tc.counter); after != before+1 {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, before+1, after)
+			}
+		})
+	}
+}
+
+func TestGauges_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	// Every tenant has one active customer, and zopsmart also has a prospect.
+	count := func(ctx *gofr.Context, f models.Filter) (int, error) {
+		switch f.Status {
+		case "":
+			return map[string]int{"acme": 1, "zopsmart": 2}[middleware.Tenant(ctx)], nil
+		case models.StatusActive:
+			return 1, nil
+		case models.StatusProspect:
+			return map[string]int{"zopsmart": 1}[middleware.Tenant(ctx)], nil
+		}
+
+		return 0, nil
+	}
+	m.EXPECT().Count(gomock.Any(), gomock.Any()).DoAndReturn(count).Times(10)
+
+	NewGauges(gofr.New(), m, []string{"acme", "zopsmart"}, time.Minute).Refresh(context.Background())
+
+	expected := map[string]float64{"total": 3, "prospect": 1, "active": 2, "suspended": 0, "closed": 0}
+	for state, count := range expected {
+		if res := testutil.ToFloat64(customers.WithLabelValues(state)); res != count {
+			t.Errorf("%v: Expected %v\nGot %v", state, count, res)
+		}
+	}
+}
+
+func TestCountTransition(t *testing.T) {
+	before := testutil.ToFloat64(transitions.WithLabelValues("prospect", "active"))
+
+	CountTransition(nil, models.Transition{From: models.StatusProspect, To: models.StatusActive})
+
+	if after := testutil.ToFloat64(transitions.WithLabelValues("prospect", "active")); after != before+1 {
+		t.Errorf("Expected %v\nGot %v", before+1, after)
+	}
+}
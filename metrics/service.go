@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/service"
+)
+
+type serviceMetrics struct {
+	next service.HandlerIn
+}
+
+// NewService counts the mutations and validation failures of next.
+func NewService(next service.HandlerIn) service.HandlerIn {
+	return serviceMetrics{next: next}
+}
+
+func (s serviceMetrics) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	res, err := s.next.Get(ctx, filter)
+	CountValidation(err)
+
+	return res, err
+}
+
+func (s serviceMetrics) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	err := s.next.Stream(ctx, filter, fn)
+	CountValidation(err)
+
+	return err
+}
+
+func (s serviceMetrics) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	res, err := s.next.GetByID(ctx, id, fields...)
+	CountValidation(err)
+
+	return res, err
+}
+
+func (s serviceMetrics) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	return s.next.GetByIDs(ctx, ids)
+}
+
+func (s serviceMetrics) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	res, err := s.next.Create(ctx, customer)
+	countMutation(ctx, "create", err)
+
+	return res, err
+}
+
+// Upsert is counted as the create or the update it turned out to be.
+func (s serviceMetrics) Upsert(ctx *gofr.Context, customer models.Customer) (models.Customer, bool, error) {
+	res, created, err := s.next.Upsert(ctx, customer)
+
+	operation := "update"
+	if created {
+		operation = "create"
+	}
+
+	countMutation(ctx, operation, err)
+
+	return res, created, err
+}
+
+func (s serviceMetrics) Update(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	res, err := s.next.Update(ctx, customer)
+	countMutation(ctx, "update", err)
+
+	return res, err
+}
+
+func (s serviceMetrics) Delete(ctx *gofr.Context, id int) error {
+	err := s.next.Delete(ctx, id)
+	countMutation(ctx, "delete", err)
+
+	return err
+}
+
+func (s serviceMetrics) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	res, err := s.next.Patch(ctx, id, customer)
+	countMutation(ctx, "patch", err)
+
+	return res, err
+}
+
+func countMutation(ctx *gofr.Context, operation string, err error) {
+	if err != nil {
+		CountValidation(err)
+		return
+	}
+
+	mutations.WithLabelValues(operation, middleware.Caller(ctx)).Inc()
+}
+
+// CountValidation counts the fields of err when it reports an invalid or missing parameter. The handlers call it
+// for the parameters they parse themselves, which never reach the service.
+func CountValidation(err error) {
+	var fields []string
+
+	switch e := err.(type) {
+	case errors.InvalidParam:
+		fields = e.Param
+	case errors.MissingParam:
+		fields = e.Param
+	}
+
+	for _, f := range fields {
+		validationFailures.WithLabelValues(f).Inc()
+	}
+}
+
+// CountTransition counts a change of customer status. It is registered as a hook on entering every status.
+func CountTransition(_ *gofr.Context, t models.Transition) {
+	transitions.WithLabelValues(string(t.From), string(t.To)).Inc()
+}
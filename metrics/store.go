@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/store"
+)
+
+type storeMetrics struct {
+	next store.ServiceIn
+}
+
+// NewStore records the latency of every call made to next.
+func NewStore(next store.ServiceIn) store.ServiceIn {
+	return storeMetrics{next: next}
+}
+
+func (s storeMetrics) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.Get(ctx, filter)
+	observe("Get", start)(err)
+
+	return res, err
+}
+
+// Stream is observed until the last customer is handed to fn, so its duration includes the time spent in fn.
+func (s storeMetrics) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	start := time.Now()
+	err := s.next.Stream(ctx, filter, fn)
+	observe("Stream", start)(err)
+
+	return err
+}
+
+func (s storeMetrics) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.GetByID(ctx, id, fields...)
+	observe("GetByID", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.GetByIDs(ctx, ids)
+	observe("GetByIDs", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) Count(ctx *gofr.Context, filter models.Filter) (int, error) {
+	start := time.Now()
+	res, err := s.next.Count(ctx, filter)
+	observe("Count", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.Create(ctx, customer)
+	observe("Create", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) Upsert(ctx *gofr.Context, customer models.Customer) (models.Customer, bool, error) {
+	start := time.Now()
+	res, created, err := s.next.Upsert(ctx, customer)
+	observe("Upsert", start)(err)
+
+	return res, created, err
+}
+
+func (s storeMetrics) Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.Update(ctx, id, customer)
+	observe("Update", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) Delete(ctx *gofr.Context, id int) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, id)
+	observe("Delete", start)(err)
+
+	return err
+}
+
+func (s storeMetrics) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.Patch(ctx, id, customer)
+	observe("Patch", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) Merge(ctx *gofr.Context, survivorID int, loserIDs []int,
+	merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error) {
+	start := time.Now()
+	res, err := s.next.Merge(ctx, survivorID, loserIDs, merge)
+	observe("Merge", start)(err)
+
+	return res, err
+}
+
+func (s storeMetrics) Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	start := time.Now()
+	res, err := s.next.Stats(ctx, filter, buckets)
+	observe("Stats", start)(err)
+
+	return res, err
+}
+
+// observe returns a func that records the time elapsed since start for method, labelled by outcome.
+func observe(method string, start time.Time) func(err error) {
+	return func(err error) {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+
+		queryDuration.WithLabelValues(method, result).Observe(time.Since(start).Seconds())
+	}
+}
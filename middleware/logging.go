@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RequestIDHeader carries the correlation id of a request, both inbound and outbound.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type ctxKey int
+
+const (
+	requestInfoKey ctxKey = iota
+	tenantKey
+	scopesKey
+	statusKey
+)
+
+// requestInfo is shared by the middlewares of a request, so that inner ones can report back to the logger.
+type requestInfo struct {
+	id     string
+	caller string
+}
+
+type logEntry struct {
+	Time       string  `json:"time"`
+	Level      string  `json:"level"`
+	RequestID  string  `json:"request_id"`
+	Method     string  `json:"method"`
+	Route      string  `json:"route"`
+	Status     int     `json:"status"`
+	LatencyMS  float64 `json:"latency_ms"`
+	Caller     string  `json:"caller,omitempty"`
+	CustomerID string  `json:"customer_id,omitempty"`
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Flush lets streamed responses through the recorder.
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// RequestID returns the correlation id of the request ctx was derived from.
+func RequestID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.id
+	}
+
+	return ""
+}
+
+// Caller returns the identity of the API key that authenticated the request ctx was derived from.
+func Caller(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.caller
+	}
+
+	return ""
+}
+
+// WithRequestID returns a copy of ctx for a request that does not pass RequestLogger, e.g. an RPC, so that
+// RequestID and Caller report on it. An invalid id is replaced by a new one.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	return context.WithValue(ctx, requestInfoKey, &requestInfo{id: id})
+}
+
+// RequestLogger assigns or propagates the X-Request-ID of every request and writes one JSON line per
+// request to out. Request and response bodies are never logged.
+func RequestLogger(out io.Writer) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+
+	enc := json.NewEncoder(out)
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+
+			info := &requestInfo{id: id}
+			r = r.WithContext(context.WithValue(r.Context(), requestInfoKey, info))
+			w.Header().Set(RequestIDHeader, id)
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(rec, r)
+
+			route, customerID := routeOf(r)
+
+			mu.Lock()
+			_ = enc.Encode(logEntry{
+				Time:       start.UTC().Format(time.RFC3339Nano),
+				Level:      "INFO",
+				RequestID:  id,
+				Method:     r.Method,
+				Route:      route,
+				Status:     rec.status,
+				LatencyMS:  float64(time.Since(start).Microseconds()) / 1000,
+				Caller:     info.caller,
+				CustomerID: customerID,
+			})
+			mu.Unlock()
+		})
+	}
+}
+
+// setCaller records the identity of the authenticated caller for the request logger.
+func setCaller(ctx context.Context, caller string) {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		info.caller = caller
+	}
+}
+
+// unmatched is the route logged for a request the router matched to no route. Its path is not logged, as it
+// may hold personal data, e.g. the name in /customer/by-name/{name}.
+const unmatched = "unmatched"
+
+// routeOf returns the route template and the customer id of the request. The id of other resources, e.g.
+// segments, and an id that is not a number are no customer id.
+func routeOf(r *http.Request) (route, customerID string) {
+	current := mux.CurrentRoute(r)
+	if current == nil {
+		return unmatched, ""
+	}
+
+	tpl, err := current.GetPathTemplate()
+	if err != nil {
+		return unmatched, ""
+	}
+
+	id := mux.Vars(r)["id"]
+	if _, err := strconv.Atoi(id); err != nil || !strings.HasPrefix(tpl, "/customer/") {
+		return tpl, ""
+	}
+
+	return tpl, id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
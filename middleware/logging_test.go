@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		desc      string
+		path      string
+		apiKey    string
+		requestID string
+		status    int
+		route     string
+		caller    string
+		customer  string
+	}{
+		{"propagates the request id", "/customer/7", "divya-zs", "abc-123", http.StatusOK, "/customer/{id}", keyIdentity("divya-zs"), "7"},
+		{"segment id is no customer id", "/segments/3/customers", "divya-zs", "", http.StatusOK, "/segments/{id}/customers",
+			keyIdentity("divya-zs"), ""},
+		{"customer name is not logged", "/customer/by-name/Asha", "divya-zs", "", http.StatusOK, "/customer/by-name/{name}",
+			keyIdentity("divya-zs"), ""},
+		{"id that is no number is no customer id", "/customer/x1", "divya-zs", "", http.StatusOK, "/customer/{id}",
+			keyIdentity("divya-zs"), ""},
+		{"unauthorized", "/customer", "wrong", "", http.StatusUnauthorized, "/customer", "", ""},
+		{"invalid request id is replaced", "/customer", "divya-zs", "bad id\n", http.StatusOK, "/customer", keyIdentity("divya-zs"), ""},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out bytes.Buffer
+
+			var seen string
+
+			inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestID(r.Context())
+			})
+
+			h := mux.NewRouter()
+			h.Use(RequestLogger(&out), Auth(Tenants{"divya-zs": "default"}, Scopes{}))
+
+			for _, route := range []string{"/customer", "/customer/{id}", "/customer/by-name/{name}", "/segments/{id}/customers"} {
+				h.Handle(route, inner)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.Header.Set("x-api-key", tc.apiKey)
+
+			if tc.requestID != "" {
+				r.Header.Set(RequestIDHeader, tc.requestID)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			var entry logEntry
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("TEST[%d], failed.\n%s\ninvalid log line %q", i+1, tc.desc, out.String())
+			}
+
+			id := w.Header().Get(RequestIDHeader)
+			if tc.requestID == "abc-123" && id != tc.requestID {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.requestID, id)
+			}
+
+			if id == "" || entry.RequestID != id || (tc.status == http.StatusOK && seen != id) {
+				t.Errorf("TEST[%d], failed.\n%s\nrequest id mismatch: header %q, log %q, context %q", i+1, tc.desc, id, entry.RequestID, seen)
+			}
+
+			expected := logEntry{Level: "INFO", RequestID: id, Method: http.MethodGet, Route: tc.route, Status: tc.status,
+				Caller: tc.caller, CustomerID: tc.customer}
+			entry.Time, entry.LatencyMS = "", 0
+
+			if entry != expected {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %+v\nGot %+v", i+1, tc.desc, expected, entry)
+			}
+
+			if bytes.Contains(out.Bytes(), []byte(tc.apiKey)) && tc.apiKey != "" {
+				t.Errorf("TEST[%d], failed.\n%s\nAPI key leaked into the log: %v", i+1, tc.desc, out.String())
+			}
+		})
+	}
+}
+
+func TestRequestLogger_Unmatched(t *testing.T) {
+	var out bytes.Buffer
+
+	h := RequestLogger(&out)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/customer/by-name/Asha", nil))
+
+	var entry logEntry
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log line %q", out.String())
+	}
+
+	if entry.Route != unmatched || entry.CustomerID != "" || entry.Status != http.StatusNotFound {
+		t.Errorf("Expected route %v without a customer id\nGot %+v", unmatched, entry)
+	}
+
+	if bytes.Contains(out.Bytes(), []byte("Asha")) {
+		t.Errorf("Expected the path to be left out of the log\nGot %v", out.String())
+	}
+}
@@ -1,15 +1,166 @@
 package middleware
 
-import "net/http"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 
-func OauthMiddleware(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		header := r.Header.Get("x-api-key")
-		if header != "divya-zs" {
-			w.WriteHeader(http.StatusUnauthorized)
+	"customer/tracing"
+)
 
-			return
+// APIKeyHeader carries the API key of a request, over HTTP and as gRPC metadata.
+const APIKeyHeader = "x-api-key"
+
+// publicPaths can be reached without an API key.
+var publicPaths = map[string]bool{
+	"/openapi.json": true,
+	"/docs":         true,
+	"/health/live":  true,
+	"/health/ready": true,
+}
+
+// Tenants maps every accepted API key to the tenant whose customers it can reach.
+type Tenants map[string]string
+
+// ParseTenants reads API keys in the form key=tenant, separated by commas.
+func ParseTenants(s string) (Tenants, error) {
+	tenants := Tenants{}
+
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid API key %q, expected key=tenant", pair)
 		}
-		h.ServeHTTP(w, r)
-	})
+
+		tenants[kv[0]] = kv[1]
+	}
+
+	return tenants, nil
+}
+
+// Names returns every tenant once, sorted.
+func (t Tenants) Names() []string {
+	seen := map[string]bool{}
+
+	var names []string
+
+	for _, tenant := range t {
+		if !seen[tenant] {
+			seen[tenant] = true
+			names = append(names, tenant)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Authenticate returns the context of a request made with key, scoped to the tenant of the key.
+func (t Tenants) Authenticate(ctx context.Context, key string) (context.Context, bool) {
+	tenant, ok := t[key]
+	if !ok || key == "" {
+		return ctx, false
+	}
+
+	setCaller(ctx, keyIdentity(key))
+
+	return WithTenant(ctx, tenant), true
+}
+
+// Scopes maps API keys to the permissions they grant, such as customer:salary:read.
+type Scopes map[string][]string
+
+// ParseScopes reads the scopes of API keys in the form key=scope scope, separated by commas.
+// Keys that are not listed are granted no scopes.
+func ParseScopes(s string) (Scopes, error) {
+	scopes := Scopes{}
+
+	if strings.TrimSpace(s) == "" {
+		return scopes, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid API key scopes %q, expected key=scope scope", pair)
+		}
+
+		scopes[kv[0]] = append(scopes[kv[0]], strings.Fields(kv[1])...)
+	}
+
+	return scopes, nil
+}
+
+// Grant returns ctx holding the scopes of key.
+func (s Scopes) Grant(ctx context.Context, key string) context.Context {
+	return WithScopes(ctx, s[key]...)
+}
+
+// WithScopes grants ctx the given scopes. Requests get their scopes from their API key; background jobs set them here.
+func WithScopes(ctx context.Context, scopes ...string) context.Context {
+	granted := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		granted[scope] = true
+	}
+
+	return context.WithValue(ctx, scopesKey, granted)
+}
+
+// Granted reports whether ctx holds scope.
+func Granted(ctx context.Context, scope string) bool {
+	granted, _ := ctx.Value(scopesKey).(map[string]bool)
+
+	return granted[scope]
+}
+
+// WithTenant scopes ctx to tenant. Requests get their tenant from their API key; background jobs set it here.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// Tenant returns the tenant ctx is scoped to, or "" when it is not scoped to any.
+func Tenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey).(string)
+
+	return tenant
+}
+
+// Auth rejects requests without a known x-api-key, scopes the others to the tenant of their key and grants
+// them the scopes of their key.
+func Auth(tenants Tenants, scopes Scopes) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if publicPaths[r.URL.Path] {
+				h.ServeHTTP(w, r)
+
+				return
+			}
+
+			_, span := tracing.Tracer().Start(r.Context(), "middleware.Auth")
+
+			key := r.Header.Get(APIKeyHeader)
+			ctx, ok := tenants.Authenticate(r.Context(), key)
+			span.End()
+
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			h.ServeHTTP(w, r.WithContext(scopes.Grant(ctx, key)))
+		})
+	}
+}
+
+// keyIdentity identifies an API key in logs without revealing it.
+func keyIdentity(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return "key:" + hex.EncodeToString(sum[:4])
 }
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAuth(t *testing.T) {
+	tenants := Tenants{"divya-zs": "zopsmart", "acme-key": "acme"}
+	scopes := Scopes{"divya-zs": {"customer:salary:read"}}
+
+	tests := []struct {
+		desc   string
+		path   string
+		apiKey string
+		status int
+		tenant string
+		salary bool
+	}{
+		{"first tenant", "/customer/1", "divya-zs", http.StatusOK, "zopsmart", true},
+		{"second tenant", "/customer/1", "acme-key", http.StatusOK, "acme", false},
+		{"unknown key", "/customer/1", "wrong", http.StatusUnauthorized, "", false},
+		{"missing key", "/customer/1", "", http.StatusUnauthorized, "", false},
+		{"public path", "/health/live", "", http.StatusOK, "", false},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var (
+				seen   string
+				salary bool
+			)
+
+			h := Auth(tenants, scopes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen, salary = Tenant(r.Context()), Granted(r.Context(), "customer:salary:read")
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.Header.Set(APIKeyHeader, tc.apiKey)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tc.status {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.status, w.Code)
+			}
+
+			if seen != tc.tenant {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.tenant, seen)
+			}
+
+			if salary != tc.salary {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.salary, salary)
+			}
+		})
+	}
+}
+
+func TestParseTenants(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected Tenants
+		names    []string
+		err      bool
+	}{
+		{"single key", "divya-zs=default", Tenants{"divya-zs": "default"}, []string{"default"}, false},
+		{"shared tenant", "a=acme, b=acme,c=zopsmart", Tenants{"a": "acme", "b": "acme", "c": "zopsmart"},
+			[]string{"acme", "zopsmart"}, false},
+		{"missing tenant", "divya-zs", nil, nil, true},
+		{"empty tenant", "divya-zs=", nil, nil, true},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseTenants(tc.input)
+		if (err != nil) != tc.err {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected error %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+
+		if names := res.Names(); !reflect.DeepEqual(names, tc.names) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.names, names)
+		}
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected Scopes
+		err      bool
+	}{
+		{"no scopes", "", Scopes{}, false},
+		{"one key", "divya-zs=customer:salary:read customer:salary:write",
+			Scopes{"divya-zs": {"customer:salary:read", "customer:salary:write"}}, false},
+		{"several keys", "a=customer:salary:read, b=", Scopes{"a": {"customer:salary:read"}, "b": nil}, false},
+		{"missing scopes", "divya-zs", nil, true},
+		{"missing key", "=customer:salary:read", nil, true},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseScopes(tc.input)
+		if (err != nil) != tc.err {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected error %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// statusWriter answers with the status its handler asked for instead of the 200 gofr writes.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	if code == http.StatusOK && s.status != 0 {
+		code = s.status
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Flush lets streamed responses through the writer.
+func (s *statusWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Status lets handlers succeed with another status than the one gofr answers their method with, e.g. 201 for
+// a PUT that created its customer, through SetStatus. gofr writes the response itself, so Status must run after
+// every middleware that records the status, for them to see the one that is sent.
+func Status(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(s, r.WithContext(context.WithValue(r.Context(), statusKey, s)))
+	})
+}
+
+// SetStatus makes the request ctx was derived from succeed with code. Errors keep their own status.
+func SetStatus(ctx context.Context, code int) {
+	if s, ok := ctx.Value(statusKey).(*statusWriter); ok {
+		s.status = code
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		desc     string
+		status   int
+		written  int
+		expected int
+	}{
+		{"created", http.StatusCreated, http.StatusOK, http.StatusCreated},
+		{"not set", 0, http.StatusOK, http.StatusOK},
+		{"errors are kept", http.StatusCreated, http.StatusBadRequest, http.StatusBadRequest},
+	}
+
+	for i, tc := range tests {
+		var out bytes.Buffer
+
+		// The logger runs before Status, as in main, so it records the status that is sent.
+		h := RequestLogger(&out)(Status(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tc.status != 0 {
+				SetStatus(r.Context(), tc.status)
+			}
+
+			w.WriteHeader(tc.written)
+		})))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/customer/by-name/Divya", nil))
+
+		var entry logEntry
+		_ = json.Unmarshal(out.Bytes(), &entry)
+
+		if w.Code != tc.expected || entry.Status != tc.expected {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v, logged %v", i+1, tc.desc, tc.expected, w.Code, entry.Status)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"customer/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider("customer", exporter)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	var inner trace.SpanContext
+
+	h := mux.NewRouter()
+	h.Use(Tracing)
+	h.HandleFunc("/customer/{id}", func(w http.ResponseWriter, r *http.Request) {
+		inner = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/customer/7", nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	_ = tp.ForceFlush(context.Background())
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span\nGot %v", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name != "GET /customer/{id}" {
+		t.Errorf("Expected %v\nGot %v", "GET /customer/{id}", span.Name)
+	}
+
+	if span.SpanContext.TraceID().String() != traceID || span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected the inbound trace to be continued\nGot trace %v, parent %v", span.SpanContext.TraceID(), span.Parent.SpanID())
+	}
+
+	if inner.SpanID() != span.SpanContext.SpanID() {
+		t.Errorf("Expected the server span to be current in the handler")
+	}
+
+	if !strings.Contains(w.Header().Get("traceparent"), traceID) {
+		t.Errorf("Expected the trace id in the response traceparent\nGot %v", w.Header().Get("traceparent"))
+	}
+
+	found := false
+
+	for _, a := range span.Attributes {
+		if a.Key == tracing.CustomerIDKey && a.Value.AsString() == "7" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected the span to carry %v", tracing.CustomerIDKey)
+	}
+}
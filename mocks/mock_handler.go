@@ -65,33 +65,53 @@ func (mr *MockHandlerInMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 }
 
 // Get mocks base method.
-func (m *MockHandlerIn) Get(ctx *gofr.Context) ([]models.Customer, error) {
+func (m *MockHandlerIn) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx)
+	ret := m.ctrl.Call(m, "Get", ctx, filter)
 	ret0, _ := ret[0].([]models.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockHandlerInMockRecorder) Get(ctx interface{}) *gomock.Call {
+func (mr *MockHandlerInMockRecorder) Get(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockHandlerIn)(nil).Get), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockHandlerIn)(nil).Get), ctx, filter)
 }
 
 // GetByID mocks base method.
-func (m *MockHandlerIn) GetByID(ctx *gofr.Context, id int) (models.Customer, error) {
+func (m *MockHandlerIn) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	varargs := []interface{}{ctx, id}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByID", varargs...)
 	ret0, _ := ret[0].(models.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockHandlerInMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+func (mr *MockHandlerInMockRecorder) GetByID(ctx, id interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHandlerIn)(nil).GetByID), ctx, id)
+	varargs := append([]interface{}{ctx, id}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHandlerIn)(nil).GetByID), varargs...)
+}
+
+// GetByIDs mocks base method.
+func (m *MockHandlerIn) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockHandlerInMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockHandlerIn)(nil).GetByIDs), ctx, ids)
 }
 
 // Patch mocks base method.
@@ -109,6 +129,20 @@ func (mr *MockHandlerInMockRecorder) Patch(ctx, id, customer interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockHandlerIn)(nil).Patch), ctx, id, customer)
 }
 
+// Stream mocks base method.
+func (m *MockHandlerIn) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockHandlerInMockRecorder) Stream(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockHandlerIn)(nil).Stream), ctx, filter, fn)
+}
+
 // Update mocks base method.
 func (m *MockHandlerIn) Update(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
 	m.ctrl.T.Helper()
@@ -123,3 +157,694 @@ func (mr *MockHandlerInMockRecorder) Update(ctx, customer interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockHandlerIn)(nil).Update), ctx, customer)
 }
+
+// Upsert mocks base method.
+func (m *MockHandlerIn) Upsert(ctx *gofr.Context, customer models.Customer) (models.Customer, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, customer)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockHandlerInMockRecorder) Upsert(ctx, customer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockHandlerIn)(nil).Upsert), ctx, customer)
+}
+
+// MockAddressHandlerIn is a mock of AddressHandlerIn interface.
+type MockAddressHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressHandlerInMockRecorder
+}
+
+// MockAddressHandlerInMockRecorder is the mock recorder for MockAddressHandlerIn.
+type MockAddressHandlerInMockRecorder struct {
+	mock *MockAddressHandlerIn
+}
+
+// NewMockAddressHandlerIn creates a new mock instance.
+func NewMockAddressHandlerIn(ctrl *gomock.Controller) *MockAddressHandlerIn {
+	mock := &MockAddressHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockAddressHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressHandlerIn) EXPECT() *MockAddressHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAddressHandlerIn) Create(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, address)
+	ret0, _ := ret[0].(models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAddressHandlerInMockRecorder) Create(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAddressHandlerIn)(nil).Create), ctx, address)
+}
+
+// Delete mocks base method.
+func (m *MockAddressHandlerIn) Delete(ctx *gofr.Context, customerID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, customerID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAddressHandlerInMockRecorder) Delete(ctx, customerID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAddressHandlerIn)(nil).Delete), ctx, customerID, id)
+}
+
+// Get mocks base method.
+func (m *MockAddressHandlerIn) Get(ctx *gofr.Context, customerID, id int) (models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, customerID, id)
+	ret0, _ := ret[0].(models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAddressHandlerInMockRecorder) Get(ctx, customerID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAddressHandlerIn)(nil).Get), ctx, customerID, id)
+}
+
+// List mocks base method.
+func (m *MockAddressHandlerIn) List(ctx *gofr.Context, customerID int) ([]models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAddressHandlerInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAddressHandlerIn)(nil).List), ctx, customerID)
+}
+
+// Update mocks base method.
+func (m *MockAddressHandlerIn) Update(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, address)
+	ret0, _ := ret[0].(models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAddressHandlerInMockRecorder) Update(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAddressHandlerIn)(nil).Update), ctx, address)
+}
+
+// MockStatsHandlerIn is a mock of StatsHandlerIn interface.
+type MockStatsHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsHandlerInMockRecorder
+}
+
+// MockStatsHandlerInMockRecorder is the mock recorder for MockStatsHandlerIn.
+type MockStatsHandlerInMockRecorder struct {
+	mock *MockStatsHandlerIn
+}
+
+// NewMockStatsHandlerIn creates a new mock instance.
+func NewMockStatsHandlerIn(ctrl *gomock.Controller) *MockStatsHandlerIn {
+	mock := &MockStatsHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockStatsHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsHandlerIn) EXPECT() *MockStatsHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockStatsHandlerIn) Get(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, filter, buckets)
+	ret0, _ := ret[0].(models.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStatsHandlerInMockRecorder) Get(ctx, filter, buckets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStatsHandlerIn)(nil).Get), ctx, filter, buckets)
+}
+
+// MockMergeHandlerIn is a mock of MergeHandlerIn interface.
+type MockMergeHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockMergeHandlerInMockRecorder
+}
+
+// MockMergeHandlerInMockRecorder is the mock recorder for MockMergeHandlerIn.
+type MockMergeHandlerInMockRecorder struct {
+	mock *MockMergeHandlerIn
+}
+
+// NewMockMergeHandlerIn creates a new mock instance.
+func NewMockMergeHandlerIn(ctrl *gomock.Controller) *MockMergeHandlerIn {
+	mock := &MockMergeHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockMergeHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMergeHandlerIn) EXPECT() *MockMergeHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Duplicates mocks base method.
+func (m *MockMergeHandlerIn) Duplicates(ctx *gofr.Context) ([]models.Duplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Duplicates", ctx)
+	ret0, _ := ret[0].([]models.Duplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Duplicates indicates an expected call of Duplicates.
+func (mr *MockMergeHandlerInMockRecorder) Duplicates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Duplicates", reflect.TypeOf((*MockMergeHandlerIn)(nil).Duplicates), ctx)
+}
+
+// Merge mocks base method.
+func (m *MockMergeHandlerIn) Merge(ctx *gofr.Context, req models.MergeRequest) (models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, req)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockMergeHandlerInMockRecorder) Merge(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockMergeHandlerIn)(nil).Merge), ctx, req)
+}
+
+// MockTransitionHandlerIn is a mock of TransitionHandlerIn interface.
+type MockTransitionHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransitionHandlerInMockRecorder
+}
+
+// MockTransitionHandlerInMockRecorder is the mock recorder for MockTransitionHandlerIn.
+type MockTransitionHandlerInMockRecorder struct {
+	mock *MockTransitionHandlerIn
+}
+
+// NewMockTransitionHandlerIn creates a new mock instance.
+func NewMockTransitionHandlerIn(ctrl *gomock.Controller) *MockTransitionHandlerIn {
+	mock := &MockTransitionHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockTransitionHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransitionHandlerIn) EXPECT() *MockTransitionHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransitionHandlerIn) Create(ctx *gofr.Context, t models.Transition) (models.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(models.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransitionHandlerInMockRecorder) Create(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransitionHandlerIn)(nil).Create), ctx, t)
+}
+
+// List mocks base method.
+func (m *MockTransitionHandlerIn) List(ctx *gofr.Context, customerID int) ([]models.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]models.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransitionHandlerInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransitionHandlerIn)(nil).List), ctx, customerID)
+}
+
+// MockTagHandlerIn is a mock of TagHandlerIn interface.
+type MockTagHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagHandlerInMockRecorder
+}
+
+// MockTagHandlerInMockRecorder is the mock recorder for MockTagHandlerIn.
+type MockTagHandlerInMockRecorder struct {
+	mock *MockTagHandlerIn
+}
+
+// NewMockTagHandlerIn creates a new mock instance.
+func NewMockTagHandlerIn(ctrl *gomock.Controller) *MockTagHandlerIn {
+	mock := &MockTagHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockTagHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagHandlerIn) EXPECT() *MockTagHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockTagHandlerIn) Add(ctx *gofr.Context, customerID int, name string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, customerID, name)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockTagHandlerInMockRecorder) Add(ctx, customerID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockTagHandlerIn)(nil).Add), ctx, customerID, name)
+}
+
+// List mocks base method.
+func (m *MockTagHandlerIn) List(ctx *gofr.Context, customerID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTagHandlerInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTagHandlerIn)(nil).List), ctx, customerID)
+}
+
+// Remove mocks base method.
+func (m *MockTagHandlerIn) Remove(ctx *gofr.Context, customerID int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, customerID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockTagHandlerInMockRecorder) Remove(ctx, customerID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTagHandlerIn)(nil).Remove), ctx, customerID, name)
+}
+
+// MockSegmentHandlerIn is a mock of SegmentHandlerIn interface.
+type MockSegmentHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockSegmentHandlerInMockRecorder
+}
+
+// MockSegmentHandlerInMockRecorder is the mock recorder for MockSegmentHandlerIn.
+type MockSegmentHandlerInMockRecorder struct {
+	mock *MockSegmentHandlerIn
+}
+
+// NewMockSegmentHandlerIn creates a new mock instance.
+func NewMockSegmentHandlerIn(ctrl *gomock.Controller) *MockSegmentHandlerIn {
+	mock := &MockSegmentHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockSegmentHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSegmentHandlerIn) EXPECT() *MockSegmentHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSegmentHandlerIn) Create(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, segment)
+	ret0, _ := ret[0].(models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSegmentHandlerInMockRecorder) Create(ctx, segment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSegmentHandlerIn)(nil).Create), ctx, segment)
+}
+
+// Customers mocks base method.
+func (m *MockSegmentHandlerIn) Customers(ctx *gofr.Context, id int, page models.Filter) ([]models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Customers", ctx, id, page)
+	ret0, _ := ret[0].([]models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Customers indicates an expected call of Customers.
+func (mr *MockSegmentHandlerInMockRecorder) Customers(ctx, id, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Customers", reflect.TypeOf((*MockSegmentHandlerIn)(nil).Customers), ctx, id, page)
+}
+
+// Delete mocks base method.
+func (m *MockSegmentHandlerIn) Delete(ctx *gofr.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSegmentHandlerInMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSegmentHandlerIn)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockSegmentHandlerIn) Get(ctx *gofr.Context, id int) (models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSegmentHandlerInMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSegmentHandlerIn)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockSegmentHandlerIn) List(ctx *gofr.Context) ([]models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSegmentHandlerInMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSegmentHandlerIn)(nil).List), ctx)
+}
+
+// Stream mocks base method.
+func (m *MockSegmentHandlerIn) Stream(ctx *gofr.Context, id int, page models.Filter, fn func(models.Customer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, id, page, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockSegmentHandlerInMockRecorder) Stream(ctx, id, page, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockSegmentHandlerIn)(nil).Stream), ctx, id, page, fn)
+}
+
+// Update mocks base method.
+func (m *MockSegmentHandlerIn) Update(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, segment)
+	ret0, _ := ret[0].(models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSegmentHandlerInMockRecorder) Update(ctx, segment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSegmentHandlerIn)(nil).Update), ctx, segment)
+}
+
+// MockAttributeHandlerIn is a mock of AttributeHandlerIn interface.
+type MockAttributeHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttributeHandlerInMockRecorder
+}
+
+// MockAttributeHandlerInMockRecorder is the mock recorder for MockAttributeHandlerIn.
+type MockAttributeHandlerInMockRecorder struct {
+	mock *MockAttributeHandlerIn
+}
+
+// NewMockAttributeHandlerIn creates a new mock instance.
+func NewMockAttributeHandlerIn(ctrl *gomock.Controller) *MockAttributeHandlerIn {
+	mock := &MockAttributeHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockAttributeHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttributeHandlerIn) EXPECT() *MockAttributeHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAttributeHandlerIn) Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, d)
+	ret0, _ := ret[0].(models.AttributeDefinition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttributeHandlerInMockRecorder) Create(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttributeHandlerIn)(nil).Create), ctx, d)
+}
+
+// Delete mocks base method.
+func (m *MockAttributeHandlerIn) Delete(ctx *gofr.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttributeHandlerInMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttributeHandlerIn)(nil).Delete), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockAttributeHandlerIn) List(ctx *gofr.Context) (models.AttributeSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].(models.AttributeSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAttributeHandlerInMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAttributeHandlerIn)(nil).List), ctx)
+}
+
+// MockRelationshipHandlerIn is a mock of RelationshipHandlerIn interface.
+type MockRelationshipHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelationshipHandlerInMockRecorder
+}
+
+// MockRelationshipHandlerInMockRecorder is the mock recorder for MockRelationshipHandlerIn.
+type MockRelationshipHandlerInMockRecorder struct {
+	mock *MockRelationshipHandlerIn
+}
+
+// NewMockRelationshipHandlerIn creates a new mock instance.
+func NewMockRelationshipHandlerIn(ctrl *gomock.Controller) *MockRelationshipHandlerIn {
+	mock := &MockRelationshipHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockRelationshipHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelationshipHandlerIn) EXPECT() *MockRelationshipHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRelationshipHandlerIn) Create(ctx *gofr.Context, r models.Relationship) (models.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(models.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRelationshipHandlerInMockRecorder) Create(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRelationshipHandlerIn)(nil).Create), ctx, r)
+}
+
+// Graph mocks base method.
+func (m *MockRelationshipHandlerIn) Graph(ctx *gofr.Context, customerID, depth int) (models.Graph, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Graph", ctx, customerID, depth)
+	ret0, _ := ret[0].(models.Graph)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Graph indicates an expected call of Graph.
+func (mr *MockRelationshipHandlerInMockRecorder) Graph(ctx, customerID, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Graph", reflect.TypeOf((*MockRelationshipHandlerIn)(nil).Graph), ctx, customerID, depth)
+}
+
+// List mocks base method.
+func (m *MockRelationshipHandlerIn) List(ctx *gofr.Context, customerID int) ([]models.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]models.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRelationshipHandlerInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRelationshipHandlerIn)(nil).List), ctx, customerID)
+}
+
+// MockGDPRHandlerIn is a mock of GDPRHandlerIn interface.
+type MockGDPRHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockGDPRHandlerInMockRecorder
+}
+
+// MockGDPRHandlerInMockRecorder is the mock recorder for MockGDPRHandlerIn.
+type MockGDPRHandlerInMockRecorder struct {
+	mock *MockGDPRHandlerIn
+}
+
+// NewMockGDPRHandlerIn creates a new mock instance.
+func NewMockGDPRHandlerIn(ctrl *gomock.Controller) *MockGDPRHandlerIn {
+	mock := &MockGDPRHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockGDPRHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGDPRHandlerIn) EXPECT() *MockGDPRHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Erase mocks base method.
+func (m *MockGDPRHandlerIn) Erase(ctx *gofr.Context, customerID int) (models.Erasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", ctx, customerID)
+	ret0, _ := ret[0].(models.Erasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockGDPRHandlerInMockRecorder) Erase(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockGDPRHandlerIn)(nil).Erase), ctx, customerID)
+}
+
+// Erasures mocks base method.
+func (m *MockGDPRHandlerIn) Erasures(ctx *gofr.Context) (models.ErasureLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erasures", ctx)
+	ret0, _ := ret[0].(models.ErasureLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erasures indicates an expected call of Erasures.
+func (mr *MockGDPRHandlerInMockRecorder) Erasures(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erasures", reflect.TypeOf((*MockGDPRHandlerIn)(nil).Erasures), ctx)
+}
+
+// Export mocks base method.
+func (m *MockGDPRHandlerIn) Export(ctx *gofr.Context, customerID int) (models.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, customerID)
+	ret0, _ := ret[0].(models.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockGDPRHandlerInMockRecorder) Export(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockGDPRHandlerIn)(nil).Export), ctx, customerID)
+}
+
+// MockKeysHandlerIn is a mock of KeysHandlerIn interface.
+type MockKeysHandlerIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeysHandlerInMockRecorder
+}
+
+// MockKeysHandlerInMockRecorder is the mock recorder for MockKeysHandlerIn.
+type MockKeysHandlerInMockRecorder struct {
+	mock *MockKeysHandlerIn
+}
+
+// NewMockKeysHandlerIn creates a new mock instance.
+func NewMockKeysHandlerIn(ctrl *gomock.Controller) *MockKeysHandlerIn {
+	mock := &MockKeysHandlerIn{ctrl: ctrl}
+	mock.recorder = &MockKeysHandlerInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeysHandlerIn) EXPECT() *MockKeysHandlerInMockRecorder {
+	return m.recorder
+}
+
+// Rotate mocks base method.
+func (m *MockKeysHandlerIn) Rotate(ctx *gofr.Context) (models.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx)
+	ret0, _ := ret[0].(models.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockKeysHandlerInMockRecorder) Rotate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockKeysHandlerIn)(nil).Rotate), ctx)
+}
@@ -35,6 +35,21 @@ func (m *MockServiceIn) EXPECT() *MockServiceInMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockServiceIn) Count(ctx *gofr.Context, filter models.Filter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockServiceInMockRecorder) Count(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockServiceIn)(nil).Count), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockServiceIn) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
 	m.ctrl.T.Helper()
@@ -65,33 +80,68 @@ func (mr *MockServiceInMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 }
 
 // Get mocks base method.
-func (m *MockServiceIn) Get(ctx *gofr.Context) ([]models.Customer, error) {
+func (m *MockServiceIn) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx)
+	ret := m.ctrl.Call(m, "Get", ctx, filter)
 	ret0, _ := ret[0].([]models.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockServiceInMockRecorder) Get(ctx interface{}) *gomock.Call {
+func (mr *MockServiceInMockRecorder) Get(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockServiceIn)(nil).Get), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockServiceIn)(nil).Get), ctx, filter)
 }
 
 // GetByID mocks base method.
-func (m *MockServiceIn) GetByID(ctx *gofr.Context, id int) (models.Customer, error) {
+func (m *MockServiceIn) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	varargs := []interface{}{ctx, id}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByID", varargs...)
 	ret0, _ := ret[0].(models.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockServiceInMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+func (mr *MockServiceInMockRecorder) GetByID(ctx, id interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceIn)(nil).GetByID), ctx, id)
+	varargs := append([]interface{}{ctx, id}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceIn)(nil).GetByID), varargs...)
+}
+
+// GetByIDs mocks base method.
+func (m *MockServiceIn) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockServiceInMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockServiceIn)(nil).GetByIDs), ctx, ids)
+}
+
+// Merge mocks base method.
+func (m *MockServiceIn) Merge(ctx *gofr.Context, survivorID int, loserIDs []int, merge func(models.Customer, []models.Customer) models.Customer) (models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, survivorID, loserIDs, merge)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockServiceInMockRecorder) Merge(ctx, survivorID, loserIDs, merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockServiceIn)(nil).Merge), ctx, survivorID, loserIDs, merge)
 }
 
 // Patch mocks base method.
@@ -109,6 +159,35 @@ func (mr *MockServiceInMockRecorder) Patch(ctx, id, customer interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockServiceIn)(nil).Patch), ctx, id, customer)
 }
 
+// Stats mocks base method.
+func (m *MockServiceIn) Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx, filter, buckets)
+	ret0, _ := ret[0].(models.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockServiceInMockRecorder) Stats(ctx, filter, buckets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockServiceIn)(nil).Stats), ctx, filter, buckets)
+}
+
+// Stream mocks base method.
+func (m *MockServiceIn) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockServiceInMockRecorder) Stream(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockServiceIn)(nil).Stream), ctx, filter, fn)
+}
+
 // Update mocks base method.
 func (m *MockServiceIn) Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
 	m.ctrl.T.Helper()
@@ -123,3 +202,603 @@ func (mr *MockServiceInMockRecorder) Update(ctx, id, customer interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockServiceIn)(nil).Update), ctx, id, customer)
 }
+
+// Upsert mocks base method.
+func (m *MockServiceIn) Upsert(ctx *gofr.Context, customer models.Customer) (models.Customer, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, customer)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockServiceInMockRecorder) Upsert(ctx, customer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockServiceIn)(nil).Upsert), ctx, customer)
+}
+
+// MockAddressServiceIn is a mock of AddressServiceIn interface.
+type MockAddressServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressServiceInMockRecorder
+}
+
+// MockAddressServiceInMockRecorder is the mock recorder for MockAddressServiceIn.
+type MockAddressServiceInMockRecorder struct {
+	mock *MockAddressServiceIn
+}
+
+// NewMockAddressServiceIn creates a new mock instance.
+func NewMockAddressServiceIn(ctrl *gomock.Controller) *MockAddressServiceIn {
+	mock := &MockAddressServiceIn{ctrl: ctrl}
+	mock.recorder = &MockAddressServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressServiceIn) EXPECT() *MockAddressServiceInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAddressServiceIn) Create(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, address)
+	ret0, _ := ret[0].(models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAddressServiceInMockRecorder) Create(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAddressServiceIn)(nil).Create), ctx, address)
+}
+
+// Delete mocks base method.
+func (m *MockAddressServiceIn) Delete(ctx *gofr.Context, customerID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, customerID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAddressServiceInMockRecorder) Delete(ctx, customerID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAddressServiceIn)(nil).Delete), ctx, customerID, id)
+}
+
+// Get mocks base method.
+func (m *MockAddressServiceIn) Get(ctx *gofr.Context, customerID, id int) (models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, customerID, id)
+	ret0, _ := ret[0].(models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAddressServiceInMockRecorder) Get(ctx, customerID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAddressServiceIn)(nil).Get), ctx, customerID, id)
+}
+
+// List mocks base method.
+func (m *MockAddressServiceIn) List(ctx *gofr.Context, customerID int) ([]models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAddressServiceInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAddressServiceIn)(nil).List), ctx, customerID)
+}
+
+// Update mocks base method.
+func (m *MockAddressServiceIn) Update(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, address)
+	ret0, _ := ret[0].(models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAddressServiceInMockRecorder) Update(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAddressServiceIn)(nil).Update), ctx, address)
+}
+
+// MockTransitionServiceIn is a mock of TransitionServiceIn interface.
+type MockTransitionServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransitionServiceInMockRecorder
+}
+
+// MockTransitionServiceInMockRecorder is the mock recorder for MockTransitionServiceIn.
+type MockTransitionServiceInMockRecorder struct {
+	mock *MockTransitionServiceIn
+}
+
+// NewMockTransitionServiceIn creates a new mock instance.
+func NewMockTransitionServiceIn(ctrl *gomock.Controller) *MockTransitionServiceIn {
+	mock := &MockTransitionServiceIn{ctrl: ctrl}
+	mock.recorder = &MockTransitionServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransitionServiceIn) EXPECT() *MockTransitionServiceInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransitionServiceIn) Create(ctx *gofr.Context, t models.Transition) (models.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(models.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransitionServiceInMockRecorder) Create(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransitionServiceIn)(nil).Create), ctx, t)
+}
+
+// List mocks base method.
+func (m *MockTransitionServiceIn) List(ctx *gofr.Context, customerID int) ([]models.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]models.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransitionServiceInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransitionServiceIn)(nil).List), ctx, customerID)
+}
+
+// MockTagServiceIn is a mock of TagServiceIn interface.
+type MockTagServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagServiceInMockRecorder
+}
+
+// MockTagServiceInMockRecorder is the mock recorder for MockTagServiceIn.
+type MockTagServiceInMockRecorder struct {
+	mock *MockTagServiceIn
+}
+
+// NewMockTagServiceIn creates a new mock instance.
+func NewMockTagServiceIn(ctrl *gomock.Controller) *MockTagServiceIn {
+	mock := &MockTagServiceIn{ctrl: ctrl}
+	mock.recorder = &MockTagServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagServiceIn) EXPECT() *MockTagServiceInMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockTagServiceIn) Add(ctx *gofr.Context, customerID int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, customerID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockTagServiceInMockRecorder) Add(ctx, customerID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockTagServiceIn)(nil).Add), ctx, customerID, name)
+}
+
+// List mocks base method.
+func (m *MockTagServiceIn) List(ctx *gofr.Context, customerID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTagServiceInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTagServiceIn)(nil).List), ctx, customerID)
+}
+
+// Remove mocks base method.
+func (m *MockTagServiceIn) Remove(ctx *gofr.Context, customerID int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, customerID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockTagServiceInMockRecorder) Remove(ctx, customerID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTagServiceIn)(nil).Remove), ctx, customerID, name)
+}
+
+// MockSegmentServiceIn is a mock of SegmentServiceIn interface.
+type MockSegmentServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockSegmentServiceInMockRecorder
+}
+
+// MockSegmentServiceInMockRecorder is the mock recorder for MockSegmentServiceIn.
+type MockSegmentServiceInMockRecorder struct {
+	mock *MockSegmentServiceIn
+}
+
+// NewMockSegmentServiceIn creates a new mock instance.
+func NewMockSegmentServiceIn(ctrl *gomock.Controller) *MockSegmentServiceIn {
+	mock := &MockSegmentServiceIn{ctrl: ctrl}
+	mock.recorder = &MockSegmentServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSegmentServiceIn) EXPECT() *MockSegmentServiceInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSegmentServiceIn) Create(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, segment)
+	ret0, _ := ret[0].(models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSegmentServiceInMockRecorder) Create(ctx, segment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSegmentServiceIn)(nil).Create), ctx, segment)
+}
+
+// Delete mocks base method.
+func (m *MockSegmentServiceIn) Delete(ctx *gofr.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSegmentServiceInMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSegmentServiceIn)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockSegmentServiceIn) Get(ctx *gofr.Context, id int) (models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSegmentServiceInMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSegmentServiceIn)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockSegmentServiceIn) List(ctx *gofr.Context) ([]models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSegmentServiceInMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSegmentServiceIn)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockSegmentServiceIn) Update(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, segment)
+	ret0, _ := ret[0].(models.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSegmentServiceInMockRecorder) Update(ctx, segment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSegmentServiceIn)(nil).Update), ctx, segment)
+}
+
+// MockAttributeServiceIn is a mock of AttributeServiceIn interface.
+type MockAttributeServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttributeServiceInMockRecorder
+}
+
+// MockAttributeServiceInMockRecorder is the mock recorder for MockAttributeServiceIn.
+type MockAttributeServiceInMockRecorder struct {
+	mock *MockAttributeServiceIn
+}
+
+// NewMockAttributeServiceIn creates a new mock instance.
+func NewMockAttributeServiceIn(ctrl *gomock.Controller) *MockAttributeServiceIn {
+	mock := &MockAttributeServiceIn{ctrl: ctrl}
+	mock.recorder = &MockAttributeServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttributeServiceIn) EXPECT() *MockAttributeServiceInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAttributeServiceIn) Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, d)
+	ret0, _ := ret[0].(models.AttributeDefinition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttributeServiceInMockRecorder) Create(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttributeServiceIn)(nil).Create), ctx, d)
+}
+
+// Delete mocks base method.
+func (m *MockAttributeServiceIn) Delete(ctx *gofr.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttributeServiceInMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttributeServiceIn)(nil).Delete), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockAttributeServiceIn) List(ctx *gofr.Context) (models.AttributeSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].(models.AttributeSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAttributeServiceInMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAttributeServiceIn)(nil).List), ctx)
+}
+
+// MockRelationshipServiceIn is a mock of RelationshipServiceIn interface.
+type MockRelationshipServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelationshipServiceInMockRecorder
+}
+
+// MockRelationshipServiceInMockRecorder is the mock recorder for MockRelationshipServiceIn.
+type MockRelationshipServiceInMockRecorder struct {
+	mock *MockRelationshipServiceIn
+}
+
+// NewMockRelationshipServiceIn creates a new mock instance.
+func NewMockRelationshipServiceIn(ctrl *gomock.Controller) *MockRelationshipServiceIn {
+	mock := &MockRelationshipServiceIn{ctrl: ctrl}
+	mock.recorder = &MockRelationshipServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelationshipServiceIn) EXPECT() *MockRelationshipServiceInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRelationshipServiceIn) Create(ctx *gofr.Context, r models.Relationship) (models.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(models.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRelationshipServiceInMockRecorder) Create(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRelationshipServiceIn)(nil).Create), ctx, r)
+}
+
+// Graph mocks base method.
+func (m *MockRelationshipServiceIn) Graph(ctx *gofr.Context, customerID, depth int) ([]models.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Graph", ctx, customerID, depth)
+	ret0, _ := ret[0].([]models.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Graph indicates an expected call of Graph.
+func (mr *MockRelationshipServiceInMockRecorder) Graph(ctx, customerID, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Graph", reflect.TypeOf((*MockRelationshipServiceIn)(nil).Graph), ctx, customerID, depth)
+}
+
+// List mocks base method.
+func (m *MockRelationshipServiceIn) List(ctx *gofr.Context, customerID int) ([]models.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, customerID)
+	ret0, _ := ret[0].([]models.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRelationshipServiceInMockRecorder) List(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRelationshipServiceIn)(nil).List), ctx, customerID)
+}
+
+// MockGDPRServiceIn is a mock of GDPRServiceIn interface.
+type MockGDPRServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockGDPRServiceInMockRecorder
+}
+
+// MockGDPRServiceInMockRecorder is the mock recorder for MockGDPRServiceIn.
+type MockGDPRServiceInMockRecorder struct {
+	mock *MockGDPRServiceIn
+}
+
+// NewMockGDPRServiceIn creates a new mock instance.
+func NewMockGDPRServiceIn(ctrl *gomock.Controller) *MockGDPRServiceIn {
+	mock := &MockGDPRServiceIn{ctrl: ctrl}
+	mock.recorder = &MockGDPRServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGDPRServiceIn) EXPECT() *MockGDPRServiceInMockRecorder {
+	return m.recorder
+}
+
+// Audit mocks base method.
+func (m *MockGDPRServiceIn) Audit(ctx *gofr.Context, customerID int) ([]models.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Audit", ctx, customerID)
+	ret0, _ := ret[0].([]models.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Audit indicates an expected call of Audit.
+func (mr *MockGDPRServiceInMockRecorder) Audit(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Audit", reflect.TypeOf((*MockGDPRServiceIn)(nil).Audit), ctx, customerID)
+}
+
+// Erase mocks base method.
+func (m *MockGDPRServiceIn) Erase(ctx *gofr.Context, customerID int) (models.Erasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", ctx, customerID)
+	ret0, _ := ret[0].(models.Erasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockGDPRServiceInMockRecorder) Erase(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockGDPRServiceIn)(nil).Erase), ctx, customerID)
+}
+
+// Erasures mocks base method.
+func (m *MockGDPRServiceIn) Erasures(ctx *gofr.Context) ([]models.Erasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erasures", ctx)
+	ret0, _ := ret[0].([]models.Erasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erasures indicates an expected call of Erasures.
+func (mr *MockGDPRServiceInMockRecorder) Erasures(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erasures", reflect.TypeOf((*MockGDPRServiceIn)(nil).Erasures), ctx)
+}
+
+// Merged mocks base method.
+func (m *MockGDPRServiceIn) Merged(ctx *gofr.Context, customerID int) ([]models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merged", ctx, customerID)
+	ret0, _ := ret[0].([]models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merged indicates an expected call of Merged.
+func (mr *MockGDPRServiceInMockRecorder) Merged(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merged", reflect.TypeOf((*MockGDPRServiceIn)(nil).Merged), ctx, customerID)
+}
+
+// MockKeyServiceIn is a mock of KeyServiceIn interface.
+type MockKeyServiceIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyServiceInMockRecorder
+}
+
+// MockKeyServiceInMockRecorder is the mock recorder for MockKeyServiceIn.
+type MockKeyServiceInMockRecorder struct {
+	mock *MockKeyServiceIn
+}
+
+// NewMockKeyServiceIn creates a new mock instance.
+func NewMockKeyServiceIn(ctrl *gomock.Controller) *MockKeyServiceIn {
+	mock := &MockKeyServiceIn{ctrl: ctrl}
+	mock.recorder = &MockKeyServiceInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyServiceIn) EXPECT() *MockKeyServiceInMockRecorder {
+	return m.recorder
+}
+
+// Reencrypt mocks base method.
+func (m *MockKeyServiceIn) Reencrypt(ctx *gofr.Context, batch int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reencrypt", ctx, batch)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reencrypt indicates an expected call of Reencrypt.
+func (mr *MockKeyServiceInMockRecorder) Reencrypt(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reencrypt", reflect.TypeOf((*MockKeyServiceIn)(nil).Reencrypt), ctx, batch)
+}
+
+// Rotate mocks base method.
+func (m *MockKeyServiceIn) Rotate(ctx *gofr.Context) (models.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx)
+	ret0, _ := ret[0].(models.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockKeyServiceInMockRecorder) Rotate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockKeyServiceIn)(nil).Rotate), ctx)
+}
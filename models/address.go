@@ -0,0 +1,22 @@
+package models
+
+const (
+	AddressBilling  = "billing"
+	AddressShipping = "shipping"
+)
+
+// Address belongs to a single customer. Country is an ISO 3166-1 alpha-2 code.
+type Address struct {
+	ID         int    `json:"id,omitempty"`
+	CustomerID int    `json:"customerId,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Street     string `json:"street,omitempty" log:"redact"`
+	City       string `json:"city,omitempty"`
+	Country    string `json:"country,omitempty"`
+	PostalCode string `json:"postalCode,omitempty" log:"redact"`
+}
+
+// Redacted returns the address as log fields with the sensitive ones masked.
+func (a Address) Redacted() map[string]interface{} {
+	return redact(a)
+}
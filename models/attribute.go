@@ -0,0 +1,236 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// AttributeAdminScope lets a caller define and remove the custom attributes of its tenant.
+const AttributeAdminScope = "customer:attributes:admin"
+
+// AttributeType is the JSON type of the values of a custom attribute. Dates are strings formatted as YYYY-MM-DD.
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+	AttributeDate    AttributeType = "date"
+)
+
+// AttributeTypes are the types an attribute can have.
+var AttributeTypes = []AttributeType{AttributeString, AttributeNumber, AttributeBoolean, AttributeDate}
+
+// attributeName matches attribute names, e.g. loyaltyTier or cost_centre.
+var attributeName = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]{0,63}$`)
+
+// Attributes are the custom attributes of a customer, keyed by name. Values are decoded from JSON, so numbers
+// are float64. In a patch a nil value removes the attribute.
+type Attributes map[string]interface{}
+
+// Value stores the attributes as a JSON object.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(map[string]interface{}(a))
+
+	return string(b), err
+}
+
+// Scan reads a JSON object. A customer without attributes is read as nil.
+func (a *Attributes) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into attributes", src)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	if len(m) == 0 {
+		m = nil
+	}
+
+	*a = m
+
+	return nil
+}
+
+// AttributeDefinition defines a custom attribute of the customers of a tenant. Enum restricts the values of a
+// string attribute.
+type AttributeDefinition struct {
+	ID        int           `json:"id,omitempty"`
+	Name      string        `json:"name"`
+	Type      AttributeType `json:"type"`
+	Required  bool          `json:"required"`
+	Enum      []string      `json:"enum,omitempty"`
+	CreatedAt *time.Time    `json:"createdAt,omitempty"`
+}
+
+// Validate checks that the definition has a valid name and type, and that only string attributes have an enum.
+func (d AttributeDefinition) Validate() error {
+	if !attributeName.MatchString(d.Name) {
+		return errors.InvalidParam{Param: []string{"name"}}
+	}
+
+	valid := false
+	for _, t := range AttributeTypes {
+		valid = valid || t == d.Type
+	}
+
+	if !valid {
+		return errors.InvalidParam{Param: []string{"type"}}
+	}
+
+	if len(d.Enum) > 0 && d.Type != AttributeString {
+		return errors.InvalidParam{Param: []string{"enum"}}
+	}
+
+	seen := map[string]bool{}
+
+	for _, v := range d.Enum {
+		if v == "" || seen[v] {
+			return errors.InvalidParam{Param: []string{"enum"}}
+		}
+		seen[v] = true
+	}
+
+	return nil
+}
+
+// Accepts reports whether v is a value of the attribute.
+func (d AttributeDefinition) Accepts(v interface{}) bool {
+	switch d.Type {
+	case AttributeNumber:
+		_, ok := v.(float64)
+		return ok
+	case AttributeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case AttributeDate:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+
+		_, err := time.Parse(dateLayout, s)
+
+		return err == nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+
+	if len(d.Enum) == 0 {
+		return true
+	}
+
+	for _, e := range d.Enum {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse reads a value of the attribute from a query parameter.
+func (d AttributeDefinition) Parse(s string) (interface{}, bool) {
+	var v interface{} = s
+
+	switch d.Type {
+	case AttributeNumber:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, false
+		}
+		v = f
+	case AttributeBoolean:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, false
+		}
+		v = b
+	}
+
+	return v, d.Accepts(v)
+}
+
+// AttributeSchema holds the attribute definitions of a tenant.
+type AttributeSchema []AttributeDefinition
+
+func (s AttributeSchema) definition(name string) (AttributeDefinition, bool) {
+	for _, d := range s {
+		if d.Name == name {
+			return d, true
+		}
+	}
+
+	return AttributeDefinition{}, false
+}
+
+// Validate checks attributes against the schema. Every attribute must be defined and hold a value of its type.
+// Unless partial, every required attribute must be given. A partial update may remove optional attributes
+// with a nil value. Invalid attributes are named as attributes.<name>.
+func (s AttributeSchema) Validate(a Attributes, partial bool) error {
+	for name, v := range a {
+		d, ok := s.definition(name)
+		if !ok || v == nil && (!partial || d.Required) || v != nil && !d.Accepts(v) {
+			return errors.InvalidParam{Param: []string{"attributes." + name}}
+		}
+	}
+
+	if partial {
+		return nil
+	}
+
+	for _, d := range s {
+		if _, ok := a[d.Name]; d.Required && !ok {
+			return errors.MissingParam{Param: []string{"attributes." + d.Name}}
+		}
+	}
+
+	return nil
+}
+
+// Typed converts the attribute values of a filter, which are read as strings, to the types of their
+// definitions. Unknown attributes and values of the wrong type are rejected as attr.<name>.
+func (s AttributeSchema) Typed(filter Attributes) (Attributes, error) {
+	res := make(Attributes, len(filter))
+
+	for name, v := range filter {
+		d, ok := s.definition(name)
+		if !ok {
+			return nil, errors.InvalidParam{Param: []string{AttributeParam + name}}
+		}
+
+		str, _ := v.(string)
+
+		if res[name], ok = d.Parse(str); !ok {
+			return nil, errors.InvalidParam{Param: []string{AttributeParam + name}}
+		}
+	}
+
+	return res, nil
+}
@@ -1,8 +1,36 @@
 package models
 
+import "time"
+
 type Customer struct {
-	ID     int    `json:"id,omitempty"`
-	Name   string `json:"name,omitempty"`
+	ID          int    `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Email       string `json:"email,omitempty" log:"redact"`
+	Phone       string `json:"phone,omitempty" log:"redact"`
+	DateOfBirth *Date  `json:"dateOfBirth,omitempty" log:"redact"`
+	// Age is computed from DateOfBirth whenever a customer is read. It is never stored.
 	Age    int    `json:"age,omitempty"`
-	Salary int    `json:"salary,omitempty"`
+	Salary *Money `json:"salary,omitempty" log:"redact" scope:"customer:salary"`
+	// Attributes are validated against the attribute schema of the tenant. They are kept as stored when a
+	// replacing write leaves them out. Business units may keep personal data in them, so they are not logged.
+	Attributes Attributes `json:"attributes,omitempty" log:"redact"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	// Status is ignored on writes. It only changes through a Transition.
+	Status Status `json:"status,omitempty"`
+}
+
+// Redacted returns the customer as log fields with the sensitive ones masked.
+func (c Customer) Redacted() map[string]interface{} {
+	return redact(c)
+}
+
+// WithAge returns the customer with Age set to its age on the given day.
+func (c Customer) WithAge(now time.Time) Customer {
+	c.Age = 0
+	if c.DateOfBirth != nil {
+		c.Age = c.DateOfBirth.AgeAt(now)
+	}
+
+	return c
 }
@@ -0,0 +1,590 @@
+package models
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCustomer_Redacted(t *testing.T) {
+	dob := NewDate(2000, time.March, 14)
+	c := Customer{ID: 1, Name: "Divya", Email: "divya@example.com", DateOfBirth: &dob, Age: 22,
+		Salary: &Money{Minor: 3000000, Currency: "INR"}, Attributes: Attributes{"plan": "gold"}}
+
+	expected := map[string]interface{}{"id": 1, "name": "Divya", "email": redactedValue, "phone": redactedValue,
+		"dateOfBirth": redactedValue, "age": 22, "salary": redactedValue, "attributes": redactedValue,
+		"createdAt": (*time.Time)(nil), "updatedAt": (*time.Time)(nil), "status": Status("")}
+
+	if res := c.Redacted(); !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %v\nGot %v", expected, res)
+	}
+}
+
+func TestCustomer_Visibility(t *testing.T) {
+	salary := &Money{Minor: 3000000, Currency: "INR"}
+	c := Customer{ID: 1, Name: "Divya", Salary: salary}
+
+	tests := []struct {
+		desc       string
+		scopes     map[string]bool
+		masked     Customer
+		unwritable []string
+	}{
+		{"no scopes", nil, Customer{ID: 1, Name: "Divya"}, []string{"salary"}},
+		{"read only", map[string]bool{"customer:salary:read": true}, c, []string{"salary"}},
+		{"write only", map[string]bool{"customer:salary:write": true}, Customer{ID: 1, Name: "Divya"}, nil},
+	}
+
+	for i, tc := range tests {
+		granted := func(scope string) bool { return tc.scopes[scope] }
+
+		if res := c.Masked(granted); !reflect.DeepEqual(res, tc.masked) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.masked, res)
+		}
+
+		if res := c.Unwritable(granted); !reflect.DeepEqual(res, tc.unwritable) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.unwritable, res)
+		}
+	}
+
+	if c.Salary != salary {
+		t.Errorf("Masked changed the customer it was called on")
+	}
+
+	if res := (Customer{ID: 1}).Unwritable(func(string) bool { return false }); res != nil {
+		t.Errorf("an unset salary needs no scope, got %v", res)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected []string
+		err      error
+	}{
+		{"every field", "", nil, nil},
+		{"id and name", "id,name", []string{"id", "name"}, nil},
+		{"spaces and repeats", "name, id ,name", []string{"name", "id"}, nil},
+		{"unknown field", "id,tenant_id", nil, errors.InvalidParam{Param: []string{"fields"}}},
+		{"go field name", "Name", nil, errors.InvalidParam{Param: []string{"fields"}}},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseFields(tc.input)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestCustomer_Project(t *testing.T) {
+	c := Customer{ID: 1, Name: "Divya", Salary: &Money{Minor: 3000000, Currency: "INR"}}
+
+	expected := map[string]interface{}{"id": 1, "phone": "", "salary": c.Salary}
+
+	if res := c.Project([]string{"id", "phone", "salary"}); !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %v\nGot %v", expected, res)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	for _, name := range []string{"Jay", "Jay ", "jay", " J.A.Y", "JAY\t"} {
+		if res := NormalizeName(name); res != "jay" {
+			t.Errorf("%q: Expected jay\nGot %q", name, res)
+		}
+	}
+
+	if res := NormalizeName("  Divya   Sharma "); res != "divya sharma" {
+		t.Errorf("Expected divya sharma\nGot %q", res)
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		desc     string
+		a, b     string
+		expected float64
+	}{
+		{"equal", "jay", "jay", 1},
+		{"one typo", "divya", "divia", 0.8},
+		{"one missing letter", "karan", "karn", 0.8},
+		{"nothing in common", "jay", "bob", 0},
+		{"both empty", "", "", 1},
+		{"one empty", "jay", "", 0},
+	}
+
+	for i, tc := range tests {
+		if res := NameSimilarity(tc.a, tc.b); res != tc.expected {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestParseMergeRules(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected MergeRules
+		valid    bool
+	}{
+		{"defaults", "", MergeRules{}, true},
+		{"rules", "email=newest, salary=survivor", MergeRules{"email": TakeNewest, "salary": KeepSurvivor}, true},
+		{"unknown field", "name=newest", nil, false},
+		{"unknown rule", "email=oldest", nil, false},
+		{"missing rule", "email", nil, false},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseMergeRules(tc.input)
+		if (err == nil) != tc.valid {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected valid %v\nGot %v", i+1, tc.desc, tc.valid, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestMergeRules_Merge(t *testing.T) {
+	old, recent := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	dob := NewDate(2000, time.March, 14)
+
+	survivor := Customer{ID: 1, Name: "Jay", Email: "jay@example.com", UpdatedAt: &old}
+	losers := []Customer{
+		{ID: 2, Name: "jay", Phone: "+919876543211", UpdatedAt: &old},
+		{ID: 3, Name: "Jay ", Email: "jay@example.org", Phone: "+919876543212", DateOfBirth: &dob, UpdatedAt: &recent},
+	}
+
+	tests := []struct {
+		desc     string
+		rules    MergeRules
+		expected Customer
+	}{
+		{"fill", MergeRules{},
+			Customer{ID: 1, Name: "Jay", Email: "jay@example.com", Phone: "+919876543211", DateOfBirth: &dob, UpdatedAt: &old}},
+		{"newest", MergeRules{"email": TakeNewest, "phone": TakeNewest},
+			Customer{ID: 1, Name: "Jay", Email: "jay@example.org", Phone: "+919876543212", DateOfBirth: &dob, UpdatedAt: &old}},
+		{"survivor", MergeRules{"phone": KeepSurvivor, "dateOfBirth": KeepSurvivor},
+			Customer{ID: 1, Name: "Jay", Email: "jay@example.com", UpdatedAt: &old}},
+	}
+
+	for i, tc := range tests {
+		if res := tc.rules.Merge(survivor, losers); !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %+v\nGot %+v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestStatus_CanTransition(t *testing.T) {
+	allowed := map[Status][]Status{
+		StatusProspect:  {StatusActive, StatusClosed},
+		StatusActive:    {StatusSuspended, StatusClosed},
+		StatusSuspended: {StatusActive, StatusClosed},
+	}
+
+	for _, from := range Statuses {
+		for _, to := range Statuses {
+			expected := false
+			for _, s := range allowed[from] {
+				expected = expected || s == to
+			}
+
+			if res := from.CanTransition(to); res != expected {
+				t.Errorf("%v to %v: Expected %v\nGot %v", from, to, expected, res)
+			}
+		}
+	}
+
+	if Status("churned").Valid() || Status("").CanTransition(StatusActive) {
+		t.Error("unknown statuses must be invalid and have no transitions")
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		desc     string
+		query    url.Values
+		expected Filter
+		err      error
+	}{
+		{"empty", url.Values{}, Filter{}, nil},
+		{"every filter", url.Values{"name": {"Divya"}, "status": {"active"}, "minAge": {"20"}, "maxAge": {"30"},
+			"limit": {"10"}, "offset": {"5"}},
+			Filter{Name: "Divya", Status: StatusActive, MinAge: 20, MaxAge: 30, Limit: 10, Offset: 5}, nil},
+		{"tags", url.Values{"tag": {"vip", "!churned", "b2b"}},
+			Filter{Tags: []string{"vip", "b2b"}, ExcludedTags: []string{"churned"}}, nil},
+		{"invalid tag", url.Values{"tag": {"VIP"}}, Filter{}, errors.InvalidParam{Param: []string{"tag"}}},
+		{"attributes", url.Values{"attr.plan": {"gold"}, "attr.seats": {"5"}},
+			Filter{Attributes: Attributes{"plan": "gold", "seats": "5"}}, nil},
+		{"bare negation", url.Values{"tag": {"!"}}, Filter{}, errors.InvalidParam{Param: []string{"tag"}}},
+		{"negative age", url.Values{"minAge": {"-1"}}, Filter{}, errors.InvalidParam{Param: []string{"minAge"}}},
+		{"unknown status", url.Values{"status": {"churned"}}, Filter{}, errors.InvalidParam{Param: []string{"status"}}},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseFilter(tc.query)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestParseSegmentFilter(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected Filter
+		err      error
+	}{
+		{"every customer", "", Filter{}, nil},
+		{"tags and age", "tag=vip&tag=!churned&minAge=30", Filter{Tags: []string{"vip"}, ExcludedTags: []string{"churned"},
+			MinAge: 30}, nil},
+		{"attributes", "attr.plan=gold", Filter{Attributes: Attributes{"plan": "gold"}}, nil},
+		{"paging", "tag=vip&limit=10", Filter{}, errors.InvalidParam{Param: []string{"filter"}}},
+		{"unknown parameter", "city=Pune", Filter{}, errors.InvalidParam{Param: []string{"filter"}}},
+		{"malformed", "tag=%zz", Filter{}, errors.InvalidParam{Param: []string{"filter"}}},
+		{"invalid filter", "maxAge=old", Filter{}, errors.InvalidParam{Param: []string{"maxAge"}}},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseSegmentFilter(tc.input)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestCustomer_WithAge(t *testing.T) {
+	dob := NewDate(2000, time.March, 14)
+	now := time.Date(2022, time.March, 13, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc     string
+		customer Customer
+		now      time.Time
+		expected int
+	}{
+		{"day before birthday", Customer{DateOfBirth: &dob}, now, 21},
+		{"on birthday", Customer{DateOfBirth: &dob}, now.Add(time.Hour), 22},
+		{"unknown date of birth", Customer{Age: 30}, now, 0},
+	}
+
+	for i, tc := range tests {
+		if res := tc.customer.WithAge(tc.now).Age; res != tc.expected {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	var c Customer
+
+	if err := json.Unmarshal([]byte(`{"dateOfBirth":"2000-03-14"}`), &c); err != nil || c.DateOfBirth == nil {
+		t.Fatalf("failed to unmarshal the date of birth: %v", err)
+	}
+
+	b, _ := json.Marshal(c)
+	if string(b) != `{"dateOfBirth":"2000-03-14"}` {
+		t.Errorf("Expected %v\nGot %v", `{"dateOfBirth":"2000-03-14"}`, string(b))
+	}
+
+	if err := json.Unmarshal([]byte(`{"dateOfBirth":"14/03/2000"}`), &c); err == nil {
+		t.Errorf("Expected an error for an invalid date")
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		desc     string
+		amount   string
+		currency string
+		expected Money
+		err      error
+	}{
+		{"whole amount", "30000", "INR", Money{Minor: 3000000, Currency: "INR"}, nil},
+		{"paise", "30000.5", "INR", Money{Minor: 3000050, Currency: "INR"}, nil},
+		{"no minor unit", "500", "JPY", Money{Minor: 500, Currency: "JPY"}, nil},
+		{"three decimals", "1.234", "BHD", Money{Minor: 1234, Currency: "BHD"}, nil},
+		{"negative", "-0.01", "USD", Money{Minor: -1, Currency: "USD"}, nil},
+		{"too many decimals", "30000.005", "INR", Money{}, errors.InvalidParam{Param: []string{"amount"}}},
+		{"decimals without minor unit", "500.0", "JPY", Money{}, errors.InvalidParam{Param: []string{"amount"}}},
+		{"not a number", "30,000", "INR", Money{}, errors.InvalidParam{Param: []string{"amount"}}},
+		{"empty amount", "", "INR", Money{}, errors.InvalidParam{Param: []string{"amount"}}},
+		{"unknown currency", "30000", "XYZ", Money{}, errors.InvalidParam{Param: []string{"currency"}}},
+	}
+
+	for i, tc := range tests {
+		res, err := ParseMoney(tc.amount, tc.currency)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if res != tc.expected {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var c Customer
+
+	body := `{"salary":{"amount":"30000.00","currency":"INR"}}`
+	if err := json.Unmarshal([]byte(`{"salary":{"amount":"30000","currency":"INR"}}`), &c); err != nil || c.Salary == nil {
+		t.Fatalf("failed to unmarshal the salary: %v", err)
+	}
+
+	b, _ := json.Marshal(c)
+	if string(b) != body {
+		t.Errorf("Expected %v\nGot %v", body, string(b))
+	}
+
+	if err := json.Unmarshal([]byte(`{"salary":{"amount":30000,"currency":"INR"}}`), &c); err == nil {
+		t.Errorf("Expected an error for a numeric amount")
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	inr := Money{Minor: 150, Currency: "INR"}
+
+	if res, err := inr.Add(inr); err != nil || res != (Money{Minor: 300, Currency: "INR"}) {
+		t.Errorf("Expected 3.00 INR\nGot %v, %v", res, err)
+	}
+
+	if _, err := inr.Add(Money{Minor: 150, Currency: "USD"}); err == nil {
+		t.Errorf("Expected an error when adding different currencies")
+	}
+}
+
+func TestAttributes_Scan(t *testing.T) {
+	tests := []struct {
+		desc     string
+		src      interface{}
+		expected Attributes
+		err      bool
+	}{
+		{"object", []byte(`{"plan":"gold","seats":5}`), Attributes{"plan": "gold", "seats": float64(5)}, false},
+		{"empty object", []byte("{}"), nil, false},
+		{"null", nil, nil, false},
+		{"not an object", "[1]", nil, true},
+	}
+
+	for i, tc := range tests {
+		var res Attributes
+
+		err := res.Scan(tc.src)
+		if (err != nil) != tc.err {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected error %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestAttributeDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		desc  string
+		input AttributeDefinition
+		err   error
+	}{
+		{"string with enum", AttributeDefinition{Name: "plan", Type: AttributeString, Enum: []string{"gold", "silver"}}, nil},
+		{"date", AttributeDefinition{Name: "renewal_date", Type: AttributeDate, Required: true}, nil},
+		{"invalid name", AttributeDefinition{Name: "Plan", Type: AttributeString}, errors.InvalidParam{Param: []string{"name"}}},
+		{"unknown type", AttributeDefinition{Name: "plan", Type: "list"}, errors.InvalidParam{Param: []string{"type"}}},
+		{"enum of numbers", AttributeDefinition{Name: "seats", Type: AttributeNumber, Enum: []string{"1"}},
+			errors.InvalidParam{Param: []string{"enum"}}},
+		{"repeated enum value", AttributeDefinition{Name: "plan", Type: AttributeString, Enum: []string{"gold", "gold"}},
+			errors.InvalidParam{Param: []string{"enum"}}},
+	}
+
+	for i, tc := range tests {
+		if err := tc.input.Validate(); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+	}
+}
+
+func TestAttributeSchema_Validate(t *testing.T) {
+	schema := AttributeSchema{
+		{Name: "plan", Type: AttributeString, Required: true, Enum: []string{"gold", "silver"}},
+		{Name: "seats", Type: AttributeNumber},
+		{Name: "trial", Type: AttributeBoolean},
+		{Name: "renewal", Type: AttributeDate},
+	}
+
+	tests := []struct {
+		desc    string
+		input   Attributes
+		partial bool
+		err     error
+	}{
+		{"every type", Attributes{"plan": "gold", "seats": float64(5), "trial": false, "renewal": "2023-01-31"}, false, nil},
+		{"missing required", Attributes{"seats": float64(5)}, false, errors.MissingParam{Param: []string{"attributes.plan"}}},
+		{"partial without required", Attributes{"seats": float64(5)}, true, nil},
+		{"removed optional", Attributes{"seats": nil}, true, nil},
+		{"removed required", Attributes{"plan": nil}, true, errors.InvalidParam{Param: []string{"attributes.plan"}}},
+		{"null on replace", Attributes{"plan": "gold", "seats": nil}, false, errors.InvalidParam{Param: []string{"attributes.seats"}}},
+		{"unknown attribute", Attributes{"plan": "gold", "region": "north"}, false,
+			errors.InvalidParam{Param: []string{"attributes.region"}}},
+		{"outside the enum", Attributes{"plan": "bronze"}, false, errors.InvalidParam{Param: []string{"attributes.plan"}}},
+		{"wrong type", Attributes{"plan": "gold", "seats": "5"}, false, errors.InvalidParam{Param: []string{"attributes.seats"}}},
+		{"invalid date", Attributes{"plan": "gold", "renewal": "31-01-2023"}, false,
+			errors.InvalidParam{Param: []string{"attributes.renewal"}}},
+	}
+
+	for i, tc := range tests {
+		if err := schema.Validate(tc.input, tc.partial); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+	}
+}
+
+func TestAttributeSchema_Typed(t *testing.T) {
+	schema := AttributeSchema{
+		{Name: "plan", Type: AttributeString, Enum: []string{"gold", "silver"}},
+		{Name: "seats", Type: AttributeNumber},
+		{Name: "trial", Type: AttributeBoolean},
+	}
+
+	tests := []struct {
+		desc     string
+		input    Attributes
+		expected Attributes
+		err      error
+	}{
+		{"typed", Attributes{"plan": "gold", "seats": "5", "trial": "true"},
+			Attributes{"plan": "gold", "seats": float64(5), "trial": true}, nil},
+		{"unknown attribute", Attributes{"region": "north"}, nil, errors.InvalidParam{Param: []string{"attr.region"}}},
+		{"not a number", Attributes{"seats": "five"}, nil, errors.InvalidParam{Param: []string{"attr.seats"}}},
+		{"outside the enum", Attributes{"plan": "bronze"}, nil, errors.InvalidParam{Param: []string{"attr.plan"}}},
+	}
+
+	for i, tc := range tests {
+		res, err := schema.Typed(tc.input)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestRelationshipType(t *testing.T) {
+	tests := []struct {
+		input        RelationshipType
+		valid        bool
+		hierarchical bool
+	}{
+		{RelationshipParent, true, true},
+		{RelationshipReferral, true, true},
+		{RelationshipHousehold, true, false},
+		{"sibling", false, false},
+	}
+
+	for i, tc := range tests {
+		if tc.input.Valid() != tc.valid {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.input, tc.valid, !tc.valid)
+		}
+
+		if tc.input.Hierarchical() != tc.hierarchical {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.input, tc.hierarchical, !tc.hierarchical)
+		}
+	}
+}
+
+func TestVerifyErasures(t *testing.T) {
+	erasedAt := time.Date(2022, time.March, 14, 10, 0, 0, 0, time.UTC)
+
+	first := Erasure{ID: 1, CustomerID: 1, Caller: "divya-zs", ErasedAt: erasedAt, PrevHash: GenesisHash}
+	first.Hash = first.Digest("zopsmart")
+	second := Erasure{ID: 2, CustomerID: 2, Caller: "divya-zs", ErasedAt: erasedAt, PrevHash: first.Hash}
+	second.Hash = second.Digest("zopsmart")
+
+	changed := first
+	changed.CustomerID = 3
+
+	tests := []struct {
+		desc     string
+		tenant   string
+		input    []Erasure
+		intact   bool
+		brokenAt int
+	}{
+		{"empty", "zopsmart", nil, true, 0},
+		{"chained", "zopsmart", []Erasure{first, second}, true, 0},
+		{"changed", "zopsmart", []Erasure{changed, second}, false, 1},
+		{"removed", "zopsmart", []Erasure{second}, false, 2},
+		{"other tenant", "acme", []Erasure{first}, false, 1},
+	}
+
+	for i, tc := range tests {
+		res := VerifyErasures(tc.tenant, tc.input)
+		if res.Intact != tc.intact || res.BrokenAt != tc.brokenAt {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v %v\nGot %v %v", i+1, tc.desc, tc.intact, tc.brokenAt,
+				res.Intact, res.BrokenAt)
+		}
+	}
+}
+
+func TestSummariseSalaries(t *testing.T) {
+	inr := func(minor int64) Money { return Money{Minor: minor, Currency: "INR"} }
+	usd := func(minor int64) Money { return Money{Minor: minor, Currency: "USD"} }
+
+	tests := []struct {
+		desc     string
+		input    []Money
+		expected []SalaryStats
+	}{
+		{"none", nil, []SalaryStats{}},
+		{"per currency", []Money{usd(1), inr(300), inr(100), usd(2), inr(400), inr(200)}, []SalaryStats{
+			{Currency: "INR", Count: 4, Min: inr(100), Max: inr(400), Avg: inr(250), P50: inr(200), P90: inr(400), P99: inr(400)},
+			{Currency: "USD", Count: 2, Min: usd(1), Max: usd(2), Avg: usd(2), P50: usd(1), P90: usd(2), P99: usd(2)},
+		}},
+	}
+
+	for i, tc := range tests {
+		res := SummariseSalaries(tc.input)
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
+
+func TestSummariseAges(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    []int
+		expected AgeStats
+	}{
+		{"none", nil, AgeStats{}},
+		{"one", []int{30}, AgeStats{Count: 1, Min: 30, Max: 30, Avg: 30, P50: 30, P90: 30, P99: 30}},
+		{"unsorted", []int{40, 20, 30, 25}, AgeStats{Count: 4, Min: 20, Max: 40, Avg: 28.75, P50: 25, P90: 40, P99: 40}},
+	}
+
+	for i, tc := range tests {
+		res := SummariseAges(tc.input)
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Duplicate is a pair of customers that are likely the same person. Score is the similarity of their
+// normalised names, from 0 to 1.
+type Duplicate struct {
+	Customers [2]Customer `json:"customers"`
+	Score     float64     `json:"score"`
+}
+
+// NormalizeName folds the variations of a name that people do not tell apart: case, punctuation and
+// surrounding or repeated whitespace. "Jay ", "jay" and "J.A.Y" all normalise to "jay".
+func NormalizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r):
+			return ' '
+		}
+
+		return -1
+	}, name)
+
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NameSimilarity scores how alike two normalised names are, from 0 for nothing in common to 1 for equal names.
+// It is one minus their edit distance relative to the longer name.
+func NameSimilarity(a, b string) float64 {
+	x, y := []rune(a), []rune(b)
+
+	longest := len(x)
+	if len(y) > longest {
+		longest = len(y)
+	}
+
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(editDistance(x, y))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between a and b, computed a row at a time.
+func editDistance(a, b []rune) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := range a {
+		cur[0] = i + 1
+
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	res := values[0]
+	for _, v := range values[1:] {
+		if v < res {
+			res = v
+		}
+	}
+
+	return res
+}
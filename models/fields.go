@@ -0,0 +1,105 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// CustomerFields are the json names of the fields of Customer, the only ones a projection may select.
+var CustomerFields = fieldNames(reflect.TypeOf(Customer{}))
+
+// ParseFields reads a comma separated projection such as "id,name". Fields are returned once, in the order
+// given. An empty projection selects every field and is returned as nil.
+func ParseFields(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var (
+		fields []string
+		seen   = map[string]bool{}
+	)
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if !isCustomerField(name) {
+			return nil, errors.InvalidParam{Param: []string{"fields"}}
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			fields = append(fields, name)
+		}
+	}
+
+	return fields, nil
+}
+
+// Project returns only the given fields of the customer, keyed by their json names. Selected fields are kept
+// even when they are empty, so that a client can tell them from the fields it did not ask for.
+func (c Customer) Project(fields []string) map[string]interface{} {
+	val := reflect.ValueOf(c)
+	t := val.Type()
+	res := make(map[string]interface{}, len(fields))
+
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+
+		for _, f := range fields {
+			if f == name {
+				res[name] = val.Field(i).Interface()
+			}
+		}
+	}
+
+	return res
+}
+
+// ProjectAll projects every customer as Project does.
+func ProjectAll(customers []Customer, fields []string) []map[string]interface{} {
+	res := make([]map[string]interface{}, len(customers))
+
+	for i := range customers {
+		res[i] = customers[i].Project(fields)
+	}
+
+	return res
+}
+
+func isCustomerField(name string) bool {
+	for _, f := range CustomerFields {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fieldNames(t reflect.Type) []string {
+	var names []string
+
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// jsonName returns the name a field is marshalled under, or "" when it is never marshalled.
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "-" || f.PkgPath != "" {
+		return ""
+	}
+
+	if name == "" {
+		return f.Name
+	}
+
+	return name
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// Filter narrows down and pages the customers returned by a listing.
+type Filter struct {
+	Name   string
+	MinAge int
+	MaxAge int
+	Status Status
+	// Tags are the tags a customer must all have, ExcludedTags the ones it must have none of.
+	Tags         []string
+	ExcludedTags []string
+	// Attributes are the custom attributes a customer must have, with these values. They are read as strings,
+	// and typed by their definitions before they reach the store.
+	Attributes Attributes
+	Limit      int
+	Offset     int
+	// Fields are the json names of the customer fields to read. Every field is read when it is empty.
+	Fields []string
+}
+
+// AttributeParam prefixes the query parameters filtering on custom attributes, e.g. attr.loyaltyTier=gold.
+const AttributeParam = "attr."
+
+// ParseFilter reads the filters and pagination of a listing from its query parameters: name, status, minAge,
+// maxAge, limit, offset, any number of tag and any attr.<name>. A tag prefixed with "!" excludes the customers
+// that have it, e.g. tag=vip&tag=!churned.
+func ParseFilter(q url.Values) (Filter, error) {
+	filter := Filter{Name: q.Get("name"), Status: Status(q.Get("status"))}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return Filter{}, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	params := []struct {
+		name string
+		dest *int
+	}{
+		{"minAge", &filter.MinAge},
+		{"maxAge", &filter.MaxAge},
+		{"limit", &filter.Limit},
+		{"offset", &filter.Offset},
+	}
+
+	for _, p := range params {
+		value := q.Get(p.name)
+		if value == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return Filter{}, errors.InvalidParam{Param: []string{p.name}}
+		}
+
+		*p.dest = n
+	}
+
+	for _, tag := range q["tag"] {
+		excluded := strings.HasPrefix(tag, "!")
+		tag = strings.TrimPrefix(tag, "!")
+
+		if !ValidTag(tag) {
+			return Filter{}, errors.InvalidParam{Param: []string{"tag"}}
+		}
+
+		if excluded {
+			filter.ExcludedTags = append(filter.ExcludedTags, tag)
+		} else {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	for param, values := range q {
+		if !strings.HasPrefix(param, AttributeParam) {
+			continue
+		}
+
+		if filter.Attributes == nil {
+			filter.Attributes = Attributes{}
+		}
+
+		filter.Attributes[strings.TrimPrefix(param, AttributeParam)] = values[0]
+	}
+
+	return filter, nil
+}
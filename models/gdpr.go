@@ -0,0 +1,83 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// GDPRAdminScope lets a caller export and erase the personal data of the customers of its tenant.
+const GDPRAdminScope = "customer:gdpr:admin"
+
+// Export is everything kept about a customer, as given to the customer on an access request. Salaries are
+// included whatever the scopes of the caller. There are no webhook deliveries to export: the service has no
+// webhooks.
+type Export struct {
+	Customer Customer `json:"customer"`
+	// Merged are the customers merged into this one, which are deleted but still hold its data.
+	Merged        []Customer     `json:"merged"`
+	Addresses     []Address      `json:"addresses"`
+	Transitions   []Transition   `json:"transitions"`
+	Tags          []string       `json:"tags"`
+	Relationships []Relationship `json:"relationships"`
+	Audit         []AuditEntry   `json:"audit"`
+	ExportedAt    time.Time      `json:"exportedAt"`
+}
+
+// AuditEntry is an action recorded in the audit log, e.g. a merge.
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Action     string          `json:"action"`
+	CustomerID int             `json:"customerId"`
+	Detail     json.RawMessage `json:"detail"`
+	Caller     string          `json:"caller"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// Erasure records that the personal data of a customer was erased. Each erasure holds the hash of the one
+// before it in its tenant, so that the log shows when an entry is changed or removed.
+type Erasure struct {
+	ID         int       `json:"id"`
+	CustomerID int       `json:"customerId"`
+	Caller     string    `json:"caller"`
+	ErasedAt   time.Time `json:"erasedAt"`
+	PrevHash   string    `json:"prevHash"`
+	Hash       string    `json:"hash"`
+}
+
+// GenesisHash is the PrevHash of the first erasure of a tenant.
+var GenesisHash = fmt.Sprintf("%064d", 0)
+
+// Digest returns the hash of the erasure in the log of tenant, from every field but its id and own hash.
+func (e Erasure) Digest(tenant string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v|%v|%v|%v|%v", e.PrevHash, tenant, e.CustomerID, e.Caller,
+		e.ErasedAt.UTC().Format(time.RFC3339Nano))))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ErasureLog is the erasure log of a tenant, oldest first.
+type ErasureLog struct {
+	Erasures []Erasure `json:"erasures"`
+	// Intact is false when an erasure does not chain to the one before it.
+	Intact bool `json:"intact"`
+	// BrokenAt is the id of the first erasure that does not chain.
+	BrokenAt int `json:"brokenAt,omitempty"`
+}
+
+// VerifyErasures checks that every erasure of tenant, oldest first, is sealed by its hash and chains to the
+// one before it. Removing the latest erasures cannot be seen from the chain alone.
+func VerifyErasures(tenant string, erasures []Erasure) ErasureLog {
+	prev := GenesisHash
+
+	for _, e := range erasures {
+		if e.PrevHash != prev || e.Digest(tenant) != e.Hash {
+			return ErasureLog{Erasures: erasures, BrokenAt: e.ID}
+		}
+		prev = e.Hash
+	}
+
+	return ErasureLog{Erasures: erasures, Intact: true}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// KeysAdminScope lets a caller rotate the data key that encrypts the customers of its tenant.
+const KeysAdminScope = "customer:keys:admin"
+
+// DataKey is a key that encrypts the names and salaries of the customers of a tenant. Only its id is shown;
+// the key itself never leaves the store.
+type DataKey struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
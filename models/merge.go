@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeRule decides which value of a field the survivor of a merge keeps.
+type MergeRule string
+
+const (
+	// KeepSurvivor keeps the value of the survivor, even when it is empty.
+	KeepSurvivor MergeRule = "survivor"
+	// FillEmpty keeps the value of the survivor, or takes the first value set among the losers when it is empty.
+	FillEmpty MergeRule = "fill"
+	// TakeNewest takes the value of the most recently updated customer that has one.
+	TakeNewest MergeRule = "newest"
+)
+
+// MergeFields are the json names of the fields merged by MergeRules. The id, name and timestamps are always
+// those of the survivor, and the age is computed again.
+var MergeFields = []string{"email", "phone", "dateOfBirth", "salary"}
+
+// MergeRules maps the json names of MergeFields to their rule. Fields without one are merged with FillEmpty.
+type MergeRules map[string]MergeRule
+
+// ParseMergeRules reads rules in the form field=rule, separated by commas, e.g. "email=newest,salary=survivor".
+func ParseMergeRules(s string) (MergeRules, error) {
+	rules := MergeRules{}
+	if strings.TrimSpace(s) == "" {
+		return rules, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 || !isMergeField(kv[0]) {
+			return nil, fmt.Errorf("invalid merge rule %q, expected one of %v=rule", pair, MergeFields)
+		}
+
+		switch rule := MergeRule(kv[1]); rule {
+		case KeepSurvivor, FillEmpty, TakeNewest:
+			rules[kv[0]] = rule
+		default:
+			return nil, fmt.Errorf("invalid merge rule %q, expected %v, %v or %v", pair, KeepSurvivor, FillEmpty, TakeNewest)
+		}
+	}
+
+	return rules, nil
+}
+
+// Merge returns the survivor with its MergeFields merged with those of the losers. Losers are tried in order.
+func (r MergeRules) Merge(survivor Customer, losers []Customer) Customer {
+	all := append([]Customer{survivor}, losers...)
+
+	newest := append([]Customer(nil), all...)
+	sort.SliceStable(newest, func(i, j int) bool {
+		return updatedAfter(newest[i], newest[j])
+	})
+
+	res := reflect.ValueOf(&survivor).Elem()
+	t := res.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if !isMergeField(name) {
+			continue
+		}
+
+		candidates := all
+
+		switch r[name] {
+		case KeepSurvivor:
+			continue
+		case TakeNewest:
+			candidates = newest
+		}
+
+		for _, c := range candidates {
+			if v := reflect.ValueOf(c).Field(i); !v.IsZero() {
+				res.Field(i).Set(v)
+				break
+			}
+		}
+	}
+
+	return survivor
+}
+
+// updatedAfter reports whether a was updated after b. Customers without an update time are the oldest.
+func updatedAfter(a, b Customer) bool {
+	if a.UpdatedAt == nil || b.UpdatedAt == nil {
+		return a.UpdatedAt != nil && b.UpdatedAt == nil
+	}
+
+	return a.UpdatedAt.After(*b.UpdatedAt)
+}
+
+func isMergeField(name string) bool {
+	for _, f := range MergeFields {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MergeRequest merges the losers into the survivor.
+type MergeRequest struct {
+	SurvivorID int   `json:"survivorId"`
+	LoserIDs   []int `json:"loserIds"`
+}
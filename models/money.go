@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// currencies maps the supported ISO 4217 codes to the number of digits of their minor unit.
+var currencies = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "CAD": 2, "CHF": 2, "CNY": 2, "EUR": 2, "GBP": 2,
+	"INR": 2, "JPY": 0, "KWD": 3, "SGD": 2, "USD": 2,
+}
+
+// Money is an amount in the minor unit of its currency, e.g. paise for INR, so that it is never rounded.
+// It is written as {"amount":"30000.00","currency":"INR"} in JSON.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// ValidCurrency reports whether code is a supported ISO 4217 currency code.
+func ValidCurrency(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// ParseMoney parses a decimal amount such as "30000.50" in the given currency. The amount may not
+// have more decimals than the minor unit of the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	digits, ok := currencies[currency]
+	if !ok {
+		return Money{}, errors.InvalidParam{Param: []string{"currency"}}
+	}
+
+	invalid := errors.InvalidParam{Param: []string{"amount"}}
+
+	whole, frac := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		whole, frac = amount[:i], amount[i+1:]
+
+		if frac == "" || len(frac) > digits {
+			return Money{}, invalid
+		}
+	}
+
+	neg := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	if whole == "" || strings.ContainsAny(whole+frac, "+-") {
+		return Money{}, invalid
+	}
+
+	minor, err := strconv.ParseInt(whole+frac+strings.Repeat("0", digits-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, invalid
+	}
+
+	if neg {
+		minor = -minor
+	}
+
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// Amount formats the amount with exactly as many decimals as the minor unit of the currency.
+func (m Money) Amount() string {
+	digits := currencies[m.Currency]
+
+	minor := m.Minor
+
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+
+	s := fmt.Sprintf("%0*d", digits+1, minor)
+	if digits == 0 {
+		return sign + s
+	}
+
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency
+}
+
+// Add sums two amounts of the same currency. Amounts of different currencies are never mixed.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, errors.Error(fmt.Sprintf("cannot add %v to %v", o.Currency, m.Currency))
+	}
+
+	return Money{Minor: m.Minor + o.Minor, Currency: m.Currency}, nil
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import "reflect"
+
+const redactedValue = "[REDACTED]"
+
+// redact turns a model into log fields keyed by their json names. Fields tagged log:"redact" are masked.
+func redact(v interface{}) map[string]interface{} {
+	val := reflect.ValueOf(v)
+	t := val.Type()
+	fields := make(map[string]interface{}, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+
+		if f.Tag.Get("log") == "redact" {
+			fields[name] = redactedValue
+			continue
+		}
+
+		fields[name] = val.Field(i).Interface()
+	}
+
+	return fields
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// RelationshipType is the kind of a relationship between two customers. A relationship is directed, from
+// FromID to ToID.
+type RelationshipType string
+
+const (
+	// RelationshipParent makes ToID the parent company of FromID.
+	RelationshipParent RelationshipType = "parent"
+	// RelationshipReferral records that FromID referred ToID.
+	RelationshipReferral RelationshipType = "referral"
+	// RelationshipHousehold puts FromID and ToID in the same household. Its direction carries no meaning.
+	RelationshipHousehold RelationshipType = "household"
+)
+
+// RelationshipTypes are the types a relationship can have.
+var RelationshipTypes = []RelationshipType{RelationshipParent, RelationshipReferral, RelationshipHousehold}
+
+// Valid reports whether t is a known relationship type.
+func (t RelationshipType) Valid() bool {
+	for _, v := range RelationshipTypes {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Hierarchical reports whether the relationships of type t form a hierarchy, in which no customer may be its
+// own ancestor. A company cannot own its parent, and a customer cannot have referred whoever referred them.
+func (t RelationshipType) Hierarchical() bool {
+	return t == RelationshipParent || t == RelationshipReferral
+}
+
+// Relationship is a typed, directed edge between two customers of a tenant.
+type Relationship struct {
+	ID        int              `json:"id,omitempty"`
+	Type      RelationshipType `json:"type"`
+	FromID    int              `json:"fromId"`
+	ToID      int              `json:"toId"`
+	CreatedAt *time.Time       `json:"createdAt,omitempty"`
+}
+
+// MaxGraphDepth is the largest number of relationships a graph may follow from its customer.
+const MaxGraphDepth = 5
+
+// Graph is the part of the relationship graph within some depth of a customer: the customers reached, and the
+// relationships between them in either direction.
+type Graph struct {
+	Customers     []Customer     `json:"customers"`
+	Relationships []Relationship `json:"relationships"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// segmentParams are the query parameters a segment filter may use besides attr.<name>. Paging is left to
+// whoever evaluates it.
+var segmentParams = map[string]bool{"name": true, "status": true, "minAge": true, "maxAge": true, "tag": true}
+
+// Segment is a named filter over the customers of a tenant. Filter is written as the query string of
+// GET /customer, e.g. "tag=vip&tag=!churned&minAge=30", and evaluated whenever the segment is read.
+type Segment struct {
+	ID        int        `json:"id,omitempty"`
+	Name      string     `json:"name"`
+	Filter    string     `json:"filter"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ParseSegmentFilter reads the filter of a segment. Parameters other than the filters of a listing, including
+// limit and offset, are rejected.
+func ParseSegmentFilter(s string) (Filter, error) {
+	q, err := url.ParseQuery(s)
+	if err != nil {
+		return Filter{}, errors.InvalidParam{Param: []string{"filter"}}
+	}
+
+	for name := range q {
+		if !segmentParams[name] && !strings.HasPrefix(name, AttributeParam) {
+			return Filter{}, errors.InvalidParam{Param: []string{"filter"}}
+		}
+	}
+
+	return ParseFilter(q)
+}
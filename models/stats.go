@@ -0,0 +1,111 @@
+package models
+
+import "sort"
+
+// Stats summarises the customers matching a filter.
+type Stats struct {
+	Count int      `json:"count"`
+	Age   AgeStats `json:"age"`
+	// Salary has one entry per currency, since amounts in different currencies are never aggregated together.
+	Salary       []SalaryStats `json:"salary" scope:"customer:salary"`
+	AgeHistogram []AgeBucket   `json:"ageHistogram"`
+}
+
+// AgeStats summarises the ages of the customers whose date of birth is known.
+type AgeStats struct {
+	Count int     `json:"count"`
+	Min   int     `json:"min"`
+	Max   int     `json:"max"`
+	Avg   float64 `json:"avg"`
+	P50   int     `json:"p50"`
+	P90   int     `json:"p90"`
+	P99   int     `json:"p99"`
+}
+
+// SalaryStats summarises the salaries paid in one currency. The average is rounded to the minor unit.
+type SalaryStats struct {
+	Currency string `json:"currency"`
+	Count    int    `json:"count"`
+	Min      Money  `json:"min"`
+	Max      Money  `json:"max"`
+	Avg      Money  `json:"avg"`
+	P50      Money  `json:"p50"`
+	P90      Money  `json:"p90"`
+	P99      Money  `json:"p99"`
+}
+
+// AgeBucket counts the customers aged from Min up to, but excluding, Max. The last bucket has no Max.
+type AgeBucket struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max,omitempty"`
+	Count int  `json:"count"`
+}
+
+// SummariseSalaries summarises salaries the way the database does: one entry per currency in currency order,
+// averages rounded half up, and percentiles that are one of the salaries. It serves the salaries the database
+// cannot aggregate because they are encrypted.
+func SummariseSalaries(salaries []Money) []SalaryStats {
+	byCurrency := map[string][]int64{}
+
+	for _, s := range salaries {
+		byCurrency[s.Currency] = append(byCurrency[s.Currency], s.Minor)
+	}
+
+	currencies := make([]string, 0, len(byCurrency))
+	for c := range byCurrency {
+		currencies = append(currencies, c)
+	}
+
+	sort.Strings(currencies)
+
+	res := []SalaryStats{}
+
+	for _, c := range currencies {
+		minors := byCurrency[c]
+		sort.Slice(minors, func(i, j int) bool { return minors[i] < minors[j] })
+
+		n := int64(len(minors))
+
+		var sum int64
+		for _, m := range minors {
+			sum += m
+		}
+
+		money := func(minor int64) Money { return Money{Minor: minor, Currency: c} }
+		percentile := func(pct int64) Money { return money(minors[rank(pct, n)]) }
+
+		res = append(res, SalaryStats{Currency: c, Count: int(n), Min: money(minors[0]), Max: money(minors[n-1]),
+			Avg: money((2*sum + n) / (2 * n)), P50: percentile(50), P90: percentile(90), P99: percentile(99)})
+	}
+
+	return res
+}
+
+// SummariseAges summarises ages the way the database does, with percentiles that are one of the ages and zeros
+// when there are none. It serves the stores that cannot aggregate in their queries.
+func SummariseAges(ages []int) AgeStats {
+	if len(ages) == 0 {
+		return AgeStats{}
+	}
+
+	sorted := append([]int{}, ages...)
+	sort.Ints(sorted)
+
+	n := int64(len(sorted))
+
+	var sum int
+	for _, a := range sorted {
+		sum += a
+	}
+
+	percentile := func(pct int64) int { return sorted[rank(pct, n)] }
+
+	return AgeStats{Count: int(n), Min: sorted[0], Max: sorted[n-1], Avg: float64(sum) / float64(n),
+		P50: percentile(50), P90: percentile(90), P99: percentile(99)}
+}
+
+// rank returns the index of the pct percentile of n sorted values: the first value that at least pct percent
+// of the values are not above, as percentile_disc picks it.
+func rank(pct, n int64) int64 {
+	return (pct*n+99)/100 - 1
+}
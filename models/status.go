@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Status is the stage of a customer in its lifecycle. Customers start as prospects and their status only
+// changes through the transitions allowed by CanTransition.
+type Status string
+
+const (
+	StatusProspect  Status = "prospect"
+	StatusActive    Status = "active"
+	StatusSuspended Status = "suspended"
+	StatusClosed    Status = "closed"
+)
+
+// Statuses are the statuses in lifecycle order.
+var Statuses = []Status{StatusProspect, StatusActive, StatusSuspended, StatusClosed}
+
+// transitions maps every status to the ones it can change to. Closed customers stay closed.
+var transitions = map[Status][]Status{
+	StatusProspect:  {StatusActive, StatusClosed},
+	StatusActive:    {StatusSuspended, StatusClosed},
+	StatusSuspended: {StatusActive, StatusClosed},
+}
+
+// Valid reports whether s is one of Statuses.
+func (s Status) Valid() bool {
+	for _, v := range Statuses {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanTransition reports whether a customer in status s may change to status to.
+func (s Status) CanTransition(to Status) bool {
+	for _, v := range transitions[s] {
+		if v == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Transition is a change of the status of a customer. Reason is given by the caller that made it.
+type Transition struct {
+	ID         int        `json:"id,omitempty"`
+	CustomerID int        `json:"customerId,omitempty"`
+	From       Status     `json:"from,omitempty"`
+	To         Status     `json:"to"`
+	Reason     string     `json:"reason"`
+	Caller     string     `json:"caller,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+}
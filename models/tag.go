@@ -0,0 +1,11 @@
+package models
+
+import "regexp"
+
+// tagName matches tag names: lowercase letters, digits, "-" and "_", starting with a letter or digit.
+var tagName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidTag reports whether name can be used as a tag. Names are case sensitive, so they are kept lowercase.
+func ValidTag(name string) bool {
+	return tagName.MatchString(name)
+}
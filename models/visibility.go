@@ -0,0 +1,83 @@
+package models
+
+import "reflect"
+
+// Fields tagged scope:"name" are only visible to callers granted name:read and only writable by callers granted
+// name:write. The service layer applies the policy to everything it returns, so REST, GraphQL, gRPC and the
+// statistics all share it.
+const (
+	readAccess  = ":read"
+	writeAccess = ":write"
+)
+
+// Masked returns the customer without the fields the caller may not read. granted reports the scopes of the caller.
+func (c Customer) Masked(granted func(scope string) bool) Customer {
+	mask(&c, granted)
+
+	return c
+}
+
+// Unwritable returns the json names of the fields set on the customer that the caller may not write.
+func (c Customer) Unwritable(granted func(scope string) bool) []string {
+	val := reflect.ValueOf(c)
+
+	var fields []string
+
+	for _, i := range denied(val.Type(), writeAccess, granted) {
+		if !val.Field(i).IsZero() {
+			fields = append(fields, jsonName(val.Type().Field(i)))
+		}
+	}
+
+	return fields
+}
+
+// Preserving returns the customer with the fields the caller may not write taken from the stored customer, so
+// that replacing a customer leaves them unchanged. stored is only called when there are such fields.
+func (c Customer) Preserving(granted func(scope string) bool, stored func() (Customer, error)) (Customer, error) {
+	fields := denied(reflect.TypeOf(c), writeAccess, granted)
+	if len(fields) == 0 {
+		return c, nil
+	}
+
+	old, err := stored()
+	if err != nil {
+		return Customer{}, err
+	}
+
+	val, oldVal := reflect.ValueOf(&c).Elem(), reflect.ValueOf(old)
+	for _, i := range fields {
+		val.Field(i).Set(oldVal.Field(i))
+	}
+
+	return c, nil
+}
+
+// Masked returns the statistics without the fields the caller may not read.
+func (s Stats) Masked(granted func(scope string) bool) Stats {
+	mask(&s, granted)
+
+	return s
+}
+
+// mask zeroes the fields of the struct v points to that the caller may not read.
+func mask(v interface{}, granted func(scope string) bool) {
+	val := reflect.ValueOf(v).Elem()
+
+	for _, i := range denied(val.Type(), readAccess, granted) {
+		val.Field(i).Set(reflect.Zero(val.Field(i).Type()))
+	}
+}
+
+// denied returns the indexes of the fields of t whose scope is not granted the given access.
+func denied(t reflect.Type, access string, granted func(scope string) bool) []int {
+	var fields []int
+
+	for i := 0; i < t.NumField(); i++ {
+		if scope := t.Field(i).Tag.Get("scope"); scope != "" && !granted(scope+access) {
+			fields = append(fields, i)
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/template"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
+)
+
+const swaggerUI = `<!DOCTYPE html>
+<html>
+<head>
+	<title>customer API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		window.onload = () => { window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"}) };
+	</script>
+</body>
+</html>
+`
+
+// Serve serves the OpenAPI document at /openapi.json.
+func Serve(ctx *gofr.Context) (interface{}, error) {
+	return types.Raw{Data: Document()}, nil
+}
+
+// UI serves a Swagger UI page that renders /openapi.json.
+func UI(ctx *gofr.Context) (interface{}, error) {
+	return template.File{Content: []byte(swaggerUI), ContentType: "text/html"}, nil
+}
@@ -0,0 +1,895 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"customer/models"
+)
+
+type Spec struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations of a path keyed by the lower-case HTTP method.
+type PathItem map[string]Operation
+
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Schema struct {
+	Ref         string            `json:"$ref,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Properties  map[string]Schema `json:"properties,omitempty"`
+	Items       *Schema           `json:"items,omitempty"`
+	Required    []string          `json:"required,omitempty"`
+	ReadOnly    bool              `json:"readOnly,omitempty"`
+	Minimum     *int              `json:"minimum,omitempty"`
+	Maximum     *int              `json:"maximum,omitempty"`
+	Enum        []string          `json:"enum,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	Responses       map[string]Response       `json:"responses"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+const apiKeyScheme = "apiKey"
+
+// Document describes every route registered in main.go.
+func Document() Spec {
+	customer := ref("Customer")
+	customers := Schema{Type: "array", Items: &customer}
+	duplicate := ref("Duplicate")
+	transition := ref("Transition")
+	address := ref("Address")
+	segment := ref("Segment")
+	attribute := ref("AttributeDefinition")
+	relationship := ref("Relationship")
+	erasure := ref("Erasure")
+	one, maxDepth := 1, models.MaxGraphDepth
+	tags := Schema{Type: "array", Items: &Schema{Type: "string"}}
+
+	spec := Spec{
+		OpenAPI: "3.1.0",
+		Info:    Info{Title: "customer", Version: "1.0"},
+		Paths: map[string]PathItem{
+			"/customer": {
+				"get": {
+					OperationID: "listCustomers", Summary: "List customers", Tags: []string{"customer"},
+					Description: "Large listings can be streamed as they are read: one customer per line with " +
+						"Accept: application/x-ndjson, one row per customer with Accept: text/csv, or as a chunked " +
+						"JSON array with stream=true. Custom attributes are matched with attr.<name>=<value>, e.g. " +
+						"attr.loyaltyTier=gold.",
+					Parameters: []Parameter{
+						query("name", "Exact customer name", Schema{Type: "string"}),
+						status(),
+						tag(),
+						query("minAge", "Minimum age, inclusive", nonNegative()),
+						query("maxAge", "Maximum age, inclusive", nonNegative()),
+						query("limit", "Maximum number of customers to return", nonNegative()),
+						query("offset", "Number of customers to skip", nonNegative()),
+						fields(),
+						query("stream", "Stream the listing as a chunked JSON array", Schema{Type: "boolean"}),
+					},
+					Responses: withCSV(withNDJSON(responses("200", "The matching customers", customers, "400"), "200", customer), "200"),
+				},
+				"post": {
+					OperationID: "createCustomer", Summary: "Create a customer", Tags: []string{"customer"},
+					RequestBody: body("application/json", customer),
+					Responses:   responses("201", "The created customer", customer, "400", "403"),
+				},
+			},
+			"/customer/stats": {
+				"get": {
+					OperationID: "customerStats", Summary: "Summarise the matching customers", Tags: []string{"customer"},
+					Description: "Results are cached for STATS_CACHE_TTL. Salaries are summarised per currency. " +
+						"Custom attributes are matched with attr.<name>=<value>.",
+					Parameters: []Parameter{
+						query("name", "Exact customer name", Schema{Type: "string"}),
+						status(),
+						tag(),
+						query("minAge", "Minimum age, inclusive", nonNegative()),
+						query("maxAge", "Maximum age, inclusive", nonNegative()),
+						query("buckets", "Ascending, comma separated lower bounds of the age histogram, e.g. 18,30,60",
+							Schema{Type: "string"}),
+					},
+					Responses: responses("200", "The statistics", ref("Stats"), "400"),
+				},
+			},
+			"/customer/duplicates": {
+				"get": {
+					OperationID: "listDuplicateCustomers", Summary: "List likely duplicate customers", Tags: []string{"customer"},
+					Description: "Pairs of customers whose normalised names are at least DUPLICATE_THRESHOLD alike, best first. " +
+						"They are found by a scan every DUPLICATE_SCAN_INTERVAL and after every merge.",
+					Responses: responses("200", "The candidate pairs", Schema{Type: "array", Items: &duplicate}),
+				},
+			},
+			"/customer/merge": {
+				"post": {
+					OperationID: "mergeCustomers", Summary: "Merge customers into a survivor", Tags: []string{"customer"},
+					Description: "In one transaction the fields of the survivor are merged with those of the losers by " +
+						"MERGE_RULES, the addresses, tags and relationships of the losers move to the survivor and the losers " +
+						"are deleted. Relationships between the merged customers are dropped, and a merge that would make a " +
+						"parent or referral cycle is refused with 409. Fields the caller may not write are never taken from " +
+						"the losers. The merge is recorded in the audit log.",
+					RequestBody: body("application/json", ref("MergeRequest")),
+					Responses:   responses("200", "The survivor", customer, "400", "404", "409"),
+				},
+			},
+			"/customer/{id}": {
+				"get": {
+					OperationID: "getCustomer", Summary: "Get a customer", Tags: []string{"customer"},
+					Parameters: []Parameter{id(), fields()},
+					Responses:  responses("200", "The customer", customer, "400", "404"),
+				},
+				"put": {
+					OperationID: "updateCustomer", Summary: "Replace a customer", Tags: []string{"customer"},
+					Parameters:  []Parameter{id()},
+					RequestBody: body("application/json", customer),
+					Responses:   responses("200", "The updated customer", customer, "400", "403", "404"),
+				},
+				"patch": {
+					OperationID: "patchCustomer", Summary: "Update the given fields of a customer", Tags: []string{"customer"},
+					Parameters:  []Parameter{id()},
+					RequestBody: body("application/merge-patch+json", customer),
+					Responses:   responses("200", "The patched fields", customer, "400", "403", "404"),
+				},
+				"delete": {
+					OperationID: "deleteCustomer", Summary: "Delete a customer", Tags: []string{"customer"},
+					Parameters: []Parameter{id()},
+					Responses: map[string]Response{
+						"204": {Description: "The customer was deleted"},
+						"400": {Ref: "#/components/responses/BadRequest"},
+						"401": {Ref: "#/components/responses/Unauthorized"},
+						"404": {Ref: "#/components/responses/NotFound"},
+						"500": {Ref: "#/components/responses/InternalError"},
+					},
+				},
+			},
+			"/customer/by-name/{name}": {
+				"put": {
+					OperationID: "upsertCustomer", Summary: "Create or replace the customer with the given name",
+					Tags: []string{"customer"},
+					Description: "The customer is created or replaced atomically, so concurrent sync jobs cannot race. " +
+						"A name in the body must match the one in the path.",
+					Parameters:  []Parameter{{Name: "name", In: "path", Required: true, Description: "Customer name", Schema: Schema{Type: "string"}}},
+					RequestBody: body("application/json", customer),
+					Responses: withCreated(responses("200", "The replaced customer", customer, "400", "403"),
+						"The created customer", customer),
+				},
+			},
+			"/customer/{id}/addresses": {
+				"get": {
+					OperationID: "listAddresses", Summary: "List the addresses of a customer", Tags: []string{"address"},
+					Parameters: []Parameter{id()},
+					Responses:  responses("200", "The addresses", Schema{Type: "array", Items: &address}, "400", "404"),
+				},
+				"post": {
+					OperationID: "createAddress", Summary: "Add an address to a customer", Tags: []string{"address"},
+					Parameters:  []Parameter{id()},
+					RequestBody: body("application/json", address),
+					Responses:   responses("201", "The created address", address, "400", "404"),
+				},
+			},
+			"/customer/{id}/transitions": {
+				"get": {
+					OperationID: "listTransitions", Summary: "List the status changes of a customer, oldest first",
+					Tags: []string{"customer"}, Parameters: []Parameter{id()},
+					Responses: responses("200", "The transitions", Schema{Type: "array", Items: &transition}, "400", "404"),
+				},
+				"post": {
+					OperationID: "createTransition", Summary: "Change the status of a customer", Tags: []string{"customer"},
+					Description: "Customers start as prospects. Prospects can become active or closed, active customers " +
+						"suspended or closed, suspended customers active or closed. Closed customers stay closed.",
+					Parameters:  []Parameter{id()},
+					RequestBody: body("application/json", transition),
+					Responses:   responses("201", "The transition", transition, "400", "404", "409"),
+				},
+			},
+			"/customer/{id}/tags": {
+				"get": {
+					OperationID: "listTags", Summary: "List the tags of a customer", Tags: []string{"tag"},
+					Parameters: []Parameter{id()},
+					Responses:  responses("200", "The tags in alphabetical order", tags, "400", "404"),
+				},
+			},
+			"/customer/{id}/relationships": {
+				"get": {
+					OperationID: "listRelationships", Summary: "List the relationships of a customer, oldest first",
+					Tags: []string{"relationship"}, Parameters: []Parameter{id()},
+					Responses: responses("200", "The relationships from and to the customer",
+						Schema{Type: "array", Items: &relationship}, "400", "404"),
+				},
+				"post": {
+					OperationID: "createRelationship", Summary: "Relate the customer to another", Tags: []string{"relationship"},
+					Description: "The relationship goes from the customer in the path to toId. Parent and referral " +
+						"relationships that would make a customer its own ancestor are rejected.",
+					Parameters:  []Parameter{id()},
+					RequestBody: body("application/json", relationship),
+					Responses:   responses("201", "The relationship", relationship, "400", "404", "409"),
+				},
+			},
+			"/customer/{id}/graph": {
+				"get": {
+					OperationID: "customerGraph", Summary: "Get the customers related to a customer", Tags: []string{"relationship"},
+					Description: "Relationships are followed in either direction.",
+					Parameters: []Parameter{
+						id(),
+						query("depth", "Number of relationships to follow, 1 by default",
+							Schema{Type: "integer", Minimum: &one, Maximum: &maxDepth}),
+					},
+					Responses: responses("200", "The customers reached and the relationships between them", ref("Graph"),
+						"400", "404"),
+				},
+			},
+			"/customer/{id}/gdpr-export": {
+				"get": {
+					OperationID: "exportCustomer", Summary: "Export everything kept about a customer", Tags: []string{"gdpr"},
+					Description: "Requires the " + models.GDPRAdminScope + " scope. Holds the customer with every field, " +
+						"the customers merged into it, its addresses, transitions, tags, relationships and audit log entries.",
+					Parameters: []Parameter{
+						id(),
+						query("format", "json by default, or zip for a ZIP archive of one JSON file per part",
+							Schema{Type: "string", Enum: []string{"json", "zip"}}),
+					},
+					Responses: withZIP(responses("200", "The export", ref("Export"), "400", "403", "404"), "200"),
+				},
+			},
+			"/customer/{id}/erase": {
+				"post": {
+					OperationID: "eraseCustomer", Summary: "Erase the personal data of a customer", Tags: []string{"gdpr"},
+					Description: "Requires the " + models.GDPRAdminScope + " scope. Irreversible. The customer and those " +
+						"merged into it are renamed erased-<id>, lose their contact details and custom attributes, keep " +
+						"only the year of their date of birth, and have their address lines and transition reasons " +
+						"blanked. Salaries, countries, statuses, tags and relationships are kept. The erasure is recorded " +
+						"in the erasure log.",
+					Parameters: []Parameter{id()},
+					Responses:  responses("201", "The erasure log entry", erasure, "400", "403", "404"),
+				},
+			},
+			"/erasures": {
+				"get": {
+					OperationID: "listErasures", Summary: "Get the erasure log", Tags: []string{"gdpr"},
+					Description: "Requires the " + models.GDPRAdminScope + " scope. Each erasure holds the SHA-256 hash " +
+						"of the one before it, so that changing or removing one shows as a broken chain.",
+					Responses: responses("200", "The erasures, oldest first", ref("ErasureLog"), "403"),
+				},
+			},
+			"/keys/rotate": {
+				"post": {
+					OperationID: "rotateKey", Summary: "Rotate the data key of the tenant", Tags: []string{"keys"},
+					Description: "Requires the " + models.KeysAdminScope + " scope. Names and salaries are encrypted " +
+						"with the new data key from then on. Customers encrypted with older data keys stay readable, " +
+						"and are re-encrypted in batches in the background. Fails when no master key is configured.",
+					Responses: responses("201", "The new data key", ref("DataKey"), "403", "409"),
+				},
+			},
+			"/customer/{id}/tags/{tag}": {
+				"put": {
+					OperationID: "addTag", Summary: "Tag a customer", Tags: []string{"tag"},
+					Description: "The tag is created on first use. Tagging a customer twice changes nothing.",
+					Parameters:  []Parameter{id(), tagName()},
+					Responses:   responses("200", "The tags of the customer", tags, "400", "404"),
+				},
+				"delete": {
+					OperationID: "removeTag", Summary: "Untag a customer", Tags: []string{"tag"},
+					Parameters: []Parameter{id(), tagName()},
+					Responses: map[string]Response{
+						"204": {Description: "The tag was removed"},
+						"400": {Ref: "#/components/responses/BadRequest"},
+						"401": {Ref: "#/components/responses/Unauthorized"},
+						"404": {Ref: "#/components/responses/NotFound"},
+						"500": {Ref: "#/components/responses/InternalError"},
+					},
+				},
+			},
+			"/segments": {
+				"get": {
+					OperationID: "listSegments", Summary: "List the saved segments", Tags: []string{"segment"},
+					Responses: responses("200", "The segments by name", Schema{Type: "array", Items: &segment}),
+				},
+				"post": {
+					OperationID: "createSegment", Summary: "Save a segment", Tags: []string{"segment"},
+					RequestBody: body("application/json", segment),
+					Responses:   responses("201", "The created segment", segment, "400", "409"),
+				},
+			},
+			"/segments/{id}": {
+				"get": {
+					OperationID: "getSegment", Summary: "Get a segment", Tags: []string{"segment"},
+					Parameters: []Parameter{segmentID()},
+					Responses:  responses("200", "The segment", segment, "400", "404"),
+				},
+				"put": {
+					OperationID: "updateSegment", Summary: "Replace a segment", Tags: []string{"segment"},
+					Parameters:  []Parameter{segmentID()},
+					RequestBody: body("application/json", segment),
+					Responses:   responses("200", "The updated segment", segment, "400", "404", "409"),
+				},
+				"delete": {
+					OperationID: "deleteSegment", Summary: "Delete a segment", Tags: []string{"segment"},
+					Parameters: []Parameter{segmentID()},
+					Responses: map[string]Response{
+						"204": {Description: "The segment was deleted"},
+						"400": {Ref: "#/components/responses/BadRequest"},
+						"401": {Ref: "#/components/responses/Unauthorized"},
+						"404": {Ref: "#/components/responses/NotFound"},
+						"500": {Ref: "#/components/responses/InternalError"},
+					},
+				},
+			},
+			"/segments/{id}/customers": {
+				"get": {
+					OperationID: "listSegmentCustomers", Summary: "Evaluate a segment", Tags: []string{"segment"},
+					Description: "The filter of the segment is applied to the customers as they are now. The customers " +
+						"can be exported like a listing: with Accept: text/csv or application/x-ndjson, or stream=true.",
+					Parameters: []Parameter{
+						segmentID(),
+						query("limit", "Maximum number of customers to return", nonNegative()),
+						query("offset", "Number of customers to skip", nonNegative()),
+						fields(),
+						query("stream", "Stream the customers as a chunked JSON array", Schema{Type: "boolean"}),
+					},
+					Responses: withCSV(withNDJSON(responses("200", "The customers in the segment", customers, "400", "404"),
+						"200", customer), "200"),
+				},
+			},
+			"/attributes": {
+				"get": {
+					OperationID: "listAttributes", Summary: "List the custom attributes", Tags: []string{"attribute"},
+					Responses: responses("200", "The attribute definitions by name", Schema{Type: "array", Items: &attribute}),
+				},
+				"post": {
+					OperationID: "createAttribute", Summary: "Define a custom attribute", Tags: []string{"attribute"},
+					Description: "Requires the " + models.AttributeAdminScope + " scope. Existing customers are only " +
+						"checked against a new required attribute on their next write.",
+					RequestBody: body("application/json", attribute),
+					Responses:   responses("201", "The created attribute", attribute, "400", "403", "409"),
+				},
+			},
+			"/attributes/{name}": {
+				"delete": {
+					OperationID: "deleteAttribute", Summary: "Remove a custom attribute", Tags: []string{"attribute"},
+					Description: "Requires the " + models.AttributeAdminScope + " scope. The attribute is removed " +
+						"from every customer.",
+					Parameters: []Parameter{attributeName()},
+					Responses: map[string]Response{
+						"204": {Description: "The attribute was removed"},
+						"401": {Ref: "#/components/responses/Unauthorized"},
+						"403": {Ref: "#/components/responses/Forbidden"},
+						"404": {Ref: "#/components/responses/NotFound"},
+						"500": {Ref: "#/components/responses/InternalError"},
+					},
+				},
+			},
+			"/customer/{id}/addresses/{addressId}": {
+				"get": {
+					OperationID: "getAddress", Summary: "Get an address of a customer", Tags: []string{"address"},
+					Parameters: []Parameter{id(), addressID()},
+					Responses:  responses("200", "The address", address, "400", "404"),
+				},
+				"put": {
+					OperationID: "updateAddress", Summary: "Replace an address of a customer", Tags: []string{"address"},
+					Parameters:  []Parameter{id(), addressID()},
+					RequestBody: body("application/json", address),
+					Responses:   responses("200", "The updated address", address, "400", "404"),
+				},
+				"delete": {
+					OperationID: "deleteAddress", Summary: "Delete an address of a customer", Tags: []string{"address"},
+					Parameters: []Parameter{id(), addressID()},
+					Responses: map[string]Response{
+						"204": {Description: "The address was deleted"},
+						"400": {Ref: "#/components/responses/BadRequest"},
+						"401": {Ref: "#/components/responses/Unauthorized"},
+						"404": {Ref: "#/components/responses/NotFound"},
+						"500": {Ref: "#/components/responses/InternalError"},
+					},
+				},
+			},
+			"/graphql": {
+				"post": {
+					OperationID: "graphql", Summary: "Run a GraphQL query or mutation", Tags: []string{"graphql"},
+					RequestBody: body("application/json", ref("GraphQLRequest")),
+					Responses: map[string]Response{
+						"200": {Description: "The GraphQL response", Content: map[string]MediaType{
+							"application/json": {Schema: Schema{Type: "object"}},
+						}},
+						"400": {Ref: "#/components/responses/BadRequest"},
+						"401": {Ref: "#/components/responses/Unauthorized"},
+					},
+				},
+			},
+			"/openapi.json": {
+				"get": {
+					OperationID: "openapi", Summary: "This document", Tags: []string{"docs"},
+					Security: &[]map[string][]string{},
+					Responses: map[string]Response{"200": {Description: "The OpenAPI document", Content: map[string]MediaType{
+						"application/json": {Schema: Schema{Type: "object"}},
+					}}},
+				},
+			},
+			"/docs": {
+				"get": {
+					OperationID: "docs", Summary: "Swagger UI for this document", Tags: []string{"docs"},
+					Security: &[]map[string][]string{},
+					Responses: map[string]Response{"200": {Description: "The Swagger UI page", Content: map[string]MediaType{
+						"text/html": {Schema: Schema{Type: "string"}},
+					}}},
+				},
+			},
+			"/health/live": {
+				"get": {
+					OperationID: "live", Summary: "Liveness probe", Tags: []string{"health"},
+					Security: &[]map[string][]string{},
+					Responses: map[string]Response{"200": {Description: "The process is up", Content: map[string]MediaType{
+						"application/json": {Schema: Schema{Type: "object", Properties: map[string]Schema{"data": ref("HealthReport")}}},
+					}}},
+				},
+			},
+			"/health/ready": {
+				"get": {
+					OperationID: "ready", Summary: "Readiness probe", Tags: []string{"health"},
+					Security: &[]map[string][]string{},
+					Responses: map[string]Response{
+						"200": {Description: "Every dependency is up", Content: map[string]MediaType{
+							"application/json": {Schema: Schema{Type: "object", Properties: map[string]Schema{"data": ref("HealthReport")}}},
+						}},
+						"503": errorResponse("A dependency is down or the instance is draining; the error detail holds the report"),
+					},
+				},
+			},
+		},
+		Components: Components{
+			Schemas: map[string]Schema{
+				"Customer":            customerSchema(),
+				"Address":             addressSchema(),
+				"Transition":          transitionSchema(),
+				"Segment":             segmentSchema(),
+				"AttributeDefinition": attributeSchema(),
+				"Relationship":        relationshipSchema(),
+				"Graph": {Type: "object", Properties: map[string]Schema{
+					"customers":     customers,
+					"relationships": {Type: "array", Items: &relationship},
+				}},
+				"Stats":      schemaOf(reflect.TypeOf(models.Stats{})),
+				"Export":     exportSchema(),
+				"Erasure":    schemaOf(reflect.TypeOf(models.Erasure{})),
+				"ErasureLog": schemaOf(reflect.TypeOf(models.ErasureLog{})),
+				"DataKey":    schemaOf(reflect.TypeOf(models.DataKey{})),
+				"Duplicate": {Type: "object", Properties: map[string]Schema{
+					"customers": {Type: "array", Items: &customer, Description: "The id and name of both customers"},
+					"score":     {Type: "number", Description: "Similarity of the normalised names, from 0 to 1"},
+				}},
+				"MergeRequest": {Type: "object", Required: []string{"survivorId", "loserIds"}, Properties: map[string]Schema{
+					"survivorId": {Type: "integer"},
+					"loserIds":   {Type: "array", Items: &Schema{Type: "integer"}},
+				}},
+				"GraphQLRequest": {Type: "object", Required: []string{"query"}, Properties: map[string]Schema{
+					"query":         {Type: "string"},
+					"operationName": {Type: "string"},
+					"variables":     {Type: "object"},
+				}},
+				"Error": {Type: "object", Properties: map[string]Schema{
+					"code":     {Type: "string"},
+					"reason":   {Type: "string"},
+					"detail":   {Type: "object"},
+					"datetime": {Type: "object"},
+				}},
+				"HealthReport": {Type: "object", Properties: map[string]Schema{
+					"status":   {Type: "string"},
+					"draining": {Type: "boolean"},
+					"components": {Type: "object", Description: "The check of every dependency, by name: database, schema, " +
+						"keyring, mongo, stats_cache and outbox. The outbox check always reports that there is no outbox relay."},
+				}},
+				"ErrorResponse": {Type: "object", Properties: map[string]Schema{
+					"errors": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Error"}},
+				}},
+			},
+			Responses: map[string]Response{
+				"BadRequest":   errorResponse("A parameter or the body is missing or invalid"),
+				"Unauthorized": {Description: "The x-api-key header is missing or wrong"},
+				"NotFound":     errorResponse("No customer, address, tag, segment or attribute exists for the given id"),
+				"Forbidden": errorResponse("The x-api-key lacks the scope needed to write a field, change the attributes, " +
+					"make a GDPR request or rotate keys"),
+				"Conflict": errorResponse("The customer cannot make the requested status change, the segment or " +
+					"attribute name is taken, the relationship exists or would make a cycle, the store cannot merge " +
+					"customers with addresses, tags or relationships, or encryption is disabled"),
+				"InternalError": errorResponse("The database could not serve the request"),
+				"NotImplemented": errorResponse("The route is served from a Postgres database that keeps the customers, " +
+					"which the service runs without"),
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				apiKeyScheme: {Type: "apiKey", In: "header", Name: "x-api-key"},
+			},
+		},
+		Security: []map[string][]string{{apiKeyScheme: {}}},
+	}
+
+	for path, item := range spec.Paths {
+		if !databaseOnly(path) {
+			continue
+		}
+
+		for _, op := range item {
+			op.Responses["501"] = Response{Ref: "#/components/responses/NotImplemented"}
+		}
+	}
+
+	return spec
+}
+
+// databaseOnly reports whether the operations of path are only served from a Postgres database, so that they
+// answer 501 without one, as handler.WithDatabase describes, on MySQL, as store.Dialect describes, or when
+// another STORE_BACKEND than postgres keeps the customers. Statistics and merges are also served by the other
+// backends.
+func databaseOnly(path string) bool {
+	for _, prefix := range []string{"/customer/stats", "/customer/merge", "/customer/{id}/addresses", "/customer/{id}/transitions", "/customer/{id}/tags",
+		"/customer/{id}/relationships", "/customer/{id}/graph", "/customer/{id}/gdpr-export", "/customer/{id}/erase",
+		"/erasures", "/keys/", "/segments"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// customerSchema is derived from the json tags of models.Customer so that it follows the model.
+func customerSchema() Schema {
+	s := readOnly(schemaOf(reflect.TypeOf(models.Customer{})), "id", "age", "createdAt", "updatedAt", "status")
+
+	if p, ok := s.Properties["email"]; ok {
+		p.Format = "email"
+		s.Properties["email"] = p
+	}
+
+	if p, ok := s.Properties["status"]; ok {
+		p.Enum, p.Description = statuses(), "Changed through POST /customer/{id}/transitions"
+		s.Properties["status"] = p
+	}
+
+	if p, ok := s.Properties["attributes"]; ok {
+		p.Description = "Custom attributes defined through /attributes. Left out, the stored ones are kept; a PATCH " +
+			"merges them and removes those set to null"
+		s.Properties["attributes"] = p
+	}
+
+	return s
+}
+
+// transitionSchema is derived from models.Transition. Only to and reason are written.
+func transitionSchema() Schema {
+	s := readOnly(schemaOf(reflect.TypeOf(models.Transition{})), "id", "customerId", "from", "caller", "createdAt")
+	s.Required = []string{"to", "reason"}
+
+	for _, name := range []string{"from", "to"} {
+		p := s.Properties[name]
+		p.Enum = statuses()
+		s.Properties[name] = p
+	}
+
+	return s
+}
+
+func statuses() []string {
+	res := make([]string, len(models.Statuses))
+	for i, status := range models.Statuses {
+		res[i] = string(status)
+	}
+
+	return res
+}
+
+func status() Parameter {
+	return query("status", "Customer status", Schema{Type: "string", Enum: statuses()})
+}
+
+// segmentSchema is derived from models.Segment. Only name and filter are written.
+func segmentSchema() Schema {
+	s := readOnly(schemaOf(reflect.TypeOf(models.Segment{})), "id", "createdAt", "updatedAt")
+	s.Required = []string{"name", "filter"}
+
+	p := s.Properties["filter"]
+	p.Description = "The query string of GET /customer without paging, e.g. tag=vip&tag=!churned&minAge=30"
+	s.Properties["filter"] = p
+
+	return s
+}
+
+// attributeSchema is derived from models.AttributeDefinition.
+func attributeSchema() Schema {
+	s := readOnly(schemaOf(reflect.TypeOf(models.AttributeDefinition{})), "id", "createdAt")
+	s.Required = []string{"name", "type"}
+
+	p := s.Properties["type"]
+	for _, t := range models.AttributeTypes {
+		p.Enum = append(p.Enum, string(t))
+	}
+	s.Properties["type"] = p
+
+	p = s.Properties["enum"]
+	p.Description = "The allowed values of a string attribute"
+	s.Properties["enum"] = p
+
+	return s
+}
+
+// relationshipSchema is derived from models.Relationship. The from customer is the one in the path.
+func relationshipSchema() Schema {
+	s := readOnly(schemaOf(reflect.TypeOf(models.Relationship{})), "id", "fromId", "createdAt")
+	s.Required = []string{"type", "toId"}
+
+	p := s.Properties["type"]
+	for _, t := range models.RelationshipTypes {
+		p.Enum = append(p.Enum, string(t))
+	}
+	p.Description = "parent: toId is the parent company of fromId. referral: fromId referred toId. " +
+		"household: both live together"
+	s.Properties["type"] = p
+
+	return s
+}
+
+// exportSchema is derived from models.Export. Its customers are described by the Customer schema, although
+// their salaries are always included.
+func exportSchema() Schema {
+	s := schemaOf(reflect.TypeOf(models.Export{}))
+	s.Description = "Everything kept about a customer. It has no webhook deliveries, since the service has no " +
+		"webhooks and sends no customer data to other services."
+	customer := ref("Customer")
+	s.Properties["customer"], s.Properties["merged"] = customer, Schema{Type: "array", Items: &customer}
+
+	p := s.Properties["audit"]
+	p.Items.Properties["detail"] = Schema{Type: "object"}
+	s.Properties["audit"] = p
+
+	return s
+}
+
+func tag() Parameter {
+	return query("tag", "Tag the customers must have, or must not have when prefixed with !. Repeatable, e.g. "+
+		"tag=vip&tag=!churned", Schema{Type: "string"})
+}
+
+func addressSchema() Schema {
+	s := readOnly(schemaOf(reflect.TypeOf(models.Address{})), "id", "customerId")
+	s.Required = []string{"type", "country", "postalCode"}
+
+	return s
+}
+
+func readOnly(s Schema, properties ...string) Schema {
+	for _, name := range properties {
+		if p, ok := s.Properties[name]; ok {
+			p.ReadOnly = true
+			s.Properties[name] = p
+		}
+	}
+
+	return s
+}
+
+// moneySchema describes models.Money, whose amount is a decimal string so that it is never rounded.
+func moneySchema() Schema {
+	return Schema{Type: "object", Required: []string{"amount", "currency"}, Properties: map[string]Schema{
+		"amount":   {Type: "string", Format: "decimal"},
+		"currency": {Type: "string", Format: "iso-4217"},
+	}}
+}
+
+func schemaOf(t reflect.Type) Schema {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return Schema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(models.Date{}):
+		return Schema{Type: "string", Format: "date"}
+	case reflect.TypeOf(models.Money{}):
+		return moneySchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.Bool:
+		return Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{Type: "number"}
+	case reflect.String:
+		return Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		items := schemaOf(t.Elem())
+		return Schema{Type: "array", Items: &items}
+	case reflect.Map:
+		return Schema{Type: "object"}
+	case reflect.Struct:
+		s := Schema{Type: "object", Properties: map[string]Schema{}}
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" || f.PkgPath != "" {
+				continue
+			}
+
+			if name == "" {
+				name = f.Name
+			}
+
+			p := schemaOf(f.Type)
+			if scope := f.Tag.Get("scope"); scope != "" {
+				p.Description = fmt.Sprintf("Only returned with the %[1]v:read scope and only writable with %[1]v:write", scope)
+			}
+
+			s.Properties[name] = p
+		}
+
+		return s
+	}
+
+	return Schema{}
+}
+
+func ref(name string) Schema {
+	return Schema{Ref: "#/components/schemas/" + name}
+}
+
+func nonNegative() Schema {
+	zero := 0
+	return Schema{Type: "integer", Minimum: &zero}
+}
+
+func query(name, description string, s Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: s}
+}
+
+// fields documents the projection of the read endpoints, whose values are the properties of the customer.
+func fields() Parameter {
+	return query("fields", "Comma separated customer properties to return, e.g. id,name. Every property by default",
+		Schema{Type: "string"})
+}
+
+func id() Parameter {
+	return Parameter{Name: "id", In: "path", Required: true, Description: "Customer id", Schema: Schema{Type: "integer"}}
+}
+
+func addressID() Parameter {
+	return Parameter{Name: "addressId", In: "path", Required: true, Description: "Address id", Schema: Schema{Type: "integer"}}
+}
+
+func tagName() Parameter {
+	return Parameter{Name: "tag", In: "path", Required: true, Description: "Tag name", Schema: Schema{Type: "string"}}
+}
+
+func attributeName() Parameter {
+	return Parameter{Name: "name", In: "path", Required: true, Description: "Attribute name", Schema: Schema{Type: "string"}}
+}
+
+func segmentID() Parameter {
+	return Parameter{Name: "id", In: "path", Required: true, Description: "Segment id", Schema: Schema{Type: "integer"}}
+}
+
+func body(contentType string, s Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: map[string]MediaType{contentType: {Schema: s}}}
+}
+
+// responses builds the success response, wrapped in gofr's data envelope, followed by the given error responses.
+func responses(code, description string, s Schema, errs ...string) map[string]Response {
+	res := map[string]Response{
+		code: {Description: description, Content: map[string]MediaType{
+			"application/json": {Schema: Schema{Type: "object", Properties: map[string]Schema{"data": s}}},
+		}},
+		"401": {Ref: "#/components/responses/Unauthorized"},
+		"500": {Ref: "#/components/responses/InternalError"},
+	}
+
+	for _, e := range errs {
+		switch e {
+		case "400":
+			res[e] = Response{Ref: "#/components/responses/BadRequest"}
+		case "403":
+			res[e] = Response{Ref: "#/components/responses/Forbidden"}
+		case "404":
+			res[e] = Response{Ref: "#/components/responses/NotFound"}
+		case "409":
+			res[e] = Response{Ref: "#/components/responses/Conflict"}
+		}
+	}
+
+	return res
+}
+
+// withCreated adds the 201 of an operation that either creates or replaces an entity.
+func withCreated(res map[string]Response, description string, s Schema) map[string]Response {
+	res["201"] = responses("201", description, s)["201"]
+
+	return res
+}
+
+// withNDJSON adds the application/x-ndjson form of a streamed response, whose lines each hold one item.
+func withNDJSON(res map[string]Response, code string, item Schema) map[string]Response {
+	r := res[code]
+	r.Content["application/x-ndjson"] = MediaType{Schema: item}
+	res[code] = r
+
+	return res
+}
+
+// withCSV adds the text/csv form of a streamed response: a header of the field names, then one row per item.
+func withCSV(res map[string]Response, code string) map[string]Response {
+	r := res[code]
+	r.Content["text/csv"] = MediaType{Schema: Schema{Type: "string"}}
+	res[code] = r
+
+	return res
+}
+
+// withZIP adds a ZIP archive to the content of the response with code.
+func withZIP(res map[string]Response, code string) map[string]Response {
+	r := res[code]
+	r.Content["application/zip"] = MediaType{Schema: Schema{Type: "string", Format: "binary"}}
+	res[code] = r
+
+	return res
+}
+
+func errorResponse(description string) Response {
+	return Response{Description: description, Content: map[string]MediaType{
+		"application/json": {Schema: ref("ErrorResponse")},
+	}}
+}
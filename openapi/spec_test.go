@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	spec := Document()
+
+	if _, err := json.Marshal(spec); err != nil {
+		t.Fatalf("failed to marshal the document: %v", err)
+	}
+
+	tests := []struct {
+		desc     string
+		property string
+		expected Schema
+	}{
+		{"id is read only", "id", Schema{Type: "integer", ReadOnly: true}},
+		{"name", "name", Schema{Type: "string"}},
+		{"email", "email", Schema{Type: "string", Format: "email"}},
+		{"date of birth", "dateOfBirth", Schema{Type: "string", Format: "date"}},
+		{"age is computed", "age", Schema{Type: "integer", ReadOnly: true}},
+		{"created at is read only", "createdAt", Schema{Type: "string", Format: "date-time", ReadOnly: true}},
+		{"salary", "salary", Schema{Type: "object", Required: []string{"amount", "currency"}, Properties: map[string]Schema{
+			"amount": {Type: "string", Format: "decimal"}, "currency": {Type: "string", Format: "iso-4217"}},
+			Description: "Only returned with the customer:salary:read scope and only writable with customer:salary:write"}},
+	}
+
+	customer := spec.Components.Schemas["Customer"]
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if !reflect.DeepEqual(customer.Properties[tc.property], tc.expected) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, customer.Properties[tc.property])
+			}
+		})
+	}
+
+	if _, ok := spec.Components.SecuritySchemes[apiKeyScheme]; !ok {
+		t.Errorf("Expected the %v security scheme", apiKeyScheme)
+	}
+}
@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"customer/middleware"
+)
+
+// authenticate scopes every RPC to the tenant of its x-api-key metadata and grants it the scopes of the key,
+// as middleware.Auth does for HTTP. The caller of the RPC is recorded, so that its mutations are counted by API
+// key.
+func authenticate(ctx context.Context, tenants middleware.Tenants, scopes middleware.Scopes) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := first(md, middleware.APIKeyHeader)
+
+	ctx = middleware.WithRequestID(ctx, first(md, middleware.RequestIDHeader))
+
+	ctx, ok := tenants.Authenticate(ctx, key)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing or unknown "+middleware.APIKeyHeader)
+	}
+
+	return scopes.Grant(ctx, key), nil
+}
+
+// first returns the first value of the metadata key, or "" when there is none.
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func unaryAuth(tenants middleware.Tenants, scopes middleware.Scopes) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, tenants, scopes)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(tenants middleware.Tenants, scopes middleware.Scopes) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), tenants, scopes)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream hands the tenant scoped context to the stream handler.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,300 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"net"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"customer/middleware"
+	"customer/models"
+	customerv1 "customer/proto/customer/v1"
+	"customer/service"
+)
+
+type Server struct {
+	customerv1.UnimplementedCustomerServiceServer
+
+	app     *gofr.Gofr
+	service service.HandlerIn
+}
+
+func New(app *gofr.Gofr, s service.HandlerIn) *Server {
+	return &Server{app: app, service: s}
+}
+
+// NewGRPCServer registers the CustomerService on a gRPC server that only accepts the API keys of tenants,
+// granting every RPC the scopes of its key.
+func NewGRPCServer(app *gofr.Gofr, s service.HandlerIn, tenants middleware.Tenants, scopes middleware.Scopes) *grpc.Server {
+	srv := grpc.NewServer(grpc.UnaryInterceptor(unaryAuth(tenants, scopes)),
+		grpc.StreamInterceptor(streamAuth(tenants, scopes)))
+	customerv1.RegisterCustomerServiceServer(srv, New(app, s))
+
+	return srv
+}
+
+// ListenAndServe serves the CustomerService on the given port until ctx is cancelled, then lets
+// the running RPCs finish before it returns.
+func ListenAndServe(ctx context.Context, app *gofr.Gofr, s service.HandlerIn, tenants middleware.Tenants,
+	scopes middleware.Scopes, port string) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+
+	srv := NewGRPCServer(app, s, tenants, scopes)
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	return srv.Serve(lis)
+}
+
+func (s *Server) GetCustomer(ctx context.Context, req *customerv1.GetCustomerRequest) (*customerv1.Customer, error) {
+	res, err := s.service.GetByID(s.context(ctx), int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(res), nil
+}
+
+func (s *Server) ListCustomers(req *customerv1.ListCustomersRequest, stream customerv1.CustomerService_ListCustomersServer) error {
+	err := s.service.Stream(s.context(stream.Context()), models.Filter{}, func(c models.Customer) error {
+		return stream.Send(toProto(c))
+	})
+
+	return toStatus(err)
+}
+
+func (s *Server) CreateCustomer(ctx context.Context, req *customerv1.CreateCustomerRequest) (*customerv1.Customer, error) {
+	if req.GetCustomer() == nil {
+		return nil, toStatus(errors.MissingParam{Param: []string{"customer"}})
+	}
+
+	customer, err := fromProto(req.GetCustomer())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res, err := s.service.Create(s.context(ctx), customer)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(res), nil
+}
+
+func (s *Server) UpdateCustomer(ctx context.Context, req *customerv1.UpdateCustomerRequest) (*customerv1.Customer, error) {
+	if req.GetCustomer().GetId() == 0 {
+		return nil, toStatus(errors.MissingParam{Param: []string{"id"}})
+	}
+
+	customer, err := fromProto(req.GetCustomer())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res, err := s.service.Update(s.context(ctx), customer)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(res), nil
+}
+
+func (s *Server) PatchCustomer(ctx context.Context, req *customerv1.PatchCustomerRequest) (*customerv1.Customer, error) {
+	if req.GetId() == 0 {
+		return nil, toStatus(errors.MissingParam{Param: []string{"id"}})
+	}
+
+	var customer models.Customer
+	if req.Name != nil {
+		customer.Name = req.GetName()
+	}
+
+	if req.Salary != nil {
+		salary, err := parseMoney(req.GetSalary())
+		if err != nil {
+			return nil, toStatus(err)
+		}
+
+		customer.Salary = salary
+	}
+
+	if req.Email != nil {
+		customer.Email = req.GetEmail()
+	}
+
+	if req.Phone != nil {
+		customer.Phone = req.GetPhone()
+	}
+
+	if req.DateOfBirth != nil {
+		dob, err := parseDate(req.GetDateOfBirth())
+		if err != nil {
+			return nil, toStatus(err)
+		}
+
+		customer.DateOfBirth = dob
+	}
+
+	if req.Attributes != nil {
+		customer.Attributes = req.GetAttributes().AsMap()
+	}
+
+	res, err := s.service.Patch(s.context(ctx), int(req.GetId()), customer)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(res), nil
+}
+
+func (s *Server) DeleteCustomer(ctx context.Context, req *customerv1.DeleteCustomerRequest) (*customerv1.DeleteCustomerResponse, error) {
+	if err := s.service.Delete(s.context(ctx), int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &customerv1.DeleteCustomerResponse{}, nil
+}
+
+// context wraps the incoming RPC context so that it can be handed to the service layer.
+func (s *Server) context(ctx context.Context) *gofr.Context {
+	c := gofr.NewContext(nil, nil, s.app)
+	c.Context = ctx
+
+	return c
+}
+
+// toStatus maps the errors returned by the service layer to gRPC status codes.
+func toStatus(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case errors.EntityNotFound:
+		return status.Error(codes.NotFound, e.Error())
+	case errors.InvalidParam:
+		return status.Error(codes.InvalidArgument, e.Error())
+	case errors.MissingParam:
+		return status.Error(codes.InvalidArgument, e.Error())
+	case errors.EntityAlreadyExists:
+		return status.Error(codes.AlreadyExists, e.Error())
+	case errors.ForbiddenRequest:
+		return status.Error(codes.PermissionDenied, e.Error())
+	case errors.DB:
+		return status.Error(codes.Internal, e.Error())
+	}
+
+	if err == sql.ErrNoRows {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	// Streams fail with the status of a failed Send, or with the context of an RPC the client gave up on.
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func toProto(c models.Customer) *customerv1.Customer {
+	res := &customerv1.Customer{
+		Id:     int64(c.ID),
+		Name:   c.Name,
+		Age:    int32(c.Age),
+		Email:  c.Email,
+		Phone:  c.Phone,
+		Status: string(c.Status),
+	}
+
+	if c.Salary != nil {
+		res.Salary = &customerv1.Money{Amount: c.Salary.Amount(), Currency: c.Salary.Currency}
+	}
+
+	if c.DateOfBirth != nil {
+		res.DateOfBirth = c.DateOfBirth.String()
+	}
+
+	if c.CreatedAt != nil {
+		res.CreatedAt = timestamppb.New(*c.CreatedAt)
+	}
+
+	if c.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*c.UpdatedAt)
+	}
+
+	// Attributes hold the JSON values read from the database, which a Struct always represents.
+	if c.Attributes != nil {
+		res.Attributes, _ = structpb.NewStruct(c.Attributes)
+	}
+
+	return res
+}
+
+// fromProto converts a customer to be written. Age and the timestamps are not writable and are ignored.
+func fromProto(c *customerv1.Customer) (models.Customer, error) {
+	dob, err := parseDate(c.GetDateOfBirth())
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	var salary *models.Money
+
+	if c.Salary != nil {
+		if salary, err = parseMoney(c.GetSalary()); err != nil {
+			return models.Customer{}, err
+		}
+	}
+
+	var attributes models.Attributes
+
+	if c.Attributes != nil {
+		attributes = c.GetAttributes().AsMap()
+	}
+
+	return models.Customer{
+		ID:          int(c.GetId()),
+		Name:        c.GetName(),
+		Email:       c.GetEmail(),
+		Phone:       c.GetPhone(),
+		DateOfBirth: dob,
+		Salary:      salary,
+		Attributes:  attributes,
+	}, nil
+}
+
+func parseDate(s string) (*models.Date, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	d, err := models.ParseDate(s)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"date_of_birth"}}
+	}
+
+	return &d, nil
+}
+
+func parseMoney(m *customerv1.Money) (*models.Money, error) {
+	res, err := models.ParseMoney(m.GetAmount(), m.GetCurrency())
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"salary"}}
+	}
+
+	return &res, nil
+}
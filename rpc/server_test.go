@@ -0,0 +1,420 @@
+package rpc
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	customerv1 "customer/proto/customer/v1"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+var (
+	// salary is 30000.00 INR, as stored and as sent over the wire.
+	salary      = &models.Money{Minor: 3000000, Currency: "INR"}
+	salaryProto = &customerv1.Money{Amount: "30000.00", Currency: "INR"}
+)
+
+func connect(t *testing.T) (*gomock.Controller, *mocks.MockHandlerIn, customerv1.CustomerServiceClient, func()) {
+	ctrl := gomock.NewController(t)
+	m := mocks.NewMockHandlerIn(ctrl)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := NewGRPCServer(gofr.New(), m, middleware.Tenants{apiKey: "zopsmart"}, middleware.Scopes{})
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(), grpc.WithUnaryInterceptor(withAPIKey), grpc.WithStreamInterceptor(withAPIKeyStream))
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+
+	return ctrl, m, customerv1.NewCustomerServiceClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+		ctrl.Finish()
+	}
+}
+
+const apiKey = "divya-zs"
+
+// withAPIKey authenticates every call that does not set an API key itself.
+func withAPIKey(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingKey(ctx), method, req, reply, cc, opts...)
+}
+
+func withAPIKeyStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+	streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingKey(ctx), desc, cc, method, opts...)
+}
+
+func outgoingKey(ctx context.Context) context.Context {
+	if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(middleware.APIKeyHeader)) > 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, middleware.APIKeyHeader, apiKey)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	scoped := func(ctx *gofr.Context, id int, _ ...string) (models.Customer, error) {
+		if tenant := middleware.Tenant(ctx); tenant != "zopsmart" {
+			t.Errorf("Expected the RPC to be scoped to zopsmart\nGot %q", tenant)
+		}
+
+		if middleware.Caller(ctx) == "" || middleware.RequestID(ctx) == "" {
+			t.Errorf("Expected the caller and request id of the RPC to be recorded")
+		}
+
+		return models.Customer{ID: id}, nil
+	}
+
+	tests := []struct {
+		desc string
+		ctx  context.Context
+		code codes.Code
+		mock *gomock.Call
+	}{
+		{"known key", context.Background(), codes.OK, m.EXPECT().GetByID(gomock.Any(), 1).DoAndReturn(scoped)},
+		{"unknown key", metadata.AppendToOutgoingContext(context.Background(), middleware.APIKeyHeader, "wrong"),
+			codes.Unauthenticated, nil},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := client.GetCustomer(tc.ctx, &customerv1.GetCustomerRequest{Id: 1})
+			if status.Code(err) != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+			}
+		})
+	}
+
+	stream, _ := client.ListCustomers(metadata.AppendToOutgoingContext(context.Background(), middleware.APIKeyHeader, "wrong"),
+		&customerv1.ListCustomersRequest{})
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected %v for a stream with an unknown key\nGot %v", codes.Unauthenticated, status.Code(err))
+	}
+}
+
+func TestServer_GetCustomer(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	customer1 := models.Customer{ID: 1, Name: "Divya", Age: 22, Salary: salary}
+
+	tests := []struct {
+		desc     string
+		id       int64
+		expected *customerv1.Customer
+		code     codes.Code
+		mock     *gomock.Call
+	}{
+		{"success", 1, &customerv1.Customer{Id: 1, Name: "Divya", Age: 22, Salary: salaryProto}, codes.OK,
+			m.EXPECT().GetByID(gomock.Any(), 1).Return(customer1, nil)},
+		{"not found", 2, nil, codes.NotFound,
+			m.EXPECT().GetByID(gomock.Any(), 2).Return(models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "2"})},
+		{"invalid param", 3, nil, codes.InvalidArgument,
+			m.EXPECT().GetByID(gomock.Any(), 3).Return(models.Customer{}, errors.InvalidParam{Param: []string{"id"}})},
+		{"internal server error", 4, nil, codes.Internal,
+			m.EXPECT().GetByID(gomock.Any(), 4).Return(models.Customer{}, errors.DB{Err: errors.Error("db error")})},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := client.GetCustomer(context.Background(), &customerv1.GetCustomerRequest{Id: tc.id})
+			if status.Code(err) != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+			}
+
+			if !proto.Equal(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+// streamed hands the customers to the callback of a mocked Stream, then fails with err.
+func streamed(customers []models.Customer, err error) func(*gofr.Context, models.Filter, func(models.Customer) error) error {
+	return func(_ *gofr.Context, _ models.Filter, fn func(models.Customer) error) error {
+		for i := range customers {
+			if err := fn(customers[i]); err != nil {
+				return err
+			}
+		}
+
+		return err
+	}
+}
+
+func TestServer_ListCustomers(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	customers := []models.Customer{
+		{ID: 1, Name: "Divya", Age: 22, Salary: salary},
+		{ID: 2, Name: "Jay", Age: 21, Salary: salary},
+	}
+
+	tests := []struct {
+		desc     string
+		expected []*customerv1.Customer
+		code     codes.Code
+		mock     *gomock.Call
+	}{
+		{"success", []*customerv1.Customer{
+			{Id: 1, Name: "Divya", Age: 22, Salary: salaryProto},
+			{Id: 2, Name: "Jay", Age: 21, Salary: salaryProto},
+		}, codes.OK, m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(streamed(customers, nil))},
+		{"internal server error", nil, codes.Internal,
+			m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(streamed(nil, errors.DB{Err: errors.Error("db error")}))},
+		{"failure after the first customer", []*customerv1.Customer{{Id: 1, Name: "Divya", Age: 22, Salary: salaryProto}}, codes.Internal,
+			m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).
+				DoAndReturn(streamed(customers[:1], errors.DB{Err: errors.Error("db error")}))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			stream, err := client.ListCustomers(context.Background(), &customerv1.ListCustomersRequest{})
+			if err != nil {
+				t.Fatalf("TEST[%d], failed.\n%s\nunexpected error %v", i+1, tc.desc, err)
+			}
+
+			var res []*customerv1.Customer
+
+			for {
+				c, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					if status.Code(err) != tc.code {
+						t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+					}
+
+					break
+				}
+
+				res = append(res, c)
+			}
+
+			if len(res) != len(tc.expected) {
+				t.Fatalf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+
+			for j := range res {
+				if !proto.Equal(tc.expected[j], res[j]) {
+					t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected[j], res[j])
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ListCustomersIsIncremental(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	received := make(chan struct{})
+
+	// The store holds back the second customer until the client has received the first one.
+	m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).DoAndReturn(func(_ *gofr.Context, _ models.Filter,
+		fn func(models.Customer) error) error {
+		if err := fn(models.Customer{ID: 1, Name: "Divya"}); err != nil {
+			return err
+		}
+
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Errorf("Expected the first customer to be sent before the listing ends")
+		}
+
+		return fn(models.Customer{ID: 2, Name: "Jay"})
+	})
+
+	stream, err := client.ListCustomers(context.Background(), &customerv1.ListCustomersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c, err := stream.Recv(); err != nil || c.GetId() != 1 {
+		t.Fatalf("Expected customer 1\nGot %v, %v", c, err)
+	}
+
+	close(received)
+
+	if c, err := stream.Recv(); err != nil || c.GetId() != 2 {
+		t.Errorf("Expected customer 2\nGot %v, %v", c, err)
+	}
+}
+
+func TestServer_CreateCustomer(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	dob := models.NewDate(2000, time.March, 14)
+	created := time.Date(2021, time.December, 1, 10, 0, 0, 0, time.UTC)
+	input := models.Customer{Name: "Divya", Email: "divya@example.com", DateOfBirth: &dob, Salary: salary}
+	customer1 := models.Customer{ID: 1, Name: "Divya", Email: "divya@example.com", DateOfBirth: &dob, Age: 22, Salary: salary,
+		CreatedAt: &created, UpdatedAt: &created}
+
+	tests := []struct {
+		desc     string
+		input    *customerv1.Customer
+		expected *customerv1.Customer
+		code     codes.Code
+		mock     *gomock.Call
+	}{
+		{"success", &customerv1.Customer{Name: "Divya", Email: "divya@example.com", DateOfBirth: "2000-03-14", Age: 40, Salary: salaryProto},
+			&customerv1.Customer{Id: 1, Name: "Divya", Email: "divya@example.com", DateOfBirth: "2000-03-14", Age: 22, Salary: salaryProto,
+				CreatedAt: timestamppb.New(created), UpdatedAt: timestamppb.New(created)}, codes.OK,
+			m.EXPECT().Create(gomock.Any(), input).Return(customer1, nil)},
+		{"missing customer", nil, nil, codes.InvalidArgument, nil},
+		{"invalid date of birth", &customerv1.Customer{Name: "Divya", DateOfBirth: "14-03-2000"}, nil, codes.InvalidArgument, nil},
+		{"invalid salary", &customerv1.Customer{Name: "Divya", Salary: &customerv1.Money{Amount: "30000.001", Currency: "INR"}},
+			nil, codes.InvalidArgument, nil},
+		{"already exists", &customerv1.Customer{Name: "Jay"}, nil, codes.AlreadyExists,
+			m.EXPECT().Create(gomock.Any(), models.Customer{Name: "Jay"}).Return(models.Customer{}, errors.EntityAlreadyExists{})},
+		{"salary without scope", &customerv1.Customer{Name: "Jay", Salary: salaryProto}, nil, codes.PermissionDenied,
+			m.EXPECT().Create(gomock.Any(), models.Customer{Name: "Jay", Salary: salary}).
+				Return(models.Customer{}, errors.ForbiddenRequest{URL: "salary"})},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := client.CreateCustomer(context.Background(), &customerv1.CreateCustomerRequest{Customer: tc.input})
+			if status.Code(err) != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+			}
+
+			if !proto.Equal(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestServer_UpdateCustomer(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	customer1 := models.Customer{ID: 1, Name: "Divya", Salary: salary}
+
+	tests := []struct {
+		desc     string
+		input    *customerv1.Customer
+		expected *customerv1.Customer
+		code     codes.Code
+		mock     *gomock.Call
+	}{
+		{"success", &customerv1.Customer{Id: 1, Name: "Divya", Salary: salaryProto},
+			&customerv1.Customer{Id: 1, Name: "Divya", Salary: salaryProto}, codes.OK,
+			m.EXPECT().Update(gomock.Any(), customer1).Return(customer1, nil)},
+		{"missing ID", &customerv1.Customer{Name: "Divya"}, nil, codes.InvalidArgument, nil},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := client.UpdateCustomer(context.Background(), &customerv1.UpdateCustomerRequest{Customer: tc.input})
+			if status.Code(err) != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+			}
+
+			if !proto.Equal(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestServer_PatchCustomer(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	name, phone := "Karan", "+919876543210"
+	attributes := models.Attributes{"plan": "gold", "seats": float64(5)}
+	attributesProto, _ := structpb.NewStruct(attributes)
+
+	tests := []struct {
+		desc     string
+		input    *customerv1.PatchCustomerRequest
+		expected *customerv1.Customer
+		code     codes.Code
+		mock     *gomock.Call
+	}{
+		{"success", &customerv1.PatchCustomerRequest{Id: 1, Name: &name},
+			&customerv1.Customer{Id: 1, Name: "Karan"}, codes.OK,
+			m.EXPECT().Patch(gomock.Any(), 1, models.Customer{Name: "Karan"}).Return(models.Customer{ID: 1, Name: "Karan"}, nil)},
+		{"contact details", &customerv1.PatchCustomerRequest{Id: 1, Phone: &phone},
+			&customerv1.Customer{Id: 1, Phone: phone}, codes.OK,
+			m.EXPECT().Patch(gomock.Any(), 1, models.Customer{Phone: phone}).Return(models.Customer{ID: 1, Phone: phone}, nil)},
+		{"salary", &customerv1.PatchCustomerRequest{Id: 1, Salary: salaryProto},
+			&customerv1.Customer{Id: 1, Salary: salaryProto}, codes.OK,
+			m.EXPECT().Patch(gomock.Any(), 1, models.Customer{Salary: salary}).Return(models.Customer{ID: 1, Salary: salary}, nil)},
+		{"attributes", &customerv1.PatchCustomerRequest{Id: 1, Attributes: attributesProto},
+			&customerv1.Customer{Id: 1, Attributes: attributesProto}, codes.OK,
+			m.EXPECT().Patch(gomock.Any(), 1, models.Customer{Attributes: attributes}).
+				Return(models.Customer{ID: 1, Attributes: attributes}, nil)},
+		{"missing ID", &customerv1.PatchCustomerRequest{Name: &name}, nil, codes.InvalidArgument, nil},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := client.PatchCustomer(context.Background(), tc.input)
+			if status.Code(err) != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+			}
+
+			if !proto.Equal(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestServer_DeleteCustomer(t *testing.T) {
+	_, m, client, done := connect(t)
+	defer done()
+
+	tests := []struct {
+		desc string
+		id   int64
+		code codes.Code
+		mock *gomock.Call
+	}{
+		{"success", 1, codes.OK, m.EXPECT().Delete(gomock.Any(), 1).Return(nil)},
+		{"not found", 2, codes.NotFound, m.EXPECT().Delete(gomock.Any(), 2).Return(errors.EntityNotFound{Entity: "customer", ID: "2"})},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := client.DeleteCustomer(context.Background(), &customerv1.DeleteCustomerRequest{Id: tc.id})
+			if status.Code(err) != tc.code {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.code, status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+type address struct {
+	store     store.AddressServiceIn
+	customers store.ServiceIn
+}
+
+func NewAddress(a store.AddressServiceIn, c store.ServiceIn) address {
+	return address{store: a, customers: c}
+}
+
+func (a address) List(ctx *gofr.Context, customerID int) ([]models.Address, error) {
+	ctx, span := tracing.Start(ctx, "service.Address.List", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if err := a.customerExists(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	res, err := a.store.List(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "Address.List", err, map[string]interface{}{"customerId": customerID})
+		return nil, err
+	}
+	return res, nil
+}
+
+func (a address) Get(ctx *gofr.Context, customerID, id int) (models.Address, error) {
+	ctx, span := tracing.Start(ctx, "service.Address.Get", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	res, err := a.store.Get(ctx, customerID, id)
+	if err != nil {
+		logDBError(ctx, "Address.Get", err, map[string]interface{}{"customerId": customerID, "id": id})
+		return models.Address{}, err
+	}
+	return res, nil
+}
+
+func (a address) Create(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	ctx, span := tracing.Start(ctx, "service.Address.Create", tracing.CustomerIDKey.Int(address.CustomerID))
+	defer span.End()
+
+	if err := validateAddress(address); err != nil {
+		return models.Address{}, err
+	}
+
+	if err := a.customerExists(ctx, address.CustomerID); err != nil {
+		return models.Address{}, err
+	}
+
+	res, err := a.store.Create(ctx, address)
+	if err != nil {
+		logDBError(ctx, "Address.Create", err, address.Redacted())
+		return models.Address{}, err
+	}
+	return res, nil
+}
+
+func (a address) Update(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	ctx, span := tracing.Start(ctx, "service.Address.Update", tracing.CustomerIDKey.Int(address.CustomerID))
+	defer span.End()
+
+	if err := validateAddress(address); err != nil {
+		return models.Address{}, err
+	}
+
+	res, err := a.store.Update(ctx, address)
+	if err != nil {
+		logDBError(ctx, "Address.Update", err, address.Redacted())
+		return models.Address{}, err
+	}
+	return res, nil
+}
+
+func (a address) Delete(ctx *gofr.Context, customerID, id int) error {
+	ctx, span := tracing.Start(ctx, "service.Address.Delete", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	err := a.store.Delete(ctx, customerID, id)
+	if err != nil {
+		logDBError(ctx, "Address.Delete", err, map[string]interface{}{"customerId": customerID, "id": id})
+	}
+	return err
+}
+
+// customerExists tells an unknown customer apart from a customer without addresses.
+func (a address) customerExists(ctx *gofr.Context, id int) error {
+	_, err := a.customers.GetByID(ctx, id)
+	if err != nil {
+		logDBError(ctx, "Address.customerExists", err, map[string]interface{}{"customerId": id})
+	}
+
+	return notFound(err, id)
+}
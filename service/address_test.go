@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"customer/mocks"
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+func connectAddress(t *testing.T) (*gomock.Controller, address, *mocks.MockAddressServiceIn, *mocks.MockServiceIn, *gofr.Context) {
+	ctrl := gomock.NewController(t)
+
+	a := mocks.NewMockAddressServiceIn(ctrl)
+	c := mocks.NewMockServiceIn(ctrl)
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.Background()
+
+	return ctrl, NewAddress(a, c), a, c, ctx
+}
+
+func TestAddress_List(t *testing.T) {
+	ctrl, s, a, c, ctx := connectAddress(t)
+	defer ctrl.Finish()
+
+	addresses := []models.Address{{ID: 1, CustomerID: 1, Type: models.AddressBilling, Country: "IN", PostalCode: "560001"}}
+
+	tests := []struct {
+		desc     string
+		expected []models.Address
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", addresses, nil, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{ID: 1}, nil),
+			a.EXPECT().List(gomock.Any(), 1).Return(addresses, nil)}},
+		{"unknown customer", nil, errors.EntityNotFound{Entity: "customer", ID: "1"}, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{}, sql.ErrNoRows)}},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")}, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{ID: 1}, nil),
+			a.EXPECT().List(gomock.Any(), 1).Return(nil, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.List(ctx, 1)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAddress_Create(t *testing.T) {
+	ctrl, s, a, c, ctx := connectAddress(t)
+	defer ctrl.Finish()
+
+	input := models.Address{CustomerID: 1, Type: models.AddressShipping, City: "Bengaluru", Country: "IN", PostalCode: "560001"}
+	created := input
+	created.ID = 7
+
+	tests := []struct {
+		desc     string
+		input    models.Address
+		expected models.Address
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", input, created, nil, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{ID: 1}, nil),
+			a.EXPECT().Create(gomock.Any(), input).Return(created, nil)}},
+		{"invalid type", models.Address{CustomerID: 1, Type: "home", Country: "IN", PostalCode: "560001"}, models.Address{},
+			errors.InvalidParam{Param: []string{"type"}}, nil},
+		{"invalid country", models.Address{CustomerID: 1, Type: models.AddressBilling, Country: "India", PostalCode: "560001"},
+			models.Address{}, errors.InvalidParam{Param: []string{"country"}}, nil},
+		{"missing postal code", models.Address{CustomerID: 1, Type: models.AddressBilling, Country: "IN"}, models.Address{},
+			errors.MissingParam{Param: []string{"postalCode"}}, nil},
+		{"unknown customer", input, models.Address{}, errors.EntityNotFound{Entity: "customer", ID: "1"}, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{}, sql.ErrNoRows)}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Create(ctx, tc.input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAddress_Update(t *testing.T) {
+	ctrl, s, a, _, ctx := connectAddress(t)
+	defer ctrl.Finish()
+
+	input := models.Address{ID: 7, CustomerID: 1, Type: models.AddressBilling, Country: "IN", PostalCode: "560002"}
+
+	tests := []struct {
+		desc     string
+		expected models.Address
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", input, nil, []*gomock.Call{a.EXPECT().Update(gomock.Any(), input).Return(input, nil)}},
+		{"not found", models.Address{}, errors.EntityNotFound{Entity: "address", ID: "7"}, []*gomock.Call{
+			a.EXPECT().Update(gomock.Any(), input).Return(models.Address{}, errors.EntityNotFound{Entity: "address", ID: "7"})}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Update(ctx, input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAddress_GetAndDelete(t *testing.T) {
+	ctrl, s, a, _, ctx := connectAddress(t)
+	defer ctrl.Finish()
+
+	notFound := errors.EntityNotFound{Entity: "address", ID: "9"}
+
+	a.EXPECT().Get(gomock.Any(), 1, 9).Return(models.Address{}, notFound)
+	a.EXPECT().Delete(gomock.Any(), 1, 9).Return(notFound)
+
+	if _, err := s.Get(ctx, 1, 9); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("Expected %v\nGot %v", notFound, err)
+	}
+
+	if err := s.Delete(ctx, 1, 9); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("Expected %v\nGot %v", notFound, err)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// attributes manages the custom attribute schema of a tenant. Only callers granted models.AttributeAdminScope
+// may change it.
+type attributes struct {
+	store store.AttributeServiceIn
+}
+
+func NewAttributes(a store.AttributeServiceIn) attributes {
+	return attributes{store: a}
+}
+
+func (a attributes) List(ctx *gofr.Context) (models.AttributeSchema, error) {
+	ctx, span := tracing.Start(ctx, "service.Attribute.List")
+	defer span.End()
+
+	res, err := a.store.List(ctx)
+	if err != nil {
+		logDBError(ctx, "Attribute.List", err, nil)
+		return nil, err
+	}
+	return res, nil
+}
+
+// Create defines an attribute. Existing customers are not checked against it, so a new required attribute is
+// only enforced on their next write.
+func (a attributes) Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error) {
+	ctx, span := tracing.Start(ctx, "service.Attribute.Create")
+	defer span.End()
+
+	if !middleware.Granted(ctx, models.AttributeAdminScope) {
+		return models.AttributeDefinition{}, errors.ForbiddenRequest{URL: "attributes"}
+	}
+
+	if err := d.Validate(); err != nil {
+		return models.AttributeDefinition{}, err
+	}
+
+	res, err := a.store.Create(ctx, d)
+	if err != nil {
+		logDBError(ctx, "Attribute.Create", err, map[string]interface{}{"name": d.Name})
+		return models.AttributeDefinition{}, err
+	}
+	return res, nil
+}
+
+// Delete removes an attribute from the schema and from every customer.
+func (a attributes) Delete(ctx *gofr.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "service.Attribute.Delete")
+	defer span.End()
+
+	if !middleware.Granted(ctx, models.AttributeAdminScope) {
+		return errors.ForbiddenRequest{URL: "attributes"}
+	}
+
+	err := a.store.Delete(ctx, name)
+	if err != nil {
+		logDBError(ctx, "Attribute.Delete", err, map[string]interface{}{"name": name})
+	}
+	return err
+}
+
+// validateAttributes checks the attributes of a customer against the schema of the tenant, as
+// models.AttributeSchema.Validate does.
+func validateAttributes(ctx *gofr.Context, schema store.AttributeServiceIn, a models.Attributes, partial bool) error {
+	s, err := schema.List(ctx)
+	if err != nil {
+		logDBError(ctx, "validateAttributes", err, nil)
+		return err
+	}
+
+	return s.Validate(a, partial)
+}
+
+// typedFilter types the attribute values of a filter by the schema of the tenant. The schema is only read when
+// the filter has attributes.
+func typedFilter(ctx *gofr.Context, schema store.AttributeServiceIn, filter models.Filter) (models.Filter, error) {
+	if len(filter.Attributes) == 0 {
+		return filter, nil
+	}
+
+	s, err := schema.List(ctx)
+	if err != nil {
+		logDBError(ctx, "typedFilter", err, nil)
+		return models.Filter{}, err
+	}
+
+	filter.Attributes, err = s.Typed(filter.Attributes)
+
+	return filter, err
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+func TestAttributes_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAttributeServiceIn(ctrl)
+	s := NewAttributes(m)
+
+	plan := models.AttributeDefinition{Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"}}
+	created := plan
+	created.ID = 1
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		input    models.AttributeDefinition
+		expected models.AttributeDefinition
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", []string{models.AttributeAdminScope}, plan, created, nil,
+			m.EXPECT().Create(gomock.Any(), plan).Return(created, nil)},
+		{"without the admin scope", salaryScopes, plan, models.AttributeDefinition{},
+			errors.ForbiddenRequest{URL: "attributes"}, nil},
+		{"invalid definition", []string{models.AttributeAdminScope}, models.AttributeDefinition{Name: "plan"},
+			models.AttributeDefinition{}, errors.InvalidParam{Param: []string{"type"}}, nil},
+		{"name taken", []string{models.AttributeAdminScope}, plan, models.AttributeDefinition{}, errors.EntityAlreadyExists{},
+			m.EXPECT().Create(gomock.Any(), plan).Return(models.AttributeDefinition{}, errors.EntityAlreadyExists{})},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Create(ctx, tc.input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAttributes_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockAttributeServiceIn(ctrl)
+	s := NewAttributes(m)
+
+	tests := []struct {
+		desc   string
+		scopes []string
+		err    error
+		mock   *gomock.Call
+	}{
+		{"success", []string{models.AttributeAdminScope}, nil, m.EXPECT().Delete(gomock.Any(), "plan").Return(nil)},
+		{"without the admin scope", nil, errors.ForbiddenRequest{URL: "attributes"}, nil},
+		{"not found", []string{models.AttributeAdminScope}, errors.EntityNotFound{Entity: "attribute", ID: "plan"},
+			m.EXPECT().Delete(gomock.Any(), "plan").Return(errors.EntityNotFound{Entity: "attribute", ID: "plan"})},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := s.Delete(ctx, "plan"); !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+// TestCustomer_Attributes checks the customer writes and listings against a schema with a required attribute.
+func TestCustomer_Attributes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, a := mocks.NewMockServiceIn(ctrl), mocks.NewMockAttributeServiceIn(ctrl)
+	h := New(m, a)
+
+	required := models.AttributeSchema{{Name: "plan", Type: models.AttributeString, Required: true},
+		{Name: "seats", Type: models.AttributeNumber}}
+	schemaOf := func(s models.AttributeSchema) *gomock.Call { return a.EXPECT().List(gomock.Any()).Return(s, nil) }
+	gold := models.Customer{ID: 1, Name: "Jay", Attributes: models.Attributes{"plan": "gold"}}
+	dbError := errors.DB{Err: errors.Error("db error")}
+
+	tests := []struct {
+		desc string
+		call func(ctx *gofr.Context) error
+		err  error
+		mock []*gomock.Call
+	}{
+		{"create", func(ctx *gofr.Context) error {
+			_, err := h.Create(ctx, gold)
+			return err
+		}, nil, []*gomock.Call{schemaOf(required), m.EXPECT().Create(gomock.Any(), gold).Return(gold, nil)}},
+		{"create without a required attribute", func(ctx *gofr.Context) error {
+			_, err := h.Create(ctx, models.Customer{Name: "Jay"})
+			return err
+		}, errors.MissingParam{Param: []string{"attributes.plan"}}, []*gomock.Call{schemaOf(required)}},
+		{"schema unavailable", func(ctx *gofr.Context) error {
+			_, err := h.Create(ctx, gold)
+			return err
+		}, dbError, []*gomock.Call{a.EXPECT().List(gomock.Any()).Return(nil, dbError)}},
+		{"update keeping the stored attributes", func(ctx *gofr.Context) error {
+			_, err := h.Update(ctx, models.Customer{ID: 1, Name: "Jay"})
+			return err
+		}, nil, []*gomock.Call{m.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(gold, nil)}},
+		{"patch removing a required attribute", func(ctx *gofr.Context) error {
+			_, err := h.Patch(ctx, 1, models.Customer{Attributes: models.Attributes{"plan": nil}})
+			return err
+		}, errors.InvalidParam{Param: []string{"attributes.plan"}}, []*gomock.Call{schemaOf(required)}},
+		{"patch removing an optional attribute", func(ctx *gofr.Context) error {
+			_, err := h.Patch(ctx, 1, models.Customer{Attributes: models.Attributes{"seats": nil}})
+			return err
+		}, nil, []*gomock.Call{schemaOf(required),
+			m.EXPECT().Patch(gomock.Any(), 1, models.Customer{Attributes: models.Attributes{"seats": nil}}).Return(gold, nil)}},
+		{"typed filter", func(ctx *gofr.Context) error {
+			_, err := h.Get(ctx, models.Filter{Attributes: models.Attributes{"seats": "5"}})
+			return err
+		}, nil, []*gomock.Call{schemaOf(required),
+			m.EXPECT().Get(gomock.Any(), models.Filter{Attributes: models.Attributes{"seats": float64(5)}}).Return(nil, nil)}},
+		{"filter on an unknown attribute", func(ctx *gofr.Context) error {
+			_, err := h.Get(ctx, models.Filter{Attributes: models.Attributes{"region": "north"}})
+			return err
+		}, errors.InvalidParam{Param: []string{"attr.region"}}, []*gomock.Call{schemaOf(required)}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), salaryScopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tc.call(ctx); !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
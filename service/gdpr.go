@@ -0,0 +1,118 @@
+package service
+
+import (
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// gdpr answers the access and erasure requests of customers. Only callers granted models.GDPRAdminScope may
+// make them, since an export holds every field of a customer whatever the other scopes of the caller.
+type gdpr struct {
+	store         store.GDPRServiceIn
+	customers     store.ServiceIn
+	addresses     store.AddressServiceIn
+	transitions   store.TransitionServiceIn
+	tags          store.TagServiceIn
+	relationships store.RelationshipServiceIn
+	now           func() time.Time
+}
+
+func NewGDPR(g store.GDPRServiceIn, c store.ServiceIn, a store.AddressServiceIn, t store.TransitionServiceIn,
+	tags store.TagServiceIn, r store.RelationshipServiceIn) gdpr {
+	return gdpr{store: g, customers: c, addresses: a, transitions: t, tags: tags, relationships: r, now: time.Now}
+}
+
+// Export gathers everything kept about a customer.
+func (g gdpr) Export(ctx *gofr.Context, customerID int) (models.Export, error) {
+	ctx, span := tracing.Start(ctx, "service.GDPR.Export", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if !middleware.Granted(ctx, models.GDPRAdminScope) {
+		return models.Export{}, errors.ForbiddenRequest{URL: "gdpr"}
+	}
+
+	res := models.Export{ExportedAt: g.now()}
+
+	customer, err := g.customers.GetByID(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "GDPR.Export", err, map[string]interface{}{"customerId": customerID})
+		return models.Export{}, notFound(err, customerID)
+	}
+	res.Customer = customer.WithAge(res.ExportedAt)
+
+	if res.Merged, err = g.store.Merged(ctx, customerID); err != nil {
+		return models.Export{}, exportError(ctx, err, customerID)
+	}
+
+	for i := range res.Merged {
+		res.Merged[i] = res.Merged[i].WithAge(res.ExportedAt)
+	}
+
+	if res.Addresses, err = g.addresses.List(ctx, customerID); err != nil {
+		return models.Export{}, exportError(ctx, err, customerID)
+	}
+
+	if res.Transitions, err = g.transitions.List(ctx, customerID); err != nil {
+		return models.Export{}, exportError(ctx, err, customerID)
+	}
+
+	if res.Tags, err = g.tags.List(ctx, customerID); err != nil {
+		return models.Export{}, exportError(ctx, err, customerID)
+	}
+
+	if res.Relationships, err = g.relationships.List(ctx, customerID); err != nil {
+		return models.Export{}, exportError(ctx, err, customerID)
+	}
+
+	if res.Audit, err = g.store.Audit(ctx, customerID); err != nil {
+		return models.Export{}, exportError(ctx, err, customerID)
+	}
+	return res, nil
+}
+
+// Erase irreversibly removes the personal data of a customer and of the customers merged into it, and
+// records the erasure in the erasure log of the tenant.
+func (g gdpr) Erase(ctx *gofr.Context, customerID int) (models.Erasure, error) {
+	ctx, span := tracing.Start(ctx, "service.GDPR.Erase", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if !middleware.Granted(ctx, models.GDPRAdminScope) {
+		return models.Erasure{}, errors.ForbiddenRequest{URL: "gdpr"}
+	}
+
+	res, err := g.store.Erase(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "GDPR.Erase", err, map[string]interface{}{"customerId": customerID})
+		return models.Erasure{}, notFound(err, customerID)
+	}
+	return res, nil
+}
+
+// Erasures returns the erasure log of the tenant, and whether its hash chain is intact.
+func (g gdpr) Erasures(ctx *gofr.Context) (models.ErasureLog, error) {
+	ctx, span := tracing.Start(ctx, "service.GDPR.Erasures")
+	defer span.End()
+
+	if !middleware.Granted(ctx, models.GDPRAdminScope) {
+		return models.ErasureLog{}, errors.ForbiddenRequest{URL: "gdpr"}
+	}
+
+	res, err := g.store.Erasures(ctx)
+	if err != nil {
+		logDBError(ctx, "GDPR.Erasures", err, nil)
+		return models.ErasureLog{}, err
+	}
+	return models.VerifyErasures(middleware.Tenant(ctx), res), nil
+}
+
+func exportError(ctx *gofr.Context, err error, customerID int) error {
+	logDBError(ctx, "GDPR.Export", err, map[string]interface{}{"customerId": customerID})
+	return err
+}
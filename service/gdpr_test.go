@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGDPR_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g, c, a := mocks.NewMockGDPRServiceIn(ctrl), mocks.NewMockServiceIn(ctrl), mocks.NewMockAddressServiceIn(ctrl)
+	tr, tags, r := mocks.NewMockTransitionServiceIn(ctrl), mocks.NewMockTagServiceIn(ctrl), mocks.NewMockRelationshipServiceIn(ctrl)
+	now := time.Date(2022, time.March, 14, 0, 0, 0, 0, time.UTC)
+	s := NewGDPR(g, c, a, tr, tags, r)
+	s.now = func() time.Time { return now }
+
+	dob := models.NewDate(2000, time.March, 14)
+	divya := models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Salary: &models.Money{Minor: 3000000, Currency: "INR"}}
+	aged := divya
+	aged.Age = 22
+	export := models.Export{
+		Customer:      aged,
+		Merged:        []models.Customer{{ID: 4, Name: "Divya K"}},
+		Addresses:     []models.Address{{ID: 1, CustomerID: 1, Type: models.AddressBilling, Country: "IN", PostalCode: "560001"}},
+		Transitions:   []models.Transition{{ID: 1, CustomerID: 1, From: models.StatusProspect, To: models.StatusActive}},
+		Tags:          []string{"vip"},
+		Relationships: []models.Relationship{},
+		Audit:         []models.AuditEntry{{ID: 1, Action: "merge", CustomerID: 1}},
+		ExportedAt:    now,
+	}
+	dbError := errors.DB{Err: errors.Error("db error")}
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		expected models.Export
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", []string{models.GDPRAdminScope}, export, nil, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(divya, nil),
+			g.EXPECT().Merged(gomock.Any(), 1).Return(export.Merged, nil),
+			a.EXPECT().List(gomock.Any(), 1).Return(export.Addresses, nil),
+			tr.EXPECT().List(gomock.Any(), 1).Return(export.Transitions, nil),
+			tags.EXPECT().List(gomock.Any(), 1).Return(export.Tags, nil),
+			r.EXPECT().List(gomock.Any(), 1).Return(export.Relationships, nil),
+			g.EXPECT().Audit(gomock.Any(), 1).Return(export.Audit, nil),
+		}},
+		{"without the gdpr scope", salaryScopes, models.Export{}, errors.ForbiddenRequest{URL: "gdpr"}, nil},
+		{"not found", []string{models.GDPRAdminScope}, models.Export{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{c.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{}, sql.ErrNoRows)}},
+		{"internal server error", []string{models.GDPRAdminScope}, models.Export{}, dbError, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1).Return(divya, nil),
+			g.EXPECT().Merged(gomock.Any(), 1).Return(nil, nil),
+			a.EXPECT().List(gomock.Any(), 1).Return(nil, dbError),
+		}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Export(ctx, 1)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestGDPR_Erase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := mocks.NewMockGDPRServiceIn(ctrl)
+	s := NewGDPR(g, nil, nil, nil, nil, nil)
+
+	erasure := models.Erasure{ID: 1, CustomerID: 1, PrevHash: models.GenesisHash, Hash: "ab"}
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		expected models.Erasure
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", []string{models.GDPRAdminScope}, erasure, nil, g.EXPECT().Erase(gomock.Any(), 1).Return(erasure, nil)},
+		{"without the gdpr scope", salaryScopes, models.Erasure{}, errors.ForbiddenRequest{URL: "gdpr"}, nil},
+		{"not found", []string{models.GDPRAdminScope}, models.Erasure{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			g.EXPECT().Erase(gomock.Any(), 1).Return(models.Erasure{}, sql.ErrNoRows)},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Erase(ctx, 1)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestGDPR_Erasures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := mocks.NewMockGDPRServiceIn(ctrl)
+	s := NewGDPR(g, nil, nil, nil, nil, nil)
+
+	first := models.Erasure{ID: 1, CustomerID: 1, PrevHash: models.GenesisHash}
+	first.Hash = first.Digest("zopsmart")
+	tampered := first
+	tampered.CustomerID = 2
+
+	tests := []struct {
+		desc     string
+		expected models.ErasureLog
+		mock     *gomock.Call
+	}{
+		{"intact", models.ErasureLog{Erasures: []models.Erasure{first}, Intact: true},
+			g.EXPECT().Erasures(gomock.Any()).Return([]models.Erasure{first}, nil)},
+		{"tampered", models.ErasureLog{Erasures: []models.Erasure{tampered}, BrokenAt: 1},
+			g.EXPECT().Erasures(gomock.Any()).Return([]models.Erasure{tampered}, nil)},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), models.GDPRAdminScope)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Erasures(ctx)
+			if err != nil {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, nil, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
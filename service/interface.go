@@ -7,10 +7,73 @@ import (
 )
 
 type HandlerIn interface {
-	Get(ctx *gofr.Context) ([]models.Customer, error)
-	GetByID(ctx *gofr.Context, id int) (models.Customer, error)
+	Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error)
+	Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error
+	GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error)
+	GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error)
 	Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error)
+	Upsert(ctx *gofr.Context, customer models.Customer) (res models.Customer, created bool, err error)
 	Update(ctx *gofr.Context, customer models.Customer) (models.Customer, error)
 	Delete(ctx *gofr.Context, id int) error
 	Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error)
 }
+
+type AddressHandlerIn interface {
+	List(ctx *gofr.Context, customerID int) ([]models.Address, error)
+	Get(ctx *gofr.Context, customerID, id int) (models.Address, error)
+	Create(ctx *gofr.Context, address models.Address) (models.Address, error)
+	Update(ctx *gofr.Context, address models.Address) (models.Address, error)
+	Delete(ctx *gofr.Context, customerID, id int) error
+}
+
+type StatsHandlerIn interface {
+	Get(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error)
+}
+
+type MergeHandlerIn interface {
+	Duplicates(ctx *gofr.Context) ([]models.Duplicate, error)
+	Merge(ctx *gofr.Context, req models.MergeRequest) (models.Customer, error)
+}
+
+type TransitionHandlerIn interface {
+	Create(ctx *gofr.Context, t models.Transition) (models.Transition, error)
+	List(ctx *gofr.Context, customerID int) ([]models.Transition, error)
+}
+
+type TagHandlerIn interface {
+	List(ctx *gofr.Context, customerID int) ([]string, error)
+	Add(ctx *gofr.Context, customerID int, name string) ([]string, error)
+	Remove(ctx *gofr.Context, customerID int, name string) error
+}
+
+type SegmentHandlerIn interface {
+	List(ctx *gofr.Context) ([]models.Segment, error)
+	Get(ctx *gofr.Context, id int) (models.Segment, error)
+	Create(ctx *gofr.Context, segment models.Segment) (models.Segment, error)
+	Update(ctx *gofr.Context, segment models.Segment) (models.Segment, error)
+	Delete(ctx *gofr.Context, id int) error
+	Customers(ctx *gofr.Context, id int, page models.Filter) ([]models.Customer, error)
+	Stream(ctx *gofr.Context, id int, page models.Filter, fn func(models.Customer) error) error
+}
+
+type AttributeHandlerIn interface {
+	List(ctx *gofr.Context) (models.AttributeSchema, error)
+	Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error)
+	Delete(ctx *gofr.Context, name string) error
+}
+
+type RelationshipHandlerIn interface {
+	Create(ctx *gofr.Context, r models.Relationship) (models.Relationship, error)
+	List(ctx *gofr.Context, customerID int) ([]models.Relationship, error)
+	Graph(ctx *gofr.Context, customerID, depth int) (models.Graph, error)
+}
+
+type GDPRHandlerIn interface {
+	Export(ctx *gofr.Context, customerID int) (models.Export, error)
+	Erase(ctx *gofr.Context, customerID int) (models.Erasure, error)
+	Erasures(ctx *gofr.Context) (models.ErasureLog, error)
+}
+
+type KeysHandlerIn interface {
+	Rotate(ctx *gofr.Context) (models.DataKey, error)
+}
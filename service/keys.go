@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// keys rotates the data keys that encrypt the names and salaries of the customers, and re-encrypts the
+// customers with the current data key in batches of batch customers.
+type keys struct {
+	store store.KeyServiceIn
+	batch int
+}
+
+func NewKeys(k store.KeyServiceIn, batch int) keys {
+	return keys{store: k, batch: batch}
+}
+
+// Rotate starts encrypting the customers of the tenant with a new data key. The customers encrypted with older
+// ones stay readable, and are re-encrypted by Run. Only callers granted models.KeysAdminScope may rotate.
+func (k keys) Rotate(ctx *gofr.Context) (models.DataKey, error) {
+	ctx, span := tracing.Start(ctx, "service.Keys.Rotate")
+	defer span.End()
+
+	if !middleware.Granted(ctx, models.KeysAdminScope) {
+		return models.DataKey{}, errors.ForbiddenRequest{URL: "keys"}
+	}
+
+	res, err := k.store.Rotate(ctx)
+	if err == store.ErrNotEncrypted {
+		return models.DataKey{}, conflict("ENCRYPTION_DISABLED", "customers are not encrypted: no master key is configured")
+	}
+	if err != nil {
+		logDBError(ctx, "Keys.Rotate", err, nil)
+		return models.DataKey{}, err
+	}
+	return res, nil
+}
+
+// Reencrypt encrypts the customers of the tenant of ctx that are in plaintext or encrypted with an older data
+// key, batch after batch, until a batch comes back short. It returns how many customers it encrypted.
+func (k keys) Reencrypt(ctx *gofr.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "service.Keys.Reencrypt")
+	defer span.End()
+
+	var total int
+
+	for {
+		n, err := k.store.Reencrypt(ctx, k.batch)
+		total += n
+
+		if err != nil {
+			logDBError(ctx, "Keys.Reencrypt", err, map[string]interface{}{"reencrypted": total})
+			return total, err
+		}
+
+		if n < k.batch || ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
+
+// Run re-encrypts the customers of every tenant every interval until ctx is cancelled.
+func (k keys) Run(ctx context.Context, app *gofr.Gofr, tenants []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, tenant := range tenants {
+			c := gofr.NewContext(nil, nil, app)
+			c.Context = middleware.WithTenant(ctx, tenant)
+
+			if _, err := k.Reencrypt(c); err != nil {
+				app.Logger.Errorf("re-encrypting customers of tenant %v: %v", tenant, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"customer/store"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeys_Rotate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	k := mocks.NewMockKeyServiceIn(ctrl)
+	s := NewKeys(k, 100)
+
+	key := models.DataKey{ID: 2, CreatedAt: time.Date(2022, time.March, 14, 0, 0, 0, 0, time.UTC)}
+	disabled := &errors.Response{StatusCode: http.StatusConflict, Code: "ENCRYPTION_DISABLED",
+		Reason: "customers are not encrypted: no master key is configured"}
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		expected models.DataKey
+		err      error
+		mock     *gomock.Call
+	}{
+		{"success", []string{models.KeysAdminScope}, key, nil, k.EXPECT().Rotate(gomock.Any()).Return(key, nil)},
+		{"without the keys scope", salaryScopes, models.DataKey{}, errors.ForbiddenRequest{URL: "keys"}, nil},
+		{"no master key", []string{models.KeysAdminScope}, models.DataKey{}, disabled,
+			k.EXPECT().Rotate(gomock.Any()).Return(models.DataKey{}, store.ErrNotEncrypted)},
+		{"db error", []string{models.KeysAdminScope}, models.DataKey{}, errors.DB{Err: errors.Error("db error")},
+			k.EXPECT().Rotate(gomock.Any()).Return(models.DataKey{}, errors.DB{Err: errors.Error("db error")})},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Rotate(ctx)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestKeys_Reencrypt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	k := mocks.NewMockKeyServiceIn(ctrl)
+	s := NewKeys(k, 2)
+	dbError := errors.DB{Err: errors.Error("db error")}
+
+	tests := []struct {
+		desc     string
+		expected int
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"until a batch comes back short", 5, nil, []*gomock.Call{
+			k.EXPECT().Reencrypt(gomock.Any(), 2).Return(2, nil),
+			k.EXPECT().Reencrypt(gomock.Any(), 2).Return(2, nil),
+			k.EXPECT().Reencrypt(gomock.Any(), 2).Return(1, nil),
+		}},
+		{"nothing to re-encrypt", 0, nil, []*gomock.Call{
+			k.EXPECT().Reencrypt(gomock.Any(), 2).Return(0, nil),
+		}},
+		{"db error", 2, dbError, []*gomock.Call{
+			k.EXPECT().Reencrypt(gomock.Any(), 2).Return(2, nil),
+			k.EXPECT().Reencrypt(gomock.Any(), 2).Return(0, dbError),
+		}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Reencrypt(ctx)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if res != tc.expected {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
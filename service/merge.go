@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// merge finds duplicate customers and merges them. Finding them scans the names of every customer of a tenant,
+// so the duplicates are cached per tenant until the next scan and listing them does not hit the table.
+type merge struct {
+	store     store.ServiceIn
+	rules     models.MergeRules
+	threshold float64
+	now       func() time.Time
+
+	mu         sync.Mutex
+	duplicates map[string][]models.Duplicate
+}
+
+// NewMerge merges customers by rules. Two customers are duplicates when the similarity of their normalised
+// names is at least threshold.
+func NewMerge(s store.ServiceIn, rules models.MergeRules, threshold float64) *merge {
+	return &merge{store: s, rules: rules, threshold: threshold, now: time.Now, duplicates: map[string][]models.Duplicate{}}
+}
+
+// Run scans the customers of every tenant for duplicates every interval until ctx is cancelled.
+func (m *merge) Run(ctx context.Context, app *gofr.Gofr, tenants []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, tenant := range tenants {
+			c := gofr.NewContext(nil, nil, app)
+			c.Context = middleware.WithTenant(ctx, tenant)
+
+			if _, err := m.Scan(c); err != nil {
+				app.Logger.Errorf("scanning duplicate customers of tenant %v: %v", tenant, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Duplicates returns the likely duplicates among the customers of the tenant of ctx, best first, as found by
+// the last scan. A tenant that was never scanned is scanned now.
+func (m *merge) Duplicates(ctx *gofr.Context) ([]models.Duplicate, error) {
+	ctx, span := tracing.Start(ctx, "service.Merge.Duplicates")
+	defer span.End()
+
+	m.mu.Lock()
+	res, ok := m.duplicates[middleware.Tenant(ctx)]
+	m.mu.Unlock()
+
+	if ok {
+		return res, nil
+	}
+
+	return m.Scan(ctx)
+}
+
+// Scan finds the duplicates among the customers of the tenant of ctx. Names are only compared within the
+// same first letter, which keeps the scan fast on large tables at the cost of missing typos in that letter.
+func (m *merge) Scan(ctx *gofr.Context) ([]models.Duplicate, error) {
+	ctx, span := tracing.Start(ctx, "service.Merge.Scan")
+	defer span.End()
+
+	type candidate struct {
+		customer models.Customer
+		name     string
+	}
+
+	blocks := map[rune][]candidate{}
+
+	err := m.store.Stream(ctx, models.Filter{Fields: []string{"id", "name"}}, func(c models.Customer) error {
+		name := models.NormalizeName(c.Name)
+		if name != "" {
+			first := []rune(name)[0]
+			blocks[first] = append(blocks[first], candidate{customer: c, name: name})
+		}
+
+		return nil
+	})
+	if err != nil {
+		logDBError(ctx, "Merge.Scan", err, nil)
+		return nil, err
+	}
+
+	res := []models.Duplicate{}
+
+	for _, block := range blocks {
+		for i := range block {
+			for j := i + 1; j < len(block); j++ {
+				score := models.NameSimilarity(block[i].name, block[j].name)
+				if score >= m.threshold {
+					res = append(res, models.Duplicate{Customers: [2]models.Customer{block[i].customer, block[j].customer}, Score: score})
+				}
+			}
+		}
+	}
+
+	for i := range res {
+		if res[i].Customers[0].ID > res[i].Customers[1].ID {
+			res[i].Customers[0], res[i].Customers[1] = res[i].Customers[1], res[i].Customers[0]
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+
+		if a.Customers[0].ID != b.Customers[0].ID {
+			return a.Customers[0].ID < b.Customers[0].ID
+		}
+
+		return a.Customers[1].ID < b.Customers[1].ID
+	})
+
+	m.mu.Lock()
+	m.duplicates[middleware.Tenant(ctx)] = res
+	m.mu.Unlock()
+
+	return res, nil
+}
+
+// Merge merges the losers into the survivor by the rules, moves their addresses, tags and relationships to the
+// survivor and soft-deletes them. A merge whose relationships would make a hierarchy cycle is refused, and so
+// is one the store cannot move them for. The fields the caller may not write are never taken from the losers.
+func (m *merge) Merge(ctx *gofr.Context, req models.MergeRequest) (models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.Merge", tracing.CustomerIDKey.Int(req.SurvivorID))
+	defer span.End()
+
+	if err := validateMerge(req); err != nil {
+		return models.Customer{}, err
+	}
+
+	res, err := m.store.Merge(ctx, req.SurvivorID, req.LoserIDs,
+		func(survivor models.Customer, losers []models.Customer) models.Customer {
+			merged, _ := m.rules.Merge(survivor, losers).Preserving(granted(ctx), func() (models.Customer, error) {
+				return survivor, nil
+			})
+
+			return merged
+		})
+	if err == store.ErrCycle {
+		return models.Customer{}, conflict("RELATIONSHIP_CYCLE",
+			fmt.Sprintf("merging customers %v into customer %v would make a relationship cycle", req.LoserIDs, req.SurvivorID))
+	}
+
+	if err == store.ErrMergeHeld {
+		return models.Customer{}, conflict("MERGE_HELD",
+			fmt.Sprintf("customers %v have addresses, tags or relationships, which this store cannot merge", req.LoserIDs))
+	}
+
+	if err != nil {
+		logDBError(ctx, "Merge", err, map[string]interface{}{"survivorId": req.SurvivorID, "loserIds": req.LoserIDs})
+		return models.Customer{}, err
+	}
+
+	// The merged customers are no longer duplicates, so the next listing scans again.
+	m.mu.Lock()
+	delete(m.duplicates, middleware.Tenant(ctx))
+	m.mu.Unlock()
+
+	return res.WithAge(m.now()).Masked(granted(ctx)), nil
+}
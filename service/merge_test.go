@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"customer/store"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// named returns customers with the given names, numbered from 1.
+func named(names ...string) []models.Customer {
+	customers := make([]models.Customer, len(names))
+	for i, name := range names {
+		customers[i] = models.Customer{ID: i + 1, Name: name}
+	}
+
+	return customers
+}
+
+func TestMerge_Duplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewMerge(m, models.MergeRules{}, 0.8)
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+	c := named("Jay", "jay ", "J.A.Y", "Divya", "Divia", "Karan", "")
+	pair := func(a, b int, score float64) models.Duplicate {
+		return models.Duplicate{Customers: [2]models.Customer{c[a-1], c[b-1]}, Score: score}
+	}
+
+	// The listing is only scanned once, then served from the scan.
+	m.EXPECT().Stream(gomock.Any(), models.Filter{Fields: []string{"id", "name"}}, gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, _ models.Filter, fn func(models.Customer) error) error {
+			for i := range c {
+				if err := fn(c[i]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	expected := []models.Duplicate{pair(1, 2, 1), pair(1, 3, 1), pair(2, 3, 1), pair(4, 5, 0.8)}
+
+	for i := 0; i < 2; i++ {
+		res, err := s.Duplicates(ctx)
+		if err != nil {
+			t.Errorf("TEST[%d], failed.\nExpected <nil>\nGot %v", i+1, err)
+		}
+
+		if !reflect.DeepEqual(res, expected) {
+			t.Errorf("TEST[%d], failed.\nExpected %v\nGot %v", i+1, expected, res)
+		}
+	}
+}
+
+func TestMerge_DuplicatesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewMerge(m, models.MergeRules{}, 0.8)
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+	dbError := errors.DB{Err: errors.Error("db error")}
+	m.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).Return(dbError)
+
+	if res, err := s.Duplicates(ctx); !reflect.DeepEqual(err, dbError) || res != nil {
+		t.Errorf("Expected %v\nGot %v, %v", dbError, res, err)
+	}
+}
+
+func TestMerge_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewMerge(m, models.MergeRules{}, 0.8)
+	s.now = func() time.Time { return now }
+
+	survivor := models.Customer{ID: 1, Name: "Jay", Email: "jay@example.com"}
+	losers := []models.Customer{{ID: 2, Name: "jay", Phone: "+919876543211", DateOfBirth: &dob, Salary: salary}}
+
+	// merged hands the stored customers to the merge of the service, as the store does within its transaction.
+	merged := func(_ *gofr.Context, _ int, _ []int,
+		merge func(models.Customer, []models.Customer) models.Customer) (models.Customer, error) {
+		return merge(survivor, losers), nil
+	}
+
+	notFound := errors.EntityNotFound{Entity: "customer", ID: "2"}
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		req      models.MergeRequest
+		expected models.Customer
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", salaryScopes, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2}},
+			models.Customer{ID: 1, Name: "Jay", Email: "jay@example.com", Phone: "+919876543211", DateOfBirth: &dob,
+				Age: 22, Salary: salary}, nil,
+			[]*gomock.Call{m.EXPECT().Merge(gomock.Any(), 1, []int{2}, gomock.Any()).DoAndReturn(merged)}},
+		{"salary not taken without scope", []string{"customer:salary:read"}, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2}},
+			models.Customer{ID: 1, Name: "Jay", Email: "jay@example.com", Phone: "+919876543211", DateOfBirth: &dob, Age: 22},
+			nil, []*gomock.Call{m.EXPECT().Merge(gomock.Any(), 1, []int{2}, gomock.Any()).DoAndReturn(merged)}},
+		{"loser not found", salaryScopes, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2}}, models.Customer{}, notFound,
+			[]*gomock.Call{m.EXPECT().Merge(gomock.Any(), 1, []int{2}, gomock.Any()).Return(models.Customer{}, notFound)}},
+		{"relationship cycle", salaryScopes, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2}}, models.Customer{},
+			conflict("RELATIONSHIP_CYCLE", "merging customers [2] into customer 1 would make a relationship cycle"),
+			[]*gomock.Call{m.EXPECT().Merge(gomock.Any(), 1, []int{2}, gomock.Any()).Return(models.Customer{}, store.ErrCycle)}},
+		{"losers held by the database", salaryScopes, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2}}, models.Customer{},
+			conflict("MERGE_HELD", "customers [2] have addresses, tags or relationships, which this store cannot merge"),
+			[]*gomock.Call{m.EXPECT().Merge(gomock.Any(), 1, []int{2}, gomock.Any()).Return(models.Customer{}, store.ErrMergeHeld)}},
+		{"missing survivor", salaryScopes, models.MergeRequest{LoserIDs: []int{2}}, models.Customer{},
+			errors.InvalidParam{Param: []string{"survivorId"}}, nil},
+		{"missing losers", salaryScopes, models.MergeRequest{SurvivorID: 1}, models.Customer{},
+			errors.MissingParam{Param: []string{"loserIds"}}, nil},
+		{"survivor among losers", salaryScopes, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2, 1}}, models.Customer{},
+			errors.InvalidParam{Param: []string{"loserIds"}}, nil},
+		{"repeated loser", salaryScopes, models.MergeRequest{SurvivorID: 1, LoserIDs: []int{2, 2}}, models.Customer{},
+			errors.InvalidParam{Param: []string{"loserIds"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Merge(ctx, tc.req)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// relationships relates customers to each other. Customers are read through the customer service, so that the
+// customers of a graph are presented like any other.
+type relationships struct {
+	store     store.RelationshipServiceIn
+	customers HandlerIn
+}
+
+func NewRelationships(r store.RelationshipServiceIn, c HandlerIn) relationships {
+	return relationships{store: r, customers: c}
+}
+
+// Create relates two customers of the tenant. A parent or referral relationship that would make a customer its
+// own ancestor is rejected with 409 Conflict, as is a relationship that already exists.
+func (r relationships) Create(ctx *gofr.Context, rel models.Relationship) (models.Relationship, error) {
+	ctx, span := tracing.Start(ctx, "service.Relationship.Create", tracing.CustomerIDKey.Int(rel.FromID))
+	defer span.End()
+
+	if err := validateRelationship(rel); err != nil {
+		return models.Relationship{}, err
+	}
+
+	found, err := r.customers.GetByIDs(ctx, []int{rel.FromID, rel.ToID})
+	if err != nil {
+		return models.Relationship{}, err
+	}
+
+	if id, ok := missing(found, rel.FromID, rel.ToID); ok {
+		return models.Relationship{}, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(id)}
+	}
+
+	res, err := r.store.Create(ctx, rel)
+	if err == sql.ErrNoRows {
+		return models.Relationship{}, conflict("RELATIONSHIP_CYCLE",
+			fmt.Sprintf("customer %v already is a %v ancestor of customer %v", rel.ToID, rel.Type, rel.FromID))
+	}
+	if err != nil {
+		logDBError(ctx, "Relationship.Create", err, map[string]interface{}{"fromId": rel.FromID, "toId": rel.ToID})
+		return models.Relationship{}, err
+	}
+	return res, nil
+}
+
+// List returns the relationships of a customer in either direction, oldest first.
+func (r relationships) List(ctx *gofr.Context, customerID int) ([]models.Relationship, error) {
+	ctx, span := tracing.Start(ctx, "service.Relationship.List", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if _, err := r.customers.GetByID(ctx, customerID, "id"); err != nil {
+		return nil, err
+	}
+
+	res, err := r.store.List(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "Relationship.List", err, map[string]interface{}{"customerId": customerID})
+		return nil, err
+	}
+	return res, nil
+}
+
+// Graph returns the customers at most depth relationships away from a customer, and the relationships between
+// them.
+func (r relationships) Graph(ctx *gofr.Context, customerID, depth int) (models.Graph, error) {
+	ctx, span := tracing.Start(ctx, "service.Relationship.Graph", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if depth < 1 || depth > models.MaxGraphDepth {
+		return models.Graph{}, errors.InvalidParam{Param: []string{"depth"}}
+	}
+
+	edges, err := r.store.Graph(ctx, customerID, depth)
+	if err != nil {
+		logDBError(ctx, "Relationship.Graph", err, map[string]interface{}{"customerId": customerID})
+		return models.Graph{}, err
+	}
+
+	ids := []int{customerID}
+	seen := map[int]bool{customerID: true}
+
+	for _, e := range edges {
+		for _, id := range []int{e.FromID, e.ToID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	customers, err := r.customers.GetByIDs(ctx, ids)
+	if err != nil {
+		return models.Graph{}, err
+	}
+
+	if _, ok := missing(customers, customerID); ok {
+		return models.Graph{}, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(customerID)}
+	}
+	return models.Graph{Customers: customers, Relationships: edges}, nil
+}
+
+// missing returns the first of ids that none of the customers has.
+func missing(customers []models.Customer, ids ...int) (int, bool) {
+	for _, id := range ids {
+		found := false
+		for i := range customers {
+			found = found || customers[i].ID == id
+		}
+
+		if !found {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
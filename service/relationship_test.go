@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRelationships_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockRelationshipServiceIn(ctrl), mocks.NewMockHandlerIn(ctrl)
+	s := NewRelationships(m, c)
+
+	parent := models.Relationship{Type: models.RelationshipParent, FromID: 2, ToID: 1}
+	created := parent
+	created.ID = 1
+	both := []models.Customer{{ID: 1, Name: "Acme"}, {ID: 2, Name: "Acme India"}}
+
+	tests := []struct {
+		desc     string
+		input    models.Relationship
+		expected models.Relationship
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", parent, created, nil, []*gomock.Call{
+			c.EXPECT().GetByIDs(gomock.Any(), []int{2, 1}).Return(both, nil),
+			m.EXPECT().Create(gomock.Any(), parent).Return(created, nil),
+		}},
+		{"unknown type", models.Relationship{Type: "sibling", FromID: 2, ToID: 1}, models.Relationship{},
+			errors.InvalidParam{Param: []string{"type"}}, nil},
+		{"related to itself", models.Relationship{Type: models.RelationshipParent, FromID: 2, ToID: 2}, models.Relationship{},
+			errors.InvalidParam{Param: []string{"toId"}}, nil},
+		{"unknown customer", parent, models.Relationship{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{c.EXPECT().GetByIDs(gomock.Any(), []int{2, 1}).Return(both[1:], nil)}},
+		{"cycle", parent, models.Relationship{}, &errors.Response{StatusCode: http.StatusConflict, Code: "RELATIONSHIP_CYCLE",
+			Reason: "customer 1 already is a parent ancestor of customer 2"}, []*gomock.Call{
+			c.EXPECT().GetByIDs(gomock.Any(), []int{2, 1}).Return(both, nil),
+			m.EXPECT().Create(gomock.Any(), parent).Return(models.Relationship{}, sql.ErrNoRows),
+		}},
+		{"already related", parent, models.Relationship{}, errors.EntityAlreadyExists{}, []*gomock.Call{
+			c.EXPECT().GetByIDs(gomock.Any(), []int{2, 1}).Return(both, nil),
+			m.EXPECT().Create(gomock.Any(), parent).Return(models.Relationship{}, errors.EntityAlreadyExists{}),
+		}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Create(ctx, tc.input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestRelationships_Graph(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockRelationshipServiceIn(ctrl), mocks.NewMockHandlerIn(ctrl)
+	s := NewRelationships(m, c)
+
+	edges := []models.Relationship{
+		{ID: 1, Type: models.RelationshipParent, FromID: 2, ToID: 1},
+		{ID: 2, Type: models.RelationshipParent, FromID: 3, ToID: 2},
+	}
+	customers := []models.Customer{{ID: 1, Name: "Acme"}, {ID: 2, Name: "Acme India"}, {ID: 3, Name: "Acme Pune"}}
+
+	tests := []struct {
+		desc     string
+		depth    int
+		expected models.Graph
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", 2, models.Graph{Customers: customers, Relationships: edges}, nil, []*gomock.Call{
+			m.EXPECT().Graph(gomock.Any(), 2, 2).Return(edges, nil),
+			c.EXPECT().GetByIDs(gomock.Any(), []int{2, 1, 3}).Return(customers, nil),
+		}},
+		{"no relationships", 1, models.Graph{Customers: customers[1:2], Relationships: []models.Relationship{}}, nil,
+			[]*gomock.Call{
+				m.EXPECT().Graph(gomock.Any(), 2, 1).Return([]models.Relationship{}, nil),
+				c.EXPECT().GetByIDs(gomock.Any(), []int{2}).Return(customers[1:2], nil),
+			}},
+		{"customer not found", 1, models.Graph{}, errors.EntityNotFound{Entity: "customer", ID: "2"}, []*gomock.Call{
+			m.EXPECT().Graph(gomock.Any(), 2, 1).Return([]models.Relationship{}, nil),
+			c.EXPECT().GetByIDs(gomock.Any(), []int{2}).Return(nil, nil),
+		}},
+		{"too deep", models.MaxGraphDepth + 1, models.Graph{}, errors.InvalidParam{Param: []string{"depth"}}, nil},
+		{"no depth", 0, models.Graph{}, errors.InvalidParam{Param: []string{"depth"}}, nil},
+		{"internal server error", 1, models.Graph{}, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().Graph(gomock.Any(), 2, 1).Return(nil, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Graph(ctx, 2, tc.depth)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
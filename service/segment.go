@@ -0,0 +1,128 @@
+package service
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// segments saves named filters and evaluates them through the customer service, so that the customers of a
+// segment are presented and masked exactly as in a listing.
+type segments struct {
+	store     store.SegmentServiceIn
+	customers HandlerIn
+}
+
+func NewSegments(s store.SegmentServiceIn, c HandlerIn) segments {
+	return segments{store: s, customers: c}
+}
+
+func (s segments) List(ctx *gofr.Context) ([]models.Segment, error) {
+	ctx, span := tracing.Start(ctx, "service.Segment.List")
+	defer span.End()
+
+	res, err := s.store.List(ctx)
+	if err != nil {
+		logDBError(ctx, "Segment.List", err, nil)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s segments) Get(ctx *gofr.Context, id int) (models.Segment, error) {
+	ctx, span := tracing.Start(ctx, "service.Segment.Get")
+	defer span.End()
+
+	res, err := s.store.Get(ctx, id)
+	if err != nil {
+		logDBError(ctx, "Segment.Get", err, map[string]interface{}{"id": id})
+		return models.Segment{}, err
+	}
+	return res, nil
+}
+
+func (s segments) Create(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	ctx, span := tracing.Start(ctx, "service.Segment.Create")
+	defer span.End()
+
+	if err := validateSegment(segment); err != nil {
+		return models.Segment{}, err
+	}
+
+	res, err := s.store.Create(ctx, segment)
+	if err != nil {
+		logDBError(ctx, "Segment.Create", err, map[string]interface{}{"name": segment.Name})
+		return models.Segment{}, err
+	}
+	return res, nil
+}
+
+func (s segments) Update(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	ctx, span := tracing.Start(ctx, "service.Segment.Update")
+	defer span.End()
+
+	if err := validateSegment(segment); err != nil {
+		return models.Segment{}, err
+	}
+
+	res, err := s.store.Update(ctx, segment)
+	if err != nil {
+		logDBError(ctx, "Segment.Update", err, map[string]interface{}{"id": segment.ID, "name": segment.Name})
+		return models.Segment{}, err
+	}
+	return res, nil
+}
+
+func (s segments) Delete(ctx *gofr.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "service.Segment.Delete")
+	defer span.End()
+
+	err := s.store.Delete(ctx, id)
+	if err != nil {
+		logDBError(ctx, "Segment.Delete", err, map[string]interface{}{"id": id})
+	}
+	return err
+}
+
+// Customers evaluates a segment. Only the paging and fields of page are used; the filter is the segment's.
+func (s segments) Customers(ctx *gofr.Context, id int, page models.Filter) ([]models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.Segment.Customers")
+	defer span.End()
+
+	filter, err := s.filter(ctx, id, page)
+	if err != nil {
+		return nil, err
+	}
+	return s.customers.Get(ctx, filter)
+}
+
+// Stream evaluates a segment like Customers, passing its customers to fn as they are read.
+func (s segments) Stream(ctx *gofr.Context, id int, page models.Filter, fn func(models.Customer) error) error {
+	ctx, span := tracing.Start(ctx, "service.Segment.Stream")
+	defer span.End()
+
+	filter, err := s.filter(ctx, id, page)
+	if err != nil {
+		return err
+	}
+	return s.customers.Stream(ctx, filter, fn)
+}
+
+// filter reads the filter of a segment, paged by page.
+func (s segments) filter(ctx *gofr.Context, id int, page models.Filter) (models.Filter, error) {
+	segment, err := s.Get(ctx, id)
+	if err != nil {
+		return models.Filter{}, err
+	}
+
+	filter, err := models.ParseSegmentFilter(segment.Filter)
+	if err != nil {
+		return models.Filter{}, err
+	}
+
+	filter.Limit, filter.Offset, filter.Fields = page.Limit, page.Offset, page.Fields
+
+	return filter, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegments_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockSegmentServiceIn(ctrl)
+	s := NewSegments(m, mocks.NewMockHandlerIn(ctrl))
+
+	vip := models.Segment{Name: "VIPs", Filter: "tag=vip&tag=!churned"}
+	stored := models.Segment{ID: 1, Name: "VIPs", Filter: "tag=vip&tag=!churned"}
+
+	tests := []struct {
+		desc     string
+		input    models.Segment
+		expected models.Segment
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", vip, stored, nil, []*gomock.Call{m.EXPECT().Create(gomock.Any(), vip).Return(stored, nil)}},
+		{"missing name", models.Segment{Filter: "tag=vip"}, models.Segment{}, errors.MissingParam{Param: []string{"name"}}, nil},
+		{"long name", models.Segment{Name: strings.Repeat("v", 65)}, models.Segment{},
+			errors.InvalidParam{Param: []string{"name"}}, nil},
+		{"paged filter", models.Segment{Name: "VIPs", Filter: "tag=vip&limit=10"}, models.Segment{},
+			errors.InvalidParam{Param: []string{"filter"}}, nil},
+		{"invalid tag", models.Segment{Name: "VIPs", Filter: "tag=V I P"}, models.Segment{},
+			errors.InvalidParam{Param: []string{"tag"}}, nil},
+		{"name taken", vip, models.Segment{}, errors.EntityAlreadyExists{},
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), vip).Return(models.Segment{}, errors.EntityAlreadyExists{})}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Create(ctx, tc.input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSegments_Customers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockSegmentServiceIn(ctrl), mocks.NewMockHandlerIn(ctrl)
+	s := NewSegments(m, c)
+
+	customers := []models.Customer{{ID: 1, Name: "Divya"}}
+	page := models.Filter{Limit: 10, Offset: 5, Fields: []string{"id", "name"}, Name: "ignored"}
+	notFound := errors.EntityNotFound{Entity: "segment", ID: "1"}
+
+	tests := []struct {
+		desc     string
+		expected []models.Customer
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", customers, nil, []*gomock.Call{
+			m.EXPECT().Get(gomock.Any(), 1).Return(models.Segment{ID: 1, Filter: "tag=vip&tag=!churned&minAge=30"}, nil),
+			c.EXPECT().Get(gomock.Any(), models.Filter{Tags: []string{"vip"}, ExcludedTags: []string{"churned"}, MinAge: 30,
+				Limit: 10, Offset: 5, Fields: []string{"id", "name"}}).Return(customers, nil),
+		}},
+		{"segment not found", nil, notFound,
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), 1).Return(models.Segment{}, notFound)}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Customers(ctx, 1, page)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
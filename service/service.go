@@ -1,61 +1,279 @@
 package service
 
 import (
+	"database/sql"
+	"strconv"
+	"time"
+
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 
+	"customer/middleware"
 	"customer/models"
 	"customer/store"
+	"customer/tracing"
 )
 
 type customer struct {
-	store store.ServiceIn
+	store      store.ServiceIn
+	attributes store.AttributeServiceIn
+	now        func() time.Time
 }
 
-func New(c store.ServiceIn) customer {
-	return customer{store: c}
+func New(c store.ServiceIn, a store.AttributeServiceIn) customer {
+	return customer{store: c, attributes: a, now: time.Now}
 }
 
-func (c customer) Get(ctx *gofr.Context) ([]models.Customer, error) {
-	res, err := c.store.Get(ctx)
+func (c customer) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.Get")
+	defer span.End()
+
+	filter, err := typedFilter(ctx, c.attributes, filter)
 	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.store.Get(ctx, filter)
+	if err != nil {
+		logDBError(ctx, "Get", err, nil)
 		return []models.Customer{}, errors.DB{Err: errors.Error("db error")}
 	}
-	return res, nil
+	return c.presentAll(ctx, res), nil
 }
 
-func (c customer) GetByID(ctx *gofr.Context, id int) (models.Customer, error) {
-	res, err := c.store.GetByID(ctx, id)
+// Stream calls fn with every customer matching the filter as it is read from the store, so that large listings
+// are never held in memory.
+func (c customer) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	ctx, span := tracing.Start(ctx, "service.Stream")
+	defer span.End()
+
+	filter, err := typedFilter(ctx, c.attributes, filter)
 	if err != nil {
-		return models.Customer{}, err
+		return err
+	}
+
+	err = c.store.Stream(ctx, filter, func(res models.Customer) error {
+		return fn(c.present(ctx, res))
+	})
+	if _, ok := err.(errors.DB); ok {
+		logDBError(ctx, "Stream", err, nil)
+		return errors.DB{Err: errors.Error("db error")}
 	}
-	return res, nil
+	return err
+}
+
+// GetByID returns the given fields of a customer, or all of them when none is given.
+func (c customer) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.GetByID", tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	res, err := c.store.GetByID(ctx, id, fields...)
+	if err != nil {
+		logDBError(ctx, "GetByID", err, map[string]interface{}{"id": id})
+		return models.Customer{}, notFound(err, id)
+	}
+	return c.present(ctx, res), nil
+}
+
+func (c customer) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.GetByIDs")
+	defer span.End()
+
+	res, err := c.store.GetByIDs(ctx, ids)
+	if err != nil {
+		logDBError(ctx, "GetByIDs", err, map[string]interface{}{"ids": ids})
+	}
+	return c.presentAll(ctx, res), err
 }
 
 func (c customer) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.Create")
+	defer span.End()
+
+	if err := validateCustomer(customer, c.now()); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := writable(ctx, customer); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := validateAttributes(ctx, c.attributes, customer.Attributes, false); err != nil {
+		return models.Customer{}, err
+	}
+
 	res, err := c.store.Create(ctx, customer)
 	if err != nil {
+		logDBError(ctx, "Create", err, customer.Redacted())
 		return models.Customer{}, err
 	}
-	return res, nil
+	return c.present(ctx, res), nil
 }
 
-func (c customer) Update(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
-	res, err := c.store.Update(ctx, customer.ID, customer)
+// Upsert creates the customer, or replaces the customer with the same name, and reports whether it was created.
+func (c customer) Upsert(ctx *gofr.Context, customer models.Customer) (models.Customer, bool, error) {
+	ctx, span := tracing.Start(ctx, "service.Upsert")
+	defer span.End()
+
+	if err := validateCustomer(customer, c.now()); err != nil {
+		return models.Customer{}, false, err
+	}
+
+	if err := writable(ctx, customer); err != nil {
+		return models.Customer{}, false, err
+	}
+
+	if err := c.replacedAttributes(ctx, customer); err != nil {
+		return models.Customer{}, false, err
+	}
+
+	// As for Update, the fields the caller cannot write are kept as stored. A new customer has none.
+	upsert, err := customer.Preserving(granted(ctx), func() (models.Customer, error) {
+		existing, err := c.store.Get(ctx, models.Filter{Name: customer.Name})
+		if err != nil || len(existing) == 0 {
+			return models.Customer{}, err
+		}
+
+		return existing[0], nil
+	})
+	if err != nil {
+		logDBError(ctx, "Upsert", err, customer.Redacted())
+		return models.Customer{}, false, err
+	}
+
+	res, created, err := c.store.Upsert(ctx, upsert)
 	if err != nil {
+		logDBError(ctx, "Upsert", err, upsert.Redacted())
+		return models.Customer{}, false, err
+	}
+	return c.present(ctx, res), created, nil
+}
+
+func (c customer) Update(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "service.Update", tracing.CustomerIDKey.Int(customer.ID))
+	defer span.End()
+
+	if err := validateCustomer(customer, c.now()); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := writable(ctx, customer); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := c.replacedAttributes(ctx, customer); err != nil {
 		return models.Customer{}, err
 	}
-	return res, nil
+
+	// A full update must not clear the fields the caller cannot write, so they are kept as stored.
+	update, err := customer.Preserving(granted(ctx), func() (models.Customer, error) {
+		return c.store.GetByID(ctx, customer.ID)
+	})
+	if err != nil {
+		logDBError(ctx, "Update", err, customer.Redacted())
+		return models.Customer{}, notFound(err, customer.ID)
+	}
+
+	res, err := c.store.Update(ctx, customer.ID, update)
+	if err != nil {
+		logDBError(ctx, "Update", err, update.Redacted())
+		return models.Customer{}, notFound(err, customer.ID)
+	}
+	return c.present(ctx, res), nil
 }
 
 func (c customer) Delete(ctx *gofr.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "service.Delete", tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
 	err := c.store.Delete(ctx, id)
 	if err != nil {
-		return err
+		logDBError(ctx, "Delete", err, map[string]interface{}{"id": id})
+		return notFound(err, id)
 	}
 	return nil
 }
 
 func (c customer) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
-	return c.store.Patch(ctx, id, customer)
+	ctx, span := tracing.Start(ctx, "service.Patch", tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	if err := validateCustomer(customer, c.now()); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := writable(ctx, customer); err != nil {
+		return models.Customer{}, err
+	}
+
+	if customer.Attributes != nil {
+		if err := validateAttributes(ctx, c.attributes, customer.Attributes, true); err != nil {
+			return models.Customer{}, err
+		}
+	}
+
+	res, err := c.store.Patch(ctx, id, customer)
+	if err != nil {
+		logDBError(ctx, "Patch", err, customer.Redacted())
+	}
+	return c.present(ctx, res), notFound(err, id)
+}
+
+// replacedAttributes validates the attributes of a customer that replaces the stored one. A customer without
+// attributes keeps the stored ones, which are left as they are.
+func (c customer) replacedAttributes(ctx *gofr.Context, customer models.Customer) error {
+	if customer.Attributes == nil {
+		return nil
+	}
+
+	return validateAttributes(ctx, c.attributes, customer.Attributes, false)
+}
+
+// present computes the age of a customer, since ages are not stored, and masks the fields the caller may not
+// read. Every customer the service returns goes through it.
+func (c customer) present(ctx *gofr.Context, res models.Customer) models.Customer {
+	return res.WithAge(c.now()).Masked(granted(ctx))
+}
+
+func (c customer) presentAll(ctx *gofr.Context, customers []models.Customer) []models.Customer {
+	for i := range customers {
+		customers[i] = c.present(ctx, customers[i])
+	}
+
+	return customers
+}
+
+// granted reports the scopes held by the caller of ctx.
+func granted(ctx *gofr.Context) func(scope string) bool {
+	return func(scope string) bool {
+		return middleware.Granted(ctx, scope)
+	}
+}
+
+// writable rejects customers that set fields the caller may not write. The rejected field is named in URL.
+func writable(ctx *gofr.Context, customer models.Customer) error {
+	if fields := customer.Unwritable(granted(ctx)); len(fields) > 0 {
+		return errors.ForbiddenRequest{URL: fields[0]}
+	}
+
+	return nil
+}
+
+// notFound reports a customer that does not exist, or belongs to another tenant, as not found.
+func notFound(err error, id int) error {
+	if err == sql.ErrNoRows {
+		return errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(id)}
+	}
+
+	return err
+}
+
+// logDBError logs database failures with the request id. Customers must be passed redacted.
+func logDBError(ctx *gofr.Context, method string, err error, fields map[string]interface{}) {
+	if _, ok := err.(errors.DB); !ok {
+		return
+	}
+
+	ctx.Logger.Errorf("request_id=%v service.%v: %v %v", middleware.RequestID(ctx), method, err, fields)
+	tracing.Fail(ctx, err)
 }
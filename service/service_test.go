@@ -2,20 +2,41 @@ package service
 
 import (
 	"context"
+	"customer/middleware"
 	"customer/mocks"
 	"customer/models"
+	"database/sql"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"github.com/golang/mock/gomock"
 	"reflect"
 	"testing"
+	"time"
+)
+
+var (
+	// now is the day the tests run on, so that the computed ages do not change.
+	now = time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+	dob = models.NewDate(2000, time.March, 14)
+	// salary is 30000.00 INR.
+	salary = &models.Money{Minor: 3000000, Currency: "INR"}
+	// salaryScopes let the caller of most tests read and write salaries.
+	salaryScopes = []string{"customer:salary:read", "customer:salary:write"}
+	// schema is the attribute schema of the tenant. No attribute is required, so that most customers have none.
+	schema = models.AttributeSchema{
+		{ID: 1, Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"}},
+		{ID: 2, Name: "seats", Type: models.AttributeNumber},
+	}
 )
 
 func connect(t *testing.T) (*gomock.Controller, customer, *mocks.MockServiceIn, *gofr.Gofr) {
 	ctrl := gomock.NewController(t)
 
 	m := mocks.NewMockServiceIn(ctrl)
-	h := New(m)
+	a := mocks.NewMockAttributeServiceIn(ctrl)
+	a.EXPECT().List(gomock.Any()).Return(schema, nil).AnyTimes()
+	h := New(m, a)
+	h.now = func() time.Time { return now }
 	app := gofr.New()
 	return ctrl, h, m, app
 }
@@ -24,7 +45,7 @@ func TestCustomer_Get(t *testing.T) {
 	ctrl, h, m, app := connect(t)
 	defer ctrl.Finish()
 	customer1 := []models.Customer{{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary,
 	}}
 
 	tests := []struct {
@@ -33,19 +54,19 @@ func TestCustomer_Get(t *testing.T) {
 		expected []models.Customer
 		err      error
 	}{
-		{"get all", []*gomock.Call{m.EXPECT().Get(gomock.Any()).Return(customer1, nil)}, customer1, nil},
+		{"get all", []*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{}).Return(customer1, nil)}, customer1, nil},
 		{"internal server error",
-			[]*gomock.Call{m.EXPECT().Get(gomock.Any()).Return([]models.Customer{}, errors.DB{Err: errors.Error("db error")})},
+			[]*gomock.Call{m.EXPECT().Get(gomock.Any(), models.Filter{}).Return([]models.Customer{}, errors.DB{Err: errors.Error("db error")})},
 			[]models.Customer{}, errors.DB{Err: errors.Error("db error")}},
 	}
 
 	for i, tc := range tests {
 		ctx := gofr.NewContext(nil, nil, app)
-		ctx.Context = context.Background()
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
 
 		t.Run(tc.desc, func(t *testing.T) {
 
-			resp, err := h.Get(ctx)
+			resp, err := h.Get(ctx, models.Filter{})
 			if !reflect.DeepEqual(tc.expected, resp) {
 				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
 			}
@@ -56,12 +77,58 @@ func TestCustomer_Get(t *testing.T) {
 	}
 }
 
+func TestCustomer_Stream(t *testing.T) {
+	ctrl, h, m, app := connect(t)
+	defer ctrl.Finish()
+
+	stored := models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Salary: salary}
+
+	tests := []struct {
+		desc     string
+		expected []models.Customer
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"customers are presented", []models.Customer{{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22}}, nil,
+			[]*gomock.Call{m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).
+				DoAndReturn(func(_ *gofr.Context, _ models.Filter, fn func(models.Customer) error) error {
+					return fn(stored)
+				})}},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).
+				Return(errors.DB{Err: errors.Error("connection reset")})}},
+		{"cancelled", nil, context.Canceled,
+			[]*gomock.Call{m.EXPECT().Stream(gomock.Any(), models.Filter{}, gomock.Any()).Return(context.Canceled)}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, app)
+		// No scope is granted, so salaries are masked.
+		ctx.Context = context.Background()
+
+		t.Run(tc.desc, func(t *testing.T) {
+			var res []models.Customer
+
+			err := h.Stream(ctx, models.Filter{}, func(c models.Customer) error {
+				res = append(res, c)
+				return nil
+			})
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
 func TestCustomer_GetByID(t *testing.T) {
 	ctrl, h, m, app := connect(t)
 	defer ctrl.Finish()
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary,
 	}
 
 	tests := []struct {
@@ -75,13 +142,15 @@ func TestCustomer_GetByID(t *testing.T) {
 			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1).Return(customer1, nil)}},
 		{"ID not found", 2, models.Customer{}, errors.EntityNotFound{Entity: "id"},
 			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.EntityNotFound{Entity: "id"})}},
+		{"another tenant's customer", 1, models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{}, sql.ErrNoRows)}},
 		{"internal server error", 1, models.Customer{}, errors.DB{Err: errors.Error("db error")},
 			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.DB{Err: errors.Error("db error")})}},
 	}
 
 	for i, tc := range tests {
 		ctx := gofr.NewContext(nil, nil, app)
-		ctx.Context = context.Background()
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
 
 		t.Run(tc.desc, func(t *testing.T) {
 			resp, err := h.GetByID(ctx, tc.ID)
@@ -96,16 +165,57 @@ func TestCustomer_GetByID(t *testing.T) {
 	}
 }
 
+func TestCustomer_GetByIDs(t *testing.T) {
+	ctrl, h, m, app := connect(t)
+	defer ctrl.Finish()
+
+	jay := models.NewDate(2001, time.January, 1)
+	customers := []models.Customer{
+		{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary},
+		{ID: 2, Name: "Jay", DateOfBirth: &jay, Age: 21, Salary: salary},
+	}
+
+	tests := []struct {
+		desc     string
+		IDs      []int
+		expected []models.Customer
+		err      error
+		mocks    []*gomock.Call
+	}{
+		{"get by IDs", []int{1, 2}, customers, nil,
+			[]*gomock.Call{m.EXPECT().GetByIDs(gomock.Any(), []int{1, 2}).Return(customers, nil)}},
+		{"internal server error", []int{1}, nil, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().GetByIDs(gomock.Any(), []int{1}).Return(nil, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, app)
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := h.GetByIDs(ctx, tc.IDs)
+
+			if !reflect.DeepEqual(tc.expected, resp) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, resp)
+			}
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
 func TestCustomer_Create(t *testing.T) {
 	ctrl, h, m, app := connect(t)
 	defer ctrl.Finish()
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary,
 	}
 	customer2 := models.Customer{
-		ID: 1, Name: "", Age: 22, Salary: 3000,
+		ID: 1, Name: "", Age: 22, Salary: &models.Money{Minor: 300000, Currency: "INR"},
 	}
+	future := models.NewDate(2030, time.January, 1)
 
 	tests := []struct {
 		desc     string
@@ -120,11 +230,25 @@ func TestCustomer_Create(t *testing.T) {
 			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.DB{Err: errors.Error("db err")})}},
 		{"internal server error", customer1, models.Customer{}, errors.DB{Err: errors.Error("db err")},
 			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.DB{Err: errors.Error("db err")})}},
+		{"duplicate email", models.Customer{Name: "Jay", Email: "divya@example.com"}, models.Customer{}, errors.EntityAlreadyExists{},
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.EntityAlreadyExists{})}},
+		{"invalid email", models.Customer{Name: "Jay", Email: "Jay <jay@example.com>"}, models.Customer{},
+			errors.InvalidParam{Param: []string{"email"}}, nil},
+		{"invalid phone", models.Customer{Name: "Jay", Phone: "09876543210"}, models.Customer{},
+			errors.InvalidParam{Param: []string{"phone"}}, nil},
+		{"date of birth in the future", models.Customer{Name: "Jay", DateOfBirth: &future}, models.Customer{},
+			errors.InvalidParam{Param: []string{"dateOfBirth"}}, nil},
+		{"negative salary", models.Customer{Name: "Jay", Salary: &models.Money{Minor: -100, Currency: "INR"}}, models.Customer{},
+			errors.InvalidParam{Param: []string{"salary"}}, nil},
+		{"unknown currency", models.Customer{Name: "Jay", Salary: &models.Money{Minor: 100, Currency: "XYZ"}}, models.Customer{},
+			errors.InvalidParam{Param: []string{"salary"}}, nil},
+		{"invalid attribute", models.Customer{Name: "Jay", Attributes: models.Attributes{"plan": "bronze"}}, models.Customer{},
+			errors.InvalidParam{Param: []string{"attributes.plan"}}, nil},
 	}
 
 	for i, tc := range tests {
 		ctx := gofr.NewContext(nil, nil, app)
-		ctx.Context = context.Background()
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
 
 		t.Run(tc.desc, func(t *testing.T) {
 
@@ -144,7 +268,7 @@ func TestCustomer_Update(t *testing.T) {
 	defer ctrl.Finish()
 
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary,
 	}
 
 	tests := []struct {
@@ -158,13 +282,15 @@ func TestCustomer_Update(t *testing.T) {
 			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(customer1, nil)}},
 		{"internal server error", customer1, models.Customer{}, errors.DB{Err: errors.Error("db err")},
 			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.DB{Err: errors.Error("db err")})}},
+		{"another tenant's customer", customer1, models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(models.Customer{}, sql.ErrNoRows)}},
 		{"ID not found", customer1, models.Customer{}, errors.EntityNotFound{Entity: "ID"},
 			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.EntityNotFound{Entity: "ID"})}},
 	}
 
 	for i, tc := range tests {
 		ctx := gofr.NewContext(nil, nil, app)
-		ctx.Context = context.Background()
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
 
 		t.Run(tc.desc, func(t *testing.T) {
 
@@ -195,13 +321,15 @@ func TestCustomer_Delete(t *testing.T) {
 			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.InvalidParam{Param: []string{"id"}})}},
 		{"ID not found", 1, errors.EntityNotFound{Entity: "ID"},
 			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.EntityNotFound{Entity: "ID"})}},
+		{"another tenant's customer", 1, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), 1).Return(sql.ErrNoRows)}},
 		{"internal server error", 1, errors.DB{Err: errors.Error("db error")},
 			[]*gomock.Call{m.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.DB{Err: errors.Error("db error")})}},
 	}
 
 	for i, tc := range tests {
 		ctx := gofr.NewContext(nil, nil, app)
-		ctx.Context = context.Background()
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
 
 		t.Run(tc.desc, func(t *testing.T) {
 			err := h.Delete(ctx, tc.ID)
@@ -216,7 +344,7 @@ func TestCustomer_Patch(t *testing.T) {
 	ctrl, h, m, app := connect(t)
 	defer ctrl.Finish()
 	customer1 := models.Customer{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
+		ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary,
 	}
 	tests := []struct {
 		desc     string
@@ -233,13 +361,15 @@ func TestCustomer_Patch(t *testing.T) {
 			[]*gomock.Call{m.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.InvalidParam{Param: []string{"id"}})}},
 		{"ID not found", 1, customer1, models.Customer{}, errors.EntityNotFound{Entity: "ID"},
 			[]*gomock.Call{m.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.EntityNotFound{Entity: "ID"})}},
+		{"another tenant's customer", 1, customer1, models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{m.EXPECT().Patch(gomock.Any(), 1, gomock.Any()).Return(models.Customer{}, sql.ErrNoRows)}},
 		{"internal server error", 1, customer1, models.Customer{}, errors.DB{Err: errors.Error("db error")},
 			[]*gomock.Call{m.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Customer{}, errors.DB{Err: errors.Error("db error")})}},
 	}
 
 	for i, tc := range tests {
 		ctx := gofr.NewContext(nil, nil, app)
-		ctx.Context = context.Background()
+		ctx.Context = middleware.WithScopes(context.Background(), salaryScopes...)
 
 		t.Run(tc.desc, func(t *testing.T) {
 			resp, err := h.Patch(ctx, tc.ID, tc.input)
@@ -252,3 +382,129 @@ func TestCustomer_Patch(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomer_Upsert(t *testing.T) {
+	ctrl, h, m, app := connect(t)
+	defer ctrl.Finish()
+
+	stored := models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary}
+	input := models.Customer{Name: "Divya", DateOfBirth: &dob}
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		input    models.Customer
+		expected models.Customer
+		created  bool
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"created", salaryScopes, stored, stored, true, nil,
+			[]*gomock.Call{m.EXPECT().Upsert(gomock.Any(), stored).Return(stored, true, nil)}},
+		{"replaced", salaryScopes, input, models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22}, false, nil,
+			[]*gomock.Call{m.EXPECT().Upsert(gomock.Any(), input).Return(models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob}, false, nil)}},
+		{"salary kept without write scope", nil, input, models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22}, false, nil,
+			[]*gomock.Call{
+				m.EXPECT().Get(gomock.Any(), models.Filter{Name: "Divya"}).Return([]models.Customer{stored}, nil),
+				m.EXPECT().Upsert(gomock.Any(), models.Customer{Name: "Divya", DateOfBirth: &dob, Salary: salary}).
+					Return(stored, false, nil),
+			}},
+		{"new customer without write scope", nil, input, models.Customer{ID: 2, Name: "Divya", DateOfBirth: &dob, Age: 22}, true, nil,
+			[]*gomock.Call{
+				m.EXPECT().Get(gomock.Any(), models.Filter{Name: "Divya"}).Return(nil, nil),
+				m.EXPECT().Upsert(gomock.Any(), input).Return(models.Customer{ID: 2, Name: "Divya", DateOfBirth: &dob}, true, nil),
+			}},
+		{"invalid email", salaryScopes, models.Customer{Name: "Divya", Email: "divya"}, models.Customer{}, false,
+			errors.InvalidParam{Param: []string{"email"}}, nil},
+		{"internal server error", salaryScopes, input, models.Customer{}, false, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().Upsert(gomock.Any(), input).Return(models.Customer{}, false, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, app)
+		ctx.Context = middleware.WithScopes(context.Background(), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, created, err := h.Upsert(ctx, tc.input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) || created != tc.created {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, tc.expected, tc.created, res, created)
+			}
+		})
+	}
+}
+
+func TestCustomer_SalaryScopes(t *testing.T) {
+	ctrl, h, m, app := connect(t)
+	defer ctrl.Finish()
+
+	stored := models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22, Salary: salary}
+	masked := models.Customer{ID: 1, Name: "Divya", DateOfBirth: &dob, Age: 22}
+	forbidden := errors.ForbiddenRequest{URL: "salary"}
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		call     func(ctx *gofr.Context) (models.Customer, error)
+		expected models.Customer
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"read without scope", nil,
+			func(ctx *gofr.Context) (models.Customer, error) { return h.GetByID(ctx, 1) }, masked, nil,
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1).Return(stored, nil)}},
+		{"read with scope", []string{"customer:salary:read"},
+			func(ctx *gofr.Context) (models.Customer, error) { return h.GetByID(ctx, 1) }, stored, nil,
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1).Return(stored, nil)}},
+		{"create without scope", []string{"customer:salary:read"},
+			func(ctx *gofr.Context) (models.Customer, error) { return h.Create(ctx, stored) }, models.Customer{}, forbidden, nil},
+		{"patch without scope", []string{"customer:salary:read"},
+			func(ctx *gofr.Context) (models.Customer, error) {
+				return h.Patch(ctx, 1, models.Customer{Salary: salary})
+			},
+			models.Customer{}, forbidden, nil},
+		{"write without read scope", []string{"customer:salary:write"},
+			func(ctx *gofr.Context) (models.Customer, error) { return h.Create(ctx, stored) }, masked, nil,
+			[]*gomock.Call{m.EXPECT().Create(gomock.Any(), stored).Return(stored, nil)}},
+		{"update keeps the stored salary", nil,
+			func(ctx *gofr.Context) (models.Customer, error) {
+				return h.Update(ctx, models.Customer{ID: 1, Name: "Jay"})
+			},
+			models.Customer{ID: 1, Name: "Jay"}, nil,
+			[]*gomock.Call{
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(stored, nil),
+				m.EXPECT().Update(gomock.Any(), 1, models.Customer{ID: 1, Name: "Jay", Salary: salary}).
+					Return(models.Customer{ID: 1, Name: "Jay", Salary: salary}, nil),
+			}},
+		{"update of another tenant's customer", nil,
+			func(ctx *gofr.Context) (models.Customer, error) {
+				return h.Update(ctx, models.Customer{ID: 1, Name: "Jay"})
+			},
+			models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{m.EXPECT().GetByID(gomock.Any(), 1).Return(models.Customer{}, sql.ErrNoRows)}},
+		{"update with scope", []string{"customer:salary:write"},
+			func(ctx *gofr.Context) (models.Customer, error) {
+				return h.Update(ctx, models.Customer{ID: 1, Name: "Jay"})
+			},
+			models.Customer{ID: 1, Name: "Jay"}, nil,
+			[]*gomock.Call{m.EXPECT().Update(gomock.Any(), 1, models.Customer{ID: 1, Name: "Jay"}).
+				Return(models.Customer{ID: 1, Name: "Jay"}, nil)}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, app)
+		ctx.Context = middleware.WithScopes(context.Background(), tc.scopes...)
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := tc.call(ctx)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
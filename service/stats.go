@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// stats serves the customer statistics. They are aggregated over the whole table, so every result is cached
+// for ttl and dashboards polling the same filter only hit the database once per ttl.
+type stats struct {
+	store      store.ServiceIn
+	attributes store.AttributeServiceIn
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats   models.Stats
+	expires time.Time
+}
+
+func NewStats(s store.ServiceIn, a store.AttributeServiceIn, ttl time.Duration) *stats {
+	return &stats{store: s, attributes: a, ttl: ttl, now: time.Now, cache: map[string]cachedStats{}}
+}
+
+// Get returns the statistics of the customers matching the filter, bucketing their ages by buckets.
+// Pagination is ignored.
+func (s *stats) Get(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	ctx, span := tracing.Start(ctx, "service.Stats.Get")
+	defer span.End()
+
+	filter, err := typedFilter(ctx, s.attributes, filter)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	filter.Limit, filter.Offset = 0, 0
+	// Every tenant has its own statistics, so the tenant is part of the key.
+	key := fmt.Sprintf("%v %+v %v", middleware.Tenant(ctx), filter, buckets)
+
+	if res, ok := s.cached(key); ok {
+		return res.Masked(granted(ctx)), nil
+	}
+
+	res, err := s.store.Stats(ctx, filter, buckets)
+	if err != nil {
+		logDBError(ctx, "Stats.Get", err, map[string]interface{}{"filter": filter})
+		return models.Stats{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, v := range s.cache {
+		if !now.Before(v.expires) {
+			delete(s.cache, k)
+		}
+	}
+
+	s.cache[key] = cachedStats{stats: res, expires: now.Add(s.ttl)}
+
+	return res.Masked(granted(ctx)), nil
+}
+
+func (s *stats) cached(key string) (models.Stats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.cache[key]
+	if !ok || !s.now().Before(v.expires) {
+		return models.Stats{}, false
+	}
+
+	return v.stats, true
+}
+
+// Check reports the size of the cache in the readiness breakdown. The cache is a map in the process, so it is
+// always up; every miss reads the database, which has a check of its own.
+func (s *stats) Check(*gofr.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fmt.Sprintf("%d cached results, ttl %v", len(s.cache), s.ttl), nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStats_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	a := mocks.NewMockAttributeServiceIn(ctrl)
+	s := NewStats(m, a, time.Minute)
+
+	clock := now
+	s.now = func() time.Time { return clock }
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+	buckets := []int{18, 30}
+	adults, all := models.Stats{Count: 2}, models.Stats{Count: 3}
+
+	tests := []struct {
+		desc     string
+		filter   models.Filter
+		elapsed  time.Duration
+		expected models.Stats
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"computed", models.Filter{MinAge: 18}, 0, adults, nil,
+			[]*gomock.Call{m.EXPECT().Stats(gomock.Any(), models.Filter{MinAge: 18}, buckets).Return(adults, nil)}},
+		{"cached, ignoring pagination", models.Filter{MinAge: 18, Limit: 5}, 30 * time.Second, adults, nil, nil},
+		{"other filter", models.Filter{}, 0, all, nil,
+			[]*gomock.Call{m.EXPECT().Stats(gomock.Any(), models.Filter{}, buckets).Return(all, nil)}},
+		{"expired", models.Filter{MinAge: 18}, 30 * time.Second, models.Stats{Count: 4}, nil,
+			[]*gomock.Call{m.EXPECT().Stats(gomock.Any(), models.Filter{MinAge: 18}, buckets).Return(models.Stats{Count: 4}, nil)}},
+		{"typed attributes", models.Filter{Attributes: models.Attributes{"seats": "5"}}, 0, adults, nil,
+			[]*gomock.Call{a.EXPECT().List(gomock.Any()).Return(schema, nil),
+				m.EXPECT().Stats(gomock.Any(), models.Filter{Attributes: models.Attributes{"seats": float64(5)}}, buckets).
+					Return(adults, nil)}},
+		{"unknown attribute", models.Filter{Attributes: models.Attributes{"region": "north"}}, 0, models.Stats{},
+			errors.InvalidParam{Param: []string{"attr.region"}},
+			[]*gomock.Call{a.EXPECT().List(gomock.Any()).Return(schema, nil)}},
+		{"internal server error", models.Filter{Name: "Divya"}, 0, models.Stats{}, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{m.EXPECT().Stats(gomock.Any(), models.Filter{Name: "Divya"}, buckets).
+				Return(models.Stats{}, errors.DB{Err: errors.Error("db error")})}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			clock = clock.Add(tc.elapsed)
+
+			res, err := s.Get(ctx, tc.filter, buckets)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestStats_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewStats(m, mocks.NewMockAttributeServiceIn(ctrl), time.Minute)
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+	m.EXPECT().Stats(gomock.Any(), models.Filter{}, nil).Return(models.Stats{Count: 1}, nil)
+
+	_, _ = s.Get(ctx, models.Filter{}, nil)
+
+	if detail, err := s.Check(ctx); err != nil || detail != "1 cached results, ttl 1m0s" {
+		t.Errorf("Expected the size of the cache\nGot %q, %v", detail, err)
+	}
+}
+
+func TestStats_GetPerTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewStats(m, mocks.NewMockAttributeServiceIn(ctrl), time.Minute)
+
+	tenantStats := func(ctx *gofr.Context, _ models.Filter, _ []int) (models.Stats, error) {
+		return models.Stats{Count: map[string]int{"acme": 1, "zopsmart": 2}[middleware.Tenant(ctx)]}, nil
+	}
+	m.EXPECT().Stats(gomock.Any(), models.Filter{}, nil).DoAndReturn(tenantStats).Times(2)
+
+	for i, tenant := range []string{"zopsmart", "acme", "zopsmart"} {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), tenant)
+
+		expected := map[string]int{"acme": 1, "zopsmart": 2}[tenant]
+
+		if res, err := s.Get(ctx, models.Filter{}, nil); err != nil || res.Count != expected {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v, %v", i+1, tenant, expected, res.Count, err)
+		}
+	}
+}
+
+func TestStats_GetWithoutSalaryScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockServiceIn(ctrl)
+	s := NewStats(m, mocks.NewMockAttributeServiceIn(ctrl), time.Minute)
+
+	salaries := models.Stats{Count: 1, Salary: []models.SalaryStats{{Currency: "INR", Count: 1}}}
+	m.EXPECT().Stats(gomock.Any(), models.Filter{}, nil).Return(salaries, nil)
+
+	tests := []struct {
+		desc     string
+		scopes   []string
+		expected models.Stats
+	}{
+		{"without scope", nil, models.Stats{Count: 1}},
+		{"with scope, from the cache", []string{"customer:salary:read"}, salaries},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithScopes(middleware.WithTenant(context.Background(), "zopsmart"), tc.scopes...)
+
+		res, err := s.Get(ctx, models.Filter{}, nil)
+		if err != nil || !reflect.DeepEqual(tc.expected, res) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v, %v", i+1, tc.desc, tc.expected, res, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/store"
+	"customer/tracing"
+)
+
+type tag struct {
+	store     store.TagServiceIn
+	customers store.ServiceIn
+}
+
+func NewTag(t store.TagServiceIn, c store.ServiceIn) tag {
+	return tag{store: t, customers: c}
+}
+
+// List returns the tags of a customer in alphabetical order.
+func (t tag) List(ctx *gofr.Context, customerID int) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "service.Tag.List", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if err := t.customerExists(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	res, err := t.store.List(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "Tag.List", err, map[string]interface{}{"customerId": customerID})
+		return nil, err
+	}
+	return res, nil
+}
+
+// Add tags a customer and returns all of its tags. Tagging a customer twice is not an error.
+func (t tag) Add(ctx *gofr.Context, customerID int, name string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "service.Tag.Add", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if err := validateTag(name); err != nil {
+		return nil, err
+	}
+
+	if err := t.customerExists(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	if err := t.store.Add(ctx, customerID, name); err != nil {
+		logDBError(ctx, "Tag.Add", err, map[string]interface{}{"customerId": customerID, "tag": name})
+		return nil, err
+	}
+
+	res, err := t.store.List(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "Tag.Add", err, map[string]interface{}{"customerId": customerID})
+		return nil, err
+	}
+	return res, nil
+}
+
+func (t tag) Remove(ctx *gofr.Context, customerID int, name string) error {
+	ctx, span := tracing.Start(ctx, "service.Tag.Remove", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if err := validateTag(name); err != nil {
+		return err
+	}
+
+	if err := t.customerExists(ctx, customerID); err != nil {
+		return err
+	}
+
+	err := t.store.Remove(ctx, customerID, name)
+	if err != nil {
+		logDBError(ctx, "Tag.Remove", err, map[string]interface{}{"customerId": customerID, "tag": name})
+	}
+	return err
+}
+
+// customerExists tells an unknown customer apart from a customer without tags.
+func (t tag) customerExists(ctx *gofr.Context, id int) error {
+	_, err := t.customers.GetByID(ctx, id, "id")
+	if err != nil {
+		logDBError(ctx, "Tag.customerExists", err, map[string]interface{}{"customerId": id})
+	}
+
+	return notFound(err, id)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+func TestTag_Add(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockTagServiceIn(ctrl), mocks.NewMockServiceIn(ctrl)
+	s := NewTag(m, c)
+
+	exists := func() *gomock.Call {
+		return c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{ID: 1}, nil)
+	}
+
+	tests := []struct {
+		desc     string
+		tag      string
+		expected []string
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", "vip", []string{"b2b", "vip"}, nil, []*gomock.Call{
+			exists(),
+			m.EXPECT().Add(gomock.Any(), 1, "vip").Return(nil),
+			m.EXPECT().List(gomock.Any(), 1).Return([]string{"b2b", "vip"}, nil),
+		}},
+		{"invalid tag", "!vip", nil, errors.InvalidParam{Param: []string{"tag"}}, nil},
+		{"customer not found", "vip", nil, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{}, sql.ErrNoRows)}},
+		{"internal server error", "vip", nil, errors.DB{Err: errors.Error("db error")}, []*gomock.Call{
+			exists(),
+			m.EXPECT().Add(gomock.Any(), 1, "vip").Return(errors.DB{Err: errors.Error("db error")}),
+		}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Add(ctx, 1, tc.tag)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestTag_Remove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockTagServiceIn(ctrl), mocks.NewMockServiceIn(ctrl)
+	s := NewTag(m, c)
+
+	tests := []struct {
+		desc string
+		err  error
+		mock []*gomock.Call
+	}{
+		{"success", nil, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{ID: 1}, nil),
+			m.EXPECT().Remove(gomock.Any(), 1, "vip").Return(nil),
+		}},
+		{"not tagged", errors.EntityNotFound{Entity: "tag", ID: "vip"}, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{ID: 1}, nil),
+			m.EXPECT().Remove(gomock.Any(), 1, "vip").Return(errors.EntityNotFound{Entity: "tag", ID: "vip"}),
+		}},
+		{"customer not found", errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{}, sql.ErrNoRows)}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := s.Remove(ctx, 1, "vip"); !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+		})
+	}
+}
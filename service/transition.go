@@ -0,0 +1,105 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/store"
+	"customer/tracing"
+)
+
+// Hook is run when a customer enters a status, with the transition that made it.
+type Hook func(ctx *gofr.Context, t models.Transition)
+
+// transitions moves customers through their lifecycle. Only the changes allowed by models.Status are made.
+type transitions struct {
+	store     store.TransitionServiceIn
+	customers store.ServiceIn
+
+	mu    sync.RWMutex
+	hooks map[models.Status][]Hook
+}
+
+func NewTransitions(t store.TransitionServiceIn, c store.ServiceIn) *transitions {
+	return &transitions{store: t, customers: c, hooks: map[models.Status][]Hook{}}
+}
+
+// OnEnter runs hook whenever a customer enters status. Hooks run in the order they were registered, within the
+// request and after the transition is stored, so they must be quick and cannot prevent it.
+func (t *transitions) OnEnter(status models.Status, hook Hook) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.hooks[status] = append(t.hooks[status], hook)
+}
+
+// Create changes the status of a customer to tr.To. A change the lifecycle does not allow, or one racing
+// another change of the same customer, is rejected with 409 Conflict.
+func (t *transitions) Create(ctx *gofr.Context, tr models.Transition) (models.Transition, error) {
+	ctx, span := tracing.Start(ctx, "service.Transition.Create", tracing.CustomerIDKey.Int(tr.CustomerID))
+	defer span.End()
+
+	if err := validateTransition(tr); err != nil {
+		return models.Transition{}, err
+	}
+
+	current, err := t.customers.GetByID(ctx, tr.CustomerID, "status")
+	if err != nil {
+		logDBError(ctx, "Transition.Create", err, map[string]interface{}{"customerId": tr.CustomerID})
+		return models.Transition{}, notFound(err, tr.CustomerID)
+	}
+
+	if !current.Status.CanTransition(tr.To) {
+		return models.Transition{}, conflict("ILLEGAL_TRANSITION",
+			fmt.Sprintf("customer %v cannot go from %v to %v", tr.CustomerID, current.Status, tr.To))
+	}
+
+	tr.From = current.Status
+
+	res, err := t.store.Create(ctx, tr)
+	if err == sql.ErrNoRows {
+		return models.Transition{}, conflict("CONCURRENT_TRANSITION",
+			fmt.Sprintf("customer %v changed while going from %v to %v", tr.CustomerID, tr.From, tr.To))
+	}
+	if err != nil {
+		logDBError(ctx, "Transition.Create", err, map[string]interface{}{"customerId": tr.CustomerID})
+		return models.Transition{}, err
+	}
+
+	t.mu.RLock()
+	hooks := t.hooks[res.To]
+	t.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(ctx, res)
+	}
+	return res, nil
+}
+
+// List returns the status history of a customer, oldest first.
+func (t *transitions) List(ctx *gofr.Context, customerID int) ([]models.Transition, error) {
+	ctx, span := tracing.Start(ctx, "service.Transition.List", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if _, err := t.customers.GetByID(ctx, customerID, "id"); err != nil {
+		logDBError(ctx, "Transition.List", err, map[string]interface{}{"customerId": customerID})
+		return nil, notFound(err, customerID)
+	}
+
+	res, err := t.store.List(ctx, customerID)
+	if err != nil {
+		logDBError(ctx, "Transition.List", err, map[string]interface{}{"customerId": customerID})
+		return nil, err
+	}
+	return res, nil
+}
+
+func conflict(code, reason string) error {
+	return &errors.Response{StatusCode: http.StatusConflict, Code: code, Reason: reason}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"customer/middleware"
+	"customer/mocks"
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestTransitions_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockTransitionServiceIn(ctrl), mocks.NewMockServiceIn(ctrl)
+	s := NewTransitions(m, c)
+
+	var entered []models.Transition
+
+	s.OnEnter(models.StatusActive, func(_ *gofr.Context, t models.Transition) { entered = append(entered, t) })
+	s.OnEnter(models.StatusClosed, func(*gofr.Context, models.Transition) { t.Error("closed hook ran") })
+
+	activate := models.Transition{CustomerID: 1, To: models.StatusActive, Reason: "signed"}
+	stored := models.Transition{ID: 1, CustomerID: 1, From: models.StatusProspect, To: models.StatusActive, Reason: "signed"}
+	status := func(s models.Status) *gomock.Call {
+		return c.EXPECT().GetByID(gomock.Any(), 1, "status").Return(models.Customer{Status: s}, nil)
+	}
+
+	tests := []struct {
+		desc     string
+		input    models.Transition
+		expected models.Transition
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", activate, stored, nil, []*gomock.Call{
+			status(models.StatusProspect),
+			m.EXPECT().Create(gomock.Any(), models.Transition{CustomerID: 1, From: models.StatusProspect,
+				To: models.StatusActive, Reason: "signed"}).Return(stored, nil),
+		}},
+		{"illegal transition", activate, models.Transition{},
+			&errors.Response{StatusCode: http.StatusConflict, Code: "ILLEGAL_TRANSITION",
+				Reason: "customer 1 cannot go from closed to active"},
+			[]*gomock.Call{status(models.StatusClosed)}},
+		{"same status", activate, models.Transition{},
+			&errors.Response{StatusCode: http.StatusConflict, Code: "ILLEGAL_TRANSITION",
+				Reason: "customer 1 cannot go from active to active"},
+			[]*gomock.Call{status(models.StatusActive)}},
+		{"concurrent transition", activate, models.Transition{},
+			&errors.Response{StatusCode: http.StatusConflict, Code: "CONCURRENT_TRANSITION",
+				Reason: "customer 1 changed while going from suspended to active"},
+			[]*gomock.Call{status(models.StatusSuspended),
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Transition{}, sql.ErrNoRows)}},
+		{"customer not found", activate, models.Transition{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{c.EXPECT().GetByID(gomock.Any(), 1, "status").Return(models.Customer{}, sql.ErrNoRows)}},
+		{"internal server error", activate, models.Transition{}, errors.DB{Err: errors.Error("db error")},
+			[]*gomock.Call{status(models.StatusProspect),
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Transition{}, errors.DB{Err: errors.Error("db error")})}},
+		{"unknown status", models.Transition{CustomerID: 1, To: "churned", Reason: "left"}, models.Transition{},
+			errors.InvalidParam{Param: []string{"to"}}, nil},
+		{"missing reason", models.Transition{CustomerID: 1, To: models.StatusActive, Reason: " "}, models.Transition{},
+			errors.MissingParam{Param: []string{"reason"}}, nil},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = middleware.WithTenant(context.Background(), "zopsmart")
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.Create(ctx, tc.input)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(entered, []models.Transition{stored}) {
+		t.Errorf("Expected the active hook to run once with %v\nGot %v", stored, entered)
+	}
+}
+
+func TestTransitions_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m, c := mocks.NewMockTransitionServiceIn(ctrl), mocks.NewMockServiceIn(ctrl)
+	s := NewTransitions(m, c)
+
+	history := []models.Transition{{ID: 1, CustomerID: 1, From: models.StatusProspect, To: models.StatusActive, Reason: "signed"}}
+
+	tests := []struct {
+		desc     string
+		expected []models.Transition
+		err      error
+		mock     []*gomock.Call
+	}{
+		{"success", history, nil, []*gomock.Call{
+			c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{ID: 1}, nil),
+			m.EXPECT().List(gomock.Any(), 1).Return(history, nil),
+		}},
+		{"customer not found", nil, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			[]*gomock.Call{c.EXPECT().GetByID(gomock.Any(), 1, "id").Return(models.Customer{}, sql.ErrNoRows)}},
+	}
+
+	for i, tc := range tests {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := s.List(ctx, 1)
+			if !reflect.DeepEqual(tc.err, err) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.err, err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, tc.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+
+	"customer/models"
+)
+
+var (
+	// e164 matches phone numbers in E.164 format, e.g. +919876543210.
+	e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	// countryCode matches ISO 3166-1 alpha-2 country codes.
+	countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// validateCustomer checks the contact details that are set. Unset fields are left to the caller.
+func validateCustomer(c models.Customer, now time.Time) error {
+	if c.Email != "" {
+		if addr, err := mail.ParseAddress(c.Email); err != nil || addr.Address != c.Email {
+			return errors.InvalidParam{Param: []string{"email"}}
+		}
+	}
+
+	if c.Phone != "" && !e164.MatchString(c.Phone) {
+		return errors.InvalidParam{Param: []string{"phone"}}
+	}
+
+	if c.DateOfBirth != nil && (c.DateOfBirth.After(now) || c.DateOfBirth.Year() < 1900) {
+		return errors.InvalidParam{Param: []string{"dateOfBirth"}}
+	}
+
+	if c.Salary != nil && (c.Salary.Minor < 0 || !models.ValidCurrency(c.Salary.Currency)) {
+		return errors.InvalidParam{Param: []string{"salary"}}
+	}
+
+	return nil
+}
+
+func validateAddress(a models.Address) error {
+	if a.Type != models.AddressBilling && a.Type != models.AddressShipping {
+		return errors.InvalidParam{Param: []string{"type"}}
+	}
+
+	if !countryCode.MatchString(a.Country) {
+		return errors.InvalidParam{Param: []string{"country"}}
+	}
+
+	if a.PostalCode == "" {
+		return errors.MissingParam{Param: []string{"postalCode"}}
+	}
+
+	return nil
+}
+
+// validateMerge checks that a merge names a survivor and distinct losers other than the survivor.
+func validateMerge(req models.MergeRequest) error {
+	if req.SurvivorID <= 0 {
+		return errors.InvalidParam{Param: []string{"survivorId"}}
+	}
+
+	if len(req.LoserIDs) == 0 {
+		return errors.MissingParam{Param: []string{"loserIds"}}
+	}
+
+	seen := map[int]bool{req.SurvivorID: true}
+
+	for _, id := range req.LoserIDs {
+		if id <= 0 || seen[id] {
+			return errors.InvalidParam{Param: []string{"loserIds"}}
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// maxReasonLength is the length of customer_transition.reason.
+const maxReasonLength = 500
+
+// validateTransition checks that a transition goes to a known status and gives a reason.
+func validateTransition(t models.Transition) error {
+	if !t.To.Valid() {
+		return errors.InvalidParam{Param: []string{"to"}}
+	}
+
+	if strings.TrimSpace(t.Reason) == "" {
+		return errors.MissingParam{Param: []string{"reason"}}
+	}
+
+	if len(t.Reason) > maxReasonLength {
+		return errors.InvalidParam{Param: []string{"reason"}}
+	}
+
+	return nil
+}
+
+// validateTag checks a tag name given in the path.
+func validateTag(name string) error {
+	if !models.ValidTag(name) {
+		return errors.InvalidParam{Param: []string{"tag"}}
+	}
+
+	return nil
+}
+
+// maxSegmentName and maxSegmentFilter are the lengths of segment.name and segment.filter.
+const (
+	maxSegmentName   = 64
+	maxSegmentFilter = 2000
+)
+
+// validateSegment checks that a segment is named and that its filter is one a listing accepts.
+func validateSegment(s models.Segment) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.MissingParam{Param: []string{"name"}}
+	}
+
+	if len(s.Name) > maxSegmentName {
+		return errors.InvalidParam{Param: []string{"name"}}
+	}
+
+	if len(s.Filter) > maxSegmentFilter {
+		return errors.InvalidParam{Param: []string{"filter"}}
+	}
+
+	_, err := models.ParseSegmentFilter(s.Filter)
+
+	return err
+}
+
+// validateRelationship checks that a relationship has a known type and relates two different customers.
+func validateRelationship(r models.Relationship) error {
+	if !r.Type.Valid() {
+		return errors.InvalidParam{Param: []string{"type"}}
+	}
+
+	if r.ToID <= 0 || r.ToID == r.FromID {
+		return errors.InvalidParam{Param: []string{"toId"}}
+	}
+
+	return nil
+}
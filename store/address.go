@@ -0,0 +1,154 @@
+package store
+
+import (
+	"customer/models"
+	"customer/tracing"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"strconv"
+)
+
+const addressColumns = "id, customer_id, type, street, city, country, postal_code"
+
+// ownedByTenant scopes addresses to the tenant of their customer.
+const ownedByTenant = "customer_id IN (SELECT id FROM customer WHERE tenant_id=? AND deleted_at IS NULL)"
+
+type address struct{}
+
+func NewAddress() address {
+	return address{}
+}
+
+// List returns the addresses of a customer in the order they were added.
+func (a address) List(ctx *gofr.Context, customerID int) ([]models.Address, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + addressColumns + " FROM address WHERE customer_id=? AND " + ownedByTenant + " ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.Address.List", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, customerID, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "Address.List", err)
+	}
+	defer rows.Close()
+
+	var res []models.Address
+
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, address)
+	}
+	return res, nil
+}
+
+func (a address) Get(ctx *gofr.Context, customerID, id int) (models.Address, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	query := "SELECT " + addressColumns + " FROM address WHERE customer_id=? AND id=? AND " + ownedByTenant
+
+	ctx, span := tracing.Start(ctx, "store.Address.Get", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	res, err := scanAddress(ctx.DB().QueryRowContext(ctx, query, customerID, id, tenant))
+	if err == sql.ErrNoRows {
+		return models.Address{}, addressNotFound(id)
+	}
+	if err != nil {
+		return models.Address{}, dbError(ctx, "Address.Get", err)
+	}
+	return res, nil
+}
+
+// Create adds an address to a customer of the tenant. Nothing is inserted for the customers of other tenants.
+func (a address) Create(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	query := "INSERT INTO address (customer_id,type,street,city,country,postal_code) " +
+		"SELECT id,?,?,?,?,? FROM customer WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING id"
+
+	ctx, span := tracing.Start(ctx, "store.Address.Create", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(address.CustomerID))
+	defer span.End()
+
+	err = ctx.DB().QueryRowContext(ctx, query, address.Type, address.Street, address.City,
+		address.Country, address.PostalCode, address.CustomerID, tenant).Scan(&address.ID)
+	if err == sql.ErrNoRows {
+		return models.Address{}, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(address.CustomerID)}
+	}
+	if err != nil {
+		return models.Address{}, dbError(ctx, "Address.Create", err)
+	}
+	return address, nil
+}
+
+func (a address) Update(ctx *gofr.Context, address models.Address) (models.Address, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	query := "UPDATE address SET type=?,street=?,city=?,country=?,postal_code=? WHERE customer_id=? AND id=? AND " + ownedByTenant
+
+	ctx, span := tracing.Start(ctx, "store.Address.Update", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(address.CustomerID))
+	defer span.End()
+
+	res, err := ctx.DB().ExecContext(ctx, query, address.Type, address.Street, address.City, address.Country,
+		address.PostalCode, address.CustomerID, address.ID, tenant)
+	if err != nil {
+		return models.Address{}, dbError(ctx, "Address.Update", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return models.Address{}, addressNotFound(address.ID)
+	}
+	return address, nil
+}
+
+func (a address) Delete(ctx *gofr.Context, customerID, id int) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM address WHERE customer_id=? AND id=? AND " + ownedByTenant
+
+	ctx, span := tracing.Start(ctx, "store.Address.Delete", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	res, err := ctx.DB().ExecContext(ctx, query, customerID, id, tenant)
+	if err != nil {
+		return dbError(ctx, "Address.Delete", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return addressNotFound(id)
+	}
+	return nil
+}
+
+func addressNotFound(id int) error {
+	return errors.EntityNotFound{Entity: "address", ID: strconv.Itoa(id)}
+}
+
+func scanAddress(row scanner) (models.Address, error) {
+	var a models.Address
+
+	err := row.Scan(&a.ID, &a.CustomerID, &a.Type, &a.Street, &a.City, &a.Country, &a.PostalCode)
+
+	return a, err
+}
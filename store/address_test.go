@@ -0,0 +1,148 @@
+package store
+
+import (
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func TestAddress_List(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAddress()
+	query := "SELECT " + addressColumns + " FROM address WHERE customer_id=? AND " + ownedByTenant + " ORDER BY id"
+	addresses := []models.Address{{ID: 1, CustomerID: 1, Type: "billing", Street: "MG Road", City: "Bengaluru", Country: "IN", PostalCode: "560001"}}
+
+	tests := []struct {
+		desc     string
+		expected []models.Address
+		err      error
+		mock     interface{}
+	}{
+		{"success", addresses, nil, mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "customer_id", "type", "street", "city", "country", "postal_code"}).
+				AddRow(1, 1, "billing", "MG Road", "Bengaluru", "IN", "560001"))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.List(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAddress_Get(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAddress()
+	query := "SELECT " + addressColumns + " FROM address WHERE customer_id=? AND id=? AND " + ownedByTenant
+
+	tests := []struct {
+		desc     string
+		expected models.Address
+		err      error
+		mock     interface{}
+	}{
+		{"success", models.Address{ID: 2, CustomerID: 1, Type: "shipping", Country: "IN", PostalCode: "560001"}, nil,
+			mock.ExpectQuery(query).WithArgs(1, 2, tenant).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "customer_id", "type", "street", "city", "country", "postal_code"}).
+					AddRow(2, 1, "shipping", "", "", "IN", "560001"))},
+		{"another customer's address", models.Address{}, errors.EntityNotFound{Entity: "address", ID: "2"},
+			mock.ExpectQuery(query).WithArgs(1, 2, tenant).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "customer_id", "type", "street", "city", "country", "postal_code"}))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Get(ctx, 1, 2)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAddress_Create(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAddress()
+	query := "INSERT INTO address (customer_id,type,street,city,country,postal_code) " +
+		"SELECT id,?,?,?,?,? FROM customer WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING id"
+	input := models.Address{CustomerID: 1, Type: "billing", Country: "IN", PostalCode: "560001"}
+	created := input
+	created.ID = 3
+
+	tests := []struct {
+		desc     string
+		expected models.Address
+		err      error
+		mock     interface{}
+	}{
+		{"success", created, nil, mock.ExpectQuery(query).WithArgs("billing", "", "", "IN", "560001", 1, tenant).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))},
+		{"customer of another tenant", models.Address{}, errors.EntityNotFound{Entity: "customer", ID: "1"},
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id"}))},
+		{"internal server error", models.Address{}, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Create(ctx, input)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAddress_UpdateAndDelete(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAddress()
+	update := "UPDATE address SET type=?,street=?,city=?,country=?,postal_code=? WHERE customer_id=? AND id=? AND " + ownedByTenant
+	remove := "DELETE FROM address WHERE customer_id=? AND id=? AND " + ownedByTenant
+	address := models.Address{ID: 3, CustomerID: 1, Type: "billing", Country: "IN", PostalCode: "560001"}
+	notFound := errors.EntityNotFound{Entity: "address", ID: "3"}
+
+	mock.ExpectExec(update).WithArgs("billing", "", "", "IN", "560001", 1, 3, tenant).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(remove).WithArgs(1, 3, tenant).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(remove).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if res, err := store.Update(ctx, address); err != nil || !reflect.DeepEqual(res, address) {
+		t.Errorf("Expected %v\nGot %v, %v", address, res, err)
+	}
+
+	if _, err := store.Update(ctx, address); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("Expected %v\nGot %v", notFound, err)
+	}
+
+	if err := store.Delete(ctx, 1, 3); err != nil {
+		t.Errorf("Expected no error\nGot %v", err)
+	}
+
+	if err := store.Delete(ctx, 1, 3); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("Expected %v\nGot %v", notFound, err)
+	}
+}
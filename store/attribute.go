@@ -0,0 +1,189 @@
+package store
+
+import (
+	"encoding/json"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/models"
+	"customer/tracing"
+)
+
+const attributeColumns = "id, name, type, required, enum, created_at"
+
+type attribute struct {
+	dialect Dialect
+}
+
+func NewAttribute() attribute {
+	return attribute{dialect: Postgres}
+}
+
+// NewMySQLAttribute returns the attribute store of the tables of database/mysql.sql.
+func NewMySQLAttribute() attribute {
+	return attribute{dialect: MySQL}
+}
+
+// List returns the attribute schema of the tenant by name.
+func (a attribute) List(ctx *gofr.Context) (models.AttributeSchema, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + attributeColumns + " FROM attribute_definition WHERE tenant_id=? ORDER BY name"
+
+	ctx, span := tracing.Start(ctx, "store.Attribute.List", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "Attribute.List", err)
+	}
+	defer rows.Close()
+
+	var res models.AttributeSchema
+
+	for rows.Next() {
+		var (
+			d    models.AttributeDefinition
+			enum []byte
+		)
+
+		if err := rows.Scan(&d.ID, &d.Name, &d.Type, &d.Required, &enum, &d.CreatedAt); err != nil {
+			return nil, errors.Error("scan error")
+		}
+
+		if enum != nil {
+			if err := json.Unmarshal(enum, &d.Enum); err != nil {
+				return nil, errors.Error("scan error")
+			}
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+// Create defines an attribute for the customers of the tenant. Attribute names are unique within a tenant.
+func (a attribute) Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.AttributeDefinition{}, err
+	}
+
+	var enum interface{}
+	if d.Enum != nil {
+		b, err := json.Marshal(d.Enum)
+		if err != nil {
+			return models.AttributeDefinition{}, err
+		}
+		enum = string(b)
+	}
+
+	if a.dialect == MySQL {
+		return createMySQLAttribute(ctx, tenant, d, enum)
+	}
+
+	query := "INSERT INTO attribute_definition (tenant_id,name,type,required,enum) VALUES(?,?,?,?,?) RETURNING id, created_at"
+
+	ctx, span := tracing.Start(ctx, "store.Attribute.Create", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	err = ctx.DB().QueryRowContext(ctx, query, tenant, d.Name, d.Type, d.Required, enum).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return models.AttributeDefinition{}, writeError(ctx, "Attribute.Create", err)
+	}
+	return d, nil
+}
+
+// createMySQLAttribute is Create on MySQL, which reads the id and creation time back in the transaction of the
+// insert since it has no RETURNING.
+func createMySQLAttribute(ctx *gofr.Context, tenant string, d models.AttributeDefinition,
+	enum interface{}) (models.AttributeDefinition, error) {
+	query := "INSERT INTO attribute_definition (tenant_id,name,type,required,enum) VALUES(?,?,?,?,CAST(? AS JSON))"
+
+	ctx, span := tracing.Start(ctx, "store.Attribute.Create", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.AttributeDefinition{}, dbError(ctx, "Attribute.Create", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, query, tenant, d.Name, d.Type, d.Required, enum)
+	if err != nil {
+		return models.AttributeDefinition{}, writeError(ctx, "Attribute.Create", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.AttributeDefinition{}, dbError(ctx, "Attribute.Create", err)
+	}
+
+	d.ID = int(id)
+
+	err = tx.QueryRowContext(ctx, "SELECT created_at FROM attribute_definition WHERE id=?", d.ID).Scan(&d.CreatedAt)
+	if err != nil {
+		return models.AttributeDefinition{}, dbError(ctx, "Attribute.Create", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.AttributeDefinition{}, dbError(ctx, "Attribute.Create", err)
+	}
+	return d, nil
+}
+
+// Delete removes an attribute from the schema of the tenant and from its customers, in one transaction.
+func (a attribute) Delete(ctx *gofr.Context, name string) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Attribute.Delete")
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(ctx, "Attribute.Delete", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM attribute_definition WHERE tenant_id=? AND name=?", tenant, name)
+	if err != nil {
+		return dbError(ctx, "Attribute.Delete", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errors.EntityNotFound{Entity: "attribute", ID: name}
+	}
+
+	query := "UPDATE customer SET attributes=attributes - ?,updated_at=now() WHERE tenant_id=? AND jsonb_exists(attributes, ?)"
+	key := name
+
+	if a.dialect == MySQL {
+		query = "UPDATE customer SET attributes=JSON_REMOVE(attributes, ?),updated_at=CURRENT_TIMESTAMP(6) " +
+			"WHERE tenant_id=? AND JSON_CONTAINS_PATH(attributes, 'one', ?)"
+		key = jsonPath(name)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, key, tenant, key); err != nil {
+		return dbError(ctx, "Attribute.Delete", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError(ctx, "Attribute.Delete", err)
+	}
+	return nil
+}
+
+// jsonPath is the MySQL JSON path of the attribute with the given name.
+func jsonPath(name string) string {
+	b, _ := json.Marshal(name)
+	return "$." + string(b)
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"reflect"
+	"testing"
+)
+
+func TestAttribute_List(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAttribute()
+	query := "SELECT " + attributeColumns + " FROM attribute_definition WHERE tenant_id=? ORDER BY name"
+	columns := []string{"id", "name", "type", "required", "enum", "created_at"}
+	schema := models.AttributeSchema{
+		{ID: 1, Name: "plan", Type: models.AttributeString, Required: true, Enum: []string{"gold", "silver"}, CreatedAt: &created},
+		{ID: 2, Name: "seats", Type: models.AttributeNumber, CreatedAt: &created},
+	}
+
+	tests := []struct {
+		desc     string
+		expected models.AttributeSchema
+		err      error
+		mock     interface{}
+	}{
+		{"success", schema, nil, mock.ExpectQuery(query).WithArgs(tenant).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "plan", "string", true, []byte(`["gold","silver"]`), created).
+			AddRow(2, "seats", "number", false, nil, created))},
+		{"no attributes", nil, nil, mock.ExpectQuery(query).WithArgs(tenant).WillReturnRows(sqlmock.NewRows(columns))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.List(ctx)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAttribute_Create(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAttribute()
+	query := "INSERT INTO attribute_definition (tenant_id,name,type,required,enum) VALUES(?,?,?,?,?) RETURNING id, created_at"
+	plan := models.AttributeDefinition{Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"}}
+	expected := models.AttributeDefinition{ID: 1, Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"},
+		CreatedAt: &created}
+
+	tests := []struct {
+		desc     string
+		expected models.AttributeDefinition
+		err      error
+		mock     interface{}
+	}{
+		{"success", expected, nil, mock.ExpectQuery(query).WithArgs(tenant, "plan", "string", false, `["gold","silver"]`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, created))},
+		{"name taken", models.AttributeDefinition{}, errors.EntityAlreadyExists{},
+			mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "23505"})},
+		{"internal server error", models.AttributeDefinition{}, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Create(ctx, plan)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestAttribute_Delete(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewAttribute()
+	deleteDefinition := "DELETE FROM attribute_definition WHERE tenant_id=? AND name=?"
+	removeValues := "UPDATE customer SET attributes=attributes - ?,updated_at=now() WHERE tenant_id=? AND jsonb_exists(attributes, ?)"
+
+	tests := []struct {
+		desc string
+		err  error
+		mock func()
+	}{
+		{"success", nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteDefinition).WithArgs(tenant, "plan").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(removeValues).WithArgs("plan", tenant, "plan").WillReturnResult(sqlmock.NewResult(0, 3))
+			mock.ExpectCommit()
+		}},
+		{"not found", errors.EntityNotFound{Entity: "attribute", ID: "plan"}, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteDefinition).WithArgs(tenant, "plan").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectRollback()
+		}},
+		{"internal server error", errors.DB{Err: errors.Error("db error")}, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteDefinition).WithArgs(tenant, "plan").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(removeValues).WillReturnError(errors.Error("db error"))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.mock()
+
+			err := store.Delete(ctx, "plan")
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("TEST[%d] %v", i+1, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,801 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/gocql/gocql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/models"
+	"customer/tracing"
+)
+
+// cassandraStore keeps the customers in the customer table of a Cassandra keyspace, with the customers of a
+// tenant in one partition in id order. CQL has neither unique indexes nor multi-row transactions, so the names
+// and emails of the live customers of a tenant are claimed in customer_key with a conditional batch, which is
+// atomic since the keys of a tenant share a partition, and ids are drawn by compare-and-set on
+// customer_sequence. Filters are matched while reading the partition of the tenant, like the in-memory store
+// matches them. Like that store it leaves addresses and tags in the database, refuses merges of customers that
+// have any with ErrMergeHeld and filters on tags with ErrTagFilter.
+//
+// A customer row is written after its keys are claimed. When that write fails, the claims are handed back.
+type cassandraStore struct {
+	session *gocql.Session
+}
+
+func NewCassandra(session *gocql.Session) cassandraStore {
+	return cassandraStore{session: session}
+}
+
+// cassandraTables are the tables of the store. Dates of birth are "2006-01-02" strings and attributes are JSON
+// objects, empty when there are none.
+var cassandraTables = []string{
+	"CREATE TABLE IF NOT EXISTS customer (tenant text, id int, name text, email text, phone text, " +
+		"date_of_birth text, salary_minor bigint, salary_currency text, created_at timestamp, updated_at timestamp, " +
+		"status text, attributes text, PRIMARY KEY (tenant, id)) WITH CLUSTERING ORDER BY (id ASC)",
+	// customer_key holds the names and emails of the live customers of a tenant, as name:<name> and
+	// email:<email>, with the id of the customer that has it.
+	"CREATE TABLE IF NOT EXISTS customer_key (tenant text, key text, id int, PRIMARY KEY (tenant, key))",
+	"CREATE TABLE IF NOT EXISTS customer_sequence (name text PRIMARY KEY, next int)",
+}
+
+const (
+	cassandraColumns = "id, name, email, phone, date_of_birth, salary_minor, salary_currency, created_at, " +
+		"updated_at, status, attributes"
+
+	claimKey   = "INSERT INTO customer_key (tenant, key, id) VALUES (?, ?, ?) IF NOT EXISTS"
+	moveKey    = "UPDATE customer_key SET id = ? WHERE tenant = ? AND key = ? IF id = ?"
+	releaseKey = "DELETE FROM customer_key WHERE tenant = ? AND key = ? IF id = ?"
+)
+
+// Migrate creates the tables the store relies on in the keyspace of the session. It is idempotent, so it runs
+// on every start. The keyspace itself is left to the operator, who chooses its replication.
+func (s cassandraStore) Migrate(ctx context.Context) error {
+	for _, table := range cassandraTables {
+		if err := s.session.Query(table).WithContext(ctx).Exec(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s cassandraStore) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	var res []models.Customer
+
+	err := s.each(ctx, "Get", filter, func(customer models.Customer) error {
+		res = append(res, customer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Stream calls fn with every customer matching the filter as soon as it is read. It stops at the first error
+// returned by fn, or when ctx is cancelled.
+func (s cassandraStore) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	return s.each(ctx, "Stream", filter, fn)
+}
+
+func (s cassandraStore) each(ctx *gofr.Context, method string, filter models.Filter, fn func(models.Customer) error) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkFilter(filter); err != nil {
+		return err
+	}
+
+	query := "SELECT " + cassandraColumns + " FROM customer WHERE tenant = ?"
+
+	ctx, span := tracing.Start(ctx, "store."+method, semconv.DBSystemCassandra, semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	iter := s.session.Query(query, tenant).WithContext(ctx).Iter()
+	now := time.Now()
+	skipped, sent := 0, 0
+
+	for {
+		var row cassandraRow
+		if !iter.Scan(row.dest()...) {
+			break
+		}
+
+		customer, err := row.customer()
+		if err != nil {
+			_ = iter.Close()
+			return errors.Error("scan error")
+		}
+
+		if !match(customer, filter, now) {
+			continue
+		}
+
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+
+		if filter.Limit > 0 && sent == filter.Limit {
+			break
+		}
+
+		if err := fn(project(customer, filter.Fields)); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		sent++
+	}
+
+	if err := iter.Close(); err != nil {
+		return queryError(ctx, method, err)
+	}
+
+	return nil
+}
+
+// GetByID reads the given fields of a customer, or all of them when none is given.
+func (s cassandraStore) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.GetByID", semconv.DBSystemCassandra, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	customer, err := s.get(ctx, tenant, id)
+	if err == sql.ErrNoRows {
+		return models.Customer{}, err
+	}
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "GetByID", err)
+	}
+	return project(customer, fields), nil
+}
+
+// GetByIDs fetches all the given customers with a single query. Unknown ids are skipped.
+func (s cassandraStore) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + cassandraColumns + " FROM customer WHERE tenant = ? AND id IN ?"
+
+	ctx, span := tracing.Start(ctx, "store.GetByIDs", semconv.DBSystemCassandra, semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	iter := s.session.Query(query, tenant, ids).WithContext(ctx).Iter()
+
+	var res []models.Customer
+
+	for {
+		var row cassandraRow
+		if !iter.Scan(row.dest()...) {
+			break
+		}
+
+		customer, err := row.customer()
+		if err != nil {
+			_ = iter.Close()
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, customer)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, dbError(ctx, "GetByIDs", err)
+	}
+	return res, nil
+}
+
+// Count returns the number of customers matching the filter. Pagination is ignored.
+func (s cassandraStore) Count(ctx *gofr.Context, filter models.Filter) (int, error) {
+	filter.Limit, filter.Offset, filter.Fields = 0, 0, []string{"id"}
+
+	count := 0
+
+	err := s.each(ctx, "Count", filter, func(models.Customer) error {
+		count++
+		return nil
+	})
+
+	return count, err
+}
+
+func (s cassandraStore) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Create", semconv.DBSystemCassandra)
+	defer span.End()
+
+	stored, err := s.insert(ctx, tenant, customer, attrs)
+	if err != nil {
+		return models.Customer{}, cassandraWriteError(ctx, "Create", err)
+	}
+
+	customer.ID, customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.ID, stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, nil
+}
+
+// Upsert creates the customer, or replaces the customer of the tenant with the same name. created reports
+// whether the customer was created. A replaced customer keeps its attributes when none are given. The name is
+// looked up before the customer is written, so the write is tried again when the name changed hands in between.
+func (s cassandraStore) Upsert(ctx *gofr.Context, customer models.Customer) (res models.Customer, created bool, err error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Upsert", semconv.DBSystemCassandra)
+	defer span.End()
+
+	var stored models.Customer
+
+	for attempt := 0; ; attempt++ {
+		var id int
+
+		err = s.session.Query("SELECT id FROM customer_key WHERE tenant = ? AND key = ?", tenant, nameKey(customer.Name)).
+			WithContext(ctx).Scan(&id)
+
+		switch {
+		case err == gocql.ErrNotFound:
+			stored, err = s.insert(ctx, tenant, customer, attrs)
+			created = true
+		case err == nil:
+			stored, err = s.replace(ctx, tenant, id, func(old models.Customer) models.Customer {
+				return replaced(old, customer, attrs)
+			})
+			created = false
+		}
+
+		// The name may have been claimed, or its customer deleted, since it was looked up.
+		if (created && err == errClaimed || !created && err == sql.ErrNoRows) && attempt < 2 {
+			continue
+		}
+
+		if err != nil {
+			return models.Customer{}, false, cassandraWriteError(ctx, "Upsert", err)
+		}
+
+		break
+	}
+
+	customer.ID, customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.ID, stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, created, nil
+}
+
+// Update replaces a customer. Its attributes are kept when none are given.
+func (s cassandraStore) Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Update", semconv.DBSystemCassandra, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	stored, err := s.replace(ctx, tenant, id, func(old models.Customer) models.Customer {
+		next := replaced(old, customer, attrs)
+		next.Name = customer.Name
+
+		return next
+	})
+	if err == sql.ErrNoRows {
+		return models.Customer{}, err
+	}
+	if err != nil {
+		return models.Customer{}, cassandraWriteError(ctx, "Update", err)
+	}
+
+	customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, nil
+}
+
+func (s cassandraStore) Delete(ctx *gofr.Context, id int) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Delete", semconv.DBSystemCassandra, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	old, err := s.get(ctx, tenant, id)
+	if err == sql.ErrNoRows {
+		return err
+	}
+	if err != nil {
+		return dbError(ctx, "Delete", err)
+	}
+
+	applied, err := s.session.Query("DELETE FROM customer WHERE tenant = ? AND id = ? IF EXISTS", tenant, id).
+		WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return dbError(ctx, "Delete", err)
+	}
+
+	if !applied {
+		return sql.ErrNoRows
+	}
+
+	// The name and the email of the customer are free again.
+	if _, err := s.move(ctx, tenant, keysOf(old), nil); err != nil {
+		return dbError(ctx, "Delete", err)
+	}
+	return nil
+}
+
+// Patch writes the fields that are set in customer. Attributes are merged into the stored ones, and removed when
+// they are nil. Nothing is written, and an empty customer is returned, when no field is set.
+func (s cassandraStore) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if customer.Name == "" && customer.Email == "" && customer.Phone == "" && customer.DateOfBirth == nil &&
+		customer.Salary == nil && customer.Attributes == nil {
+		return models.Customer{}, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Patch", semconv.DBSystemCassandra, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	var patchErr error
+
+	_, err = s.replace(ctx, tenant, id, func(next models.Customer) models.Customer {
+		if customer.Name != "" {
+			next.Name = customer.Name
+		}
+
+		if customer.Email != "" {
+			next.Email = customer.Email
+		}
+
+		if customer.Phone != "" {
+			next.Phone = customer.Phone
+		}
+
+		if customer.DateOfBirth != nil {
+			next.DateOfBirth = customer.DateOfBirth
+		}
+
+		if customer.Salary != nil {
+			next.Salary = customer.Salary
+		}
+
+		if customer.Attributes != nil {
+			next.Attributes, patchErr = patchAttributes(next.Attributes, customer.Attributes)
+		}
+
+		return next
+	})
+	if patchErr != nil {
+		return models.Customer{}, patchErr
+	}
+	if err == sql.ErrNoRows {
+		return models.Customer{}, err
+	}
+	if err != nil {
+		return models.Customer{}, cassandraWriteError(ctx, "Patch", err)
+	}
+
+	customer.ID = id
+	return customer, nil
+}
+
+// Merge merges the losers into the survivor: merge computes the survivor from them, and the losers are deleted.
+// The keys of the customers are moved to the survivor at once, and the rows of the tenant are then written in
+// one batch, which is atomic and isolated since they share a partition. Addresses, tags and relationships are
+// kept in the database, so losers that have any are refused with ErrMergeHeld.
+func (s cassandraStore) Merge(ctx *gofr.Context, survivorID int, loserIDs []int,
+	merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := checkMerge(ctx, loserIDs); err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Merge", semconv.DBSystemCassandra, tracing.CustomerIDKey.Int(survivorID))
+	defer span.End()
+
+	ids := append([]int{survivorID}, loserIDs...)
+	found := make([]models.Customer, len(ids))
+	before := map[string]int{}
+
+	for i, id := range ids {
+		found[i], err = s.get(ctx, tenant, id)
+		if err == sql.ErrNoRows {
+			return models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(id)}
+		}
+		if err != nil {
+			return models.Customer{}, dbError(ctx, "Merge", err)
+		}
+
+		for key, holder := range keysOf(found[i]) {
+			before[key] = holder
+		}
+	}
+
+	res := merge(clone(found[0]), found[1:])
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	survivor := found[0]
+	survivor.Email, survivor.Phone, survivor.DateOfBirth, survivor.Salary, survivor.UpdatedAt =
+		res.Email, res.Phone, res.DateOfBirth, res.Salary, &now
+
+	after := keysOf(survivor)
+
+	applied, err := s.move(ctx, tenant, before, after)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+
+	if !applied {
+		return models.Customer{}, errors.EntityAlreadyExists{}
+	}
+
+	b := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	for _, id := range loserIDs {
+		b.Query("DELETE FROM customer WHERE tenant = ? AND id = ?", tenant, id)
+	}
+
+	if err := s.put(b, tenant, survivor); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := s.session.ExecuteBatch(b); err != nil {
+		_, _ = s.move(ctx, tenant, after, before)
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+
+	res.UpdatedAt = &now
+
+	return res, nil
+}
+
+// Stats aggregates the customers matching the filter. Pagination is ignored.
+func (s cassandraStore) Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	filter.Limit, filter.Offset, filter.Fields = 0, 0, []string{"dateOfBirth", "salary"}
+
+	var customers []models.Customer
+
+	err := s.each(ctx, "Stats", filter, func(customer models.Customer) error {
+		customers = append(customers, customer)
+		return nil
+	})
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	return summarise(customers, buckets, time.Now()), nil
+}
+
+// errClaimed is returned by the writes of the store when a name or an email is held by another customer.
+var errClaimed = errors.Error("name or email claimed by another customer")
+
+// insert claims the keys of a new customer and writes it.
+func (s cassandraStore) insert(ctx *gofr.Context, tenant string, customer models.Customer,
+	attrs models.Attributes) (models.Customer, error) {
+	id, err := s.nextID(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	stored := customer
+	stored.ID, stored.CreatedAt, stored.UpdatedAt, stored.Status = id, &now, &now, models.StatusProspect
+	stored.Age, stored.Attributes = 0, attrs
+
+	keys := keysOf(stored)
+
+	applied, err := s.move(ctx, tenant, nil, keys)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if !applied {
+		return models.Customer{}, errClaimed
+	}
+
+	b := s.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+	if err := s.put(b, tenant, stored); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := s.session.ExecuteBatch(b); err != nil {
+		_, _ = s.move(ctx, tenant, keys, nil)
+		return models.Customer{}, err
+	}
+
+	return stored, nil
+}
+
+// replace writes the customer that next returns for the stored one, moving its keys first. It is sql.ErrNoRows
+// when the customer is not found or was deleted in the meantime.
+func (s cassandraStore) replace(ctx *gofr.Context, tenant string, id int,
+	next func(old models.Customer) models.Customer) (models.Customer, error) {
+	old, err := s.get(ctx, tenant, id)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	stored := next(clone(old))
+	stored.UpdatedAt = &now
+
+	before, after := keysOf(old), keysOf(stored)
+
+	applied, err := s.move(ctx, tenant, before, after)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if !applied {
+		return models.Customer{}, errClaimed
+	}
+
+	row, err := newCassandraRow(stored)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	applied, err = s.session.Query("UPDATE customer SET name = ?, email = ?, phone = ?, date_of_birth = ?, "+
+		"salary_minor = ?, salary_currency = ?, updated_at = ?, attributes = ? WHERE tenant = ? AND id = ? IF EXISTS",
+		row.name, row.email, row.phone, row.dateOfBirth, row.salaryMinor, row.salaryCurrency, row.updatedAt,
+		row.attributes, tenant, id).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil || !applied {
+		_, _ = s.move(ctx, tenant, after, before)
+	}
+
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if !applied {
+		return models.Customer{}, sql.ErrNoRows
+	}
+
+	return stored, nil
+}
+
+// get reads a customer of the tenant. It is sql.ErrNoRows when there is none.
+func (s cassandraStore) get(ctx context.Context, tenant string, id int) (models.Customer, error) {
+	var row cassandraRow
+
+	err := s.session.Query("SELECT "+cassandraColumns+" FROM customer WHERE tenant = ? AND id = ?", tenant, id).
+		WithContext(ctx).Scan(row.dest()...)
+	if err == gocql.ErrNotFound {
+		return models.Customer{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	return row.customer()
+}
+
+// put adds the write of the whole row of a customer to b.
+func (s cassandraStore) put(b *gocql.Batch, tenant string, c models.Customer) error {
+	row, err := newCassandraRow(c)
+	if err != nil {
+		return err
+	}
+
+	b.Query("INSERT INTO customer (tenant, "+cassandraColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		tenant, row.id, row.name, row.email, row.phone, row.dateOfBirth, row.salaryMinor, row.salaryCurrency,
+		row.createdAt, row.updatedAt, row.status, row.attributes)
+
+	return nil
+}
+
+// move hands the keys of a tenant from the customers that hold them before to those that hold them after, in a
+// conditional batch: keys that nobody holds are claimed, keys that change hands are moved and keys that nobody
+// holds after are released. applied is false, and nothing changes, when a key is not held as before says.
+func (s cassandraStore) move(ctx context.Context, tenant string, before, after map[string]int) (applied bool, err error) {
+	b := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+
+	for key, id := range after {
+		switch from, ok := before[key]; {
+		case !ok:
+			b.Query(claimKey, tenant, key, id)
+		case from != id:
+			b.Query(moveKey, id, tenant, key, from)
+		}
+	}
+
+	for key, id := range before {
+		if _, ok := after[key]; !ok {
+			b.Query(releaseKey, tenant, key, id)
+		}
+	}
+
+	if b.Size() == 0 {
+		return true, nil
+	}
+
+	applied, iter, err := s.session.MapExecuteBatchCAS(b, map[string]interface{}{})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, iter.Close()
+}
+
+// nextID draws the next customer id from customer_sequence, whose next column holds the id handed out next.
+func (s cassandraStore) nextID(ctx context.Context) (int, error) {
+	current := map[string]interface{}{}
+
+	applied, err := s.session.Query("INSERT INTO customer_sequence (name, next) VALUES ('customer', 2) IF NOT EXISTS").
+		WithContext(ctx).MapScanCAS(current)
+	if err != nil || applied {
+		return 1, err
+	}
+
+	for {
+		next, _ := current["next"].(int)
+		current = map[string]interface{}{}
+
+		applied, err := s.session.Query("UPDATE customer_sequence SET next = ? WHERE name = 'customer' IF next = ?",
+			next+1, next).WithContext(ctx).MapScanCAS(current)
+		if err != nil || applied {
+			return next, err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// keysOf returns the keys a live customer holds, with its id.
+func keysOf(c models.Customer) map[string]int {
+	keys := map[string]int{nameKey(c.Name): c.ID}
+	if c.Email != "" {
+		keys["email:"+c.Email] = c.ID
+	}
+
+	return keys
+}
+
+func nameKey(name string) string {
+	return "name:" + name
+}
+
+// cassandraRow is a row of the customer table.
+type cassandraRow struct {
+	id             int
+	name           string
+	email          string
+	phone          string
+	dateOfBirth    string
+	salaryMinor    *int64
+	salaryCurrency string
+	createdAt      time.Time
+	updatedAt      time.Time
+	status         string
+	attributes     string
+}
+
+// newCassandraRow returns the row of a customer. Its attributes are stored as they would be in the database,
+// see copyAttributes.
+func newCassandraRow(c models.Customer) (cassandraRow, error) {
+	row := cassandraRow{id: c.ID, name: c.Name, email: c.Email, phone: c.Phone, status: string(c.Status)}
+
+	if c.DateOfBirth != nil {
+		row.dateOfBirth = c.DateOfBirth.String()
+	}
+
+	if c.Salary != nil {
+		minor := c.Salary.Minor
+		row.salaryMinor, row.salaryCurrency = &minor, c.Salary.Currency
+	}
+
+	if c.CreatedAt != nil {
+		row.createdAt = *c.CreatedAt
+	}
+
+	if c.UpdatedAt != nil {
+		row.updatedAt = *c.UpdatedAt
+	}
+
+	if len(c.Attributes) > 0 {
+		b, err := json.Marshal(c.Attributes)
+		if err != nil {
+			return cassandraRow{}, err
+		}
+
+		row.attributes = string(b)
+	}
+
+	return row, nil
+}
+
+// dest returns the destinations of the columns in cassandraColumns.
+func (r *cassandraRow) dest() []interface{} {
+	return []interface{}{&r.id, &r.name, &r.email, &r.phone, &r.dateOfBirth, &r.salaryMinor, &r.salaryCurrency,
+		&r.createdAt, &r.updatedAt, &r.status, &r.attributes}
+}
+
+// customer reads the customer of the row.
+func (r cassandraRow) customer() (models.Customer, error) {
+	createdAt, updatedAt := r.createdAt.UTC(), r.updatedAt.UTC()
+
+	c := models.Customer{ID: r.id, Name: r.name, Email: r.email, Phone: r.phone, CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt, Status: models.Status(r.status)}
+
+	if r.dateOfBirth != "" {
+		dob, err := models.ParseDate(r.dateOfBirth)
+		if err != nil {
+			return models.Customer{}, err
+		}
+
+		c.DateOfBirth = &dob
+	}
+
+	if r.salaryMinor != nil {
+		c.Salary = &models.Money{Minor: *r.salaryMinor, Currency: r.salaryCurrency}
+	}
+
+	if r.attributes != "" {
+		if err := json.Unmarshal([]byte(r.attributes), &c.Attributes); err != nil {
+			return models.Customer{}, err
+		}
+	}
+
+	return c, nil
+}
+
+// cassandraWriteError reports a name or an email held by another customer as an existing entity and any other
+// failure as dbError does.
+func cassandraWriteError(ctx *gofr.Context, method string, err error) error {
+	if err == errClaimed {
+		return errors.EntityAlreadyExists{}
+	}
+
+	return dbError(ctx, method, err)
+}
@@ -0,0 +1,534 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/gocql/gocql"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"customer/middleware"
+	"customer/models"
+)
+
+// The conformance tests hold every customer store to the same behaviour. They always run against the in-memory
+// store. They run against Postgres when CONFORMANCE_POSTGRES is set, with the database configured by the DB_*
+// variables and migrated with database/database.sql, against MongoDB when CONFORMANCE_MONGO_URI points at a
+// replica set, and against Cassandra when CONFORMANCE_CASSANDRA_HOSTS lists the hosts of a cluster, which the CI
+// workflow does for all three. Every test writes to tenants of its own, so that a database can be shared between
+// runs.
+
+func TestConformance_Memory(t *testing.T) {
+	testConformance(t, gofr.New(), NewMemory())
+}
+
+func TestConformance_Postgres(t *testing.T) {
+	if os.Getenv("CONFORMANCE_POSTGRES") == "" {
+		t.Skip("CONFORMANCE_POSTGRES is not set")
+	}
+
+	testConformance(t, gofr.New(), New(nil))
+}
+
+func TestConformance_Mongo(t *testing.T) {
+	uri := os.Getenv("CONFORMANCE_MONGO_URI")
+	if uri == "" {
+		t.Skip("CONFORMANCE_MONGO_URI is not set")
+	}
+
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Disconnect(ctx) }()
+
+	db := client.Database(fmt.Sprintf("conformance_%d", time.Now().UnixNano()))
+	defer func() { _ = db.Drop(ctx) }()
+
+	s := NewMongo(db)
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	testConformance(t, gofr.New(), s)
+}
+
+func TestConformance_Cassandra(t *testing.T) {
+	hosts := os.Getenv("CONFORMANCE_CASSANDRA_HOSTS")
+	if hosts == "" {
+		t.Skip("CONFORMANCE_CASSANDRA_HOSTS is not set")
+	}
+
+	cluster := gocql.NewCluster(strings.Split(hosts, ",")...)
+	cluster.Timeout = 30 * time.Second
+
+	admin, err := cluster.CreateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+
+	keyspace := fmt.Sprintf("conformance_%d", time.Now().UnixNano())
+
+	err = admin.Query("CREATE KEYSPACE " + keyspace +
+		" WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}").Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = admin.Query("DROP KEYSPACE " + keyspace).Exec() }()
+
+	cluster.Keyspace = keyspace
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	s := NewCassandra(session)
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	testConformance(t, gofr.New(), s)
+}
+
+func testConformance(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	tests := []struct {
+		desc string
+		test func(t *testing.T, app *gofr.Gofr, s ServiceIn)
+	}{
+		{"not found", testNotFound},
+		{"create", testCreate},
+		{"unique", testUnique},
+		{"update", testUpdate},
+		{"upsert", testUpsert},
+		{"patch", testPatch},
+		{"delete", testDelete},
+		{"tenants", testTenants},
+		{"filter", testFilter},
+		{"get by ids", testGetByIDs},
+		{"merge", testMerge},
+		{"stats", testStats},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) { tc.test(t, app, s) })
+	}
+}
+
+// runs numbers the tenants of the conformance tests.
+var runs int64
+
+// newTenant returns a context scoped to a tenant that no other test writes to.
+func newTenant(app *gofr.Gofr) *gofr.Context {
+	ctx := gofr.NewContext(nil, nil, app)
+	ctx.Context = middleware.WithTenant(context.Background(),
+		fmt.Sprintf("conformance-%d-%d", time.Now().UnixNano(), atomic.AddInt64(&runs, 1)))
+
+	return ctx
+}
+
+// stored returns the customer without what the store sets when writing it.
+func stored(c models.Customer) models.Customer {
+	c.CreatedAt, c.UpdatedAt = nil, nil
+	return c
+}
+
+func mustCreate(t *testing.T, ctx *gofr.Context, s ServiceIn, c models.Customer) models.Customer {
+	t.Helper()
+
+	res, err := s.Create(ctx, c)
+	if err != nil {
+		t.Fatalf("Expected %v to be created\nGot %v", c.Name, err)
+	}
+
+	return res
+}
+
+// bornYearsAgo returns the date of birth of a customer who turned age this year on the 1st of January.
+func bornYearsAgo(age int) *models.Date {
+	d := models.NewDate(time.Now().Year()-age, time.January, 1)
+	return &d
+}
+
+func testNotFound(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	missing := 2147483000
+
+	_, getErr := s.GetByID(ctx, missing)
+	_, updateErr := s.Update(ctx, missing, models.Customer{Name: "Divya"})
+	_, patchErr := s.Patch(ctx, missing, models.Customer{Phone: "+919876543210"})
+	deleteErr := s.Delete(ctx, missing)
+
+	for i, err := range []error{getErr, updateErr, patchErr, deleteErr} {
+		if err != sql.ErrNoRows {
+			t.Errorf("TEST[%d] Expected %v\nGot %v", i, sql.ErrNoRows, err)
+		}
+	}
+
+	survivor := mustCreate(t, ctx, s, models.Customer{Name: "Divya"})
+	keep := func(survivor models.Customer, _ []models.Customer) models.Customer { return survivor }
+
+	_, err := s.Merge(ctx, survivor.ID, []int{missing}, keep)
+	if expected := (errors.EntityNotFound{Entity: "customer", ID: fmt.Sprint(missing)}); err != expected {
+		t.Errorf("Expected %v\nGot %v", expected, err)
+	}
+
+	untenanted := gofr.NewContext(nil, nil, app)
+	untenanted.Context = context.Background()
+
+	if _, err := s.GetByID(untenanted, survivor.ID); err != errNoTenant {
+		t.Errorf("Expected %v\nGot %v", errNoTenant, err)
+	}
+}
+
+func testCreate(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	dob := models.NewDate(2000, time.March, 14)
+	divya := models.Customer{Name: "Divya", Email: "divya@example.com", Phone: "+919876543210", DateOfBirth: &dob,
+		Salary: inr(3000000), Attributes: models.Attributes{"tier": "gold", "visits": float64(3)}}
+
+	res := mustCreate(t, ctx, s, divya)
+	if res.ID == 0 || res.CreatedAt == nil || res.UpdatedAt == nil || res.Status != models.StatusProspect {
+		t.Errorf("Expected an id, timestamps and the prospect status\nGot %+v", res)
+	}
+
+	expected := divya
+	expected.ID, expected.Status = res.ID, models.StatusProspect
+
+	if got, err := s.GetByID(ctx, res.ID); err != nil || !reflect.DeepEqual(stored(got), expected) {
+		t.Errorf("Expected %+v\nGot %+v, %v", expected, stored(got), err)
+	}
+
+	if got, err := s.GetByID(ctx, res.ID, "name", "age"); err != nil ||
+		!reflect.DeepEqual(got, models.Customer{Name: "Divya", DateOfBirth: &dob}) {
+		t.Errorf("Expected the name and the date of birth\nGot %+v, %v", got, err)
+	}
+
+	next := mustCreate(t, ctx, s, models.Customer{Name: "Ravi"})
+	if next.ID <= res.ID {
+		t.Errorf("Expected an id above %v\nGot %v", res.ID, next.ID)
+	}
+
+	if got, err := s.GetByID(ctx, next.ID); err != nil || got.Attributes != nil || got.Email != "" || got.Salary != nil {
+		t.Errorf("Expected a customer without attributes, email or salary\nGot %+v, %v", got, err)
+	}
+}
+
+func testUnique(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	mustCreate(t, ctx, s, models.Customer{Name: "Divya", Email: "divya@example.com"})
+	ravi := mustCreate(t, ctx, s, models.Customer{Name: "Ravi"})
+
+	tests := []struct {
+		desc string
+		err  error
+		run  func() error
+	}{
+		{"same name", errors.EntityAlreadyExists{}, func() error {
+			_, err := s.Create(ctx, models.Customer{Name: "Divya"})
+			return err
+		}},
+		{"same email", errors.EntityAlreadyExists{}, func() error {
+			_, err := s.Create(ctx, models.Customer{Name: "Asha", Email: "divya@example.com"})
+			return err
+		}},
+		{"no email", nil, func() error {
+			_, err := s.Create(ctx, models.Customer{Name: "Asha"})
+			return err
+		}},
+		{"update to a taken name", errors.EntityAlreadyExists{}, func() error {
+			_, err := s.Update(ctx, ravi.ID, models.Customer{Name: "Divya"})
+			return err
+		}},
+		{"patch to a taken email", errors.EntityAlreadyExists{}, func() error {
+			_, err := s.Patch(ctx, ravi.ID, models.Customer{Email: "divya@example.com"})
+			return err
+		}},
+		{"upsert to a taken email", errors.EntityAlreadyExists{}, func() error {
+			_, _, err := s.Upsert(ctx, models.Customer{Name: "Ravi", Email: "divya@example.com"})
+			return err
+		}},
+	}
+
+	for i, tc := range tests {
+		if err := tc.run(); err != tc.err {
+			t.Errorf("TEST[%d] %v\nExpected %v\nGot %v", i, tc.desc, tc.err, err)
+		}
+	}
+
+	if got, err := s.GetByID(ctx, ravi.ID); err != nil || got.Name != "Ravi" || got.Email != "" {
+		t.Errorf("Expected Ravi to be unchanged\nGot %+v, %v", got, err)
+	}
+}
+
+func testUpdate(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	created := mustCreate(t, ctx, s, models.Customer{Name: "Divya", Email: "divya@example.com",
+		Attributes: models.Attributes{"tier": "gold"}})
+
+	dob := models.NewDate(1990, time.July, 1)
+	update := models.Customer{ID: created.ID, Name: "Divya S", Phone: "+919876543210", DateOfBirth: &dob}
+
+	res, err := s.Update(ctx, created.ID, update)
+	if err != nil || res.Status != models.StatusProspect || res.UpdatedAt == nil ||
+		!reflect.DeepEqual(res.Attributes, models.Attributes{"tier": "gold"}) {
+		t.Errorf("Expected the stored status and attributes\nGot %+v, %v", res, err)
+	}
+
+	expected := update
+	expected.Status, expected.Attributes = models.StatusProspect, models.Attributes{"tier": "gold"}
+
+	if got, err := s.GetByID(ctx, created.ID); err != nil || !reflect.DeepEqual(stored(got), expected) {
+		t.Errorf("Expected %+v\nGot %+v, %v", expected, stored(got), err)
+	}
+
+	if _, err := s.Update(ctx, created.ID, models.Customer{Name: "Divya S", Attributes: models.Attributes{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := s.GetByID(ctx, created.ID); err != nil || got.Attributes != nil || got.Phone != "" {
+		t.Errorf("Expected the attributes and the phone to be removed\nGot %+v, %v", got, err)
+	}
+}
+
+func testUpsert(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+
+	res, created, err := s.Upsert(ctx, models.Customer{Name: "Divya", Email: "divya@example.com",
+		Attributes: models.Attributes{"tier": "gold"}})
+	if err != nil || !created || res.ID == 0 || res.Status != models.StatusProspect {
+		t.Fatalf("Expected Divya to be created\nGot %+v, %v, %v", res, created, err)
+	}
+
+	again, created, err := s.Upsert(ctx, models.Customer{Name: "Divya", Phone: "+919876543210"})
+	if err != nil || created || again.ID != res.ID || !reflect.DeepEqual(again.Attributes, models.Attributes{"tier": "gold"}) {
+		t.Errorf("Expected Divya to be replaced, keeping her attributes\nGot %+v, %v, %v", again, created, err)
+	}
+
+	got, err := s.GetByID(ctx, res.ID)
+	if err != nil || got.Email != "" || got.Phone != "+919876543210" {
+		t.Errorf("Expected the email to be replaced by the phone\nGot %+v, %v", got, err)
+	}
+
+	if other, created, err := s.Upsert(ctx, models.Customer{Name: "Ravi"}); err != nil || !created || other.Attributes != nil {
+		t.Errorf("Expected Ravi to be created without attributes\nGot %+v, %v, %v", other, created, err)
+	}
+}
+
+func testPatch(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	dob := models.NewDate(2000, time.March, 14)
+	created := mustCreate(t, ctx, s, models.Customer{Name: "Divya", Email: "divya@example.com", DateOfBirth: &dob,
+		Attributes: models.Attributes{"tier": "gold", "visits": float64(3)}})
+
+	if res, err := s.Patch(ctx, created.ID, models.Customer{}); err != nil || !reflect.DeepEqual(res, models.Customer{}) {
+		t.Errorf("Expected an empty patch to do nothing\nGot %+v, %v", res, err)
+	}
+
+	patch := models.Customer{Phone: "+919876543210", Salary: inr(100), Attributes: models.Attributes{"tier": nil, "vip": true}}
+
+	res, err := s.Patch(ctx, created.ID, patch)
+
+	expected := patch
+	expected.ID = created.ID
+
+	if err != nil || !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %+v\nGot %+v, %v", expected, res, err)
+	}
+
+	got, err := s.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.Customer{ID: created.ID, Name: "Divya", Email: "divya@example.com", Phone: "+919876543210",
+		DateOfBirth: &dob, Salary: inr(100), Status: models.StatusProspect,
+		Attributes: models.Attributes{"visits": float64(3), "vip": true}}
+	if !reflect.DeepEqual(stored(got), want) {
+		t.Errorf("Expected %+v\nGot %+v", want, stored(got))
+	}
+}
+
+func testDelete(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	created := mustCreate(t, ctx, s, models.Customer{Name: "Divya"})
+
+	if err := s.Delete(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetByID(ctx, created.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected %v\nGot %v", sql.ErrNoRows, err)
+	}
+
+	if err := s.Delete(ctx, created.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected %v\nGot %v", sql.ErrNoRows, err)
+	}
+
+	mustCreate(t, ctx, s, models.Customer{Name: "Divya"})
+}
+
+func testTenants(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx, other := newTenant(app), newTenant(app)
+	created := mustCreate(t, ctx, s, models.Customer{Name: "Divya", Email: "divya@example.com"})
+
+	mustCreate(t, other, s, models.Customer{Name: "Divya", Email: "divya@example.com"})
+
+	if _, err := s.GetByID(other, created.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected %v\nGot %v", sql.ErrNoRows, err)
+	}
+
+	if _, err := s.Patch(other, created.ID, models.Customer{Phone: "+919876543210"}); err != sql.ErrNoRows {
+		t.Errorf("Expected %v\nGot %v", sql.ErrNoRows, err)
+	}
+
+	if err := s.Delete(other, created.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected %v\nGot %v", sql.ErrNoRows, err)
+	}
+
+	if count, err := s.Count(ctx, models.Filter{}); err != nil || count != 1 {
+		t.Errorf("Expected 1 customer\nGot %v, %v", count, err)
+	}
+}
+
+func testFilter(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	divya := mustCreate(t, ctx, s, models.Customer{Name: "Divya", DateOfBirth: bornYearsAgo(30),
+		Attributes: models.Attributes{"tier": "gold"}})
+	ravi := mustCreate(t, ctx, s, models.Customer{Name: "Ravi", DateOfBirth: bornYearsAgo(20),
+		Attributes: models.Attributes{"tier": "silver"}})
+	asha := mustCreate(t, ctx, s, models.Customer{Name: "Asha"})
+
+	tests := []struct {
+		desc     string
+		filter   models.Filter
+		expected []int
+	}{
+		{"all", models.Filter{}, []int{divya.ID, ravi.ID, asha.ID}},
+		{"name", models.Filter{Name: "Ravi"}, []int{ravi.ID}},
+		{"status", models.Filter{Status: models.StatusActive}, nil},
+		{"min age", models.Filter{MinAge: 30}, []int{divya.ID}},
+		{"max age", models.Filter{MaxAge: 29}, []int{ravi.ID}},
+		{"age range", models.Filter{MinAge: 20, MaxAge: 30}, []int{divya.ID, ravi.ID}},
+		{"attributes", models.Filter{Attributes: models.Attributes{"tier": "gold"}}, []int{divya.ID}},
+		{"limit", models.Filter{Limit: 2}, []int{divya.ID, ravi.ID}},
+		{"offset", models.Filter{Limit: 2, Offset: 2}, []int{asha.ID}},
+	}
+
+	for i, tc := range tests {
+		res, err := s.Get(ctx, tc.filter)
+
+		var ids []int
+		for _, c := range res {
+			ids = append(ids, c.ID)
+		}
+
+		if err != nil || !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("TEST[%d] %v\nExpected %v\nGot %v, %v", i, tc.desc, tc.expected, ids, err)
+		}
+	}
+
+	if count, err := s.Count(ctx, models.Filter{MinAge: 20, Limit: 1}); err != nil || count != 2 {
+		t.Errorf("Expected 2 customers\nGot %v, %v", count, err)
+	}
+
+	var streamed []string
+
+	err := s.Stream(ctx, models.Filter{Fields: []string{"name"}}, func(c models.Customer) error {
+		streamed = append(streamed, c.Name)
+
+		if c.ID != 0 {
+			t.Errorf("Expected only the name\nGot %+v", c)
+		}
+
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(streamed, []string{"Divya", "Ravi", "Asha"}) {
+		t.Errorf("Expected every name\nGot %v, %v", streamed, err)
+	}
+}
+
+func testGetByIDs(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx, other := newTenant(app), newTenant(app)
+	divya := mustCreate(t, ctx, s, models.Customer{Name: "Divya"})
+	ravi := mustCreate(t, ctx, s, models.Customer{Name: "Ravi"})
+	theirs := mustCreate(t, other, s, models.Customer{Name: "Asha"})
+
+	res, err := s.GetByIDs(ctx, []int{ravi.ID, theirs.ID, 2147483000, divya.ID})
+
+	found := map[int]string{}
+	for _, c := range res {
+		found[c.ID] = c.Name
+	}
+
+	if expected := map[int]string{divya.ID: "Divya", ravi.ID: "Ravi"}; err != nil || !reflect.DeepEqual(found, expected) {
+		t.Errorf("Expected %v\nGot %v, %v", expected, found, err)
+	}
+
+	if res, err := s.GetByIDs(ctx, nil); err != nil || res != nil {
+		t.Errorf("Expected nothing\nGot %v, %v", res, err)
+	}
+}
+
+func testMerge(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	survivor := mustCreate(t, ctx, s, models.Customer{Name: "Divya", Phone: "+919876543210"})
+	loser := mustCreate(t, ctx, s, models.Customer{Name: "Divya S", Email: "divya@example.com", Salary: inr(100)})
+
+	res, err := s.Merge(ctx, survivor.ID, []int{loser.ID}, func(survivor models.Customer, losers []models.Customer) models.Customer {
+		survivor.Email, survivor.Salary = losers[0].Email, losers[0].Salary
+		return survivor
+	})
+	if err != nil || res.ID != survivor.ID || res.UpdatedAt == nil {
+		t.Fatalf("Expected the survivor\nGot %+v, %v", res, err)
+	}
+
+	if _, err := s.GetByID(ctx, loser.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected the loser to be deleted\nGot %v", err)
+	}
+
+	got, err := s.GetByID(ctx, survivor.ID)
+	if err != nil || got.Email != "divya@example.com" || !reflect.DeepEqual(got.Salary, inr(100)) || got.Phone != "+919876543210" {
+		t.Errorf("Expected the merged survivor\nGot %+v, %v", got, err)
+	}
+
+	mustCreate(t, ctx, s, models.Customer{Name: "Divya S"})
+}
+
+func testStats(t *testing.T, app *gofr.Gofr, s ServiceIn) {
+	ctx := newTenant(app)
+	mustCreate(t, ctx, s, models.Customer{Name: "Divya", DateOfBirth: bornYearsAgo(30), Salary: inr(300)})
+	mustCreate(t, ctx, s, models.Customer{Name: "Ravi", DateOfBirth: bornYearsAgo(20), Salary: inr(100)})
+	mustCreate(t, ctx, s, models.Customer{Name: "Asha"})
+
+	res, err := s.Stats(ctx, models.Filter{}, []int{18, 25})
+
+	max18, max25 := 18, 25
+	expected := models.Stats{Count: 3, Age: models.AgeStats{Count: 2, Min: 20, Max: 30, Avg: 25, P50: 20, P90: 30, P99: 30},
+		Salary: []models.SalaryStats{{Currency: "INR", Count: 2, Min: *inr(100), Max: *inr(300), Avg: *inr(200),
+			P50: *inr(100), P90: *inr(300), P99: *inr(300)}},
+		AgeHistogram: []models.AgeBucket{{Max: &max18}, {Min: 18, Max: &max25, Count: 1}, {Min: 25, Count: 1}}}
+
+	if err != nil || !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %+v\nGot %+v, %v", expected, res, err)
+	}
+
+	if _, err := s.Stats(ctx, models.Filter{Tags: []string{"vip"}}, nil); err != nil && err != ErrTagFilter {
+		t.Errorf("Expected the tag filter to be served or refused with %v\nGot %v", ErrTagFilter, err)
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"fmt"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// Dialect is a SQL dialect the customer store is written for. Postgres serves every store; YugabyteDB is served
+// through its Postgres-compatible YSQL API. MySQL serves the customers in the tables of database/mysql.sql:
+// upserts use ON DUPLICATE KEY UPDATE instead of ON CONFLICT, written rows are read back instead of returned and
+// attributes are merged and matched with the JSON functions of MySQL.
+// Encryption, statistics, merges and the stores of everything kept next to the customers, such as addresses and
+// tags, are written for Postgres only.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+)
+
+// ErrPostgresOnly is returned by the methods of the customer store that are written for Postgres only.
+var ErrPostgresOnly = errors.Error("only supported on Postgres")
+
+// ParseDialect returns the dialect named by DB_DIALECT, so that the service does not start against a database
+// its queries are not written for.
+func ParseDialect(name string) (Dialect, error) {
+	switch d := Dialect(name); d {
+	case Postgres, MySQL:
+		return d, nil
+	}
+
+	return "", fmt.Errorf("DB_DIALECT %q is not supported: use postgres or mysql", name)
+}
+
+// now is the current time of a write. MySQL keeps microseconds, so that a write in the same second as the
+// previous one still changes the row and is counted as affected.
+func (d Dialect) now() string {
+	if d == MySQL {
+		return "CURRENT_TIMESTAMP(6)"
+	}
+
+	return "now()"
+}
+
+// bornBefore is the latest date of birth of a customer who is at least the given number of years old.
+func (d Dialect) bornBefore() string {
+	if d == MySQL {
+		return "DATE_SUB(CURRENT_DATE, INTERVAL ? YEAR)"
+	}
+
+	return "CURRENT_DATE - make_interval(years => ?)"
+}
+
+// mergeAttributes merges the given attributes into the stored ones and removes those that are null. MySQL
+// implements that merge as JSON_MERGE_PATCH.
+func (d Dialect) mergeAttributes() string {
+	if d == MySQL {
+		return "JSON_MERGE_PATCH(attributes, CAST(? AS JSON))"
+	}
+
+	return "jsonb_strip_nulls(attributes || ?::jsonb)"
+}
+
+// containsAttributes matches the customers whose attributes contain the given ones. On Postgres it is served by
+// the GIN index on attributes.
+func (d Dialect) containsAttributes() string {
+	if d == MySQL {
+		return "JSON_CONTAINS(attributes, CAST(? AS JSON))"
+	}
+
+	return "attributes @> ?::jsonb"
+}
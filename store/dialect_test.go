@@ -0,0 +1,23 @@
+package store
+
+import "testing"
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Dialect
+		ok       bool
+	}{
+		{"postgres", Postgres, true},
+		{"mysql", MySQL, true},
+		{"sqlite", "", false},
+		{"", "", false},
+	}
+
+	for i, tc := range tests {
+		d, err := ParseDialect(tc.name)
+		if d != tc.expected || (err == nil) != tc.ok {
+			t.Errorf("TEST[%d] %q: Expected %q, ok %v\nGot %q, %v", i+1, tc.name, tc.expected, tc.ok, d, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"database/sql"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/tracing"
+)
+
+const (
+	erasureColumns = "id, customer_id, caller, erased_at, prev_hash, hash"
+	// mergedInto holds the customers merged into a customer, and those merged into them in turn.
+	mergedInto = "WITH RECURSIVE merged(id) AS (SELECT id FROM customer WHERE merged_into=? UNION " +
+		"SELECT customer.id FROM customer JOIN merged ON customer.merged_into = merged.id) "
+)
+
+type gdpr struct {
+	keys *Keyring
+}
+
+func NewGDPR(keys *Keyring) gdpr {
+	return gdpr{keys: keys}
+}
+
+// Merged returns the customers of the tenant that were merged into a customer, directly or not, oldest first.
+func (s gdpr) Merged(ctx *gofr.Context, customerID int) ([]models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k := s.keys.of(tenant)
+	cols := k.columns(columns)
+	query := mergedInto + "SELECT " + selectList(cols) + " FROM customer WHERE tenant_id=? AND id IN (SELECT id FROM merged) " +
+		"ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.GDPR.Merged", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, customerID, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "GDPR.Merged", err)
+	}
+	defer rows.Close()
+
+	res := []models.Customer{}
+
+	for rows.Next() {
+		r, err := scanRow(rows, cols)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+
+		customer, err := k.decrypt(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, customer)
+	}
+	return res, nil
+}
+
+// Audit returns the audit log entries of the tenant about a customer, including the merges it was lost in,
+// oldest first.
+func (s gdpr) Audit(ctx *gofr.Context, customerID int) ([]models.AuditEntry, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT id, action, customer_id, detail, caller, created_at FROM audit_log " +
+		"WHERE tenant_id=? AND (customer_id=? OR detail->'losers' @> to_jsonb(?::int)) ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.GDPR.Audit", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, tenant, customerID, customerID)
+	if err != nil {
+		return nil, dbError(ctx, "GDPR.Audit", err)
+	}
+	defer rows.Close()
+
+	res := []models.AuditEntry{}
+
+	for rows.Next() {
+		var (
+			e      models.AuditEntry
+			detail []byte
+		)
+
+		if err := rows.Scan(&e.ID, &e.Action, &e.CustomerID, &detail, &e.Caller, &e.CreatedAt); err != nil {
+			return nil, errors.Error("scan error")
+		}
+		e.Detail = detail
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// Erase removes the personal data of a customer of the tenant and of the customers merged into it, and records
+// the erasure in erasure_log, in one transaction. Names become erased-<id>, contact details and custom
+// attributes are removed, dates of birth are kept to the year, and address lines and transition reasons are
+// blanked. Erased names are not personal data, so they are stored in plaintext even when names are encrypted.
+// Salaries, countries, statuses, tags and relationships are kept for statistics. A customer can be erased again
+// once new data was written to it. When the customer is not found, nothing changes and sql.ErrNoRows is
+// returned.
+func (s gdpr) Erase(ctx *gofr.Context, customerID int) (models.Erasure, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Erasure{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.GDPR.Erase", tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	e := models.Erasure{CustomerID: customerID, Caller: middleware.Caller(ctx)}
+
+	query := "UPDATE customer SET erased_at=now(),updated_at=now() " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING erased_at"
+
+	err = tx.QueryRowContext(ctx, query, customerID, tenant).Scan(&e.ErasedAt)
+	if err == sql.ErrNoRows {
+		return models.Erasure{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+	}
+
+	erased := []struct {
+		query string
+		args  []interface{}
+	}{
+		{mergedInto + "UPDATE customer SET name='erased-' || id,name_enc=NULL,name_index=NULL,email=NULL,phone=NULL," +
+			"date_of_birth=date_trunc('year', date_of_birth)::date,attributes='{}',erased_at=?,updated_at=now() " +
+			"WHERE id=? OR id IN (SELECT id FROM merged)", []interface{}{customerID, e.ErasedAt, customerID}},
+		{mergedInto + "UPDATE address SET street='',city='',postal_code='' " +
+			"WHERE customer_id=? OR customer_id IN (SELECT id FROM merged)", []interface{}{customerID, customerID}},
+		{mergedInto + "UPDATE customer_transition SET reason='' " +
+			"WHERE customer_id=? OR customer_id IN (SELECT id FROM merged)", []interface{}{customerID, customerID}},
+	}
+
+	for _, q := range erased {
+		if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+		}
+	}
+
+	// Erasures of a tenant are chained one at a time, so that two of them cannot follow the same entry.
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "erasure_log:"+tenant); err != nil {
+		return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+	}
+
+	query = "SELECT hash FROM erasure_log WHERE tenant_id=? ORDER BY id DESC LIMIT 1"
+
+	err = tx.QueryRowContext(ctx, query, tenant).Scan(&e.PrevHash)
+	if err == sql.ErrNoRows {
+		e.PrevHash = models.GenesisHash
+	} else if err != nil {
+		return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+	}
+
+	e.Hash = e.Digest(tenant)
+	query = "INSERT INTO erasure_log (tenant_id,customer_id,caller,erased_at,prev_hash,hash) VALUES(?,?,?,?,?,?) RETURNING id"
+
+	err = tx.QueryRowContext(ctx, query, tenant, e.CustomerID, e.Caller, e.ErasedAt, e.PrevHash, e.Hash).Scan(&e.ID)
+	if err != nil {
+		return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Erasure{}, dbError(ctx, "GDPR.Erase", err)
+	}
+	return e, nil
+}
+
+// Erasures returns the erasure log of the tenant, oldest first.
+func (s gdpr) Erasures(ctx *gofr.Context) ([]models.Erasure, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + erasureColumns + " FROM erasure_log WHERE tenant_id=? ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.GDPR.Erasures", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "GDPR.Erasures", err)
+	}
+	defer rows.Close()
+
+	res := []models.Erasure{}
+
+	for rows.Next() {
+		var e models.Erasure
+
+		if err := rows.Scan(&e.ID, &e.CustomerID, &e.Caller, &e.ErasedAt, &e.PrevHash, &e.Hash); err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
@@ -0,0 +1,201 @@
+package store
+
+import (
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func TestGDPR_Merged(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewGDPR(nil)
+	query := mergedInto + "SELECT " + customerColumns + " FROM customer WHERE tenant_id=? AND id IN (SELECT id FROM merged) " +
+		"ORDER BY id"
+
+	tests := []struct {
+		desc     string
+		expected []models.Customer
+		err      error
+		mock     interface{}
+	}{
+		{"success", []models.Customer{divya()}, nil,
+			mock.ExpectQuery(query).WithArgs(2, tenant).WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
+		{"none merged", []models.Customer{}, nil,
+			mock.ExpectQuery(query).WithArgs(2, tenant).WillReturnRows(sqlmock.NewRows(columnNames))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Merged(ctx, 2)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestGDPR_Audit(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewGDPR(nil)
+	query := "SELECT id, action, customer_id, detail, caller, created_at FROM audit_log " +
+		"WHERE tenant_id=? AND (customer_id=? OR detail->'losers' @> to_jsonb(?::int)) ORDER BY id"
+	detail := `{"losers":[1],"addresses":2,"tags":0}`
+
+	tests := []struct {
+		desc     string
+		expected []models.AuditEntry
+		err      error
+		mock     interface{}
+	}{
+		{"success", []models.AuditEntry{{ID: 1, Action: "merge", CustomerID: 2, Detail: json.RawMessage(detail),
+			Caller: "divya-zs", CreatedAt: created}}, nil,
+			mock.ExpectQuery(query).WithArgs(tenant, 1, 1).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "action", "customer_id", "detail", "caller", "created_at"}).
+					AddRow(1, "merge", 2, []byte(detail), "divya-zs", created))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Audit(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestGDPR_Erase(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewGDPR(nil)
+	mark := "UPDATE customer SET erased_at=now(),updated_at=now() " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING erased_at"
+	customers := mergedInto + "UPDATE customer SET name='erased-' || id,name_enc=NULL,name_index=NULL,email=NULL,phone=NULL," +
+		"date_of_birth=date_trunc('year', date_of_birth)::date,attributes='{}',erased_at=?,updated_at=now() " +
+		"WHERE id=? OR id IN (SELECT id FROM merged)"
+	addresses := mergedInto + "UPDATE address SET street='',city='',postal_code='' " +
+		"WHERE customer_id=? OR customer_id IN (SELECT id FROM merged)"
+	transitions := mergedInto + "UPDATE customer_transition SET reason='' " +
+		"WHERE customer_id=? OR customer_id IN (SELECT id FROM merged)"
+	lock := "SELECT pg_advisory_xact_lock(hashtext(?))"
+	last := "SELECT hash FROM erasure_log WHERE tenant_id=? ORDER BY id DESC LIMIT 1"
+	insert := "INSERT INTO erasure_log (tenant_id,customer_id,caller,erased_at,prev_hash,hash) VALUES(?,?,?,?,?,?) RETURNING id"
+
+	first := models.Erasure{ID: 1, CustomerID: 1, ErasedAt: created, PrevHash: models.GenesisHash}
+	first.Hash = first.Digest(tenant)
+	second := models.Erasure{ID: 2, CustomerID: 1, ErasedAt: created, PrevHash: first.Hash}
+	second.Hash = second.Digest(tenant)
+
+	anonymised := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(mark).WithArgs(1, tenant).WillReturnRows(sqlmock.NewRows([]string{"erased_at"}).AddRow(created))
+		mock.ExpectExec(customers).WithArgs(1, created, 1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(addresses).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(transitions).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lock).WithArgs("erasure_log:" + tenant).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	tests := []struct {
+		desc     string
+		expected models.Erasure
+		err      error
+		mock     func()
+	}{
+		{"first erasure", first, nil, func() {
+			anonymised()
+			mock.ExpectQuery(last).WithArgs(tenant).WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(insert).WithArgs(tenant, 1, "", created, models.GenesisHash, first.Hash).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectCommit()
+		}},
+		{"chained erasure", second, nil, func() {
+			anonymised()
+			mock.ExpectQuery(last).WithArgs(tenant).WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow(first.Hash))
+			mock.ExpectQuery(insert).WithArgs(tenant, 1, "", created, first.Hash, second.Hash).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			mock.ExpectCommit()
+		}},
+		{"not found", models.Erasure{}, sql.ErrNoRows, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(mark).WithArgs(1, tenant).WillReturnError(sql.ErrNoRows)
+			mock.ExpectRollback()
+		}},
+		{"internal server error", models.Erasure{}, errors.DB{Err: errors.Error("db error")}, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(mark).WithArgs(1, tenant).WillReturnRows(sqlmock.NewRows([]string{"erased_at"}).AddRow(created))
+			mock.ExpectExec(customers).WillReturnError(errors.Error("db error"))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.mock()
+
+			res, err := store.Erase(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("TEST[%d] %v", i+1, err)
+			}
+		})
+	}
+}
+
+func TestGDPR_Erasures(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewGDPR(nil)
+	query := "SELECT " + erasureColumns + " FROM erasure_log WHERE tenant_id=? ORDER BY id"
+
+	tests := []struct {
+		desc     string
+		expected []models.Erasure
+		err      error
+		mock     interface{}
+	}{
+		{"success", []models.Erasure{{ID: 1, CustomerID: 3, Caller: "divya-zs", ErasedAt: created,
+			PrevHash: models.GenesisHash, Hash: "ab"}}, nil,
+			mock.ExpectQuery(query).WithArgs(tenant).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "customer_id", "caller", "erased_at", "prev_hash", "hash"}).
+					AddRow(1, 3, "divya-zs", created, models.GenesisHash, "ab"))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Erasures(ctx)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
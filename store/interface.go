@@ -6,10 +6,68 @@ import (
 )
 
 type ServiceIn interface {
-	Get(ctx *gofr.Context) ([]models.Customer, error)
-	GetByID(ctx *gofr.Context, id int) (models.Customer, error)
+	Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error)
+	Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error
+	GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error)
+	GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error)
+	Count(ctx *gofr.Context, filter models.Filter) (int, error)
 	Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error)
+	Upsert(ctx *gofr.Context, customer models.Customer) (res models.Customer, created bool, err error)
 	Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error)
 	Delete(ctx *gofr.Context, id int) error
 	Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error)
+	Merge(ctx *gofr.Context, survivorID int, loserIDs []int,
+		merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error)
+	Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error)
+}
+
+type AddressServiceIn interface {
+	List(ctx *gofr.Context, customerID int) ([]models.Address, error)
+	Get(ctx *gofr.Context, customerID, id int) (models.Address, error)
+	Create(ctx *gofr.Context, address models.Address) (models.Address, error)
+	Update(ctx *gofr.Context, address models.Address) (models.Address, error)
+	Delete(ctx *gofr.Context, customerID, id int) error
+}
+
+type TransitionServiceIn interface {
+	Create(ctx *gofr.Context, t models.Transition) (models.Transition, error)
+	List(ctx *gofr.Context, customerID int) ([]models.Transition, error)
+}
+
+type TagServiceIn interface {
+	List(ctx *gofr.Context, customerID int) ([]string, error)
+	Add(ctx *gofr.Context, customerID int, name string) error
+	Remove(ctx *gofr.Context, customerID int, name string) error
+}
+
+type SegmentServiceIn interface {
+	List(ctx *gofr.Context) ([]models.Segment, error)
+	Get(ctx *gofr.Context, id int) (models.Segment, error)
+	Create(ctx *gofr.Context, segment models.Segment) (models.Segment, error)
+	Update(ctx *gofr.Context, segment models.Segment) (models.Segment, error)
+	Delete(ctx *gofr.Context, id int) error
+}
+
+type AttributeServiceIn interface {
+	List(ctx *gofr.Context) (models.AttributeSchema, error)
+	Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error)
+	Delete(ctx *gofr.Context, name string) error
+}
+
+type RelationshipServiceIn interface {
+	Create(ctx *gofr.Context, r models.Relationship) (models.Relationship, error)
+	List(ctx *gofr.Context, customerID int) ([]models.Relationship, error)
+	Graph(ctx *gofr.Context, customerID, depth int) ([]models.Relationship, error)
+}
+
+type GDPRServiceIn interface {
+	Merged(ctx *gofr.Context, customerID int) ([]models.Customer, error)
+	Audit(ctx *gofr.Context, customerID int) ([]models.AuditEntry, error)
+	Erase(ctx *gofr.Context, customerID int) (models.Erasure, error)
+	Erasures(ctx *gofr.Context) ([]models.Erasure, error)
+}
+
+type KeyServiceIn interface {
+	Rotate(ctx *gofr.Context) (models.DataKey, error)
+	Reencrypt(ctx *gofr.Context, batch int) (int, error)
 }
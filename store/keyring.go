@@ -0,0 +1,682 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/encryption"
+	"customer/middleware"
+	"customer/models"
+	"customer/tracing"
+)
+
+// The purposes of the keys in data_key.
+const (
+	purposeData  = "data"
+	purposeIndex = "index"
+)
+
+// errEncryption is returned when a field cannot be encrypted or decrypted. The cause is only logged.
+var errEncryption = errors.Error("encryption error")
+
+// Keyring holds the keys that encrypt the names and salaries of the customers, which data_key holds wrapped by
+// the master key. Each tenant has a current data key that values are encrypted with, older data keys that still
+// open the values encrypted before a rotation, and an index key for the blind indexes of names, which is never
+// rotated so that lookups keep matching. Unwrapped keys are cached; the current data key is read again after
+// ttl, so that every instance picks up a rotation.
+type Keyring struct {
+	master encryption.MasterKey
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	keys    map[keyRef]encryption.DataKey
+	current map[string]currentKey
+	index   map[string]encryption.IndexKey
+}
+
+// keyRef is a data key of a tenant.
+type keyRef struct {
+	tenant string
+	id     int
+}
+
+// currentKey is the current data key of a tenant until expires.
+type currentKey struct {
+	id      int
+	expires time.Time
+}
+
+// NewKeyring returns a keyring whose keys are wrapped by master. A store given no keyring keeps names and
+// salaries in plaintext.
+func NewKeyring(master encryption.MasterKey, ttl time.Duration) *Keyring {
+	return &Keyring{master: master, ttl: ttl, now: time.Now, keys: map[keyRef]encryption.DataKey{},
+		current: map[string]currentKey{}, index: map[string]encryption.IndexKey{}}
+}
+
+// of returns the keys of the customers of tenant, which are nil without a keyring.
+func (r *Keyring) of(tenant string) *fieldKeys {
+	if r == nil {
+		return nil
+	}
+
+	return &fieldKeys{ring: r, tenant: tenant}
+}
+
+// Check is the readiness check of the keyring. It unwraps the newest data key with the master key, so that an
+// instance given another master key than the one the stored keys are wrapped by is never routed traffic it
+// could not decrypt.
+func (r *Keyring) Check(ctx *gofr.Context) (string, error) {
+	query := "SELECT tenant_id, id, wrapped FROM data_key WHERE purpose='data' ORDER BY id DESC LIMIT 1"
+
+	var (
+		tenant  string
+		id      int
+		wrapped []byte
+	)
+
+	err := ctx.DB().QueryRowContext(ctx, query).Scan(&tenant, &id, &wrapped)
+	if err == sql.ErrNoRows {
+		return "master key " + r.master.ID() + ", no data key yet", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	detail := fmt.Sprintf("master key %v, data key %v", r.master.ID(), id)
+	if _, err := r.unwrap(ctx, tenant, id, wrapped); err != nil {
+		return detail, err
+	}
+
+	return detail, nil
+}
+
+// currentKey returns the data key that values of the tenant are encrypted with, as cached.
+func (r *Keyring) currentKey(ctx *gofr.Context, tenant string) (encryption.DataKey, error) {
+	r.mu.Lock()
+	c, ok := r.current[tenant]
+	key := r.keys[keyRef{tenant, c.id}]
+	r.mu.Unlock()
+
+	if ok && r.now().Before(c.expires) {
+		return key, nil
+	}
+
+	return r.latest(ctx, tenant)
+}
+
+// latest reads the data key that values of the tenant are encrypted with, creating the first one.
+func (r *Keyring) latest(ctx *gofr.Context, tenant string) (encryption.DataKey, error) {
+	query := "SELECT id, wrapped FROM data_key WHERE tenant_id=? AND purpose='data' ORDER BY id DESC LIMIT 1"
+
+	ctx, span := tracing.Start(ctx, "store.Keyring.latest", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	var (
+		id      int
+		wrapped []byte
+	)
+
+	err := ctx.DB().QueryRowContext(ctx, query, tenant).Scan(&id, &wrapped)
+	if err == sql.ErrNoRows {
+		key, _, err := r.rotate(ctx, tenant)
+		return key, err
+	}
+	if err != nil {
+		return encryption.DataKey{}, dbError(ctx, "Keyring", err)
+	}
+
+	key, err := r.unwrap(ctx, tenant, id, wrapped)
+	if err != nil {
+		return encryption.DataKey{}, err
+	}
+
+	r.remember(tenant, key, true)
+
+	return key, nil
+}
+
+// key returns the data key of the tenant with the given id.
+func (r *Keyring) key(ctx *gofr.Context, tenant string, id int) (encryption.DataKey, error) {
+	r.mu.Lock()
+	key, ok := r.keys[keyRef{tenant, id}]
+	r.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	query := "SELECT wrapped FROM data_key WHERE id=? AND tenant_id=? AND purpose='data'"
+
+	ctx, span := tracing.Start(ctx, "store.Keyring.key", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	var wrapped []byte
+
+	err := ctx.DB().QueryRowContext(ctx, query, id, tenant).Scan(&wrapped)
+	if err == sql.ErrNoRows {
+		return encryption.DataKey{}, keyError(ctx, fmt.Errorf("data key %v of tenant %v not found", id, tenant))
+	}
+	if err != nil {
+		return encryption.DataKey{}, dbError(ctx, "Keyring", err)
+	}
+
+	if key, err = r.unwrap(ctx, tenant, id, wrapped); err != nil {
+		return encryption.DataKey{}, err
+	}
+
+	r.remember(tenant, key, false)
+
+	return key, nil
+}
+
+// rotate creates a new data key for the tenant, which becomes its current one.
+func (r *Keyring) rotate(ctx *gofr.Context, tenant string) (encryption.DataKey, time.Time, error) {
+	plain, wrapped, err := r.generate(ctx, tenant, purposeData)
+	if err != nil {
+		return encryption.DataKey{}, time.Time{}, err
+	}
+
+	query := "INSERT INTO data_key (tenant_id,purpose,master_key_id,wrapped) VALUES(?,'data',?,?) RETURNING id, created_at"
+
+	ctx, span := tracing.Start(ctx, "store.Keyring.rotate", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	var (
+		id      int
+		created time.Time
+	)
+
+	if err := ctx.DB().QueryRowContext(ctx, query, tenant, r.master.ID(), wrapped).Scan(&id, &created); err != nil {
+		return encryption.DataKey{}, time.Time{}, dbError(ctx, "Keyring", err)
+	}
+
+	key, err := encryption.NewDataKey(id, plain)
+	if err != nil {
+		return encryption.DataKey{}, time.Time{}, keyError(ctx, err)
+	}
+
+	r.remember(tenant, key, true)
+
+	return key, created, nil
+}
+
+// indexKey returns the index key of the tenant, creating it the first time.
+func (r *Keyring) indexKey(ctx *gofr.Context, tenant string) (encryption.IndexKey, error) {
+	r.mu.Lock()
+	key, ok := r.index[tenant]
+	r.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	_, wrapped, err := r.generate(ctx, tenant, purposeIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// The index key of the tenant is only inserted when it has none, and the stored one is returned either way.
+	query := "INSERT INTO data_key (tenant_id,purpose,master_key_id,wrapped) VALUES(?,'index',?,?) " +
+		"ON CONFLICT (tenant_id) WHERE purpose='index' DO UPDATE SET tenant_id=EXCLUDED.tenant_id RETURNING wrapped"
+
+	ctx, span := tracing.Start(ctx, "store.Keyring.indexKey", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	if err := ctx.DB().QueryRowContext(ctx, query, tenant, r.master.ID(), wrapped).Scan(&wrapped); err != nil {
+		return nil, dbError(ctx, "Keyring", err)
+	}
+
+	plain, err := r.master.Unwrap(ctx, wrapped, wrapAAD(tenant, purposeIndex))
+	if err != nil {
+		return nil, keyError(ctx, fmt.Errorf("unwrapping index key of tenant %v: %w", tenant, err))
+	}
+
+	r.mu.Lock()
+	r.index[tenant] = plain
+	r.mu.Unlock()
+
+	return plain, nil
+}
+
+// generate returns a new key for the tenant, and the key wrapped by the master key.
+func (r *Keyring) generate(ctx *gofr.Context, tenant, purpose string) (plain, wrapped []byte, err error) {
+	if plain, err = encryption.GenerateKey(); err != nil {
+		return nil, nil, keyError(ctx, err)
+	}
+
+	if wrapped, err = r.master.Wrap(ctx, plain, wrapAAD(tenant, purpose)); err != nil {
+		return nil, nil, keyError(ctx, fmt.Errorf("wrapping %v key of tenant %v: %w", purpose, tenant, err))
+	}
+
+	return plain, wrapped, nil
+}
+
+func (r *Keyring) unwrap(ctx *gofr.Context, tenant string, id int, wrapped []byte) (encryption.DataKey, error) {
+	plain, err := r.master.Unwrap(ctx, wrapped, wrapAAD(tenant, purposeData))
+	if err != nil {
+		return encryption.DataKey{}, keyError(ctx, fmt.Errorf("unwrapping data key %v of tenant %v: %w", id, tenant, err))
+	}
+
+	key, err := encryption.NewDataKey(id, plain)
+	if err != nil {
+		return encryption.DataKey{}, keyError(ctx, err)
+	}
+
+	return key, nil
+}
+
+// remember caches a data key of the tenant, as its current one when current is set.
+func (r *Keyring) remember(tenant string, key encryption.DataKey, current bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[keyRef{tenant, key.ID}] = key
+
+	if current {
+		r.current[tenant] = currentKey{id: key.ID, expires: r.now().Add(r.ttl)}
+	}
+}
+
+// wrapAAD binds a wrapped key to its tenant and purpose.
+func wrapAAD(tenant, purpose string) []byte {
+	return []byte(tenant + "|" + purpose)
+}
+
+// keyError logs a failure to encrypt or decrypt together with the request id. The caller only sees
+// errEncryption.
+func keyError(ctx *gofr.Context, err error) error {
+	ctx.Logger.Errorf("request_id=%v store.Keyring: %v", middleware.RequestID(ctx), err)
+	tracing.Fail(ctx, err)
+
+	return errEncryption
+}
+
+// fieldKeys encrypts the names and salaries of the customers of a tenant. A nil *fieldKeys keeps them in
+// plaintext, so every query reads and writes the plaintext columns only.
+type fieldKeys struct {
+	ring   *Keyring
+	tenant string
+	// pinned, when set, encrypts instead of the current data key.
+	pinned *encryption.DataKey
+}
+
+// sealedColumns are read after the customer columns when fields are encrypted. A customer written before
+// encryption was enabled is read from its plaintext columns until it is re-encrypted.
+var sealedColumns = []column{
+	{"name_enc", []string{"name"}, func(r *customerRow) interface{} { return &r.nameEnc }},
+	{"salary_enc", []string{"salary"}, func(r *customerRow) interface{} { return &r.salaryEnc }},
+}
+
+// columns adds to cols the encrypted columns of their fields, and the id the encrypted fields are bound to.
+func (k *fieldKeys) columns(cols []column) []column {
+	if k == nil {
+		return cols
+	}
+
+	res := append([]column{}, cols...)
+
+	for _, sealed := range sealedColumns {
+		for _, col := range cols {
+			if contains(col.fields, sealed.fields[0]) {
+				res = append(res, sealed)
+				break
+			}
+		}
+	}
+
+	if len(res) > len(cols) && !contains(cols[0].fields, "id") {
+		res = append([]column{columns[0]}, res...)
+	}
+
+	return res
+}
+
+// decrypt returns the customer read into r, with its encrypted fields decrypted.
+func (k *fieldKeys) decrypt(ctx *gofr.Context, r customerRow) (models.Customer, error) {
+	if r.nameEnc != nil {
+		name, err := k.open(ctx, r.ID, "name", r.nameEnc)
+		if err != nil {
+			return models.Customer{}, err
+		}
+
+		r.Name = string(name)
+	}
+
+	if r.salaryEnc != nil {
+		b, err := k.open(ctx, r.ID, "salary", r.salaryEnc)
+		if err != nil {
+			return models.Customer{}, err
+		}
+
+		salary, err := parseSalary(string(b))
+		if err != nil {
+			return models.Customer{}, keyError(ctx, err)
+		}
+
+		r.Salary = &salary
+	}
+
+	return r.Customer, nil
+}
+
+// sealedField is how a field of a customer is written: the values of its plaintext columns and, when fields
+// are encrypted, the values of the columns that hold it encrypted. The plaintext columns are then NULL.
+type sealedField struct {
+	plainColumns []string
+	plain        []interface{}
+	columns      []string
+	values       []interface{}
+}
+
+// name returns how the name of the customer with the given id is written.
+func (k *fieldKeys) name(ctx *gofr.Context, id int, name string) (sealedField, error) {
+	f := sealedField{plainColumns: []string{"name"}, plain: []interface{}{name}}
+	if k == nil {
+		return f, nil
+	}
+
+	sealed, err := k.seal(ctx, id, "name", []byte(name))
+	if err != nil {
+		return sealedField{}, err
+	}
+
+	index, err := k.index(ctx, name)
+	if err != nil {
+		return sealedField{}, err
+	}
+
+	f.plain = []interface{}{nil}
+	f.columns, f.values = []string{"name_enc", "name_index"}, []interface{}{sealed, index}
+
+	return f, nil
+}
+
+// salary returns how the salary of a customer is written. An unknown salary is NULL in every column.
+func (k *fieldKeys) salary(ctx *gofr.Context, c models.Customer) (sealedField, error) {
+	minor, currency := salary(c)
+
+	f := sealedField{plainColumns: []string{"salary_minor", "salary_currency"}, plain: []interface{}{minor, currency}}
+	if k == nil {
+		return f, nil
+	}
+
+	f.plain = []interface{}{nil, nil}
+	f.columns, f.values = []string{"salary_enc"}, []interface{}{nil}
+
+	if c.Salary == nil {
+		return f, nil
+	}
+
+	sealed, err := k.seal(ctx, c.ID, "salary", []byte(formatSalary(*c.Salary)))
+	if err != nil {
+		return sealedField{}, err
+	}
+
+	f.values = []interface{}{sealed}
+
+	return f, nil
+}
+
+// assignments returns the assignments that write the field, and their values.
+func (f sealedField) assignments() (set []string, values []interface{}) {
+	for i, col := range f.plainColumns {
+		if f.columns == nil {
+			set = append(set, col+" = ?")
+			values = append(values, f.plain[i])
+		} else {
+			set = append(set, col+" = NULL")
+		}
+	}
+
+	for i, col := range f.columns {
+		set = append(set, col+" = ?")
+		values = append(values, f.values[i])
+	}
+
+	return set, values
+}
+
+// sealedInsert returns the encrypted columns of fields, their placeholders and their values. Both lists start
+// with a comma unless they are empty. They start with the id the fields are sealed for, which the insert takes
+// instead of its default.
+func sealedInsert(id int, fields ...sealedField) (columns, marks string, values []interface{}) {
+	for _, f := range fields {
+		if f.columns != nil && values == nil {
+			columns, marks, values = ",id", ",?", []interface{}{id}
+		}
+
+		for i, col := range f.columns {
+			columns += "," + col
+			marks += ",?"
+			values = append(values, f.values[i])
+		}
+	}
+
+	return columns, marks, values
+}
+
+// sealedSet returns the assignments of the encrypted columns of fields, starting with a comma unless there are
+// none, and their values.
+func sealedSet(fields ...sealedField) (set string, values []interface{}) {
+	for _, f := range fields {
+		for i, col := range f.columns {
+			set += "," + col + "=?"
+			values = append(values, f.values[i])
+		}
+	}
+
+	return set, values
+}
+
+// sealedExcluded returns the assignments of the encrypted columns of fields to the values of the row that an
+// upsert could not insert.
+func sealedExcluded(fields ...sealedField) string {
+	var set string
+
+	for _, f := range fields {
+		for _, col := range f.columns {
+			set += "," + col + "=EXCLUDED." + col
+		}
+	}
+
+	return set
+}
+
+// nameMatch returns the condition finding the customers with the given name: encrypted names by their blind
+// index, and the names not encrypted yet by themselves.
+func (k *fieldKeys) nameMatch(ctx *gofr.Context, name string) (string, []interface{}, error) {
+	if k == nil {
+		return "name = ?", []interface{}{name}, nil
+	}
+
+	index, err := k.index(ctx, name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "(name_index = ? OR name = ?)", []interface{}{index, name}, nil
+}
+
+// sealName encrypts the customer of the tenant with the given name if it was written before names were
+// encrypted, so that the unique index on blind indexes finds it.
+func (k *fieldKeys) sealName(ctx *gofr.Context, name string) error {
+	if k == nil || name == "" {
+		return nil
+	}
+
+	key, err := k.ring.currentKey(ctx, k.tenant)
+	if err != nil {
+		return err
+	}
+
+	_, err = k.reseal(ctx, key, " AND name = ?", []interface{}{name}, 1)
+
+	return err
+}
+
+// reseal encrypts with key at most limit customers of the tenant matching cond whose fields are in plaintext or
+// encrypted with another data key, and returns how many it encrypted. Customers locked by another transaction
+// are skipped, and left for the next call.
+func (k *fieldKeys) reseal(ctx *gofr.Context, key encryption.DataKey, cond string, args []interface{}, limit int) (int, error) {
+	pinned := &fieldKeys{ring: k.ring, tenant: k.tenant, pinned: &key}
+	cols := k.columns(selectColumns([]string{"id", "name", "salary"}))
+
+	query := "SELECT " + selectList(cols) + " FROM customer WHERE tenant_id=?" + cond +
+		" AND (name IS NOT NULL OR salary_minor IS NOT NULL OR substring(name_enc FROM 1 FOR 4) <> ? OR " +
+		"substring(salary_enc FROM 1 FOR 4) <> ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+
+	ctx, span := tracing.Start(ctx, "store.Keyring.reseal", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, dbError(ctx, "Reencrypt", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	qp := append(append([]interface{}{k.tenant}, args...), key.Prefix(), key.Prefix(), limit)
+
+	found, err := scanRows(ctx, tx, query, qp, cols)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, r := range found {
+		customer, err := k.decrypt(ctx, r)
+		if err != nil {
+			return 0, err
+		}
+
+		name, err := pinned.name(ctx, customer.ID, customer.Name)
+		if err != nil {
+			return 0, err
+		}
+
+		pay, err := pinned.salary(ctx, customer)
+		if err != nil {
+			return 0, err
+		}
+
+		set, values := sealedSet(name, pay)
+		query := "UPDATE customer SET name=NULL,salary_minor=NULL,salary_currency=NULL" + set + " WHERE id=?"
+
+		if _, err := tx.ExecContext(ctx, query, append(values, customer.ID)...); err != nil {
+			return 0, dbError(ctx, "Reencrypt", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError(ctx, "Reencrypt", err)
+	}
+
+	return len(found), nil
+}
+
+// scanRows reads every row of a query holding the given columns, so that tx can run other queries afterwards.
+func scanRows(ctx *gofr.Context, tx *sql.Tx, query string, qp []interface{}, cols []column) ([]customerRow, error) {
+	rows, err := tx.QueryContext(ctx, query, qp...)
+	if err != nil {
+		return nil, dbError(ctx, "Reencrypt", err)
+	}
+	defer rows.Close()
+
+	var res []customerRow
+
+	for rows.Next() {
+		r, err := scanRow(rows, cols)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "Reencrypt", err)
+	}
+
+	return res, nil
+}
+
+// aad binds an encrypted value to its tenant, customer and field, so that it cannot be copied to another one,
+// e.g. the salary of one customer to another customer of the tenant.
+func (k *fieldKeys) aad(id int, field string) []byte {
+	return []byte(k.tenant + "|customer." + strconv.Itoa(id) + "." + field)
+}
+
+func (k *fieldKeys) seal(ctx *gofr.Context, id int, field string, plaintext []byte) ([]byte, error) {
+	var key encryption.DataKey
+
+	if k.pinned != nil {
+		key = *k.pinned
+	} else {
+		var err error
+		if key, err = k.ring.currentKey(ctx, k.tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	sealed, err := key.Seal(plaintext, k.aad(id, field))
+	if err != nil {
+		return nil, keyError(ctx, err)
+	}
+
+	return sealed, nil
+}
+
+func (k *fieldKeys) open(ctx *gofr.Context, id int, field string, ciphertext []byte) ([]byte, error) {
+	keyID, err := encryption.KeyID(ciphertext)
+	if err != nil {
+		return nil, keyError(ctx, err)
+	}
+
+	key, err := k.ring.key(ctx, k.tenant, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := key.Open(ciphertext, k.aad(id, field))
+	if err != nil {
+		return nil, keyError(ctx, fmt.Errorf("opening %v of customer %v of tenant %v: %w", field, id, k.tenant, err))
+	}
+
+	return plaintext, nil
+}
+
+// index returns the blind index of a name.
+func (k *fieldKeys) index(ctx *gofr.Context, name string) ([]byte, error) {
+	key, err := k.ring.indexKey(ctx, k.tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Index("name", name), nil
+}
+
+// formatSalary encodes a salary as its minor units and currency, e.g. "3000000 INR".
+func formatSalary(m models.Money) string {
+	return strconv.FormatInt(m.Minor, 10) + " " + m.Currency
+}
+
+func parseSalary(s string) (models.Money, error) {
+	i := strings.IndexByte(s, ' ')
+	if i < 0 {
+		return models.Money{}, errors.Error("malformed salary")
+	}
+
+	minor, err := strconv.ParseInt(s[:i], 10, 64)
+	if err != nil {
+		return models.Money{}, errors.Error("malformed salary")
+	}
+
+	return models.Money{Minor: minor, Currency: s[i+1:]}, nil
+}
@@ -0,0 +1,364 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"customer/encryption"
+	"customer/models"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	master, _   = encryption.NewLocalKey(bytes.Repeat([]byte{1}, encryption.KeySize))
+	dataKey, _  = encryption.NewDataKey(1, bytes.Repeat([]byte{2}, encryption.KeySize))
+	newerKey, _ = encryption.NewDataKey(2, bytes.Repeat([]byte{3}, encryption.KeySize))
+	indexKey    = encryption.IndexKey(bytes.Repeat([]byte{4}, encryption.KeySize))
+)
+
+// nextID is the query reserving the id of an encrypted customer before it is inserted.
+const nextID = "SELECT nextval(pg_get_serial_sequence('customer', 'id'))"
+
+// keyring returns a keyring holding dataKey as the current data key of the tenant and indexKey as its index
+// key, so that no key is read from the database.
+func keyring() *Keyring {
+	ring := NewKeyring(master, time.Hour)
+	ring.remember(tenant, dataKey, true)
+	ring.index[tenant] = indexKey
+
+	return ring
+}
+
+// sealed returns the value of field encrypted by key for the customer of the tenant with the given id.
+func sealed(key encryption.DataKey, id int, field, value string) []byte {
+	b, _ := key.Seal([]byte(value), []byte(tenant+"|customer."+strconv.Itoa(id)+"."+field))
+	return b
+}
+
+// sealedArg matches a query argument that key opens to value as field of the customer of the tenant with the
+// given id.
+type sealedArg struct {
+	key          encryption.DataKey
+	id           int
+	field, value string
+}
+
+func (a sealedArg) Match(v driver.Value) bool {
+	b, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+
+	plaintext, err := a.key.Open(b, []byte(tenant+"|customer."+strconv.Itoa(a.id)+"."+a.field))
+
+	return err == nil && string(plaintext) == a.value
+}
+
+func TestStore_EncryptedGetByID(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := New(keyring())
+	names := append(append([]string{}, columnNames...), "name_enc", "salary_enc")
+	query := "SELECT " + customerColumns + ", name_enc, salary_enc FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL"
+
+	encrypted := func(name, salary []byte) *sqlmock.Rows {
+		return sqlmock.NewRows(names).AddRow(1, nil, "divya@example.com", "+919876543210", dob.Time, nil, nil, created,
+			created, "active", []byte("{}"), name, salary)
+	}
+
+	plaintext := sqlmock.NewRows(names).AddRow(1, "Divya", "divya@example.com", "+919876543210", dob.Time, 3000000, "INR",
+		created, created, "active", []byte("{}"), nil, nil)
+
+	tests := []struct {
+		desc     string
+		expected models.Customer
+		err      error
+		mock     interface{}
+	}{
+		{"encrypted", divya(), nil, mock.ExpectQuery(query).WithArgs(1, tenant).
+			WillReturnRows(encrypted(sealed(dataKey, 1, "name", "Divya"), sealed(dataKey, 1, "salary", "3000000 INR")))},
+		{"written before encryption", divya(), nil, mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(plaintext)},
+		{"copied from another field", models.Customer{}, errEncryption, mock.ExpectQuery(query).WithArgs(1, tenant).
+			WillReturnRows(encrypted(sealed(dataKey, 1, "salary", "Divya"), nil))},
+		{"copied from another customer", models.Customer{}, errEncryption, mock.ExpectQuery(query).WithArgs(1, tenant).
+			WillReturnRows(encrypted(sealed(dataKey, 1, "name", "Divya"), sealed(dataKey, 2, "salary", "3000000 INR")))},
+	}
+
+	for i, tc := range tests {
+		res, err := store.GetByID(ctx, 1)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+		}
+	}
+}
+
+func TestStore_EncryptedGetByName(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := New(keyring())
+	query := "SELECT id FROM customer WHERE tenant_id = ? AND deleted_at IS NULL AND (name_index = ? OR name = ?)"
+
+	mock.ExpectQuery(query).WithArgs(tenant, indexKey.Index("name", "Divya"), "Divya").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	res, err := store.Get(ctx, models.Filter{Name: "Divya", Fields: []string{"id"}})
+	if err != nil || !reflect.DeepEqual(res, []models.Customer{{ID: 1}}) {
+		t.Errorf("Expected %v\nGot %v, %v", []models.Customer{{ID: 1}}, res, err)
+	}
+}
+
+func TestStore_EncryptedCreate(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := New(keyring())
+	input := divya()
+	input.ID, input.CreatedAt, input.UpdatedAt = 0, nil, nil
+
+	legacy := "SELECT id, name, salary_minor, salary_currency, name_enc, salary_enc FROM customer WHERE tenant_id=? AND name = ? " +
+		"AND (name IS NOT NULL OR salary_minor IS NOT NULL OR substring(name_enc FROM 1 FOR 4) <> ? OR " +
+		"substring(salary_enc FROM 1 FOR 4) <> ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes," +
+		"id,name_enc,name_index,salary_enc) VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,?,?,?,?,?) " +
+		"RETURNING id, created_at, updated_at, status"
+
+	mock.ExpectQuery(nextID).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(legacy).WithArgs(tenant, "Divya", dataKey.Prefix(), dataKey.Prefix(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary_minor", "salary_currency", "name_enc", "salary_enc"}))
+	mock.ExpectCommit()
+	mock.ExpectQuery(query).WithArgs(tenant, nil, "divya@example.com", "+919876543210", "2000-03-14", nil, nil, "{}", 1,
+		sealedArg{dataKey, 1, "name", "Divya"}, indexKey.Index("name", "Divya"), sealedArg{dataKey, 1, "salary", "3000000 INR"}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "status"}).AddRow(1, created, created, "active"))
+
+	res, err := store.Create(ctx, input)
+	if err != nil || !reflect.DeepEqual(res, divya()) {
+		t.Errorf("Expected %v\nGot %v, %v", divya(), res, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// A customer replaced by an upsert keeps its id, so its fields are sealed again for it rather than for the id
+// reserved for a new customer.
+func TestStore_EncryptedUpsert(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := New(keyring())
+	input := divya()
+	input.ID, input.CreatedAt, input.UpdatedAt = 0, nil, nil
+
+	legacy := "SELECT id, name, salary_minor, salary_currency, name_enc, salary_enc FROM customer WHERE tenant_id=? AND name = ? " +
+		"AND (name IS NOT NULL OR salary_minor IS NOT NULL OR substring(name_enc FROM 1 FOR 4) <> ? OR " +
+		"substring(salary_enc FROM 1 FOR 4) <> ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes," +
+		"id,name_enc,name_index,salary_enc) VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,COALESCE(?::jsonb,'{}'),?,?,?,?) " +
+		"ON CONFLICT (tenant_id, name_index) WHERE deleted_at IS NULL DO UPDATE SET email=EXCLUDED.email,phone=EXCLUDED.phone," +
+		"date_of_birth=EXCLUDED.date_of_birth,salary_minor=EXCLUDED.salary_minor,salary_currency=EXCLUDED.salary_currency," +
+		"name_enc=EXCLUDED.name_enc,name_index=EXCLUDED.name_index,salary_enc=EXCLUDED.salary_enc," +
+		"attributes=COALESCE(?::jsonb,customer.attributes),updated_at=now() " +
+		"RETURNING id, created_at, updated_at, status, attributes, (xmax = 0)"
+	reseal := "UPDATE customer SET name_enc=?,name_index=?,salary_enc=? WHERE id=?"
+	noLegacy := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(legacy).WithArgs(tenant, "Divya", dataKey.Prefix(), dataKey.Prefix(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary_minor", "salary_currency", "name_enc", "salary_enc"}))
+		mock.ExpectCommit()
+	}
+
+	mock.ExpectQuery(nextID).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(5))
+	noLegacy()
+	mock.ExpectBegin()
+	mock.ExpectQuery(query).WithArgs(tenant, nil, "divya@example.com", "+919876543210", "2000-03-14", nil, nil, nil, 5,
+		sealedArg{dataKey, 5, "name", "Divya"}, indexKey.Index("name", "Divya"), sealedArg{dataKey, 5, "salary", "3000000 INR"}, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "status", "attributes", "created"}).
+			AddRow(1, created, created, "active", []byte("{}"), false))
+	noLegacy()
+	mock.ExpectExec(reseal).WithArgs(sealedArg{dataKey, 1, "name", "Divya"}, indexKey.Index("name", "Divya"),
+		sealedArg{dataKey, 1, "salary", "3000000 INR"}, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, created, err := store.Upsert(ctx, input)
+	if err != nil || created || !reflect.DeepEqual(res, divya()) {
+		t.Errorf("Expected %v, false\nGot %v, %v, %v", divya(), res, created, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStore_EncryptedStats(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := New(keyring())
+	where := " WHERE tenant_id = ? AND deleted_at IS NULL"
+	ages := "SELECT COUNT(*), COUNT(age), COALESCE(MIN(age), 0), COALESCE(MAX(age), 0), COALESCE(AVG(age), 0)::float8, " +
+		percentiles("age", "0") + " FROM (SELECT salary_minor, salary_currency, " + ageColumn + " AS age FROM customer" + where + ") c"
+	salaries := "SELECT id, salary_minor, salary_currency, salary_enc FROM customer" + where +
+		" AND (salary_minor IS NOT NULL OR salary_enc IS NOT NULL)"
+
+	mock.ExpectQuery(ages).WithArgs(tenant).WillReturnRows(
+		sqlmock.NewRows([]string{"count", "count", "min", "max", "avg", "p50", "p90", "p99"}).AddRow(2, 0, 0, 0, 0.0, 0, 0, 0))
+	mock.ExpectQuery(salaries).WithArgs(tenant).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "salary_minor", "salary_currency", "salary_enc"}).
+			AddRow(1, nil, nil, sealed(dataKey, 1, "salary", "4000000 INR")).AddRow(2, 2000000, "INR", nil))
+
+	inr := func(minor int64) models.Money { return models.Money{Minor: minor, Currency: "INR"} }
+	expected := []models.SalaryStats{{Currency: "INR", Count: 2, Min: inr(2000000), Max: inr(4000000), Avg: inr(3000000),
+		P50: inr(2000000), P90: inr(4000000), P99: inr(4000000)}}
+
+	res, err := store.Stats(ctx, models.Filter{}, nil)
+	if err != nil || res.Count != 2 || !reflect.DeepEqual(res.Salary, expected) {
+		t.Errorf("Expected %v\nGot %v, %v", expected, res.Salary, err)
+	}
+}
+
+func TestKeys_Reencrypt(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	ring := keyring()
+	keys := NewKeys(ring)
+
+	wrapped, _ := master.Wrap(context.Background(), bytes.Repeat([]byte{3}, encryption.KeySize), wrapAAD(tenant, purposeData))
+	latest := "SELECT id, wrapped FROM data_key WHERE tenant_id=? AND purpose='data' ORDER BY id DESC LIMIT 1"
+	stale := "SELECT id, name, salary_minor, salary_currency, name_enc, salary_enc FROM customer WHERE tenant_id=? " +
+		"AND (name IS NOT NULL OR salary_minor IS NOT NULL OR substring(name_enc FROM 1 FOR 4) <> ? OR " +
+		"substring(salary_enc FROM 1 FOR 4) <> ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+	update := "UPDATE customer SET name=NULL,salary_minor=NULL,salary_currency=NULL,name_enc=?,name_index=?,salary_enc=? WHERE id=?"
+
+	mock.ExpectQuery(latest).WithArgs(tenant).WillReturnRows(sqlmock.NewRows([]string{"id", "wrapped"}).AddRow(2, wrapped))
+	mock.ExpectBegin()
+	mock.ExpectQuery(stale).WithArgs(tenant, newerKey.Prefix(), newerKey.Prefix(), 10).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name", "salary_minor", "salary_currency", "name_enc", "salary_enc"}).
+			AddRow(1, "Divya", 3000000, "INR", nil, nil).
+			AddRow(2, nil, nil, nil, sealed(dataKey, 2, "name", "Asha"), nil))
+	mock.ExpectExec(update).WithArgs(sealedArg{newerKey, 1, "name", "Divya"}, indexKey.Index("name", "Divya"),
+		sealedArg{newerKey, 1, "salary", "3000000 INR"}, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(sealedArg{newerKey, 2, "name", "Asha"}, indexKey.Index("name", "Asha"), nil, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := keys.Reencrypt(ctx, 10)
+	if n != 2 || err != nil {
+		t.Errorf("Expected 2 customers re-encrypted\nGot %v, %v", n, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	// The latest data key becomes the current one.
+	if key, err := ring.currentKey(ctx, tenant); err != nil || key.ID != 2 {
+		t.Errorf("Expected current data key 2\nGot %v, %v", key.ID, err)
+	}
+}
+
+func TestKeys_Rotate(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	query := "INSERT INTO data_key (tenant_id,purpose,master_key_id,wrapped) VALUES(?,'data',?,?) RETURNING id, created_at"
+
+	tests := []struct {
+		desc     string
+		ring     *Keyring
+		expected models.DataKey
+		err      error
+		mock     interface{}
+	}{
+		{"rotated", keyring(), models.DataKey{ID: 2, CreatedAt: created}, nil,
+			mock.ExpectQuery(query).WithArgs(tenant, master.ID(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, created))},
+		{"no master key", nil, models.DataKey{}, ErrNotEncrypted, nil},
+	}
+
+	for i, tc := range tests {
+		res, err := NewKeys(tc.ring).Rotate(ctx)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+		}
+
+		if tc.ring != nil {
+			if key, _ := tc.ring.currentKey(ctx, tenant); key.ID != tc.expected.ID {
+				t.Errorf("TEST[%d] Expected current data key %v\nGot %v", i+1, tc.expected.ID, key.ID)
+			}
+		}
+	}
+}
+
+func TestKeyring_IndexKey(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	ring := NewKeyring(master, time.Hour)
+	wrapped, _ := master.Wrap(context.Background(), indexKey, wrapAAD(tenant, purposeIndex))
+	query := "INSERT INTO data_key (tenant_id,purpose,master_key_id,wrapped) VALUES(?,'index',?,?) " +
+		"ON CONFLICT (tenant_id) WHERE purpose='index' DO UPDATE SET tenant_id=EXCLUDED.tenant_id RETURNING wrapped"
+
+	// The stored index key wins over the one generated for the insert, and is only read once.
+	mock.ExpectQuery(query).WithArgs(tenant, master.ID(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"wrapped"}).AddRow(wrapped))
+
+	for i := 0; i < 2; i++ {
+		key, err := ring.indexKey(ctx, tenant)
+		if err != nil || !bytes.Equal(key, indexKey) {
+			t.Errorf("TEST[%d] Expected %x\nGot %x, %v", i+1, []byte(indexKey), []byte(key), err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKeyring_Check(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	query := "SELECT tenant_id, id, wrapped FROM data_key WHERE purpose='data' ORDER BY id DESC LIMIT 1"
+	columns := []string{"tenant_id", "id", "wrapped"}
+	wrapped, _ := master.Wrap(context.Background(), bytes.Repeat([]byte{2}, encryption.KeySize), wrapAAD(tenant, purposeData))
+	other, _ := encryption.NewLocalKey(bytes.Repeat([]byte{9}, encryption.KeySize))
+	otherWrapped, _ := other.Wrap(context.Background(), bytes.Repeat([]byte{2}, encryption.KeySize), wrapAAD(tenant, purposeData))
+
+	tests := []struct {
+		desc   string
+		detail string
+		err    error
+		mock   interface{}
+	}{
+		{"data key unwrapped", "master key " + master.ID() + ", data key 1", nil,
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns).AddRow(tenant, 1, wrapped))},
+		{"no data key yet", "master key " + master.ID() + ", no data key yet", nil,
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns))},
+		{"wrapped by another master key", "master key " + master.ID() + ", data key 1", errEncryption,
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns).AddRow(tenant, 1, otherWrapped))},
+	}
+
+	for i, tc := range tests {
+		detail, err := NewKeyring(master, time.Hour).Check(ctx)
+		if !reflect.DeepEqual(err, tc.err) || detail != tc.detail {
+			t.Errorf("TEST[%d] %v: Expected %q, %v\nGot %q, %v", i+1, tc.desc, tc.detail, tc.err, detail, err)
+		}
+	}
+}
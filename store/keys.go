@@ -0,0 +1,69 @@
+package store
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+	"customer/tracing"
+)
+
+// ErrNotEncrypted is returned when a data key is rotated while no master key is configured.
+var ErrNotEncrypted = errors.Error("no master key is configured")
+
+// keys rotates the data keys of the tenants and re-encrypts their customers.
+type keys struct {
+	ring *Keyring
+}
+
+func NewKeys(ring *Keyring) keys {
+	return keys{ring: ring}
+}
+
+// Rotate creates a new data key for the tenant, which its names and salaries are encrypted with from then on.
+// Other instances pick it up once their cached current key expires.
+func (s keys) Rotate(ctx *gofr.Context) (models.DataKey, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.DataKey{}, err
+	}
+
+	if s.ring == nil {
+		return models.DataKey{}, ErrNotEncrypted
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Keys.Rotate")
+	defer span.End()
+
+	key, created, err := s.ring.rotate(ctx, tenant)
+	if err != nil {
+		return models.DataKey{}, err
+	}
+	return models.DataKey{ID: key.ID, CreatedAt: created}, nil
+}
+
+// Reencrypt encrypts with the latest data key at most batch customers of the tenant that are in plaintext or
+// encrypted with an older data key, and returns how many it encrypted. Without a master key there is nothing
+// to encrypt.
+func (s keys) Reencrypt(ctx *gofr.Context, batch int) (int, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.ring == nil {
+		return 0, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Keys.Reencrypt")
+	defer span.End()
+
+	// The latest data key is read rather than the cached one, so that customers are never encrypted back with
+	// the key that a rotation on another instance replaced.
+	key, err := s.ring.latest(ctx, tenant)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.ring.of(tenant).reseal(ctx, key, "", nil, batch)
+}
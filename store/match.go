@@ -0,0 +1,229 @@
+package store
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+)
+
+// This file holds what the stores that do not speak SQL share: they select, read and aggregate customers in
+// Go the way the SQL queries do.
+
+// ErrTagFilter is returned by the stores other than the SQL one for a filter on tags, since tags are kept in
+// the database next to the customers and cannot be joined to customers stored elsewhere.
+var ErrTagFilter = errors.Error("filtering customers by tag needs the postgres store")
+
+// checkFilter refuses a filter that cannot be matched outside the database.
+func checkFilter(f models.Filter) error {
+	if len(f.Tags) > 0 || len(f.ExcludedTags) > 0 {
+		return ErrTagFilter
+	}
+
+	return nil
+}
+
+// ErrMergeHeld is returned by the stores other than the SQL one for a merge whose losers have addresses, tags or
+// relationships, since those are kept in the database and cannot be moved in the transaction of the merge.
+var ErrMergeHeld = errors.Error("merging customers with addresses, tags or relationships needs the postgres store")
+
+// checkMerge refuses a merge whose losers have addresses, tags or relationships in the database. Without a
+// database they have none.
+func checkMerge(ctx *gofr.Context, loserIDs []int) error {
+	db := ctx.DB()
+	if db == nil || db.DB == nil {
+		return nil
+	}
+
+	in, ids := idList(loserIDs)
+	query := "SELECT EXISTS (SELECT 1 FROM address WHERE customer_id IN (" + in + ")) OR " +
+		"EXISTS (SELECT 1 FROM customer_tag WHERE customer_id IN (" + in + ")) OR " +
+		"EXISTS (SELECT 1 FROM customer_relationship WHERE from_id IN (" + in + ") OR to_id IN (" + in + "))"
+
+	var held bool
+
+	qp := append(append(append(append([]interface{}{}, ids...), ids...), ids...), ids...)
+	if err := db.QueryRowContext(ctx, query, qp...).Scan(&held); err != nil {
+		return dbError(ctx, "Merge", err)
+	}
+
+	if held {
+		return ErrMergeHeld
+	}
+
+	return nil
+}
+
+// match reports whether the customer passes the filter the way whereClause selects it. Customers without a
+// date of birth never pass an age filter.
+func match(c models.Customer, f models.Filter, now time.Time) bool {
+	if f.Name != "" && c.Name != f.Name {
+		return false
+	}
+
+	if f.Status != "" && c.Status != f.Status {
+		return false
+	}
+
+	for name, want := range f.Attributes {
+		if v, ok := c.Attributes[name]; !ok || !reflect.DeepEqual(v, want) {
+			return false
+		}
+	}
+
+	if f.MinAge != 0 || f.MaxAge != 0 {
+		if c.DateOfBirth == nil {
+			return false
+		}
+
+		age := c.DateOfBirth.AgeAt(now)
+		if f.MinAge != 0 && age < f.MinAge || f.MaxAge != 0 && age > f.MaxAge {
+			return false
+		}
+	}
+
+	return true
+}
+
+// page returns the page of customers the filter asks for, like pageClause.
+func page(customers []models.Customer, f models.Filter) []models.Customer {
+	if f.Offset > 0 {
+		if f.Offset >= len(customers) {
+			return nil
+		}
+
+		customers = customers[f.Offset:]
+	}
+
+	if f.Limit > 0 && f.Limit < len(customers) {
+		customers = customers[:f.Limit]
+	}
+
+	return customers
+}
+
+// project returns the given fields of the customer, or all of them when none is given, like selectColumns.
+// Ages are computed from the date of birth, so reading them keeps it.
+func project(c models.Customer, fields []string) models.Customer {
+	if len(fields) == 0 {
+		return c
+	}
+
+	var res models.Customer
+
+	for _, col := range selectColumns(fields) {
+		switch col.fields[0] {
+		case "id":
+			res.ID = c.ID
+		case "name":
+			res.Name = c.Name
+		case "email":
+			res.Email = c.Email
+		case "phone":
+			res.Phone = c.Phone
+		case "dateOfBirth":
+			res.DateOfBirth = c.DateOfBirth
+		case "salary":
+			res.Salary = c.Salary
+		case "createdAt":
+			res.CreatedAt = c.CreatedAt
+		case "updatedAt":
+			res.UpdatedAt = c.UpdatedAt
+		case "status":
+			res.Status = c.Status
+		case "attributes":
+			res.Attributes = c.Attributes
+		}
+	}
+
+	return res
+}
+
+// summarise aggregates the customers like Stats does in the database.
+func summarise(customers []models.Customer, buckets []int, now time.Time) models.Stats {
+	stats := models.Stats{Count: len(customers), AgeHistogram: histogram(buckets)}
+
+	var (
+		ages     []int
+		salaries []models.Money
+	)
+
+	for _, c := range customers {
+		if c.DateOfBirth != nil {
+			age := c.DateOfBirth.AgeAt(now)
+			ages = append(ages, age)
+
+			// Like ageHistogram, no customer is counted without buckets.
+			if len(buckets) > 0 {
+				stats.AgeHistogram[bucketOf(age, buckets)].Count++
+			}
+		}
+
+		if c.Salary != nil {
+			salaries = append(salaries, *c.Salary)
+		}
+	}
+
+	stats.Age = models.SummariseAges(ages)
+	stats.Salary = models.SummariseSalaries(salaries)
+
+	return stats
+}
+
+// bucketOf returns the histogram bucket of age, numbered like width_bucket: the number of bounds it reached.
+func bucketOf(age int, buckets []int) int {
+	n := 0
+
+	for _, b := range buckets {
+		if age >= b {
+			n++
+		}
+	}
+
+	return n
+}
+
+// copyAttributes returns a copy of the attributes as the database stores them: decoded from JSON, so that
+// numbers are float64, and nil when there are none.
+func copyAttributes(a models.Attributes) (models.Attributes, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var res models.Attributes
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// patchAttributes merges the attributes of a patch into the stored ones, removing those that are nil in it, as
+// setClause does.
+func patchAttributes(stored, patch models.Attributes) (models.Attributes, error) {
+	res := models.Attributes{}
+
+	for name, v := range stored {
+		res[name] = v
+	}
+
+	for name, v := range patch {
+		if v == nil {
+			delete(res, name)
+			continue
+		}
+
+		res[name] = v
+	}
+
+	return copyAttributes(res)
+}
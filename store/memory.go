@@ -0,0 +1,631 @@
+package store
+
+import (
+	"database/sql"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"customer/models"
+)
+
+// memory keeps the customers in memory, for tests and demos that run without a database. It behaves like the
+// SQL store: ids are assigned in order, names and emails are unique among the live customers of a tenant, a
+// missing customer is sql.ErrNoRows and a patch only writes the fields it sets. Everything else about the
+// customers, such as their addresses and tags, stays in the database, so a merge of customers that have any is
+// refused with ErrMergeHeld and a filter on tags with ErrTagFilter. Only their attribute schema is kept too, see Attributes. It is
+// safe for concurrent use.
+type memory struct {
+	mu     sync.RWMutex
+	lastID int
+	// records holds the customers in id order, byID the same records by id.
+	records []*record
+	byID    map[int]*record
+	now     func() time.Time
+	// definitions holds the attribute schema of every tenant, see Attributes.
+	definitions     map[string][]models.AttributeDefinition
+	lastAttributeID int
+}
+
+// record is a stored customer, together with what models.Customer has no field for.
+type record struct {
+	tenant   string
+	customer models.Customer
+	deleted  bool
+}
+
+func NewMemory() *memory {
+	return &memory{byID: map[int]*record{}, now: time.Now, definitions: map[string][]models.AttributeDefinition{}}
+}
+
+func (m *memory) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	var res []models.Customer
+
+	err := m.Stream(ctx, filter, func(customer models.Customer) error {
+		res = append(res, customer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Stream calls fn with the matching customers as they were when it was called, so that fn may use the store.
+func (m *memory) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	customers, err := m.selected(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range page(customers, filter) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(project(c, filter.Fields)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByID reads the given fields of a customer, or all of them when none is given.
+func (m *memory) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r := m.live(tenant, id)
+	if r == nil {
+		return models.Customer{}, sql.ErrNoRows
+	}
+	return project(clone(r.customer), fields), nil
+}
+
+// GetByIDs reads all the given customers in id order. Unknown ids are skipped.
+func (m *memory) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var res []models.Customer
+
+	for _, r := range m.records {
+		if r.tenant == tenant && !r.deleted && containsID(ids, r.customer.ID) {
+			res = append(res, clone(r.customer))
+		}
+	}
+	return res, nil
+}
+
+// Count returns the number of customers matching the filter. Pagination is ignored.
+func (m *memory) Count(ctx *gofr.Context, filter models.Filter) (int, error) {
+	customers, err := m.selected(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(customers), nil
+}
+
+func (m *memory) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.taken(tenant, customer) {
+		return models.Customer{}, errors.EntityAlreadyExists{}
+	}
+
+	now := m.now()
+	m.lastID++
+	customer.ID, customer.CreatedAt, customer.UpdatedAt, customer.Status = m.lastID, &now, &now, models.StatusProspect
+
+	stored := customer
+	stored.Age, stored.Attributes = 0, attrs
+	m.insert(tenant, stored)
+
+	return customer, nil
+}
+
+// Upsert creates the customer, or replaces the customer of the tenant with the same name. created reports
+// whether the customer was created. A replaced customer keeps its attributes when none are given.
+func (m *memory) Upsert(ctx *gofr.Context, customer models.Customer) (res models.Customer, created bool, err error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	var r *record
+
+	for _, candidate := range m.records {
+		if candidate.tenant == tenant && !candidate.deleted && candidate.customer.Name == customer.Name {
+			r = candidate
+			break
+		}
+	}
+
+	if r == nil {
+		if m.taken(tenant, customer) {
+			return models.Customer{}, false, errors.EntityAlreadyExists{}
+		}
+
+		m.lastID++
+		stored := customer
+		stored.ID, stored.CreatedAt, stored.UpdatedAt, stored.Status = m.lastID, &now, &now, models.StatusProspect
+		stored.Age, stored.Attributes = 0, attrs
+		r, created = m.insert(tenant, stored), true
+	} else {
+		stored := replaced(r.customer, customer, attrs)
+		stored.UpdatedAt = &now
+
+		if m.taken(tenant, stored) {
+			return models.Customer{}, false, errors.EntityAlreadyExists{}
+		}
+
+		r.customer = clone(stored)
+	}
+
+	stored := clone(r.customer)
+	customer.ID, customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.ID, stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, created, nil
+}
+
+// Update replaces a customer. Its attributes are kept when none are given.
+func (m *memory) Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.live(tenant, id)
+	if r == nil {
+		return models.Customer{}, sql.ErrNoRows
+	}
+
+	now := m.now()
+	stored := replaced(r.customer, customer, attrs)
+	stored.Name, stored.UpdatedAt = customer.Name, &now
+
+	if m.taken(tenant, stored) {
+		return models.Customer{}, errors.EntityAlreadyExists{}
+	}
+
+	r.customer = clone(stored)
+
+	stored = clone(stored)
+	customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, nil
+}
+
+func (m *memory) Delete(ctx *gofr.Context, id int) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.live(tenant, id)
+	if r == nil {
+		return sql.ErrNoRows
+	}
+
+	delete(m.byID, id)
+
+	for i := range m.records {
+		if m.records[i] == r {
+			m.records = append(m.records[:i], m.records[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Patch writes the fields that are set in customer. Attributes are merged into the stored ones, and removed when
+// they are nil. Nothing is written, and an empty customer is returned, when no field is set.
+func (m *memory) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if customer.Name == "" && customer.Email == "" && customer.Phone == "" && customer.DateOfBirth == nil &&
+		customer.Salary == nil && customer.Attributes == nil {
+		return models.Customer{}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.live(tenant, id)
+	if r == nil {
+		return models.Customer{}, sql.ErrNoRows
+	}
+
+	stored := r.customer
+
+	if customer.Name != "" {
+		stored.Name = customer.Name
+	}
+
+	if customer.Email != "" {
+		stored.Email = customer.Email
+	}
+
+	if customer.Phone != "" {
+		stored.Phone = customer.Phone
+	}
+
+	if customer.DateOfBirth != nil {
+		stored.DateOfBirth = customer.DateOfBirth
+	}
+
+	if customer.Salary != nil {
+		stored.Salary = customer.Salary
+	}
+
+	if customer.Attributes != nil {
+		if stored.Attributes, err = patchAttributes(stored.Attributes, customer.Attributes); err != nil {
+			return models.Customer{}, err
+		}
+	}
+
+	now := m.now()
+	stored.UpdatedAt = &now
+
+	if m.taken(tenant, stored) {
+		return models.Customer{}, errors.EntityAlreadyExists{}
+	}
+
+	r.customer = clone(stored)
+	customer.ID = id
+
+	return customer, nil
+}
+
+// Merge merges the losers into the survivor at once: merge computes the survivor from them, and the losers are
+// soft-deleted. Addresses, tags and relationships are kept in the database, so losers that have any are refused
+// with ErrMergeHeld.
+func (m *memory) Merge(ctx *gofr.Context, survivorID int, loserIDs []int,
+	merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := checkMerge(ctx, loserIDs); err != nil {
+		return models.Customer{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := append([]int{survivorID}, loserIDs...)
+	found := make([]*record, len(ids))
+
+	for i, id := range ids {
+		if found[i] = m.live(tenant, id); found[i] == nil {
+			return models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(id)}
+		}
+	}
+
+	losers := make([]models.Customer, len(loserIDs))
+	for i := range loserIDs {
+		losers[i] = clone(found[i+1].customer)
+	}
+
+	res := merge(clone(found[0].customer), losers)
+
+	now := m.now()
+	survivor := found[0].customer
+	survivor.Email, survivor.Phone, survivor.DateOfBirth, survivor.Salary, survivor.UpdatedAt =
+		res.Email, res.Phone, res.DateOfBirth, res.Salary, &now
+
+	// The losers are deleted first, so that the survivor can take their email.
+	if m.taken(tenant, survivor, loserIDs...) {
+		return models.Customer{}, errors.EntityAlreadyExists{}
+	}
+
+	for _, r := range found[1:] {
+		r.deleted = true
+		r.customer.UpdatedAt = &now
+	}
+
+	found[0].customer = clone(survivor)
+	res.UpdatedAt = &now
+
+	return res, nil
+}
+
+// Stats aggregates the customers matching the filter. Pagination is ignored.
+func (m *memory) Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	customers, err := m.selected(ctx, filter)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	return summarise(customers, buckets, m.now()), nil
+}
+
+// memoryAttributes keeps the attribute schema of the customers of a memory store, so that customers are
+// validated without a database.
+type memoryAttributes struct {
+	m *memory
+}
+
+// Attributes returns the store of the attribute schema of the customers in m.
+func (m *memory) Attributes() memoryAttributes {
+	return memoryAttributes{m: m}
+}
+
+// List returns the attribute schema of the tenant by name.
+func (a memoryAttributes) List(ctx *gofr.Context) (models.AttributeSchema, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.m.mu.RLock()
+	defer a.m.mu.RUnlock()
+
+	var res models.AttributeSchema
+
+	for _, d := range a.m.definitions[tenant] {
+		res = append(res, cloneDefinition(d))
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+// Create defines an attribute for the customers of the tenant. Attribute names are unique within a tenant.
+func (a memoryAttributes) Create(ctx *gofr.Context, d models.AttributeDefinition) (models.AttributeDefinition, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.AttributeDefinition{}, err
+	}
+
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+
+	for _, defined := range a.m.definitions[tenant] {
+		if defined.Name == d.Name {
+			return models.AttributeDefinition{}, errors.EntityAlreadyExists{}
+		}
+	}
+
+	now := a.m.now()
+	a.m.lastAttributeID++
+	d.ID, d.CreatedAt = a.m.lastAttributeID, &now
+
+	a.m.definitions[tenant] = append(a.m.definitions[tenant], cloneDefinition(d))
+
+	return d, nil
+}
+
+// Delete removes an attribute from the schema of the tenant and from its customers.
+func (a memoryAttributes) Delete(ctx *gofr.Context, name string) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+
+	definitions := a.m.definitions[tenant]
+
+	i := 0
+	for i < len(definitions) && definitions[i].Name != name {
+		i++
+	}
+
+	if i == len(definitions) {
+		return errors.EntityNotFound{Entity: "attribute", ID: name}
+	}
+
+	a.m.definitions[tenant] = append(definitions[:i:i], definitions[i+1:]...)
+
+	now := a.m.now()
+
+	for _, r := range a.m.records {
+		if _, ok := r.customer.Attributes[name]; r.tenant != tenant || !ok {
+			continue
+		}
+
+		attrs, err := patchAttributes(r.customer.Attributes, models.Attributes{name: nil})
+		if err != nil {
+			return err
+		}
+
+		r.customer.Attributes, r.customer.UpdatedAt = attrs, &now
+	}
+
+	return nil
+}
+
+// selected returns copies of the live customers of the tenant of ctx that match the filter, in id order.
+func (m *memory) selected(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFilter(filter); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.now()
+
+	var res []models.Customer
+
+	for _, r := range m.records {
+		if r.tenant == tenant && !r.deleted && match(r.customer, filter, now) {
+			res = append(res, clone(r.customer))
+		}
+	}
+
+	return res, nil
+}
+
+// live returns the customer of the tenant with the given id, or nil when there is none or it is deleted.
+func (m *memory) live(tenant string, id int) *record {
+	r, ok := m.byID[id]
+	if !ok || r.tenant != tenant || r.deleted {
+		return nil
+	}
+
+	return r
+}
+
+// taken reports whether another live customer of the tenant has the name or the email of c, as the unique
+// indexes would. The customers with the ids in except are left out, as well as c itself.
+func (m *memory) taken(tenant string, c models.Customer, except ...int) bool {
+	for _, r := range m.records {
+		if r.tenant != tenant || r.deleted || r.customer.ID == c.ID || containsID(except, r.customer.ID) {
+			continue
+		}
+
+		if r.customer.Name == c.Name || c.Email != "" && r.customer.Email == c.Email {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insert stores a copy of a new customer.
+func (m *memory) insert(tenant string, c models.Customer) *record {
+	r := &record{tenant: tenant, customer: clone(c)}
+
+	m.records = append(m.records, r)
+	m.byID[c.ID] = r
+
+	return r
+}
+
+// replaced returns the stored customer with the fields a replacing write sets: everything but its name, which
+// identifies it, and its attributes when none are given.
+func replaced(stored, c models.Customer, attrs models.Attributes) models.Customer {
+	stored.Email, stored.Phone, stored.DateOfBirth, stored.Salary = c.Email, c.Phone, c.DateOfBirth, c.Salary
+
+	if c.Attributes != nil {
+		stored.Attributes = attrs
+	}
+
+	return stored
+}
+
+// clone returns a copy of c that shares no memory with it, so that callers and the store never see each other's
+// changes.
+func clone(c models.Customer) models.Customer {
+	if c.DateOfBirth != nil {
+		d := *c.DateOfBirth
+		c.DateOfBirth = &d
+	}
+
+	if c.Salary != nil {
+		s := *c.Salary
+		c.Salary = &s
+	}
+
+	if c.CreatedAt != nil {
+		t := *c.CreatedAt
+		c.CreatedAt = &t
+	}
+
+	if c.UpdatedAt != nil {
+		t := *c.UpdatedAt
+		c.UpdatedAt = &t
+	}
+
+	if c.Attributes != nil {
+		attrs := make(models.Attributes, len(c.Attributes))
+		for name, v := range c.Attributes {
+			attrs[name] = v
+		}
+
+		c.Attributes = attrs
+	}
+
+	return c
+}
+
+func cloneDefinition(d models.AttributeDefinition) models.AttributeDefinition {
+	if d.Enum != nil {
+		d.Enum = append([]string{}, d.Enum...)
+	}
+
+	if d.CreatedAt != nil {
+		t := *d.CreatedAt
+		d.CreatedAt = &t
+	}
+
+	return d
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
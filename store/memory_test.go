@@ -0,0 +1,173 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"customer/models"
+)
+
+func TestMemory_ConcurrentWrites(t *testing.T) {
+	s := NewMemory()
+	ctx := newTenant(gofr.New())
+
+	const writers = 50
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		ids     = map[int]bool{}
+		created int
+	)
+
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			res, err := s.Create(ctx, models.Customer{Name: fmt.Sprintf("Customer %d", i)})
+			if err != nil {
+				t.Errorf("Expected customer %d to be created\nGot %v", i, err)
+				return
+			}
+
+			mu.Lock()
+			ids[res.ID] = true
+			mu.Unlock()
+		}(i)
+
+		// Every writer races for the same name, so only one of them may create it.
+		go func() {
+			defer wg.Done()
+
+			_, err := s.Create(ctx, models.Customer{Name: "Divya"})
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			} else if err != (errors.EntityAlreadyExists{}) {
+				t.Errorf("Expected %v\nGot %v", errors.EntityAlreadyExists{}, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(ids) != writers || created != 1 {
+		t.Errorf("Expected %v distinct ids and Divya created once\nGot %v ids, %v times", writers, len(ids), created)
+	}
+
+	if count, err := s.Count(ctx, models.Filter{}); err != nil || count != writers+1 {
+		t.Errorf("Expected %v customers\nGot %v, %v", writers+1, count, err)
+	}
+}
+
+func TestMemory_ReadsAreCopies(t *testing.T) {
+	s := NewMemory()
+	ctx := newTenant(gofr.New())
+
+	created := mustCreate(t, ctx, s, models.Customer{Name: "Divya", Salary: inr(100),
+		Attributes: models.Attributes{"tier": "gold"}})
+
+	got, _ := s.GetByID(ctx, created.ID)
+	got.Salary.Minor = 1
+	got.Attributes["tier"] = "silver"
+
+	again, _ := s.GetByID(ctx, created.ID)
+	if !reflect.DeepEqual(again.Salary, inr(100)) || again.Attributes["tier"] != "gold" {
+		t.Errorf("Expected the stored customer to be unchanged\nGot %+v", again)
+	}
+}
+
+func TestMemory_Attributes(t *testing.T) {
+	s := NewMemory()
+	now := time.Date(2021, time.December, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	attrs := s.Attributes()
+	ctx, other := newTenant(gofr.New()), newTenant(gofr.New())
+
+	seats := models.AttributeDefinition{Name: "seats", Type: models.AttributeNumber}
+	plan := models.AttributeDefinition{Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"}}
+
+	for _, d := range []models.AttributeDefinition{seats, plan} {
+		if _, err := attrs.Create(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := attrs.Create(ctx, plan); err != (errors.EntityAlreadyExists{}) {
+		t.Errorf("Expected %v\nGot %v", errors.EntityAlreadyExists{}, err)
+	}
+
+	expected := models.AttributeSchema{
+		{ID: 2, Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"}, CreatedAt: &now},
+		{ID: 1, Name: "seats", Type: models.AttributeNumber, CreatedAt: &now},
+	}
+
+	if res, err := attrs.List(ctx); err != nil || !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %v\nGot %v, %v", expected, res, err)
+	}
+
+	if res, err := attrs.List(other); err != nil || res != nil {
+		t.Errorf("Expected no attributes for another tenant\nGot %v, %v", res, err)
+	}
+
+	divya := mustCreate(t, ctx, s, models.Customer{Name: "Divya",
+		Attributes: models.Attributes{"plan": "gold", "seats": 3}})
+
+	if err := attrs.Delete(ctx, "plan"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := attrs.Delete(ctx, "plan"); err != (errors.EntityNotFound{Entity: "attribute", ID: "plan"}) {
+		t.Errorf("Expected the attribute not to be found\nGot %v", err)
+	}
+
+	expectedAttributes := models.Attributes{"seats": float64(3)}
+	if got, err := s.GetByID(ctx, divya.ID); err != nil || !reflect.DeepEqual(got.Attributes, expectedAttributes) {
+		t.Errorf("Expected %v\nGot %v, %v", expectedAttributes, got.Attributes, err)
+	}
+}
+
+// A merge is refused when the losers have addresses, tags or relationships in the database, which the in-memory
+// store cannot move.
+func TestMemory_MergeHeld(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	s := NewMemory()
+	survivor := mustCreate(t, ctx, s, models.Customer{Name: "Divya"})
+	loser := mustCreate(t, ctx, s, models.Customer{Name: "Divya K"})
+
+	query := "SELECT EXISTS (SELECT 1 FROM address WHERE customer_id IN (?)) OR " +
+		"EXISTS (SELECT 1 FROM customer_tag WHERE customer_id IN (?)) OR " +
+		"EXISTS (SELECT 1 FROM customer_relationship WHERE from_id IN (?) OR to_id IN (?))"
+	keep := func(survivor models.Customer, _ []models.Customer) models.Customer { return survivor }
+
+	mock.ExpectQuery(query).WithArgs(loser.ID, loser.ID, loser.ID, loser.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(true))
+
+	if _, err := s.Merge(ctx, survivor.ID, []int{loser.ID}, keep); err != ErrMergeHeld {
+		t.Errorf("Expected %v\nGot %v", ErrMergeHeld, err)
+	}
+
+	if _, err := s.GetByID(ctx, loser.ID); err != nil {
+		t.Errorf("Expected the loser to be kept\nGot %v", err)
+	}
+
+	mock.ExpectQuery(query).WithArgs(loser.ID, loser.ID, loser.ID, loser.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(false))
+
+	if _, err := s.Merge(ctx, survivor.ID, []int{loser.ID}, keep); err != nil {
+		t.Errorf("Expected the merge to succeed\nGot %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/tracing"
+)
+
+// mergeAction is the audit_log action of a merge.
+const mergeAction = "merge"
+
+// ErrCycle is returned by Merge when the relationships moved to the survivor would make it its own ancestor in
+// a hierarchy.
+var ErrCycle = errors.Error("relationship cycle")
+
+// mergeDetail is the audit_log detail of a merge. It holds no customer data, only ids.
+type mergeDetail struct {
+	Losers        []int `json:"losers"`
+	Addresses     int64 `json:"addresses"`
+	Tags          int64 `json:"tags"`
+	Relationships int64 `json:"relationships"`
+}
+
+// Merge merges the losers into the survivor in one transaction. The customers are locked, merge computes the
+// survivor from them, the addresses, tags and relationships of the losers are moved to the survivor, the losers
+// are soft-deleted and the merge is recorded in audit_log. Either all of it happens or none of it. It is written
+// for Postgres only.
+func (s store) Merge(ctx *gofr.Context, survivorID int, loserIDs []int,
+	merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if s.dialect == MySQL {
+		return models.Customer{}, ErrPostgresOnly
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Merge", tracing.CustomerIDKey.Int(survivorID))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	k := s.keys.of(tenant)
+
+	survivor, losers, err := lockMerged(ctx, tx, k, tenant, survivorID, loserIDs)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	res := merge(survivor, losers)
+	in, inParams := idList(loserIDs)
+
+	// The losers are deleted first, so that the survivor can take their email.
+	query := "UPDATE customer SET deleted_at=now(),merged_into=?,updated_at=now() WHERE id IN (" + in + ")"
+	if _, err := tx.ExecContext(ctx, query, append([]interface{}{survivorID}, inParams...)...); err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+
+	pay, err := k.salary(ctx, res)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	set, values := sealedSet(pay)
+	query = "UPDATE customer SET email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?,salary_minor=?,salary_currency=?," +
+		"updated_at=now()" + set + " WHERE id=? RETURNING updated_at"
+
+	qp := append(append([]interface{}{res.Email, res.Phone, res.DateOfBirth}, pay.plain...), values...)
+
+	err = tx.QueryRowContext(ctx, query, append(qp, survivorID)...).Scan(&res.UpdatedAt)
+	if err != nil {
+		return models.Customer{}, writeError(ctx, "Merge", err)
+	}
+
+	query = "UPDATE address SET customer_id=? WHERE customer_id IN (" + in + ")"
+
+	moved, err := tx.ExecContext(ctx, query, append([]interface{}{survivorID}, inParams...)...)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+
+	// Links of the losers stay with them, so that the survivor only gains the tags it does not have yet.
+	query = "INSERT INTO customer_tag (customer_id,tag_id) SELECT DISTINCT ?,tag_id FROM customer_tag " +
+		"WHERE customer_id IN (" + in + ") ON CONFLICT DO NOTHING"
+
+	tagged, err := tx.ExecContext(ctx, query, append([]interface{}{survivorID}, inParams...)...)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+
+	related, err := moveRelationships(ctx, tx, tenant, survivorID, loserIDs)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	detail := mergeDetail{Losers: loserIDs, Relationships: related}
+	detail.Addresses, _ = moved.RowsAffected()
+	detail.Tags, _ = tagged.RowsAffected()
+
+	if err := audit(ctx, tx, tenant, mergeAction, survivorID, detail); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+	return res, nil
+}
+
+// lockMerged reads the survivor and the losers of a merge, locking them until the end of tx. A customer that
+// does not exist, is deleted or belongs to another tenant is reported as not found.
+func lockMerged(ctx *gofr.Context, tx *sql.Tx, k *fieldKeys, tenant string, survivorID int, loserIDs []int) (
+	survivor models.Customer, losers []models.Customer, err error) {
+	ids := append([]int{survivorID}, loserIDs...)
+	in, qp := idList(ids)
+	cols := k.columns(columns)
+
+	query := fmt.Sprintf("SELECT %v FROM customer WHERE tenant_id=? AND deleted_at IS NULL AND id IN (%v) FOR UPDATE",
+		selectList(cols), in)
+
+	rows, err := tx.QueryContext(ctx, query, append([]interface{}{tenant}, qp...)...)
+	if err != nil {
+		return models.Customer{}, nil, dbError(ctx, "Merge", err)
+	}
+	defer rows.Close()
+
+	found := map[int]models.Customer{}
+
+	for rows.Next() {
+		r, err := scanRow(rows, cols)
+		if err != nil {
+			return models.Customer{}, nil, errors.Error("scan error")
+		}
+
+		customer, err := k.decrypt(ctx, r)
+		if err != nil {
+			return models.Customer{}, nil, err
+		}
+		found[customer.ID] = customer
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.Customer{}, nil, dbError(ctx, "Merge", err)
+	}
+
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return models.Customer{}, nil, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(id)}
+		}
+	}
+
+	for _, id := range loserIDs {
+		losers = append(losers, found[id])
+	}
+	return found[survivorID], losers, nil
+}
+
+// moveRelationships moves the relationships of the losers of a merge to the survivor in tx, and returns how many
+// the survivor gained. Relationships between the merged customers would relate the survivor to itself and are
+// dropped, and those the survivor already has are not repeated. Relationships are locked as relationship.Create
+// locks them, and ErrCycle is returned when the moved ones close a hierarchy through the survivor.
+func moveRelationships(ctx *gofr.Context, tx *sql.Tx, tenant string, survivorID int, loserIDs []int) (int64, error) {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "customer_relationship:"+tenant); err != nil {
+		return 0, dbError(ctx, "Merge", err)
+	}
+
+	in, inParams := idList(loserIDs)
+	losers := "from_id IN (" + in + ") OR to_id IN (" + in + ")"
+
+	// Each end that is a loser becomes the survivor. The oldest of the relationships that become the same keeps
+	// its creation time.
+	query := "INSERT INTO customer_relationship (type,from_id,to_id,created_at) " +
+		"SELECT type, from_id, to_id, MIN(created_at) FROM (SELECT type, " +
+		"CASE WHEN from_id IN (" + in + ") THEN ? ELSE from_id END AS from_id, " +
+		"CASE WHEN to_id IN (" + in + ") THEN ? ELSE to_id END AS to_id, created_at " +
+		"FROM customer_relationship WHERE " + losers + ") moved " +
+		"WHERE from_id <> to_id GROUP BY type, from_id, to_id ON CONFLICT DO NOTHING"
+
+	qp := append(append([]interface{}{}, inParams...), survivorID)
+	qp = append(append(qp, inParams...), survivorID)
+	qp = append(append(qp, inParams...), inParams...)
+
+	inserted, err := tx.ExecContext(ctx, query, qp...)
+	if err != nil {
+		return 0, dbError(ctx, "Merge", err)
+	}
+
+	query = "DELETE FROM customer_relationship WHERE " + losers
+	if _, err := tx.ExecContext(ctx, query, append(append([]interface{}{}, inParams...), inParams...)...); err != nil {
+		return 0, dbError(ctx, "Merge", err)
+	}
+
+	// Only the relationships of the survivor changed, so a new cycle goes through it.
+	var hierarchical []interface{}
+
+	for _, t := range models.RelationshipTypes {
+		if t.Hierarchical() {
+			hierarchical = append(hierarchical, t)
+		}
+	}
+
+	types := strings.TrimSuffix(strings.Repeat("?,", len(hierarchical)), ",")
+	query = "WITH RECURSIVE reach(id, type) AS (" +
+		"SELECT to_id, type FROM customer_relationship WHERE from_id=? AND type IN (" + types + ") UNION " +
+		"SELECT r.to_id, r.type FROM customer_relationship r JOIN reach ON r.from_id = reach.id AND r.type = reach.type) " +
+		"SELECT 1 FROM reach WHERE id=? LIMIT 1"
+
+	var cycle int
+
+	err = tx.QueryRowContext(ctx, query, append(append([]interface{}{survivorID}, hierarchical...), survivorID)...).Scan(&cycle)
+	if err == nil {
+		return 0, ErrCycle
+	}
+	if err != sql.ErrNoRows {
+		return 0, dbError(ctx, "Merge", err)
+	}
+
+	n, _ := inserted.RowsAffected()
+
+	return n, nil
+}
+
+// audit records an action on a customer in audit_log, together with the caller that made it.
+func audit(ctx *gofr.Context, tx *sql.Tx, tenant, action string, customerID int, detail interface{}) error {
+	b, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO audit_log (tenant_id,action,customer_id,detail,caller) VALUES(?,?,?,?,?)"
+
+	ctx, span := tracing.Start(ctx, "store.audit", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	if _, err := tx.ExecContext(ctx, query, tenant, action, customerID, string(b), middleware.Caller(ctx)); err != nil {
+		return dbError(ctx, "audit", err)
+	}
+	return nil
+}
+
+// idList returns the placeholders of an IN list of ids, and the ids as query parameters.
+func idList(ids []int) (in string, qp []interface{}) {
+	for _, id := range ids {
+		qp = append(qp, id)
+	}
+
+	return strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), qp
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func TestStore_Merge(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	lock := "SELECT " + customerColumns + " FROM customer WHERE tenant_id=? AND deleted_at IS NULL AND id IN (?,?) FOR UPDATE"
+	deleteLosers := "UPDATE customer SET deleted_at=now(),merged_into=?,updated_at=now() WHERE id IN (?)"
+	updateSurvivor := "UPDATE customer SET email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?,salary_minor=?," +
+		"salary_currency=?,updated_at=now() WHERE id=? RETURNING updated_at"
+	moveAddresses := "UPDATE address SET customer_id=? WHERE customer_id IN (?)"
+	copyTags := "INSERT INTO customer_tag (customer_id,tag_id) SELECT DISTINCT ?,tag_id FROM customer_tag " +
+		"WHERE customer_id IN (?) ON CONFLICT DO NOTHING"
+	lockRelationships := "SELECT pg_advisory_xact_lock(hashtext(?))"
+	moveRelationships := "INSERT INTO customer_relationship (type,from_id,to_id,created_at) " +
+		"SELECT type, from_id, to_id, MIN(created_at) FROM (SELECT type, " +
+		"CASE WHEN from_id IN (?) THEN ? ELSE from_id END AS from_id, CASE WHEN to_id IN (?) THEN ? ELSE to_id END AS to_id, " +
+		"created_at FROM customer_relationship WHERE from_id IN (?) OR to_id IN (?)) moved " +
+		"WHERE from_id <> to_id GROUP BY type, from_id, to_id ON CONFLICT DO NOTHING"
+	deleteRelationships := "DELETE FROM customer_relationship WHERE from_id IN (?) OR to_id IN (?)"
+	cycle := "WITH RECURSIVE reach(id, type) AS (" +
+		"SELECT to_id, type FROM customer_relationship WHERE from_id=? AND type IN (?,?) UNION " +
+		"SELECT r.to_id, r.type FROM customer_relationship r JOIN reach ON r.from_id = reach.id AND r.type = reach.type) " +
+		"SELECT 1 FROM reach WHERE id=? LIMIT 1"
+	audit := "INSERT INTO audit_log (tenant_id,action,customer_id,detail,caller) VALUES(?,?,?,?,?)"
+
+	locked := func() *sqlmock.Rows {
+		return divyaRow(sqlmock.NewRows(columnNames)).
+			AddRow(2, "divya ", "", "+919876543211", nil, nil, nil, created, created, "prospect", []byte("{}"))
+	}
+
+	// merge takes the phone of the loser, so that the update shows the merged customer is written.
+	merge := func(survivor models.Customer, losers []models.Customer) models.Customer {
+		survivor.Phone = losers[0].Phone
+		return survivor
+	}
+
+	merged := divya()
+	merged.Phone = "+919876543211"
+
+	tests := []struct {
+		desc     string
+		expected models.Customer
+		err      error
+		mock     func()
+	}{
+		{"success", merged, nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lock).WithArgs(tenant, 1, 2).WillReturnRows(locked())
+			mock.ExpectExec(deleteLosers).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(updateSurvivor).
+				WithArgs("divya@example.com", "+919876543211", "2000-03-14", 3000000, "INR", 1).
+				WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(created))
+			mock.ExpectExec(moveAddresses).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(copyTags).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(lockRelationships).WithArgs("customer_relationship:" + tenant).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(moveRelationships).WithArgs(2, 1, 2, 1, 2, 2).WillReturnResult(sqlmock.NewResult(0, 3))
+			mock.ExpectExec(deleteRelationships).WithArgs(2, 2).WillReturnResult(sqlmock.NewResult(0, 4))
+			mock.ExpectQuery(cycle).WithArgs(1, models.RelationshipParent, models.RelationshipReferral, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+			mock.ExpectExec(audit).WithArgs(tenant, "merge", 1, `{"losers":[2],"addresses":2,"tags":1,"relationships":3}`, "").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+		}},
+		{"relationship cycle", models.Customer{}, ErrCycle, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lock).WithArgs(tenant, 1, 2).WillReturnRows(locked())
+			mock.ExpectExec(deleteLosers).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(updateSurvivor).WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(created))
+			mock.ExpectExec(moveAddresses).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(copyTags).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(lockRelationships).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(moveRelationships).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(deleteRelationships).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(cycle).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			mock.ExpectRollback()
+		}},
+		{"loser not found", models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: "2"}, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lock).WithArgs(tenant, 1, 2).WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))
+			mock.ExpectRollback()
+		}},
+		{"audit failure", models.Customer{}, errors.DB{Err: errors.Error("db error")}, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lock).WithArgs(tenant, 1, 2).WillReturnRows(locked())
+			mock.ExpectExec(deleteLosers).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(updateSurvivor).WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(created))
+			mock.ExpectExec(moveAddresses).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(copyTags).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(lockRelationships).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(moveRelationships).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(deleteRelationships).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(cycle).WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+			mock.ExpectExec(audit).WillReturnError(errors.Error("db error"))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.mock()
+
+			res, err := store.Merge(ctx, 1, []int{2}, merge)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("TEST[%d] %v", i+1, err)
+			}
+		})
+	}
+}
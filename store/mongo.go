@@ -0,0 +1,722 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/models"
+	"customer/tracing"
+)
+
+// mongoStore keeps the customers in the customer collection of a MongoDB database, as one document each. Ids are
+// assigned in order from the counters collection, and partial unique indexes keep names and emails unique among
+// the live customers of a tenant, so that it behaves like the SQL store. Like the in-memory store it leaves
+// addresses and tags in the database, refuses merges of customers that have any with ErrMergeHeld and filters on
+// tags with ErrTagFilter. Merges are transactions,
+// which need a replica set.
+type mongoStore struct {
+	db *mongo.Database
+}
+
+func NewMongo(db *mongo.Database) mongoStore {
+	return mongoStore{db: db}
+}
+
+// mongoCustomer is the document of a customer. Empty emails and phones are left out, so that the unique email
+// index only applies to set values, and dates of birth are "2006-01-02" strings that sort like dates. live is
+// unset when the customer is merged into another one.
+type mongoCustomer struct {
+	ID             int                    `bson:"_id"`
+	Tenant         string                 `bson:"tenant"`
+	Name           string                 `bson:"name"`
+	Email          string                 `bson:"email,omitempty"`
+	Phone          string                 `bson:"phone,omitempty"`
+	DateOfBirth    string                 `bson:"dateOfBirth,omitempty"`
+	SalaryMinor    *int64                 `bson:"salaryMinor,omitempty"`
+	SalaryCurrency string                 `bson:"salaryCurrency,omitempty"`
+	CreatedAt      time.Time              `bson:"createdAt"`
+	UpdatedAt      time.Time              `bson:"updatedAt"`
+	Status         models.Status          `bson:"status"`
+	Attributes     map[string]interface{} `bson:"attributes,omitempty"`
+	Live           bool                   `bson:"live,omitempty"`
+	MergedInto     int                    `bson:"mergedInto,omitempty"`
+}
+
+// Migrate creates the indexes the store relies on. It is idempotent, so it runs on every start.
+func (s mongoStore) Migrate(ctx context.Context) error {
+	live := bson.D{{Key: "live", Value: true}}
+
+	_, err := s.customers().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(live)},
+		{Keys: bson.D{{Key: "tenant", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+				append(live, bson.E{Key: "email", Value: bson.D{{Key: "$exists", Value: true}}}))},
+		{Keys: bson.D{{Key: "tenant", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+
+	return err
+}
+
+func (s mongoStore) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	var res []models.Customer
+
+	err := s.each(ctx, "Get", filter, func(customer models.Customer) error {
+		res = append(res, customer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Stream calls fn with every customer matching the filter as soon as it is read. It stops at the first error
+// returned by fn, or when ctx is cancelled.
+func (s mongoStore) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	return s.each(ctx, "Stream", filter, fn)
+}
+
+func (s mongoStore) each(ctx *gofr.Context, method string, filter models.Filter, fn func(models.Customer) error) error {
+	query, err := mongoFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "store."+method, semconv.DBSystemMongoDB)
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	if filter.Offset > 0 {
+		opts.SetSkip(int64(filter.Offset))
+	}
+
+	cursor, err := s.customers().Find(ctx, query, opts)
+	if err != nil {
+		return queryError(ctx, method, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		customer, err := decodeCustomer(cursor)
+		if err != nil {
+			return errors.Error("scan error")
+		}
+
+		if err := fn(project(customer, filter.Fields)); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return queryError(ctx, method, err)
+	}
+
+	return nil
+}
+
+// GetByID reads the given fields of a customer, or all of them when none is given.
+func (s mongoStore) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.GetByID", semconv.DBSystemMongoDB, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	customer, err := decodeCustomer(s.customers().FindOne(ctx, liveCustomer(tenant, id)))
+	if err == mongo.ErrNoDocuments {
+		return models.Customer{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "GetByID", err)
+	}
+	return project(customer, fields), nil
+}
+
+// GetByIDs fetches all the given customers with a single query. Unknown ids are skipped.
+func (s mongoStore) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.GetByIDs", semconv.DBSystemMongoDB)
+	defer span.End()
+
+	query := bson.D{{Key: "tenant", Value: tenant}, {Key: "live", Value: true},
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+
+	cursor, err := s.customers().Find(ctx, query, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, dbError(ctx, "GetByIDs", err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []models.Customer
+
+	for cursor.Next(ctx) {
+		customer, err := decodeCustomer(cursor)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, customer)
+	}
+	return res, nil
+}
+
+// Count returns the number of customers matching the filter. Pagination is ignored.
+func (s mongoStore) Count(ctx *gofr.Context, filter models.Filter) (int, error) {
+	query, err := mongoFilter(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Count", semconv.DBSystemMongoDB)
+	defer span.End()
+
+	count, err := s.customers().CountDocuments(ctx, query)
+	if err != nil {
+		return 0, dbError(ctx, "Count", err)
+	}
+
+	return int(count), nil
+}
+
+func (s mongoStore) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Create", semconv.DBSystemMongoDB)
+	defer span.End()
+
+	id, err := s.nextID(ctx)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Create", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	doc, err := toDocument(tenant, customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	doc.ID, doc.CreatedAt, doc.UpdatedAt, doc.Status, doc.Live = id, now, now, models.StatusProspect, true
+
+	if _, err := s.customers().InsertOne(ctx, doc); err != nil {
+		return models.Customer{}, mongoWriteError(ctx, "Create", err)
+	}
+
+	customer.ID, customer.CreatedAt, customer.UpdatedAt, customer.Status = id, &now, &now, doc.Status
+
+	return customer, nil
+}
+
+// Upsert creates the customer, or replaces the customer of the tenant with the same name, in a single operation
+// so that concurrent writers cannot race. created reports whether the customer was created. A replaced customer
+// keeps its attributes when none are given.
+func (s mongoStore) Upsert(ctx *gofr.Context, customer models.Customer) (res models.Customer, created bool, err error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Upsert", semconv.DBSystemMongoDB)
+	defer span.End()
+
+	// Like a serial column in an upsert, an id is drawn even when the customer is replaced.
+	id, err := s.nextID(ctx)
+	if err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	set, unset, err := replacement(customer)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: append(set, bson.E{Key: "updatedAt", Value: now})},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "_id", Value: id}, {Key: "createdAt", Value: now},
+			{Key: "status", Value: models.StatusProspect}}},
+	}
+
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	query := bson.D{{Key: "tenant", Value: tenant}, {Key: "name", Value: customer.Name}, {Key: "live", Value: true}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	stored, err := decodeCustomer(s.customers().FindOneAndUpdate(ctx, query, update, opts))
+	if err != nil {
+		return models.Customer{}, false, mongoWriteError(ctx, "Upsert", err)
+	}
+
+	customer.ID, customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.ID, stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, stored.ID == id, nil
+}
+
+// Update replaces a customer. Its attributes are kept when none are given.
+func (s mongoStore) Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Update", semconv.DBSystemMongoDB, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	set, unset, err := replacement(customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	set = append(set, bson.E{Key: "name", Value: customer.Name},
+		bson.E{Key: "updatedAt", Value: time.Now().UTC().Truncate(time.Millisecond)})
+
+	update := bson.D{{Key: "$set", Value: set}}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	stored, err := decodeCustomer(s.customers().FindOneAndUpdate(ctx, liveCustomer(tenant, id), update, opts))
+	if err == mongo.ErrNoDocuments {
+		return models.Customer{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return models.Customer{}, mongoWriteError(ctx, "Update", err)
+	}
+
+	customer.CreatedAt, customer.UpdatedAt, customer.Status, customer.Attributes =
+		stored.CreatedAt, stored.UpdatedAt, stored.Status, stored.Attributes
+
+	return customer, nil
+}
+
+func (s mongoStore) Delete(ctx *gofr.Context, id int) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Delete", semconv.DBSystemMongoDB, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	res, err := s.customers().DeleteOne(ctx, liveCustomer(tenant, id))
+	if err != nil {
+		return dbError(ctx, "Delete", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Patch writes the fields that are set in customer. Attributes are merged into the stored ones, and removed when
+// they are nil. Nothing is written, and an empty customer is returned, when no field is set.
+func (s mongoStore) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	var set, unset bson.D
+
+	if customer.Name != "" {
+		set = append(set, bson.E{Key: "name", Value: customer.Name})
+	}
+
+	if customer.Email != "" {
+		set = append(set, bson.E{Key: "email", Value: customer.Email})
+	}
+
+	if customer.Phone != "" {
+		set = append(set, bson.E{Key: "phone", Value: customer.Phone})
+	}
+
+	if customer.DateOfBirth != nil {
+		set = append(set, bson.E{Key: "dateOfBirth", Value: customer.DateOfBirth.String()})
+	}
+
+	if customer.Salary != nil {
+		set = append(set, bson.E{Key: "salaryMinor", Value: customer.Salary.Minor},
+			bson.E{Key: "salaryCurrency", Value: customer.Salary.Currency})
+	}
+
+	// Attributes are set and removed one by one, so that the others are kept.
+	attrs, err := copyAttributes(customer.Attributes)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	for name := range customer.Attributes {
+		if v, ok := attrs[name]; ok && v != nil {
+			set = append(set, bson.E{Key: "attributes." + name, Value: v})
+		} else {
+			unset = append(unset, bson.E{Key: "attributes." + name, Value: ""})
+		}
+	}
+
+	// No value is passed for update
+	if set == nil && unset == nil && customer.Attributes == nil {
+		return models.Customer{}, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Patch", semconv.DBSystemMongoDB, tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	update := bson.D{{Key: "$set", Value: append(set, bson.E{Key: "updatedAt", Value: time.Now().UTC().Truncate(time.Millisecond)})}}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	res, err := s.customers().UpdateOne(ctx, liveCustomer(tenant, id), update)
+	if err != nil {
+		return models.Customer{}, mongoWriteError(ctx, "Patch", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return models.Customer{}, sql.ErrNoRows
+	}
+	customer.ID = id
+	return customer, nil
+}
+
+// Merge merges the losers into the survivor in one transaction: merge computes the survivor from them, and the
+// losers are marked as merged. Addresses, tags and relationships are kept in the database, so losers that have
+// any are refused with ErrMergeHeld.
+func (s mongoStore) Merge(ctx *gofr.Context, survivorID int, loserIDs []int,
+	merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := checkMerge(ctx, loserIDs); err != nil {
+		return models.Customer{}, err
+	}
+
+	ctx, span := tracing.Start(ctx, "store.Merge", semconv.DBSystemMongoDB, tracing.CustomerIDKey.Int(survivorID))
+	defer span.End()
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Merge", err)
+	}
+	defer session.EndSession(ctx)
+
+	res, err := session.WithTransaction(ctx, func(tx mongo.SessionContext) (interface{}, error) {
+		return s.merge(tx, tenant, survivorID, loserIDs, merge)
+	})
+	if err != nil {
+		if _, ok := err.(errors.EntityNotFound); ok {
+			return models.Customer{}, err
+		}
+
+		return models.Customer{}, mongoWriteError(ctx, "Merge", err)
+	}
+	return res.(models.Customer), nil
+}
+
+func (s mongoStore) merge(tx mongo.SessionContext, tenant string, survivorID int, loserIDs []int,
+	merge func(survivor models.Customer, losers []models.Customer) models.Customer) (models.Customer, error) {
+	ids := append([]int{survivorID}, loserIDs...)
+	query := bson.D{{Key: "tenant", Value: tenant}, {Key: "live", Value: true},
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+
+	cursor, err := s.customers().Find(tx, query)
+	if err != nil {
+		return models.Customer{}, err
+	}
+	defer cursor.Close(tx)
+
+	found := map[int]models.Customer{}
+
+	for cursor.Next(tx) {
+		customer, err := decodeCustomer(cursor)
+		if err != nil {
+			return models.Customer{}, err
+		}
+		found[customer.ID] = customer
+	}
+
+	if err := cursor.Err(); err != nil {
+		return models.Customer{}, err
+	}
+
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: strconv.Itoa(id)}
+		}
+	}
+
+	losers := make([]models.Customer, len(loserIDs))
+	for i, id := range loserIDs {
+		losers[i] = found[id]
+	}
+
+	res := merge(found[survivorID], losers)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	// The losers are marked first, so that the survivor can take their email.
+	_, err = s.customers().UpdateMany(tx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: loserIDs}}}}, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "mergedInto", Value: survivorID}, {Key: "updatedAt", Value: now}}},
+		{Key: "$unset", Value: bson.D{{Key: "live", Value: ""}}},
+	})
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	survivor := res
+	survivor.Attributes = nil
+
+	set, unset, err := replacement(survivor)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	update := bson.D{{Key: "$set", Value: append(set, bson.E{Key: "updatedAt", Value: now})}}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	if _, err := s.customers().UpdateOne(tx, bson.D{{Key: "_id", Value: survivorID}}, update); err != nil {
+		return models.Customer{}, err
+	}
+
+	res.UpdatedAt = &now
+
+	return res, nil
+}
+
+// Stats aggregates the customers matching the filter. Pagination is ignored.
+func (s mongoStore) Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	filter.Limit, filter.Offset, filter.Fields = 0, 0, []string{"dateOfBirth", "salary"}
+
+	var customers []models.Customer
+
+	err := s.each(ctx, "Stats", filter, func(customer models.Customer) error {
+		customers = append(customers, customer)
+		return nil
+	})
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	return summarise(customers, buckets, time.Now()), nil
+}
+
+func (s mongoStore) customers() *mongo.Collection {
+	return s.db.Collection("customer")
+}
+
+// nextID draws the next customer id from the counters collection.
+func (s mongoStore) nextID(ctx context.Context) (int, error) {
+	var counter struct {
+		Seq int `bson:"seq"`
+	}
+
+	err := s.db.Collection("counters").FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: "customer"}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: 1}}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&counter)
+
+	return counter.Seq, err
+}
+
+// mongoFilter scopes the customers to the tenant of ctx and narrows them down by the filter, like whereClause.
+func mongoFilter(ctx *gofr.Context, f models.Filter) (bson.D, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFilter(f); err != nil {
+		return nil, err
+	}
+
+	query := bson.D{{Key: "tenant", Value: tenant}, {Key: "live", Value: true}}
+
+	if f.Name != "" {
+		query = append(query, bson.E{Key: "name", Value: f.Name})
+	}
+
+	if f.Status != "" {
+		query = append(query, bson.E{Key: "status", Value: f.Status})
+	}
+
+	for name, v := range f.Attributes {
+		query = append(query, bson.E{Key: "attributes." + name, Value: v})
+	}
+
+	// Dates of birth sort like dates, so ages are compared through them.
+	var born bson.D
+
+	if f.MinAge != 0 {
+		born = append(born, bson.E{Key: "$lte", Value: yearsAgo(f.MinAge).String()})
+	}
+
+	if f.MaxAge != 0 {
+		born = append(born, bson.E{Key: "$gt", Value: yearsAgo(f.MaxAge + 1).String()})
+	}
+
+	if born != nil {
+		query = append(query, bson.E{Key: "dateOfBirth", Value: born})
+	}
+
+	return query, nil
+}
+
+// yearsAgo returns the date the given number of years before today. The 29th of February falls back to the 28th
+// in years that have none, like in Postgres.
+func yearsAgo(years int) models.Date {
+	now := time.Now()
+
+	d := models.NewDate(now.Year()-years, now.Month(), now.Day())
+	if d.Month() != now.Month() {
+		d = models.NewDate(now.Year()-years, now.Month(), now.Day()-1)
+	}
+
+	return d
+}
+
+// liveCustomer selects the customer of the tenant with the given id unless it was merged.
+func liveCustomer(tenant string, id int) bson.D {
+	return bson.D{{Key: "_id", Value: id}, {Key: "tenant", Value: tenant}, {Key: "live", Value: true}}
+}
+
+// replacement returns the fields a replacing write sets, and the empty ones it removes: everything but the name,
+// which identifies the customer, and the attributes when none are given.
+func replacement(c models.Customer) (set, unset bson.D, err error) {
+	doc, err := toDocument("", c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := []struct {
+		name  string
+		value interface{}
+		empty bool
+	}{
+		{"email", doc.Email, doc.Email == ""},
+		{"phone", doc.Phone, doc.Phone == ""},
+		{"dateOfBirth", doc.DateOfBirth, doc.DateOfBirth == ""},
+		{"salaryMinor", doc.SalaryMinor, doc.SalaryMinor == nil},
+		{"salaryCurrency", doc.SalaryCurrency, doc.SalaryMinor == nil},
+	}
+
+	for _, f := range fields {
+		if f.empty {
+			unset = append(unset, bson.E{Key: f.name, Value: ""})
+		} else {
+			set = append(set, bson.E{Key: f.name, Value: f.value})
+		}
+	}
+
+	if c.Attributes != nil {
+		attrs := doc.Attributes
+		if attrs == nil {
+			attrs = map[string]interface{}{}
+		}
+
+		set = append(set, bson.E{Key: "attributes", Value: attrs})
+	}
+
+	return set, unset, nil
+}
+
+// toDocument returns the document of a customer of the tenant. Its attributes are stored as they would be in
+// the database, see copyAttributes.
+func toDocument(tenant string, c models.Customer) (mongoCustomer, error) {
+	attrs, err := copyAttributes(c.Attributes)
+	if err != nil {
+		return mongoCustomer{}, err
+	}
+
+	doc := mongoCustomer{Tenant: tenant, Name: c.Name, Email: c.Email, Phone: c.Phone, Attributes: attrs}
+
+	if c.DateOfBirth != nil {
+		doc.DateOfBirth = c.DateOfBirth.String()
+	}
+
+	if c.Salary != nil {
+		minor := c.Salary.Minor
+		doc.SalaryMinor, doc.SalaryCurrency = &minor, c.Salary.Currency
+	}
+
+	return doc, nil
+}
+
+type decoder interface {
+	Decode(v interface{}) error
+}
+
+// decodeCustomer reads a customer document.
+func decodeCustomer(d decoder) (models.Customer, error) {
+	var doc mongoCustomer
+	if err := d.Decode(&doc); err != nil {
+		return models.Customer{}, err
+	}
+
+	c := models.Customer{ID: doc.ID, Name: doc.Name, Email: doc.Email, Phone: doc.Phone, CreatedAt: &doc.CreatedAt,
+		UpdatedAt: &doc.UpdatedAt, Status: doc.Status}
+
+	if doc.DateOfBirth != "" {
+		dob, err := models.ParseDate(doc.DateOfBirth)
+		if err != nil {
+			return models.Customer{}, err
+		}
+
+		c.DateOfBirth = &dob
+	}
+
+	if doc.SalaryMinor != nil {
+		c.Salary = &models.Money{Minor: *doc.SalaryMinor, Currency: doc.SalaryCurrency}
+	}
+
+	attrs, err := copyAttributes(doc.Attributes)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	c.Attributes = attrs
+
+	return c, nil
+}
+
+// mongoWriteError reports a duplicate name or email as an existing entity and any other failure as dbError does.
+func mongoWriteError(ctx *gofr.Context, method string, err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return errors.EntityAlreadyExists{}
+	}
+
+	return dbError(ctx, method, err)
+}
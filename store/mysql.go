@@ -0,0 +1,163 @@
+package store
+
+import (
+	"customer/models"
+	"customer/tracing"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// The customer writes of the MySQL store. MySQL has no RETURNING, so the columns the database fills in are read
+// back by id, in the transaction of the write.
+
+// written reads back the columns of the customer with the given id that the database fills in.
+const written = "SELECT name, created_at, updated_at, status, attributes FROM customer WHERE id=?"
+
+func createMySQL(ctx *gofr.Context, tenant string, customer models.Customer) (models.Customer, error) {
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes) " +
+		"VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,CAST(? AS JSON))"
+
+	ctx, span := tracing.Start(ctx, "store.Create", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Create", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	minor, currency := salary(customer)
+
+	res, err := tx.ExecContext(ctx, query, tenant, customer.Name, customer.Email, customer.Phone, customer.DateOfBirth,
+		minor, currency, customer.Attributes)
+	if err != nil {
+		return models.Customer{}, writeError(ctx, "Create", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Create", err)
+	}
+
+	customer.ID = int(id)
+
+	if _, err := readWritten(ctx, tx, &customer); err != nil {
+		return models.Customer{}, dbError(ctx, "Create", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Customer{}, dbError(ctx, "Create", err)
+	}
+
+	return customer, nil
+}
+
+// upsertMySQL is Upsert on MySQL. ON DUPLICATE KEY UPDATE sets LAST_INSERT_ID to the id of the replaced
+// customer, and MySQL reports one affected row for an insert and two for an update.
+//
+// Unlike ON CONFLICT, ON DUPLICATE KEY UPDATE cannot name the key it applies to, so it also replaces the customer
+// whose email is taken. That customer has another name, and the transaction is rolled back as a duplicate email.
+func upsertMySQL(ctx *gofr.Context, tenant string, customer models.Customer) (res models.Customer, created bool, err error) {
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes) " +
+		"VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,COALESCE(CAST(? AS JSON),JSON_OBJECT())) " +
+		"ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id),email=VALUES(email),phone=VALUES(phone)," +
+		"date_of_birth=VALUES(date_of_birth),salary_minor=VALUES(salary_minor),salary_currency=VALUES(salary_currency)," +
+		"attributes=COALESCE(CAST(? AS JSON),attributes),updated_at=CURRENT_TIMESTAMP(6)"
+
+	ctx, span := tracing.Start(ctx, "store.Upsert", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	attrs := attributes(customer)
+	minor, currency := salary(customer)
+
+	result, err := tx.ExecContext(ctx, query, tenant, customer.Name, customer.Email, customer.Phone, customer.DateOfBirth,
+		minor, currency, attrs, attrs)
+	if err != nil {
+		return models.Customer{}, false, writeError(ctx, "Upsert", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+
+	customer.ID = int(id)
+
+	name, err := readWritten(ctx, tx, &customer)
+	if err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+
+	if name != customer.Name {
+		return models.Customer{}, false, errors.EntityAlreadyExists{}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+
+	return customer, affected == 1, nil
+}
+
+func updateMySQL(ctx *gofr.Context, tenant string, id int, customer models.Customer) (models.Customer, error) {
+	query := "UPDATE customer SET name=?,email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?,salary_minor=?," +
+		"salary_currency=?,attributes=COALESCE(CAST(? AS JSON),attributes),updated_at=CURRENT_TIMESTAMP(6) " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL"
+
+	ctx, span := tracing.Start(ctx, "store.Update", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Customer{}, dbError(ctx, "Update", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	minor, currency := salary(customer)
+
+	res, err := tx.ExecContext(ctx, query, customer.Name, customer.Email, customer.Phone, customer.DateOfBirth, minor,
+		currency, attributes(customer), id, tenant)
+	if err != nil {
+		return models.Customer{}, writeError(ctx, "Update", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return models.Customer{}, sql.ErrNoRows
+	}
+
+	customer.ID = id
+
+	if _, err := readWritten(ctx, tx, &customer); err != nil {
+		return models.Customer{}, dbError(ctx, "Update", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Customer{}, dbError(ctx, "Update", err)
+	}
+
+	return customer, nil
+}
+
+// readWritten reads the columns the database filled in into the customer, and returns its stored name.
+func readWritten(ctx *gofr.Context, tx *sql.Tx, customer *models.Customer) (name string, err error) {
+	err = tx.QueryRowContext(ctx, written, customer.ID).
+		Scan(&name, &customer.CreatedAt, &customer.UpdatedAt, &customer.Status, &customer.Attributes)
+
+	return name, err
+}
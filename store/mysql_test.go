@@ -0,0 +1,320 @@
+package store
+
+import (
+	"customer/models"
+	"database/sql"
+	"database/sql/driver"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"reflect"
+	"testing"
+)
+
+// writtenColumns are the columns read back by written.
+var writtenColumns = []string{"name", "created_at", "updated_at", "status", "attributes"}
+
+func TestMySQL_Create(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQL()
+
+	input := divya()
+	input.ID, input.CreatedAt, input.UpdatedAt, input.Status = 0, nil, nil, ""
+
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes) " +
+		"VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,CAST(? AS JSON))"
+
+	tests := []struct {
+		desc     string
+		expected models.Customer
+		err      error
+		mock     func()
+	}{
+		{"created", divya(), nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WithArgs(tenant, "Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", "{}").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(written).WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(writtenColumns).AddRow("Divya", created, created, "active", []byte("{}")))
+			mock.ExpectCommit()
+		}},
+		{"email of another customer", models.Customer{}, errors.EntityAlreadyExists{}, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		tc.mock()
+
+		res, err := store.Create(ctx, input)
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, tc.expected, tc.err, res, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQL_Upsert(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQL()
+
+	input := divya()
+	input.ID, input.CreatedAt, input.UpdatedAt, input.Status = 0, nil, nil, ""
+
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes) " +
+		"VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,COALESCE(CAST(? AS JSON),JSON_OBJECT())) " +
+		"ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id),email=VALUES(email),phone=VALUES(phone)," +
+		"date_of_birth=VALUES(date_of_birth),salary_minor=VALUES(salary_minor),salary_currency=VALUES(salary_currency)," +
+		"attributes=COALESCE(CAST(? AS JSON),attributes),updated_at=CURRENT_TIMESTAMP(6)"
+	args := []driver.Value{tenant, "Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", nil, nil}
+
+	// upsert expects the statement to report the given affected rows for the customer with id 1, read back as name.
+	upsert := func(affected int64, name string) func() {
+		return func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(1, affected))
+			mock.ExpectQuery(written).WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(writtenColumns).AddRow(name, created, created, "active", []byte("{}")))
+		}
+	}
+
+	tests := []struct {
+		desc     string
+		expected models.Customer
+		created  bool
+		err      error
+		mock     func()
+	}{
+		{"created", divya(), true, nil, func() {
+			upsert(1, "Divya")()
+			mock.ExpectCommit()
+		}},
+		{"updated", divya(), false, nil, func() {
+			upsert(2, "Divya")()
+			mock.ExpectCommit()
+		}},
+		{"email of another customer", models.Customer{}, false, errors.EntityAlreadyExists{}, func() {
+			upsert(2, "Jay")()
+			mock.ExpectRollback()
+		}},
+		{"internal server error", models.Customer{}, false, errors.DB{Err: errors.Error("db error")}, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WillReturnError(errors.Error("db error"))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		tc.mock()
+
+		res, created, err := store.Upsert(ctx, input)
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(res, tc.expected) || created != tc.created {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v, %v\nGot %v, %v, %v", i+1, tc.desc, tc.expected, tc.created, tc.err,
+				res, created, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQL_Update(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQL()
+
+	input := divya()
+	input.CreatedAt, input.UpdatedAt, input.Status = nil, nil, ""
+
+	query := "UPDATE customer SET name=?,email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?,salary_minor=?," +
+		"salary_currency=?,attributes=COALESCE(CAST(? AS JSON),attributes),updated_at=CURRENT_TIMESTAMP(6) " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL"
+	args := []driver.Value{"Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", nil, 1, tenant}
+
+	tests := []struct {
+		desc     string
+		expected models.Customer
+		err      error
+		mock     func()
+	}{
+		{"updated", divya(), nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(written).WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(writtenColumns).AddRow("Divya", created, created, "active", []byte("{}")))
+			mock.ExpectCommit()
+		}},
+		{"not found", models.Customer{}, sql.ErrNoRows, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		tc.mock()
+
+		res, err := store.Update(ctx, 1, input)
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, tc.expected, tc.err, res, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQL_Patch(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQL()
+
+	// JSON_MERGE_PATCH removes the attributes given as null.
+	mock.ExpectExec("UPDATE customer SET attributes = JSON_MERGE_PATCH(attributes, CAST(? AS JSON)), "+
+		"updated_at = CURRENT_TIMESTAMP(6) where id = ? AND tenant_id = ? AND deleted_at IS NULL").
+		WithArgs(`{"plan":"gold","seats":null}`, 1, tenant).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	input := models.Customer{Attributes: models.Attributes{"plan": "gold", "seats": nil}}
+	expected := models.Customer{ID: 1, Attributes: input.Attributes}
+
+	res, err := store.Patch(ctx, 1, input)
+	if err != nil || !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %v\nGot %v, %v", expected, res, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQL_Count(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQL()
+
+	tests := []struct {
+		desc   string
+		filter models.Filter
+		count  int
+		err    error
+		mock   func()
+	}{
+		{"by age", models.Filter{MinAge: 18, MaxAge: 30}, 2, nil, func() {
+			mock.ExpectQuery("SELECT COUNT(*) FROM customer WHERE tenant_id = ? AND deleted_at IS NULL AND "+
+				"date_of_birth <= DATE_SUB(CURRENT_DATE, INTERVAL ? YEAR) AND date_of_birth > DATE_SUB(CURRENT_DATE, INTERVAL ? YEAR)").
+				WithArgs(tenant, 18, 31).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		}},
+		{"by attribute", models.Filter{Attributes: models.Attributes{"plan": "gold"}}, 1, nil, func() {
+			mock.ExpectQuery("SELECT COUNT(*) FROM customer WHERE tenant_id = ? AND deleted_at IS NULL AND "+
+				"JSON_CONTAINS(attributes, CAST(? AS JSON))").
+				WithArgs(tenant, `{"plan":"gold"}`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		}},
+		{"by tag", models.Filter{Tags: []string{"vip"}}, 0, ErrTagFilter, func() {}},
+	}
+
+	for i, tc := range tests {
+		tc.mock()
+
+		count, err := store.Count(ctx, tc.filter)
+		if err != tc.err || count != tc.count {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, tc.count, tc.err, count, err)
+		}
+	}
+}
+
+func TestMySQL_PostgresOnly(t *testing.T) {
+	db, _, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQL()
+
+	if _, err := store.Stats(ctx, models.Filter{}, nil); err != ErrPostgresOnly {
+		t.Errorf("Expected %v for statistics\nGot %v", ErrPostgresOnly, err)
+	}
+
+	if _, err := store.Merge(ctx, 1, []int{2}, nil); err != ErrPostgresOnly {
+		t.Errorf("Expected %v for a merge\nGot %v", ErrPostgresOnly, err)
+	}
+}
+
+func TestMySQL_AttributeCreate(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQLAttribute()
+	query := "INSERT INTO attribute_definition (tenant_id,name,type,required,enum) VALUES(?,?,?,?,CAST(? AS JSON))"
+	plan := models.AttributeDefinition{Name: "plan", Type: models.AttributeString, Enum: []string{"gold", "silver"}}
+	expected := plan
+	expected.ID, expected.CreatedAt = 1, &created
+
+	tests := []struct {
+		desc     string
+		expected models.AttributeDefinition
+		err      error
+		mock     func()
+	}{
+		{"created", expected, nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WithArgs(tenant, "plan", "string", false, `["gold","silver"]`).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery("SELECT created_at FROM attribute_definition WHERE id=?").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+			mock.ExpectCommit()
+		}},
+		{"defined already", models.AttributeDefinition{}, errors.EntityAlreadyExists{}, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		tc.mock()
+
+		res, err := store.Create(ctx, plan)
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v, %v\nGot %v, %v", i+1, tc.desc, tc.expected, tc.err, res, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQL_AttributeDelete(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewMySQLAttribute()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM attribute_definition WHERE tenant_id=? AND name=?").WithArgs(tenant, "plan").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE customer SET attributes=JSON_REMOVE(attributes, ?),updated_at=CURRENT_TIMESTAMP(6) "+
+		"WHERE tenant_id=? AND JSON_CONTAINS_PATH(attributes, 'one', ?)").WithArgs(`$."plan"`, tenant, `$."plan"`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	if err := store.Delete(ctx, "plan"); err != nil {
+		t.Errorf("Expected the attribute to be deleted\nGot %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
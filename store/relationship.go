@@ -0,0 +1,153 @@
+package store
+
+import (
+	"database/sql"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/models"
+	"customer/tracing"
+)
+
+const (
+	relationshipColumns = "id, type, from_id, to_id, created_at"
+	// tenantCustomers selects the customers of the tenant that are not deleted. Relationships are only read
+	// when both of their customers are.
+	tenantCustomers = "SELECT id FROM customer WHERE tenant_id=? AND deleted_at IS NULL"
+	// reachable holds the customers reached from a customer by following relationships of one type forwards.
+	reachable = "WITH RECURSIVE reach(id) AS (SELECT ?::int UNION " +
+		"SELECT r.to_id FROM customer_relationship r JOIN reach ON r.from_id = reach.id WHERE r.type = ?) " +
+		"SELECT 1 FROM reach WHERE id = ?"
+)
+
+type relationship struct{}
+
+func NewRelationship() relationship {
+	return relationship{}
+}
+
+// Create relates two customers of the tenant. A hierarchical relationship that would make a cycle is not
+// created and sql.ErrNoRows is returned. Hierarchical relationships of a tenant are created one at a time, so
+// that two of them cannot close a cycle together.
+func (s relationship) Create(ctx *gofr.Context, r models.Relationship) (models.Relationship, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Relationship{}, err
+	}
+
+	query := "INSERT INTO customer_relationship (type,from_id,to_id) SELECT ?,?,? " +
+		"WHERE ? IN (" + tenantCustomers + ") AND ? IN (" + tenantCustomers + ")"
+	args := []interface{}{r.Type, r.FromID, r.ToID, r.FromID, tenant, r.ToID, tenant}
+
+	// The relationship makes a cycle when its from customer is reached from its to customer.
+	if r.Type.Hierarchical() {
+		query += " AND NOT EXISTS (" + reachable + ")"
+		args = append(args, r.ToID, r.Type, r.FromID)
+	}
+
+	query += " RETURNING id, created_at"
+
+	ctx, span := tracing.Start(ctx, "store.Relationship.Create", semconv.DBStatementKey.String(query),
+		tracing.CustomerIDKey.Int(r.FromID))
+	defer span.End()
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Relationship{}, dbError(ctx, "Relationship.Create", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	if r.Type.Hierarchical() {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "customer_relationship:"+tenant); err != nil {
+			return models.Relationship{}, dbError(ctx, "Relationship.Create", err)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&r.ID, &r.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.Relationship{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return models.Relationship{}, writeError(ctx, "Relationship.Create", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Relationship{}, dbError(ctx, "Relationship.Create", err)
+	}
+	return r, nil
+}
+
+// List returns the relationships of a customer of the tenant in either direction, oldest first.
+func (s relationship) List(ctx *gofr.Context, customerID int) ([]models.Relationship, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + relationshipColumns + " FROM customer_relationship WHERE (from_id=? OR to_id=?) " +
+		"AND from_id IN (" + tenantCustomers + ") AND to_id IN (" + tenantCustomers + ") ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.Relationship.List", semconv.DBStatementKey.String(query),
+		tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, customerID, customerID, tenant, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "Relationship.List", err)
+	}
+	defer rows.Close()
+
+	return scanRelationships(ctx, "Relationship.List", rows)
+}
+
+// Graph returns the relationships between the customers of the tenant that are at most depth relationships
+// away from a customer, following them in either direction. Deleted customers are not walked through.
+func (s relationship) Graph(ctx *gofr.Context, customerID, depth int) ([]models.Relationship, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// UNION drops the customers reached again at the same depth, and the depth bounds the walk, so cycles end.
+	query := "WITH RECURSIVE walk(id, depth) AS (" +
+		"SELECT id, 0 FROM customer WHERE id=? AND tenant_id=? AND deleted_at IS NULL UNION " +
+		"SELECT customer.id, walk.depth + 1 FROM walk " +
+		"JOIN customer_relationship r ON walk.id IN (r.from_id, r.to_id) " +
+		"JOIN customer ON customer.id = CASE WHEN r.from_id = walk.id THEN r.to_id ELSE r.from_id END " +
+		"AND customer.deleted_at IS NULL WHERE walk.depth < ?) " +
+		"SELECT " + relationshipColumns + " FROM customer_relationship " +
+		"WHERE from_id IN (SELECT id FROM walk) AND to_id IN (SELECT id FROM walk) ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.Relationship.Graph", semconv.DBStatementKey.String(query),
+		tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, customerID, tenant, depth)
+	if err != nil {
+		return nil, dbError(ctx, "Relationship.Graph", err)
+	}
+	defer rows.Close()
+
+	return scanRelationships(ctx, "Relationship.Graph", rows)
+}
+
+func scanRelationships(ctx *gofr.Context, method string, rows *sql.Rows) ([]models.Relationship, error) {
+	res := []models.Relationship{}
+
+	for rows.Next() {
+		var r models.Relationship
+
+		if err := rows.Scan(&r.ID, &r.Type, &r.FromID, &r.ToID, &r.CreatedAt); err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, method, err)
+	}
+	return res, nil
+}
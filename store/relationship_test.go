@@ -0,0 +1,163 @@
+package store
+
+import (
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"reflect"
+	"testing"
+)
+
+func TestRelationship_Create(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewRelationship()
+	insert := "INSERT INTO customer_relationship (type,from_id,to_id) SELECT ?,?,? " +
+		"WHERE ? IN (" + tenantCustomers + ") AND ? IN (" + tenantCustomers + ")"
+	lock := "SELECT pg_advisory_xact_lock(hashtext(?))"
+	returned := []string{"id", "created_at"}
+
+	parent := models.Relationship{Type: models.RelationshipParent, FromID: 2, ToID: 1}
+	household := models.Relationship{Type: models.RelationshipHousehold, FromID: 2, ToID: 3}
+
+	tests := []struct {
+		desc     string
+		input    models.Relationship
+		expected models.Relationship
+		err      error
+		mock     func()
+	}{
+		{"hierarchical", parent,
+			models.Relationship{ID: 1, Type: models.RelationshipParent, FromID: 2, ToID: 1, CreatedAt: &created}, nil, func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(lock).WithArgs("customer_relationship:" + tenant).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(insert+" AND NOT EXISTS ("+reachable+") RETURNING id, created_at").
+					WithArgs("parent", 2, 1, 2, tenant, 1, tenant, 1, "parent", 2).
+					WillReturnRows(sqlmock.NewRows(returned).AddRow(1, created))
+				mock.ExpectCommit()
+			}},
+		{"cycle", parent, models.Relationship{}, sql.ErrNoRows, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(insert + " AND NOT EXISTS (" + reachable + ") RETURNING id, created_at").WillReturnError(sql.ErrNoRows)
+			mock.ExpectRollback()
+		}},
+		{"not hierarchical", household,
+			models.Relationship{ID: 2, Type: models.RelationshipHousehold, FromID: 2, ToID: 3, CreatedAt: &created}, nil, func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(insert+" RETURNING id, created_at").WithArgs("household", 2, 3, 2, tenant, 3, tenant).
+					WillReturnRows(sqlmock.NewRows(returned).AddRow(2, created))
+				mock.ExpectCommit()
+			}},
+		{"already related", household, models.Relationship{}, errors.EntityAlreadyExists{}, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(insert + " RETURNING id, created_at").WillReturnError(&pq.Error{Code: "23505"})
+			mock.ExpectRollback()
+		}},
+		{"internal server error", parent, models.Relationship{}, errors.DB{Err: errors.Error("db error")}, func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(lock).WillReturnError(errors.Error("db error"))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.mock()
+
+			res, err := store.Create(ctx, tc.input)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("TEST[%d] %v", i+1, err)
+			}
+		})
+	}
+}
+
+func TestRelationship_List(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewRelationship()
+	query := "SELECT " + relationshipColumns + " FROM customer_relationship WHERE (from_id=? OR to_id=?) " +
+		"AND from_id IN (" + tenantCustomers + ") AND to_id IN (" + tenantCustomers + ") ORDER BY id"
+	relationships := []models.Relationship{
+		{ID: 1, Type: models.RelationshipParent, FromID: 2, ToID: 1, CreatedAt: &created},
+		{ID: 2, Type: models.RelationshipReferral, FromID: 1, ToID: 3, CreatedAt: &created},
+	}
+
+	tests := []struct {
+		desc     string
+		expected []models.Relationship
+		err      error
+		mock     interface{}
+	}{
+		{"success", relationships, nil, mock.ExpectQuery(query).WithArgs(1, 1, tenant, tenant).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "type", "from_id", "to_id", "created_at"}).
+				AddRow(1, "parent", 2, 1, created).AddRow(2, "referral", 1, 3, created))},
+		{"no relationships", []models.Relationship{}, nil, mock.ExpectQuery(query).WithArgs(1, 1, tenant, tenant).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type", "from_id", "to_id", "created_at"}))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.List(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestRelationship_Graph(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewRelationship()
+	query := "WITH RECURSIVE walk(id, depth) AS (" +
+		"SELECT id, 0 FROM customer WHERE id=? AND tenant_id=? AND deleted_at IS NULL UNION " +
+		"SELECT customer.id, walk.depth + 1 FROM walk " +
+		"JOIN customer_relationship r ON walk.id IN (r.from_id, r.to_id) " +
+		"JOIN customer ON customer.id = CASE WHEN r.from_id = walk.id THEN r.to_id ELSE r.from_id END " +
+		"AND customer.deleted_at IS NULL WHERE walk.depth < ?) " +
+		"SELECT " + relationshipColumns + " FROM customer_relationship " +
+		"WHERE from_id IN (SELECT id FROM walk) AND to_id IN (SELECT id FROM walk) ORDER BY id"
+
+	tests := []struct {
+		desc     string
+		expected []models.Relationship
+		err      error
+		mock     interface{}
+	}{
+		{"success", []models.Relationship{{ID: 1, Type: models.RelationshipParent, FromID: 2, ToID: 1, CreatedAt: &created}}, nil,
+			mock.ExpectQuery(query).WithArgs(1, tenant, 2).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "type", "from_id", "to_id", "created_at"}).AddRow(1, "parent", 2, 1, created))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Graph(ctx, 1, 2)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
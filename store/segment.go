@@ -0,0 +1,148 @@
+package store
+
+import (
+	"database/sql"
+	"strconv"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/models"
+	"customer/tracing"
+)
+
+const segmentColumns = "id, name, filter, created_at, updated_at"
+
+type segment struct{}
+
+func NewSegment() segment {
+	return segment{}
+}
+
+// List returns the segments of the tenant by name.
+func (s segment) List(ctx *gofr.Context) ([]models.Segment, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + segmentColumns + " FROM segment WHERE tenant_id=? ORDER BY name"
+
+	ctx, span := tracing.Start(ctx, "store.Segment.List", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "Segment.List", err)
+	}
+	defer rows.Close()
+
+	var res []models.Segment
+
+	for rows.Next() {
+		segment, err := scanSegment(rows)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, segment)
+	}
+	return res, nil
+}
+
+func (s segment) Get(ctx *gofr.Context, id int) (models.Segment, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Segment{}, err
+	}
+
+	query := "SELECT " + segmentColumns + " FROM segment WHERE id=? AND tenant_id=?"
+
+	ctx, span := tracing.Start(ctx, "store.Segment.Get", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	res, err := scanSegment(ctx.DB().QueryRowContext(ctx, query, id, tenant))
+	if err == sql.ErrNoRows {
+		return models.Segment{}, segmentNotFound(id)
+	}
+	if err != nil {
+		return models.Segment{}, dbError(ctx, "Segment.Get", err)
+	}
+	return res, nil
+}
+
+// Create saves a segment of the tenant. Segment names are unique within a tenant.
+func (s segment) Create(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Segment{}, err
+	}
+
+	query := "INSERT INTO segment (tenant_id,name,filter) VALUES(?,?,?) RETURNING id, created_at, updated_at"
+
+	ctx, span := tracing.Start(ctx, "store.Segment.Create", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	err = ctx.DB().QueryRowContext(ctx, query, tenant, segment.Name, segment.Filter).
+		Scan(&segment.ID, &segment.CreatedAt, &segment.UpdatedAt)
+	if err != nil {
+		return models.Segment{}, writeError(ctx, "Segment.Create", err)
+	}
+	return segment, nil
+}
+
+func (s segment) Update(ctx *gofr.Context, segment models.Segment) (models.Segment, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Segment{}, err
+	}
+
+	query := "UPDATE segment SET name=?,filter=?,updated_at=now() WHERE id=? AND tenant_id=? RETURNING created_at, updated_at"
+
+	ctx, span := tracing.Start(ctx, "store.Segment.Update", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	err = ctx.DB().QueryRowContext(ctx, query, segment.Name, segment.Filter, segment.ID, tenant).
+		Scan(&segment.CreatedAt, &segment.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.Segment{}, segmentNotFound(segment.ID)
+	}
+	if err != nil {
+		return models.Segment{}, writeError(ctx, "Segment.Update", err)
+	}
+	return segment, nil
+}
+
+func (s segment) Delete(ctx *gofr.Context, id int) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM segment WHERE id=? AND tenant_id=?"
+
+	ctx, span := tracing.Start(ctx, "store.Segment.Delete", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	res, err := ctx.DB().ExecContext(ctx, query, id, tenant)
+	if err != nil {
+		return dbError(ctx, "Segment.Delete", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return segmentNotFound(id)
+	}
+	return nil
+}
+
+func segmentNotFound(id int) error {
+	return errors.EntityNotFound{Entity: "segment", ID: strconv.Itoa(id)}
+}
+
+func scanSegment(row scanner) (models.Segment, error) {
+	var s models.Segment
+
+	err := row.Scan(&s.ID, &s.Name, &s.Filter, &s.CreatedAt, &s.UpdatedAt)
+
+	return s, err
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"reflect"
+	"testing"
+)
+
+var segmentColumnNames = []string{"id", "name", "filter", "created_at", "updated_at"}
+
+func vip() models.Segment {
+	return models.Segment{ID: 1, Name: "VIPs", Filter: "tag=vip&tag=!churned", CreatedAt: &created, UpdatedAt: &created}
+}
+
+func TestSegment_List(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewSegment()
+	query := "SELECT " + segmentColumns + " FROM segment WHERE tenant_id=? ORDER BY name"
+
+	tests := []struct {
+		desc     string
+		expected []models.Segment
+		err      error
+		mock     interface{}
+	}{
+		{"success", []models.Segment{vip()}, nil,
+			mock.ExpectQuery(query).WithArgs(tenant).WillReturnRows(sqlmock.NewRows(segmentColumnNames).
+				AddRow(1, "VIPs", "tag=vip&tag=!churned", created, created))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.List(ctx)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSegment_Get(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewSegment()
+	query := "SELECT " + segmentColumns + " FROM segment WHERE id=? AND tenant_id=?"
+
+	tests := []struct {
+		desc     string
+		expected models.Segment
+		err      error
+		mock     interface{}
+	}{
+		{"success", vip(), nil,
+			mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(sqlmock.NewRows(segmentColumnNames).
+				AddRow(1, "VIPs", "tag=vip&tag=!churned", created, created))},
+		{"not found", models.Segment{}, errors.EntityNotFound{Entity: "segment", ID: "1"},
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(segmentColumnNames))},
+		{"internal server error", models.Segment{}, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Get(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSegment_Create(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewSegment()
+	query := "INSERT INTO segment (tenant_id,name,filter) VALUES(?,?,?) RETURNING id, created_at, updated_at"
+	input := models.Segment{Name: "VIPs", Filter: "tag=vip&tag=!churned"}
+
+	tests := []struct {
+		desc     string
+		expected models.Segment
+		err      error
+		mock     interface{}
+	}{
+		{"success", vip(), nil,
+			mock.ExpectQuery(query).WithArgs(tenant, "VIPs", "tag=vip&tag=!churned").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, created, created))},
+		{"name taken", models.Segment{}, errors.EntityAlreadyExists{},
+			mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "23505"})},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Create(ctx, input)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSegment_Update(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewSegment()
+	query := "UPDATE segment SET name=?,filter=?,updated_at=now() WHERE id=? AND tenant_id=? RETURNING created_at, updated_at"
+	input := models.Segment{ID: 1, Name: "VIPs", Filter: "tag=vip&tag=!churned"}
+
+	tests := []struct {
+		desc     string
+		expected models.Segment
+		err      error
+		mock     interface{}
+	}{
+		{"success", vip(), nil,
+			mock.ExpectQuery(query).WithArgs("VIPs", "tag=vip&tag=!churned", 1, tenant).
+				WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(created, created))},
+		{"not found", models.Segment{}, errors.EntityNotFound{Entity: "segment", ID: "1"},
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}))},
+		{"name taken", models.Segment{}, errors.EntityAlreadyExists{},
+			mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "23505"})},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Update(ctx, input)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSegment_Delete(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewSegment()
+	query := "DELETE FROM segment WHERE id=? AND tenant_id=?"
+
+	tests := []struct {
+		desc string
+		err  error
+		mock interface{}
+	}{
+		{"success", nil, mock.ExpectExec(query).WithArgs(1, tenant).WillReturnResult(sqlmock.NewResult(0, 1))},
+		{"not found", errors.EntityNotFound{Entity: "segment", ID: "1"},
+			mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := store.Delete(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+		})
+	}
+}
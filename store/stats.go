@@ -0,0 +1,233 @@
+package store
+
+import (
+	"customer/models"
+	"customer/tracing"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"fmt"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"strconv"
+	"strings"
+)
+
+// ageColumn computes the age of a customer today, the same way whereClause filters by age.
+const ageColumn = "date_part('year', age(CURRENT_DATE, date_of_birth))::int"
+
+// Stats aggregates the customers matching the filter in the database. Pagination is ignored. buckets are the
+// ascending lower bounds of the age histogram; customers younger than the first bound fall in a bucket from 0.
+// It is written for Postgres only.
+func (s store) Stats(ctx *gofr.Context, filter models.Filter, buckets []int) (models.Stats, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	if s.dialect == MySQL {
+		return models.Stats{}, ErrPostgresOnly
+	}
+
+	k := s.keys.of(tenant)
+
+	where, qp, err := whereClause(ctx, s.dialect, k, tenant, filter)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	from := " FROM (SELECT salary_minor, salary_currency, " + ageColumn + " AS age FROM customer" + where + ") c"
+
+	ctx, span := tracing.Start(ctx, "store.Stats")
+	defer span.End()
+
+	stats, err := ageStats(ctx, from, qp)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	// Encrypted salaries cannot be aggregated by the database, so they are summarised here instead.
+	if k == nil {
+		stats.Salary, err = salaryStats(ctx, from, qp)
+	} else {
+		stats.Salary, err = sealedSalaryStats(ctx, k, where, qp)
+	}
+
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	if stats.AgeHistogram, err = ageHistogram(ctx, from, qp, buckets); err != nil {
+		return models.Stats{}, err
+	}
+
+	return stats, nil
+}
+
+func ageStats(ctx *gofr.Context, from string, qp []interface{}) (models.Stats, error) {
+	query := "SELECT COUNT(*), COUNT(age), COALESCE(MIN(age), 0), COALESCE(MAX(age), 0), COALESCE(AVG(age), 0)::float8, " +
+		percentiles("age", "0") + from
+
+	ctx, span := tracing.Start(ctx, "store.ageStats", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	var (
+		stats models.Stats
+		age   = &stats.Age
+	)
+
+	err := ctx.DB().QueryRowContext(ctx, query, qp...).
+		Scan(&stats.Count, &age.Count, &age.Min, &age.Max, &age.Avg, &age.P50, &age.P90, &age.P99)
+	if err != nil {
+		return models.Stats{}, dbError(ctx, "Stats", err)
+	}
+
+	return stats, nil
+}
+
+func salaryStats(ctx *gofr.Context, from string, qp []interface{}) ([]models.SalaryStats, error) {
+	query := "SELECT salary_currency, COUNT(*), MIN(salary_minor), MAX(salary_minor), round(AVG(salary_minor))::bigint, " +
+		percentiles("salary_minor", "") + from + " WHERE salary_minor IS NOT NULL GROUP BY salary_currency ORDER BY salary_currency"
+
+	ctx, span := tracing.Start(ctx, "store.salaryStats", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, qp...)
+	if err != nil {
+		return nil, dbError(ctx, "Stats", err)
+	}
+	defer rows.Close()
+
+	res := []models.SalaryStats{}
+
+	for rows.Next() {
+		var (
+			s                            models.SalaryStats
+			min, max, avg, p50, p90, p99 int64
+		)
+
+		if err := rows.Scan(&s.Currency, &s.Count, &min, &max, &avg, &p50, &p90, &p99); err != nil {
+			return nil, dbError(ctx, "Stats", err)
+		}
+
+		money := func(minor int64) models.Money { return models.Money{Minor: minor, Currency: s.Currency} }
+		s.Min, s.Max, s.Avg, s.P50, s.P90, s.P99 = money(min), money(max), money(avg), money(p50), money(p90), money(p99)
+
+		res = append(res, s)
+	}
+
+	return res, nil
+}
+
+// sealedSalaryStats reads the salaries of the customers that where selects, decrypting those that are
+// encrypted, and summarises them like salaryStats.
+func sealedSalaryStats(ctx *gofr.Context, k *fieldKeys, where string, qp []interface{}) ([]models.SalaryStats, error) {
+	cols := k.columns(selectColumns([]string{"salary"}))
+	query := "SELECT " + selectList(cols) + " FROM customer" + where +
+		" AND (salary_minor IS NOT NULL OR salary_enc IS NOT NULL)"
+
+	ctx, span := tracing.Start(ctx, "store.sealedSalaryStats", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, qp...)
+	if err != nil {
+		return nil, dbError(ctx, "Stats", err)
+	}
+	defer rows.Close()
+
+	var salaries []models.Money
+
+	for rows.Next() {
+		r, err := scanRow(rows, cols)
+		if err != nil {
+			return nil, dbError(ctx, "Stats", err)
+		}
+
+		customer, err := k.decrypt(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if customer.Salary != nil {
+			salaries = append(salaries, *customer.Salary)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "Stats", err)
+	}
+
+	return models.SummariseSalaries(salaries), nil
+}
+
+// ageHistogram counts the customers of known age in every bucket. width_bucket numbers the buckets from 0 for
+// ages below the first bound to len(buckets) for ages from the last bound.
+func ageHistogram(ctx *gofr.Context, from string, qp []interface{}, buckets []int) ([]models.AgeBucket, error) {
+	res := histogram(buckets)
+
+	if len(buckets) == 0 {
+		return res, nil
+	}
+
+	bounds := make([]string, len(buckets))
+	for i, b := range buckets {
+		bounds[i] = strconv.Itoa(b)
+	}
+
+	query := fmt.Sprintf("SELECT width_bucket(age, ARRAY[%v]), COUNT(*)%v WHERE age IS NOT NULL GROUP BY 1",
+		strings.Join(bounds, ","), from)
+
+	ctx, span := tracing.Start(ctx, "store.ageHistogram", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, qp...)
+	if err != nil {
+		return nil, dbError(ctx, "Stats", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket, count int
+
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, dbError(ctx, "Stats", err)
+		}
+
+		if bucket >= 0 && bucket < len(res) {
+			res[bucket].Count = count
+		}
+	}
+
+	return res, nil
+}
+
+// histogram returns the empty buckets of an age histogram with the given lower bounds.
+func histogram(buckets []int) []models.AgeBucket {
+	res := make([]models.AgeBucket, len(buckets)+1)
+
+	for i := range res {
+		if i > 0 {
+			res[i].Min = buckets[i-1]
+		}
+
+		if i < len(buckets) {
+			max := buckets[i]
+			res[i].Max = &max
+		}
+	}
+
+	return res
+}
+
+// percentiles selects the 50th, 90th and 99th percentile of column. Each of them is one of the values, so that
+// salaries stay whole minor units. When empty is set it replaces the percentiles of no rows.
+func percentiles(column, empty string) string {
+	levels := []string{"0.5", "0.9", "0.99"}
+	selects := make([]string, len(levels))
+
+	for i, level := range levels {
+		selects[i] = fmt.Sprintf("percentile_disc(%v) WITHIN GROUP (ORDER BY %v)", level, column)
+		if empty != "" {
+			selects[i] = fmt.Sprintf("COALESCE(%v, %v)", selects[i], empty)
+		}
+	}
+
+	return strings.Join(selects, ", ")
+}
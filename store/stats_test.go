@@ -0,0 +1,97 @@
+package store
+
+import (
+	"customer/models"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func TestStore_Stats(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	from := " FROM (SELECT salary_minor, salary_currency, " + ageColumn + " AS age FROM customer" +
+		" WHERE tenant_id = ? AND deleted_at IS NULL AND date_of_birth <= CURRENT_DATE - make_interval(years => ?)) c"
+	ages := "SELECT COUNT(*), COUNT(age), COALESCE(MIN(age), 0), COALESCE(MAX(age), 0), COALESCE(AVG(age), 0)::float8, " +
+		percentiles("age", "0") + from
+	salaries := "SELECT salary_currency, COUNT(*), MIN(salary_minor), MAX(salary_minor), round(AVG(salary_minor))::bigint, " +
+		percentiles("salary_minor", "") + from + " WHERE salary_minor IS NOT NULL GROUP BY salary_currency ORDER BY salary_currency"
+	histogram := "SELECT width_bucket(age, ARRAY[21,25]), COUNT(*)" + from + " WHERE age IS NOT NULL GROUP BY 1"
+
+	inrStats := func(minor ...int64) models.SalaryStats {
+		m := make([]models.Money, len(minor))
+		for i := range minor {
+			m[i] = models.Money{Minor: minor[i], Currency: "INR"}
+		}
+
+		return models.SalaryStats{Currency: "INR", Count: 3, Min: m[0], Max: m[1], Avg: m[2], P50: m[3], P90: m[4], P99: m[5]}
+	}
+	twentyOne, twentyFive := 21, 25
+
+	expected := models.Stats{
+		Count:  4,
+		Age:    models.AgeStats{Count: 3, Min: 20, Max: 22, Avg: 21, P50: 21, P90: 22, P99: 22},
+		Salary: []models.SalaryStats{inrStats(2000000, 4000000, 3000000, 3000000, 4000000, 4000000)},
+		AgeHistogram: []models.AgeBucket{{Min: 0, Max: &twentyOne, Count: 1}, {Min: 21, Max: &twentyFive, Count: 2},
+			{Min: 25, Count: 0}},
+	}
+
+	tests := []struct {
+		desc     string
+		expected models.Stats
+		err      error
+		mock     []*sqlmock.ExpectedQuery
+	}{
+		{"success", expected, nil, []*sqlmock.ExpectedQuery{
+			mock.ExpectQuery(ages).WithArgs(tenant, 18).WillReturnRows(
+				sqlmock.NewRows([]string{"count", "count", "min", "max", "avg", "p50", "p90", "p99"}).AddRow(4, 3, 20, 22, 21.0, 21, 22, 22)),
+			mock.ExpectQuery(salaries).WithArgs(tenant, 18).WillReturnRows(
+				sqlmock.NewRows([]string{"salary_currency", "count", "min", "max", "round", "p50", "p90", "p99"}).
+					AddRow("INR", 3, 2000000, 4000000, 3000000, 3000000, 4000000, 4000000)),
+			mock.ExpectQuery(histogram).WithArgs(tenant, 18).WillReturnRows(
+				sqlmock.NewRows([]string{"width_bucket", "count"}).AddRow(0, 1).AddRow(1, 2)),
+		}},
+		{"internal server error", models.Stats{}, errors.DB{Err: errors.Error("db error")}, []*sqlmock.ExpectedQuery{
+			mock.ExpectQuery(ages).WillReturnError(errors.Error("db error")),
+		}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Stats(ctx, models.Filter{MinAge: 18, Limit: 10}, []int{21, 25})
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestStore_StatsWithoutBuckets(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	from := " FROM (SELECT salary_minor, salary_currency, " + ageColumn + " AS age FROM customer WHERE tenant_id = ? AND deleted_at IS NULL) c"
+
+	mock.ExpectQuery("SELECT COUNT(*), COUNT(age), COALESCE(MIN(age), 0), COALESCE(MAX(age), 0), COALESCE(AVG(age), 0)::float8, " +
+		percentiles("age", "0") + from).WillReturnRows(
+		sqlmock.NewRows([]string{"count", "count", "min", "max", "avg", "p50", "p90", "p99"}).AddRow(0, 0, 0, 0, 0.0, 0, 0, 0))
+	mock.ExpectQuery("SELECT salary_currency, COUNT(*), MIN(salary_minor), MAX(salary_minor), round(AVG(salary_minor))::bigint, " +
+		percentiles("salary_minor", "") + from + " WHERE salary_minor IS NOT NULL GROUP BY salary_currency ORDER BY salary_currency").
+		WillReturnRows(sqlmock.NewRows([]string{"salary_currency"}))
+
+	expected := models.Stats{Salary: []models.SalaryStats{}, AgeHistogram: []models.AgeBucket{{}}}
+
+	res, err := store.Stats(ctx, models.Filter{}, nil)
+	if err != nil || !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %v\nGot %v, %v", expected, res, err)
+	}
+}
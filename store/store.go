@@ -1,131 +1,773 @@
 package store
 
 import (
+	"customer/middleware"
 	"customer/models"
+	"customer/tracing"
 	"database/sql"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"strings"
 )
 
-type store struct{}
+// SchemaVersion is the schema_version of database/database.sql, and of database/mysql.sql, that the queries are
+// written against.
+const SchemaVersion = 11
 
-func New() store {
-	return store{}
+// columns maps the customer columns to the fields of models.Customer they are read into. Email and phone are
+// stored as NULL when empty so that the unique email constraint only applies to set values. Salaries are
+// stored in minor units next to their currency, and both are NULL when the salary is unknown. Names and
+// salaries are NULL too when they are encrypted, see sealedColumns. Ages are computed from the date of birth,
+// so reading them needs that column. Custom attributes are a JSON object.
+var columns = []column{
+	{"id", []string{"id"}, func(r *customerRow) interface{} { return &r.ID }},
+	{"name", []string{"name"}, func(r *customerRow) interface{} { return &r.name }},
+	{"COALESCE(email, '')", []string{"email"}, func(r *customerRow) interface{} { return &r.Email }},
+	{"COALESCE(phone, '')", []string{"phone"}, func(r *customerRow) interface{} { return &r.Phone }},
+	{"date_of_birth", []string{"dateOfBirth", "age"}, func(r *customerRow) interface{} { return &r.DateOfBirth }},
+	{"salary_minor", []string{"salary"}, func(r *customerRow) interface{} { return &r.minor }},
+	{"salary_currency", []string{"salary"}, func(r *customerRow) interface{} { return &r.currency }},
+	{"created_at", []string{"createdAt"}, func(r *customerRow) interface{} { return &r.CreatedAt }},
+	{"updated_at", []string{"updatedAt"}, func(r *customerRow) interface{} { return &r.UpdatedAt }},
+	{"status", []string{"status"}, func(r *customerRow) interface{} { return &r.Status }},
+	{"attributes", []string{"attributes"}, func(r *customerRow) interface{} { return &r.Attributes }},
 }
 
-func (s store) Get(ctx *gofr.Context) ([]models.Customer, error) {
+// customerColumns selects every customer column.
+var customerColumns = selectList(columns)
 
-	rows, err := ctx.DB().QueryContext(ctx, "SELECT * FROM customer")
+// uniqueViolation is the Postgres error code for a unique constraint violation.
+const uniqueViolation = "23505"
+
+// duplicateEntry is the MySQL error number for a duplicate value of a unique key.
+const duplicateEntry = 1062
+
+// errNoTenant is returned instead of running a query that is not scoped to a tenant.
+var errNoTenant = errors.Error("request is not scoped to a tenant")
+
+// store reads and writes the customers. With a keyring their names and salaries are encrypted, and read back
+// decrypted, so that the layers above never see the difference.
+type store struct {
+	keys    *Keyring
+	dialect Dialect
+}
+
+func New(keys *Keyring) store {
+	return store{keys: keys, dialect: Postgres}
+}
+
+// NewMySQL returns the store of the customers kept in MySQL, see Dialect. Their names and salaries are never
+// encrypted.
+func NewMySQL() store {
+	return store{dialect: MySQL}
+}
+
+func (s store) Get(ctx *gofr.Context, filter models.Filter) ([]models.Customer, error) {
+	var res []models.Customer
+
+	err := s.each(ctx, "Get", filter, func(customer models.Customer) error {
+		res = append(res, customer)
+		return nil
+	})
 	if err != nil {
-		return nil, errors.DB{Err: err}
+		return nil, err
 	}
+	return res, nil
+}
 
-	var res []models.Customer
+// Stream calls fn with every customer matching the filter as soon as it is scanned, so that listings of any
+// size are never held in memory. It stops at the first error returned by fn, or when ctx is cancelled.
+func (s store) Stream(ctx *gofr.Context, filter models.Filter, fn func(models.Customer) error) error {
+	return s.each(ctx, "Stream", filter, fn)
+}
+
+func (s store) each(ctx *gofr.Context, method string, filter models.Filter, fn func(models.Customer) error) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	k := s.keys.of(tenant)
+	cols := k.columns(selectColumns(filter.Fields))
+
+	where, qp, err := whereClause(ctx, s.dialect, k, tenant, filter)
+	if err != nil {
+		return err
+	}
+
+	query := "SELECT " + selectList(cols) + " FROM customer" + where + pageClause(filter)
+
+	ctx, span := tracing.Start(ctx, "store."+method, semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, qp...)
+	if err != nil {
+		return queryError(ctx, method, err)
+	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var customer models.Customer
-		err := rows.Scan(&customer.ID, &customer.Name, &customer.Age, &customer.Salary)
+		r, err := scanRow(rows, cols)
+		if err != nil {
+			return errors.Error("scan error")
+		}
 
+		customer, err := k.decrypt(ctx, r)
 		if err != nil {
-			return nil, errors.Error("scan error")
+			return err
+		}
+
+		if err := fn(customer); err != nil {
+			return err
 		}
-		res = append(res, customer)
 	}
-	return res, nil
 
+	if err := rows.Err(); err != nil {
+		return queryError(ctx, method, err)
+	}
+
+	return nil
 }
 
-func (s store) GetByID(ctx *gofr.Context, id int) (models.Customer, error) {
-	var customer models.Customer
-	rows := ctx.DB().QueryRowContext(ctx, "SELECT * FROM customer where id=?", id)
-	err := rows.Scan(&customer.ID, &customer.Name, &customer.Age, &customer.Salary)
+// GetByID reads the given fields of a customer, or all of them when none is given.
+func (s store) GetByID(ctx *gofr.Context, id int, fields ...string) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	k := s.keys.of(tenant)
+	cols := k.columns(selectColumns(fields))
+	query := "SELECT " + selectList(cols) + " FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL"
+
+	ctx, span := tracing.Start(ctx, "store.GetByID", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	r, err := scanRow(ctx.DB().QueryRowContext(ctx, query, id, tenant), cols)
 	if err == sql.ErrNoRows {
 		return models.Customer{}, sql.ErrNoRows
 	}
 	if err != nil {
-		return models.Customer{}, errors.DB{Err: err}
+		return models.Customer{}, dbError(ctx, "GetByID", err)
 	}
-	return customer, nil
+	return k.decrypt(ctx, r)
+}
+
+// GetByIDs fetches all the given customers with a single query. Unknown ids are skipped.
+func (s store) GetByIDs(ctx *gofr.Context, ids []int) ([]models.Customer, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	qp := []interface{}{tenant}
+	for i := range ids {
+		qp = append(qp, ids[i])
+	}
+
+	k := s.keys.of(tenant)
+	cols := k.columns(columns)
+	query := fmt.Sprintf("SELECT %v FROM customer WHERE tenant_id=? AND deleted_at IS NULL AND id IN (%v)", selectList(cols),
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","))
+
+	ctx, span := tracing.Start(ctx, "store.GetByIDs", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, qp...)
+	if err != nil {
+		return nil, dbError(ctx, "GetByIDs", err)
+	}
+	defer rows.Close()
+
+	var res []models.Customer
+
+	for rows.Next() {
+		r, err := scanRow(rows, cols)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+
+		customer, err := k.decrypt(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, customer)
+	}
+	return res, nil
+}
+
+// Count returns the number of customers matching the filter. Pagination is ignored.
+func (s store) Count(ctx *gofr.Context, filter models.Filter) (int, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	where, qp, err := whereClause(ctx, s.dialect, s.keys.of(tenant), tenant, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "SELECT COUNT(*) FROM customer" + where
+
+	ctx, span := tracing.Start(ctx, "store.Count", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	var count int
+
+	err = ctx.DB().QueryRowContext(ctx, query, qp...).Scan(&count)
+	if err != nil {
+		return 0, dbError(ctx, "Count", err)
+	}
+
+	return count, nil
 }
 
 func (s store) Create(ctx *gofr.Context, customer models.Customer) (models.Customer, error) {
-	_, err := ctx.DB().ExecContext(ctx, "INSERT INTO customer (name,age,salary) VALUES(?,?,?)",
-		customer.Name, customer.Age, customer.Salary)
+	tenant, err := tenantOf(ctx)
 	if err != nil {
-		return models.Customer{}, errors.DB{Err: err}
+		return models.Customer{}, err
+	}
+
+	if s.dialect == MySQL {
+		return createMySQL(ctx, tenant, customer)
+	}
+
+	k := s.keys.of(tenant)
+
+	id, err := reserveID(ctx, k, "Create")
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	name, pay, err := sealCustomer(ctx, k, id, customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	sealed, marks, values := sealedInsert(id, name, pay)
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes" +
+		sealed + ") VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,?" + marks + ") " +
+		"RETURNING id, created_at, updated_at, status"
+
+	ctx, span := tracing.Start(ctx, "store.Create", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	qp := append([]interface{}{tenant, name.plain[0], customer.Email, customer.Phone, customer.DateOfBirth},
+		append(pay.plain, customer.Attributes)...)
+
+	err = ctx.DB().QueryRowContext(ctx, query, append(qp, values...)...).
+		Scan(&customer.ID, &customer.CreatedAt, &customer.UpdatedAt, &customer.Status)
+	if err != nil {
+		return models.Customer{}, writeError(ctx, "Create", err)
 	}
 	return customer, nil
 }
 
+// Upsert creates the customer, or replaces the customer of the tenant with the same name, in a single statement
+// so that concurrent writers cannot race. created reports whether the customer was created. A replaced customer
+// keeps its attributes when none are given. On MySQL it is written with ON DUPLICATE KEY UPDATE, see upsertMySQL.
+//
+// Encrypted fields are sealed for the id the customer would be created with. A replaced customer keeps its own id,
+// so its fields are sealed again for it in the same transaction.
+func (s store) Upsert(ctx *gofr.Context, customer models.Customer) (res models.Customer, created bool, err error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	if s.dialect == MySQL {
+		return upsertMySQL(ctx, tenant, customer)
+	}
+
+	k := s.keys.of(tenant)
+
+	id, err := reserveID(ctx, k, "Upsert")
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	name, pay, err := sealCustomer(ctx, k, id, customer)
+	if err != nil {
+		return models.Customer{}, false, err
+	}
+
+	// Encrypted names are unique by their blind index.
+	target := "(tenant_id, name)"
+	if k != nil {
+		target = "(tenant_id, name_index)"
+	}
+
+	sealed, marks, values := sealedInsert(id, name, pay)
+
+	// xmax is only 0 for a row that the statement inserted rather than updated.
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes" +
+		sealed + ") VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,COALESCE(?::jsonb,'{}')" + marks + ") " +
+		"ON CONFLICT " + target + " WHERE deleted_at IS NULL DO UPDATE SET email=EXCLUDED.email,phone=EXCLUDED.phone," +
+		"date_of_birth=EXCLUDED.date_of_birth,salary_minor=EXCLUDED.salary_minor,salary_currency=EXCLUDED.salary_currency" +
+		sealedExcluded(name, pay) + ",attributes=COALESCE(?::jsonb,customer.attributes),updated_at=now() " +
+		"RETURNING id, created_at, updated_at, status, attributes, (xmax = 0)"
+
+	ctx, span := tracing.Start(ctx, "store.Upsert", semconv.DBStatementKey.String(query))
+	defer span.End()
+
+	attrs := attributes(customer)
+	qp := append([]interface{}{tenant, name.plain[0], customer.Email, customer.Phone, customer.DateOfBirth},
+		append(pay.plain, attrs)...)
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+	// Rolling back a committed transaction does nothing.
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRowContext(ctx, query, append(append(qp, values...), attrs)...).
+		Scan(&customer.ID, &customer.CreatedAt, &customer.UpdatedAt, &customer.Status, &customer.Attributes, &created)
+	if err != nil {
+		return models.Customer{}, false, writeError(ctx, "Upsert", err)
+	}
+
+	if k != nil && !created {
+		if name, pay, err = sealCustomer(ctx, k, customer.ID, customer); err != nil {
+			return models.Customer{}, false, err
+		}
+
+		set, values := sealedSet(name, pay)
+		query := "UPDATE customer SET " + strings.TrimPrefix(set, ",") + " WHERE id=?"
+
+		if _, err := tx.ExecContext(ctx, query, append(values, customer.ID)...); err != nil {
+			return models.Customer{}, false, dbError(ctx, "Upsert", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Customer{}, false, dbError(ctx, "Upsert", err)
+	}
+
+	return customer, created, nil
+}
+
+// Update replaces a customer. Its attributes are kept when none are given.
 func (s store) Update(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
-	_, err := ctx.DB().ExecContext(ctx, "UPDATE customer SET name=?,age=?,salary=? WHERE id=?",
-		customer.Name, customer.Age, customer.Salary, id)
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if s.dialect == MySQL {
+		return updateMySQL(ctx, tenant, id, customer)
+	}
+
+	name, pay, err := sealCustomer(ctx, s.keys.of(tenant), id, customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	set, values := sealedSet(name, pay)
+	query := "UPDATE customer SET name=?,email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?," +
+		"salary_minor=?,salary_currency=?,attributes=COALESCE(?::jsonb,attributes),updated_at=now()" + set + " " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING created_at, updated_at, status, attributes"
+
+	ctx, span := tracing.Start(ctx, "store.Update", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	qp := append([]interface{}{name.plain[0], customer.Email, customer.Phone, customer.DateOfBirth},
+		append(pay.plain, attributes(customer))...)
+
+	err = ctx.DB().QueryRowContext(ctx, query, append(append(qp, values...), id, tenant)...).
+		Scan(&customer.CreatedAt, &customer.UpdatedAt, &customer.Status, &customer.Attributes)
 	if err == sql.ErrNoRows {
 		return models.Customer{}, sql.ErrNoRows
 	}
 	if err != nil {
-		return models.Customer{}, errors.DB{Err: err}
+		return models.Customer{}, writeError(ctx, "Update", err)
 	}
 	return customer, nil
 }
 
 func (s store) Delete(ctx *gofr.Context, id int) error {
-	_, err := ctx.DB().ExecContext(ctx, "DELETE FROM customer where id=?", id)
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL"
+
+	ctx, span := tracing.Start(ctx, "store.Delete", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(id))
+	defer span.End()
+
+	res, err := ctx.DB().ExecContext(ctx, query, id, tenant)
 	if err == sql.ErrNoRows {
 		return sql.ErrNoRows
 	}
 	if err != nil {
-		return errors.DB{Err: err}
+		return dbError(ctx, "Delete", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
 	}
 	return nil
 }
 
 func (s store) Patch(ctx *gofr.Context, id int, customer models.Customer) (models.Customer, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
 	query := "UPDATE customer"
-	set, qp := setClause(customer)
+
+	set, qp, err := setClause(ctx, s.dialect, s.keys.of(tenant), id, customer)
+	if err != nil {
+		return models.Customer{}, err
+	}
 
 	// No value is passed for update
 	if qp == nil {
 		return models.Customer{}, nil
 	}
 
-	query = fmt.Sprintf("%v %v where id = ?", query, set)
+	query = fmt.Sprintf("%v %v where id = ? AND tenant_id = ? AND deleted_at IS NULL", query, set)
+
+	qp = append(qp, id, tenant)
 
-	qp = append(qp, id)
+	ctx, span := tracing.Start(ctx, "store.Patch", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(id))
+	defer span.End()
 
-	_, err := ctx.DB().ExecContext(ctx, query, qp...)
+	res, err := ctx.DB().ExecContext(ctx, query, qp...)
 	if err == sql.ErrNoRows {
 		return models.Customer{}, sql.ErrNoRows
 	}
 	if err != nil {
-		return models.Customer{}, errors.DB{Err: err}
+		return models.Customer{}, writeError(ctx, "Patch", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return models.Customer{}, sql.ErrNoRows
 	}
 	customer.ID = id
 	return customer, nil
 }
 
-func setClause(s models.Customer) (set string, filed []interface{}) {
-	set = `SET`
+func setClause(ctx *gofr.Context, d Dialect, k *fieldKeys, id int, s models.Customer) (set string, filed []interface{},
+	err error) {
+	var assignments []string
+
+	s.ID = id
 
 	if s.Name != "" {
-		set += " name = ?,"
-		filed = append(filed, s.Name)
+		if err := k.sealName(ctx, s.Name); err != nil {
+			return "", nil, err
+		}
+
+		name, err := k.name(ctx, id, s.Name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		set, values := name.assignments()
+		assignments = append(assignments, set...)
+		filed = append(filed, values...)
 	}
 
-	if s.Age != 0 {
-		set += " age = ?,"
-		filed = append(filed, s.Age)
+	if s.Email != "" {
+		assignments = append(assignments, "email = ?")
+		filed = append(filed, s.Email)
+	}
+
+	if s.Phone != "" {
+		assignments = append(assignments, "phone = ?")
+		filed = append(filed, s.Phone)
+	}
+
+	if s.DateOfBirth != nil {
+		assignments = append(assignments, "date_of_birth = ?")
+		filed = append(filed, s.DateOfBirth)
+	}
+
+	if s.Salary != nil {
+		pay, err := k.salary(ctx, s)
+		if err != nil {
+			return "", nil, err
+		}
+
+		set, values := pay.assignments()
+		assignments = append(assignments, set...)
+		filed = append(filed, values...)
 	}
 
-	if s.Salary != 0 {
-		set += " salary = ?"
-		filed = append(filed, s.Salary)
+	// The given attributes are merged into the stored ones, and removed when they are null.
+	if s.Attributes != nil {
+		assignments = append(assignments, "attributes = "+d.mergeAttributes())
+		filed = append(filed, s.Attributes)
 	}
 
-	if set == "SET" {
-		return "", nil
+	if len(assignments) == 0 {
+		return "", nil, nil
+	}
+
+	return "SET " + strings.Join(assignments, ", ") + ", updated_at = " + d.now(), filed, nil
+}
+
+// whereClause scopes the customers to the tenant and narrows them down by the filter.
+func whereClause(ctx *gofr.Context, d Dialect, k *fieldKeys, tenant string, f models.Filter) (where string,
+	filed []interface{}, err error) {
+	// Tags are kept in Postgres only.
+	if d == MySQL {
+		if err := checkFilter(f); err != nil {
+			return "", nil, err
+		}
+	}
+
+	conditions := []string{"tenant_id = ?", "deleted_at IS NULL"}
+	filed = append(filed, tenant)
+
+	if f.Name != "" {
+		cond, values, err := k.nameMatch(ctx, f.Name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		conditions = append(conditions, cond)
+		filed = append(filed, values...)
+	}
+
+	if f.Status != "" {
+		conditions = append(conditions, "status = ?")
+		filed = append(filed, f.Status)
+	}
+
+	for _, tag := range f.Tags {
+		conditions = append(conditions, "EXISTS ("+taggedWith+")")
+		filed = append(filed, tag)
+	}
+
+	for _, tag := range f.ExcludedTags {
+		conditions = append(conditions, "NOT EXISTS ("+taggedWith+")")
+		filed = append(filed, tag)
+	}
+
+	if len(f.Attributes) > 0 {
+		conditions = append(conditions, d.containsAttributes())
+		filed = append(filed, f.Attributes)
+	}
+
+	// Ages are compared through the date of birth, since they are not stored.
+	if f.MinAge != 0 {
+		conditions = append(conditions, "date_of_birth <= "+d.bornBefore())
+		filed = append(filed, f.MinAge)
+	}
+
+	if f.MaxAge != 0 {
+		conditions = append(conditions, "date_of_birth > "+d.bornBefore())
+		filed = append(filed, f.MaxAge+1)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), filed, nil
+}
+
+func pageClause(f models.Filter) string {
+	var page string
+
+	if f.Limit > 0 {
+		page += fmt.Sprintf(" LIMIT %d", f.Limit)
+	}
+
+	if f.Offset > 0 {
+		page += fmt.Sprintf(" OFFSET %d", f.Offset)
+	}
+
+	return page
+}
+
+// dbError logs a failed query together with the request id before handing it to the service.
+func dbError(ctx *gofr.Context, method string, err error) error {
+	ctx.Logger.Errorf("request_id=%v store.%v: %v", middleware.RequestID(ctx), method, err)
+	tracing.Fail(ctx, err)
+
+	return errors.DB{Err: err}
+}
+
+// queryError reports a query that failed because its request was cancelled with the error of the context,
+// since that is not a database failure, and any other failure as dbError does.
+func queryError(ctx *gofr.Context, method string, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return dbError(ctx, method, err)
+}
+
+// writeError reports a duplicate email as an existing entity and any other failure as dbError does.
+func writeError(ctx *gofr.Context, method string, err error) error {
+	if e, ok := err.(*pq.Error); ok && e.Code == uniqueViolation {
+		return errors.EntityAlreadyExists{}
+	}
+
+	if e, ok := err.(*mysql.MySQLError); ok && e.Number == duplicateEntry {
+		return errors.EntityAlreadyExists{}
+	}
+
+	return dbError(ctx, method, err)
+}
+
+// tenantOf returns the tenant the queries of ctx are scoped to. A query is never run without one, so that a
+// request that skipped the Auth middleware cannot reach the customers of every tenant.
+func tenantOf(ctx *gofr.Context) (string, error) {
+	tenant := middleware.Tenant(ctx)
+	if tenant == "" {
+		return "", errNoTenant
+	}
+
+	return tenant, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// column is a customer column, read by dest. fields are the json names of the customer fields that need it.
+type column struct {
+	expr   string
+	fields []string
+	dest   func(r *customerRow) interface{}
+}
+
+// customerRow receives the columns of a customer that have no field of their own.
+type customerRow struct {
+	models.Customer
+	name      sql.NullString
+	minor     sql.NullInt64
+	currency  sql.NullString
+	nameEnc   []byte
+	salaryEnc []byte
+}
+
+// selectColumns returns the columns needed to read the given fields, or every column when none is given.
+func selectColumns(fields []string) []column {
+	if len(fields) == 0 {
+		return columns
+	}
+
+	var cols []column
+
+	for _, col := range columns {
+		for _, f := range fields {
+			if contains(col.fields, f) {
+				cols = append(cols, col)
+				break
+			}
+		}
+	}
+
+	return cols
+}
+
+func selectList(cols []column) string {
+	exprs := make([]string, len(cols))
+	for i := range cols {
+		exprs[i] = cols[i].expr
+	}
+
+	return strings.Join(exprs, ", ")
+}
+
+// scanRow reads a row holding the given columns, in the same order. Encrypted fields are left to
+// fieldKeys.decrypt.
+func scanRow(row scanner, cols []column) (customerRow, error) {
+	var r customerRow
+
+	dest := make([]interface{}, len(cols))
+	for i := range cols {
+		dest[i] = cols[i].dest(&r)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return customerRow{}, err
+	}
+
+	r.Name = r.name.String
+
+	if r.minor.Valid && r.currency.Valid {
+		r.Salary = &models.Money{Minor: r.minor.Int64, Currency: r.currency.String}
+	}
+
+	return r, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attributes returns the attributes column of a replaced customer, which is NULL to keep the stored attributes
+// when none are given.
+func attributes(c models.Customer) interface{} {
+	if c.Attributes == nil {
+		return nil
+	}
+
+	return c.Attributes
+}
+
+// reserveID takes the id of a customer about to be inserted from the sequence of the customer table, so that its
+// encrypted fields can be bound to it before the insert. It returns 0 when fields are not encrypted, and the
+// insert takes the id itself.
+func reserveID(ctx *gofr.Context, k *fieldKeys, method string) (int, error) {
+	if k == nil {
+		return 0, nil
+	}
+
+	var id int
+
+	err := ctx.DB().QueryRowContext(ctx, "SELECT nextval(pg_get_serial_sequence('customer', 'id'))").Scan(&id)
+	if err != nil {
+		return 0, dbError(ctx, method, err)
+	}
+
+	return id, nil
+}
+
+// sealCustomer returns how the name and the salary of the customer with the given id are stored. A customer of
+// the same name that was written before names were encrypted is encrypted first, so that names stay unique.
+func sealCustomer(ctx *gofr.Context, k *fieldKeys, id int, c models.Customer) (name, pay sealedField, err error) {
+	if err := k.sealName(ctx, c.Name); err != nil {
+		return sealedField{}, sealedField{}, err
+	}
+
+	if name, err = k.name(ctx, id, c.Name); err != nil {
+		return sealedField{}, sealedField{}, err
+	}
+
+	c.ID = id
+
+	if pay, err = k.salary(ctx, c); err != nil {
+		return sealedField{}, sealedField{}, err
+	}
+
+	return name, pay, nil
+}
+
+// salary returns the salary columns of the customer, which are NULL when the salary is unknown.
+func salary(c models.Customer) (minor, currency interface{}) {
+	if c.Salary == nil {
+		return nil, nil
 	}
 
-	return set, filed
+	return c.Salary.Minor, c.Salary.Currency
 }
@@ -2,15 +2,20 @@ package store
 
 import (
 	"context"
+	"customer/middleware"
 	"customer/models"
 	"database/sql"
 	"developer.zopsmart.com/go/gofr/pkg/datastore"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	gofrLog "developer.zopsmart.com/go/gofr/pkg/log"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"io"
 	"log"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func InitializeDb() (*sql.DB, sqlmock.Sqlmock, *gofr.Context, store) {
@@ -18,23 +23,48 @@ func InitializeDb() (*sql.DB, sqlmock.Sqlmock, *gofr.Context, store) {
 	if err != nil {
 		log.Println(err)
 	}
-	g := gofr.Gofr{DataStore: datastore.DataStore{ORM: db}}
+	g := gofr.Gofr{DataStore: datastore.DataStore{ORM: db}, Logger: gofrLog.NewMockLogger(io.Discard)}
 	ctx := gofr.NewContext(nil, nil, &g)
-	ctx.Context = context.Background()
-	store := New()
+	ctx.Context = middleware.WithTenant(context.Background(), tenant)
+	store := New(nil)
 	return db, mock, ctx, store
 }
 
+// tenant is the tenant of the requests in these tests. other is another tenant.
+const tenant, other = "zopsmart", "acme"
+
+var (
+	dob         = models.NewDate(2000, time.March, 14)
+	created     = time.Date(2021, time.December, 1, 10, 0, 0, 0, time.UTC)
+	columnNames = []string{"id", "name", "email", "phone", "date_of_birth", "salary_minor", "salary_currency", "created_at", "updated_at", "status",
+		"attributes"}
+)
+
+// divya is the customer returned by divyaRow.
+func divya() models.Customer {
+	d, c := dob, created
+
+	return models.Customer{ID: 1, Name: "Divya", Email: "divya@example.com", Phone: "+919876543210",
+		DateOfBirth: &d, Salary: inr(3000000), CreatedAt: &c, UpdatedAt: &c, Status: models.StatusActive}
+}
+
+func divyaRow(rows *sqlmock.Rows) *sqlmock.Rows {
+	return rows.AddRow(1, "Divya", "divya@example.com", "+919876543210", dob.Time, 3000000, "INR", created, created, "active", []byte("{}"))
+}
+
+// inr returns a salary of the given number of paise.
+func inr(paise int64) *models.Money {
+	return &models.Money{Minor: paise, Currency: "INR"}
+}
+
 func TestStore_Get(t *testing.T) {
 	db, mock, ctx, store := InitializeDb()
 	defer db.Close()
 
-	customer1 := []models.Customer{{
-		ID: 1, Name: "Divya", Age: 22, Salary: 30000,
-	}}
+	customer1 := []models.Customer{divya()}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "age", "salary", "scanError"}).AddRow(1, "Divya", 22, 30000, "scanError")
-	query := "SELECT * FROM customer"
+	rows := sqlmock.NewRows([]string{"id", "name", "scanError"}).AddRow(1, "Divya", "scanError")
+	query := "SELECT " + customerColumns + " FROM customer WHERE tenant_id = ? AND deleted_at IS NULL"
 	tests := []struct {
 		desc     string
 		expected []models.Customer
@@ -42,7 +72,7 @@ func TestStore_Get(t *testing.T) {
 		mock     interface{}
 	}{
 		{"success", customer1, nil,
-			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "Age", "Salary"}).AddRow(1, "Divya", 22, 30000))},
+			mock.ExpectQuery(query).WithArgs(tenant).WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
 		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
 			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
 		{"scan error", nil, errors.Error("scan error"), mock.ExpectQuery(query).WillReturnRows(rows)},
@@ -51,7 +81,7 @@ func TestStore_Get(t *testing.T) {
 	for i, tc := range tests {
 
 		t.Run(tc.desc, func(t *testing.T) {
-			res, err := store.Get(ctx)
+			res, err := store.Get(ctx, models.Filter{})
 			if !reflect.DeepEqual(err, tc.err) {
 				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.err, err)
 			}
@@ -62,12 +92,98 @@ func TestStore_Get(t *testing.T) {
 	}
 }
 
+func TestStore_GetFiltered(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	customer1 := []models.Customer{divya()}
+	query := "SELECT " + customerColumns + " FROM customer WHERE tenant_id = ? AND deleted_at IS NULL"
+
+	tests := []struct {
+		desc     string
+		filter   models.Filter
+		expected []models.Customer
+		err      error
+		mock     interface{}
+	}{
+		{"name and age range", models.Filter{Name: "Divya", MinAge: 20, MaxAge: 30}, customer1, nil,
+			mock.ExpectQuery(query+" AND name = ? AND date_of_birth <= CURRENT_DATE - make_interval(years => ?)"+
+				" AND date_of_birth > CURRENT_DATE - make_interval(years => ?)").WithArgs(tenant, "Divya", 20, 31).
+				WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
+		{"tags", models.Filter{Tags: []string{"vip"}, ExcludedTags: []string{"churned"}}, customer1, nil,
+			mock.ExpectQuery(query+" AND EXISTS ("+taggedWith+") AND NOT EXISTS ("+taggedWith+")").
+				WithArgs(tenant, "vip", "churned").WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
+		{"attributes", models.Filter{Attributes: models.Attributes{"seats": float64(5)}}, customer1, nil,
+			mock.ExpectQuery(query+" AND attributes @> ?::jsonb").WithArgs(tenant, `{"seats":5}`).
+				WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
+		{"paged", models.Filter{Limit: 10, Offset: 20}, customer1, nil,
+			mock.ExpectQuery(query + " LIMIT 10 OFFSET 20").WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
+		{"projected", models.Filter{Fields: []string{"name", "age"}}, []models.Customer{{Name: "Divya", DateOfBirth: &dob}}, nil,
+			mock.ExpectQuery("SELECT name, date_of_birth FROM customer WHERE tenant_id = ? AND deleted_at IS NULL").WithArgs(tenant).
+				WillReturnRows(sqlmock.NewRows([]string{"name", "date_of_birth"}).AddRow("Divya", dob.Time))},
+	}
+
+	for i, tc := range tests {
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Get(ctx, tc.filter)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestStore_Stream(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	query := "SELECT " + customerColumns + " FROM customer WHERE tenant_id = ? AND deleted_at IS NULL"
+	stop := errors.Error("client went away")
+
+	mock.ExpectQuery(query).WithArgs(tenant).
+		WillReturnRows(divyaRow(divyaRow(sqlmock.NewRows(columnNames))))
+
+	var calls int
+
+	err := store.Stream(ctx, models.Filter{}, func(c models.Customer) error {
+		calls++
+
+		if !reflect.DeepEqual(c, divya()) {
+			t.Errorf("Expected%v\nGot%v", divya(), c)
+		}
+
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("Expected the stream to stop at the first error\nGot %v after %d customers", err, calls)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx.Context)
+	cancel()
+	ctx.Context = cancelled
+
+	mock.ExpectQuery(query).WithArgs(tenant).WillDelayFor(time.Second).
+		WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))
+
+	err = store.Stream(ctx, models.Filter{}, func(models.Customer) error {
+		t.Errorf("Expected no customer from a cancelled request")
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected%v\nGot%v", context.Canceled, err)
+	}
+}
+
 func TestStore_GetByID(t *testing.T) {
 	db, mock, ctx, store := InitializeDb()
 	defer db.Close()
 
-	customer1 := models.Customer{ID: 1, Name: "Divya", Age: 22, Salary: 30000}
-	query := "SELECT * FROM customer where id=?"
+	customer1 := divya()
+	query := "SELECT " + customerColumns + " FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL"
 	tests := []struct {
 		desc     string
 		id       int
@@ -76,11 +192,11 @@ func TestStore_GetByID(t *testing.T) {
 		mock     interface{}
 	}{
 		{"success", 1, customer1, nil,
-			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "Age", "Salary"}).AddRow(1, "Divya", 22, 30000))},
+			mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)))},
 		{"internal server error", 1, models.Customer{}, errors.DB{Err: errors.Error("db error")},
-			mock.ExpectQuery(query).WithArgs(1).WillReturnError(errors.Error("db error"))},
+			mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnError(errors.Error("db error"))},
 		{"ID not found", 5, models.Customer{}, sql.ErrNoRows,
-			mock.ExpectQuery(query).WithArgs(5).WillReturnError(sql.ErrNoRows)},
+			mock.ExpectQuery(query).WithArgs(5, tenant).WillReturnError(sql.ErrNoRows)},
 	}
 
 	for i, tc := range tests {
@@ -98,13 +214,117 @@ func TestStore_GetByID(t *testing.T) {
 	}
 }
 
+func TestStore_GetByIDFields(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	tests := []struct {
+		desc     string
+		fields   []string
+		expected models.Customer
+		mock     interface{}
+	}{
+		{"id and name", []string{"id", "name"}, models.Customer{ID: 1, Name: "Divya"},
+			mock.ExpectQuery("SELECT id, name FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL").WithArgs(1, tenant).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Divya"))},
+		{"salary", []string{"salary"}, models.Customer{Salary: inr(3000000)},
+			mock.ExpectQuery("SELECT salary_minor, salary_currency FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL").
+				WithArgs(1, tenant).WillReturnRows(sqlmock.NewRows([]string{"salary_minor", "salary_currency"}).AddRow(3000000, "INR"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.GetByID(ctx, 1, tc.fields...)
+			if err != nil {
+				t.Errorf("TEST[%d] Expected no error\nGot%v", i+1, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestStore_GetByIDs(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	customers := []models.Customer{divya(), {ID: 2, Name: "Jay", Attributes: models.Attributes{"plan": "gold"}, CreatedAt: &created,
+		UpdatedAt: &created, Status: models.StatusProspect}}
+	query := "SELECT " + customerColumns + " FROM customer WHERE tenant_id=? AND deleted_at IS NULL AND id IN (?,?)"
+	tests := []struct {
+		desc     string
+		ids      []int
+		expected []models.Customer
+		err      error
+		mock     interface{}
+	}{
+		{"success", []int{1, 2}, customers, nil,
+			mock.ExpectQuery(query).WithArgs(tenant, 1, 2).WillReturnRows(divyaRow(sqlmock.NewRows(columnNames)).
+				AddRow(2, "Jay", "", "", nil, nil, nil, created, created, "prospect", []byte(`{"plan":"gold"}`)))},
+		{"internal server error", []int{1, 2}, nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WithArgs(tenant, 1, 2).WillReturnError(errors.Error("db error"))},
+		{"no ids", nil, nil, nil, nil},
+	}
+
+	for i, tc := range tests {
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.GetByIDs(ctx, tc.ids)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestStore_Count(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	tests := []struct {
+		desc     string
+		filter   models.Filter
+		expected int
+		err      error
+		mock     interface{}
+	}{
+		{"all", models.Filter{Limit: 5}, 3, nil,
+			mock.ExpectQuery("SELECT COUNT(*) FROM customer WHERE tenant_id = ? AND deleted_at IS NULL").WithArgs(tenant).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))},
+		{"filtered", models.Filter{MinAge: 22}, 2, nil,
+			mock.ExpectQuery("SELECT COUNT(*) FROM customer WHERE tenant_id = ? AND deleted_at IS NULL AND date_of_birth <= CURRENT_DATE - make_interval(years => ?)").
+				WithArgs(tenant, 22).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))},
+		{"internal server error", models.Filter{}, 0, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery("SELECT COUNT(*) FROM customer WHERE tenant_id = ? AND deleted_at IS NULL").WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Count(ctx, tc.filter)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.err, err)
+			}
+			if res != tc.expected {
+				t.Errorf("TEST[%d] Expected%v\nGot%v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
 func TestStore_Create(t *testing.T) {
 	db, mock, ctx, store := InitializeDb()
 	defer db.Close()
 
-	customer1 := models.Customer{ID: 0, Name: "Divya", Age: 22, Salary: 30000}
+	input := divya()
+	input.ID, input.CreatedAt, input.UpdatedAt = 0, nil, nil
 
-	query := "INSERT INTO customer (name,age,salary) VALUES(?,?,?)"
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes) " +
+		"VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,?) RETURNING id, created_at, updated_at, status"
 	tests := []struct {
 		desc     string
 		input    models.Customer
@@ -112,10 +332,13 @@ func TestStore_Create(t *testing.T) {
 		err      error
 		mock     interface{}
 	}{
-		{"success", customer1, customer1, nil,
-			mock.ExpectExec(query).WithArgs("Divya", 22, 30000).WillReturnResult(sqlmock.NewResult(1, 1))},
-		{"internal server error", customer1, models.Customer{}, errors.DB{Err: errors.Error("db error")},
-			mock.ExpectExec(query).WillReturnError(errors.Error("db error"))},
+		{"success", input, divya(), nil,
+			mock.ExpectQuery(query).WithArgs(tenant, "Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", "{}").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "status"}).AddRow(1, created, created, "active"))},
+		{"duplicate email", input, models.Customer{}, errors.EntityAlreadyExists{},
+			mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "23505"})},
+		{"internal server error", input, models.Customer{}, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
 	}
 
 	for i, tc := range tests {
@@ -132,13 +355,83 @@ func TestStore_Create(t *testing.T) {
 	}
 }
 
+func TestStore_Upsert(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	input := divya()
+	input.ID, input.CreatedAt, input.UpdatedAt = 0, nil, nil
+
+	query := "INSERT INTO customer (tenant_id,name,email,phone,date_of_birth,salary_minor,salary_currency,attributes) " +
+		"VALUES(?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,COALESCE(?::jsonb,'{}')) " +
+		"ON CONFLICT (tenant_id, name) WHERE deleted_at IS NULL DO UPDATE SET email=EXCLUDED.email,phone=EXCLUDED.phone," +
+		"date_of_birth=EXCLUDED.date_of_birth,salary_minor=EXCLUDED.salary_minor,salary_currency=EXCLUDED.salary_currency," +
+		"attributes=COALESCE(?::jsonb,customer.attributes),updated_at=now() " +
+		"RETURNING id, created_at, updated_at, status, attributes, (xmax = 0)"
+	returned := []string{"id", "created_at", "updated_at", "status", "attributes", "created"}
+
+	tests := []struct {
+		desc     string
+		expected models.Customer
+		created  bool
+		err      error
+		mock     func()
+	}{
+		{"created", divya(), true, nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).WithArgs(tenant, "Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", nil, nil).
+				WillReturnRows(sqlmock.NewRows(returned).AddRow(1, created, created, "active", []byte("{}"), true))
+			mock.ExpectCommit()
+		}},
+		{"updated", divya(), false, nil, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).WithArgs(tenant, "Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", nil, nil).
+				WillReturnRows(sqlmock.NewRows(returned).AddRow(1, created, created, "active", []byte("{}"), false))
+			mock.ExpectCommit()
+		}},
+		{"email of another customer", models.Customer{}, false, errors.EntityAlreadyExists{}, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "23505"})
+			mock.ExpectRollback()
+		}},
+		{"internal server error", models.Customer{}, false, errors.DB{Err: errors.Error("db error")}, func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))
+			mock.ExpectRollback()
+		}},
+	}
+
+	for i, tc := range tests {
+		tc.mock()
+
+		t.Run(tc.desc, func(t *testing.T) {
+			res, created, err := store.Upsert(ctx, input)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) || created != tc.created {
+				t.Errorf("TEST[%d] Expected %v, %v\nGot %v, %v", i+1, tc.expected, tc.created, res, created)
+			}
+		})
+	}
+}
+
 func TestStore_Update(t *testing.T) {
 	db, mock, ctx, store := InitializeDb()
 	defer db.Close()
 
-	customer1 := models.Customer{ID: 1, Name: "Divya", Age: 22, Salary: 30000}
+	input := divya()
+	input.CreatedAt, input.UpdatedAt = nil, nil
+
+	withAttributes := input
+	withAttributes.Attributes = models.Attributes{"plan": "gold"}
+	updated := divya()
+	updated.Attributes = models.Attributes{"plan": "gold", "seats": float64(5)}
 
-	query := "UPDATE customer SET name=?,age=?,salary=? WHERE id=?"
+	query := "UPDATE customer SET name=?,email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?," +
+		"salary_minor=?,salary_currency=?,attributes=COALESCE(?::jsonb,attributes),updated_at=now() " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING created_at, updated_at, status, attributes"
+	returned := []string{"created_at", "updated_at", "status", "attributes"}
 	tests := []struct {
 		desc     string
 		ID       int
@@ -147,12 +440,17 @@ func TestStore_Update(t *testing.T) {
 		err      error
 		mock     interface{}
 	}{
-		{"success", customer1.ID, customer1, customer1, nil,
-			mock.ExpectExec(query).WithArgs("Divya", 22, 30000, 1).WillReturnResult(sqlmock.NewResult(1, 1))},
-		{"internal server error", customer1.ID, customer1, models.Customer{},
-			errors.DB{Err: errors.Error("db error")}, mock.ExpectExec(query).WillReturnError(errors.Error("db error"))},
-		{"invalid id", 1, customer1, models.Customer{}, sql.ErrNoRows,
-			mock.ExpectExec(query).WillReturnError(sql.ErrNoRows)},
+		{"success", input.ID, input, divya(), nil,
+			mock.ExpectQuery(query).WithArgs("Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", nil, 1, tenant).
+				WillReturnRows(sqlmock.NewRows(returned).AddRow(created, created, "active", []byte("{}")))},
+		{"attributes", input.ID, withAttributes, updated, nil,
+			mock.ExpectQuery(query).WithArgs("Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR",
+				`{"plan":"gold"}`, 1, tenant).
+				WillReturnRows(sqlmock.NewRows(returned).AddRow(created, created, "active", []byte(`{"plan":"gold","seats":5}`)))},
+		{"internal server error", input.ID, input, models.Customer{},
+			errors.DB{Err: errors.Error("db error")}, mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+		{"invalid id", 1, input, models.Customer{}, sql.ErrNoRows,
+			mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)},
 	}
 
 	for i, tc := range tests {
@@ -173,11 +471,11 @@ func TestStore_Delete(t *testing.T) {
 	db, mock, ctx, store := InitializeDb()
 	defer db.Close()
 
-	customer1 := models.Customer{ID: 1, Name: "Divya", Age: 22, Salary: 30000}
+	customer1 := divya()
 
-	query := "DELETE FROM customer where id=?"
+	query := "DELETE FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL"
 
-	mock.ExpectExec(query).WithArgs(customer1.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(customer1.ID, tenant).WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(query).WillReturnError(errors.Error("db error"))
 	mock.ExpectExec(query).WillReturnError(sql.ErrNoRows)
 
@@ -206,9 +504,10 @@ func TestStore_Patch(t *testing.T) {
 	db, mock, ctx, store := InitializeDb()
 	defer db.Close()
 
-	customer1 := models.Customer{ID: 1, Name: "Divya", Age: 22, Salary: 30000}
+	customer1 := models.Customer{ID: 1, Name: "Divya", Salary: inr(3000000)}
+	salaryOnly := models.Customer{ID: 1, Salary: inr(4000000)}
 
-	query := "UPDATE customer SET name = ?, age = ?, salary = ? where id = ?"
+	query := "UPDATE customer SET name = ?, salary_minor = ?, salary_currency = ?, updated_at = now() where id = ? AND tenant_id = ? AND deleted_at IS NULL"
 	query1 := "UPDATE customer"
 	tests := []struct {
 		desc     string
@@ -219,7 +518,19 @@ func TestStore_Patch(t *testing.T) {
 		mock     interface{}
 	}{
 		{"success", customer1.ID, customer1, customer1, nil,
-			mock.ExpectExec(query).WithArgs("Divya", 22, 30000, 1).WillReturnResult(sqlmock.NewResult(1, 1))},
+			mock.ExpectExec(query).WithArgs("Divya", 3000000, "INR", 1, tenant).WillReturnResult(sqlmock.NewResult(1, 1))},
+		{"single field", 1, models.Customer{Salary: inr(4000000)}, salaryOnly, nil,
+			mock.ExpectExec("UPDATE customer SET salary_minor = ?, salary_currency = ?, updated_at = now() where id = ? AND tenant_id = ? AND deleted_at IS NULL").
+				WithArgs(4000000, "INR", 1, tenant).
+				WillReturnResult(sqlmock.NewResult(1, 1))},
+		{"attributes", 1, models.Customer{Attributes: models.Attributes{"plan": "gold", "seats": nil}},
+			models.Customer{ID: 1, Attributes: models.Attributes{"plan": "gold", "seats": nil}}, nil,
+			mock.ExpectExec("UPDATE customer SET attributes = jsonb_strip_nulls(attributes || ?::jsonb), updated_at = now() "+
+				"where id = ? AND tenant_id = ? AND deleted_at IS NULL").WithArgs(`{"plan":"gold","seats":null}`, 1, tenant).
+				WillReturnResult(sqlmock.NewResult(1, 1))},
+		{"duplicate email", 1, models.Customer{Email: "jay@example.com"}, models.Customer{}, errors.EntityAlreadyExists{},
+			mock.ExpectExec("UPDATE customer SET email = ?, updated_at = now() where id = ? AND tenant_id = ? AND deleted_at IS NULL").
+				WillReturnError(&pq.Error{Code: "23505"})},
 		{"internal server error", customer1.ID, customer1, models.Customer{},
 			errors.DB{Err: errors.Error("db error")}, mock.ExpectExec(query).WillReturnError(errors.Error("db error"))},
 		{"no values to patch", 1, models.Customer{}, models.Customer{}, nil,
@@ -239,3 +550,60 @@ func TestStore_Patch(t *testing.T) {
 		})
 	}
 }
+
+// TestStore_CrossTenant checks that the customers of another tenant are out of reach: every query is bound to the
+// tenant of the request, so the database finds no row and the customer is reported as not found.
+func TestStore_CrossTenant(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	ctx.Context = middleware.WithTenant(context.Background(), other)
+	customer1 := divya()
+
+	mock.ExpectQuery("SELECT "+customerColumns+" FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL").WithArgs(1, other).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("UPDATE customer SET name=?,email=NULLIF(?,''),phone=NULLIF(?,''),date_of_birth=?,"+
+		"salary_minor=?,salary_currency=?,attributes=COALESCE(?::jsonb,attributes),updated_at=now() "+
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL RETURNING created_at, updated_at, status, attributes").
+		WithArgs("Divya", "divya@example.com", "+919876543210", "2000-03-14", 3000000, "INR", nil, 1, other).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE customer SET name = ?, updated_at = now() where id = ? AND tenant_id = ? AND deleted_at IS NULL").
+		WithArgs("Jay", 1, other).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM customer where id=? AND tenant_id=? AND deleted_at IS NULL").WithArgs(1, other).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tests := []struct {
+		desc string
+		call func() error
+	}{
+		{"get", func() error { _, err := store.GetByID(ctx, 1); return err }},
+		{"update", func() error { _, err := store.Update(ctx, 1, customer1); return err }},
+		{"patch", func() error { _, err := store.Patch(ctx, 1, models.Customer{Name: "Jay"}); return err }},
+		{"delete", func() error { return store.Delete(ctx, 1) }},
+	}
+
+	for i, tc := range tests {
+		if err := tc.call(); err != sql.ErrNoRows {
+			t.Errorf("TEST[%d], failed.\n%s\nExpected %v\nGot %v", i+1, tc.desc, sql.ErrNoRows, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestStore_WithoutTenant(t *testing.T) {
+	db, mock, ctx, store := InitializeDb()
+	defer db.Close()
+
+	ctx.Context = context.Background()
+
+	if _, err := store.Get(ctx, models.Filter{}); err != errNoTenant {
+		t.Errorf("Expected %v\nGot %v", errNoTenant, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expected no query without a tenant: %v", err)
+	}
+}
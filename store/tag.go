@@ -0,0 +1,98 @@
+package store
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/tracing"
+)
+
+// taggedWith selects the links of the customer of the enclosing query to the tag named by its parameter. The
+// tag needs no tenant, since customers are only ever linked to the tags of their own tenant.
+const taggedWith = "SELECT 1 FROM customer_tag JOIN tag ON tag.id = customer_tag.tag_id " +
+	"WHERE customer_tag.customer_id = customer.id AND tag.name = ?"
+
+type tag struct{}
+
+func NewTag() tag {
+	return tag{}
+}
+
+// List returns the tags of a customer of the tenant in alphabetical order.
+func (t tag) List(ctx *gofr.Context, customerID int) ([]string, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT tag.name FROM customer_tag JOIN tag ON tag.id = customer_tag.tag_id WHERE customer_id=? AND " +
+		ownedByTenant + " ORDER BY tag.name"
+
+	ctx, span := tracing.Start(ctx, "store.Tag.List", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, customerID, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "Tag.List", err)
+	}
+	defer rows.Close()
+
+	res := []string{}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, name)
+	}
+	return res, nil
+}
+
+// Add tags a customer of the tenant, creating the tag on first use. Adding a tag the customer already has
+// changes nothing, and nothing is linked for the customers of other tenants.
+func (t tag) Add(ctx *gofr.Context, customerID int, name string) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The no-op update makes the tag id come back when the tag already exists.
+	query := "WITH t AS (INSERT INTO tag (tenant_id,name) VALUES(?,?) " +
+		"ON CONFLICT (tenant_id,name) DO UPDATE SET name=EXCLUDED.name RETURNING id) " +
+		"INSERT INTO customer_tag (customer_id,tag_id) SELECT customer.id, t.id FROM customer, t " +
+		"WHERE customer.id=? AND customer.tenant_id=? AND customer.deleted_at IS NULL ON CONFLICT DO NOTHING"
+
+	ctx, span := tracing.Start(ctx, "store.Tag.Add", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	if _, err := ctx.DB().ExecContext(ctx, query, tenant, name, customerID, tenant); err != nil {
+		return dbError(ctx, "Tag.Add", err)
+	}
+	return nil
+}
+
+// Remove untags a customer of the tenant. The tag itself is kept for the other customers that have it.
+func (t tag) Remove(ctx *gofr.Context, customerID int, name string) error {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM customer_tag WHERE customer_id=? AND tag_id IN (SELECT id FROM tag WHERE tenant_id=? AND name=?) AND " +
+		ownedByTenant
+
+	ctx, span := tracing.Start(ctx, "store.Tag.Remove", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	res, err := ctx.DB().ExecContext(ctx, query, customerID, tenant, name, tenant)
+	if err != nil {
+		return dbError(ctx, "Tag.Remove", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errors.EntityNotFound{Entity: "tag", ID: name}
+	}
+	return nil
+}
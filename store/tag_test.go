@@ -0,0 +1,104 @@
+package store
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func TestTag_List(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewTag()
+	query := "SELECT tag.name FROM customer_tag JOIN tag ON tag.id = customer_tag.tag_id WHERE customer_id=? AND " +
+		ownedByTenant + " ORDER BY tag.name"
+
+	tests := []struct {
+		desc     string
+		expected []string
+		err      error
+		mock     interface{}
+	}{
+		{"success", []string{"b2b", "vip"}, nil,
+			mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("b2b").AddRow("vip"))},
+		{"no tags", []string{}, nil,
+			mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(sqlmock.NewRows([]string{"name"}))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.List(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestTag_Add(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewTag()
+	query := "WITH t AS (INSERT INTO tag (tenant_id,name) VALUES(?,?) " +
+		"ON CONFLICT (tenant_id,name) DO UPDATE SET name=EXCLUDED.name RETURNING id) " +
+		"INSERT INTO customer_tag (customer_id,tag_id) SELECT customer.id, t.id FROM customer, t " +
+		"WHERE customer.id=? AND customer.tenant_id=? AND customer.deleted_at IS NULL ON CONFLICT DO NOTHING"
+
+	tests := []struct {
+		desc string
+		err  error
+		mock interface{}
+	}{
+		{"success", nil, mock.ExpectExec(query).WithArgs(tenant, "vip", 1, tenant).WillReturnResult(sqlmock.NewResult(0, 1))},
+		{"already tagged", nil, mock.ExpectExec(query).WithArgs(tenant, "vip", 1, tenant).WillReturnResult(sqlmock.NewResult(0, 0))},
+		{"internal server error", errors.DB{Err: errors.Error("db error")},
+			mock.ExpectExec(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := store.Add(ctx, 1, "vip")
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestTag_Remove(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewTag()
+	query := "DELETE FROM customer_tag WHERE customer_id=? AND tag_id IN (SELECT id FROM tag WHERE tenant_id=? AND name=?) AND " +
+		ownedByTenant
+
+	tests := []struct {
+		desc string
+		err  error
+		mock interface{}
+	}{
+		{"success", nil, mock.ExpectExec(query).WithArgs(1, tenant, "vip", tenant).WillReturnResult(sqlmock.NewResult(0, 1))},
+		{"not tagged", errors.EntityNotFound{Entity: "tag", ID: "vip"},
+			mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))},
+		{"internal server error", errors.DB{Err: errors.Error("db error")},
+			mock.ExpectExec(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := store.Remove(ctx, 1, "vip")
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"database/sql"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"customer/middleware"
+	"customer/models"
+	"customer/tracing"
+)
+
+const transitionColumns = "id, customer_id, from_status, to_status, reason, caller, created_at"
+
+type transition struct{}
+
+func NewTransition() transition {
+	return transition{}
+}
+
+// Create changes the status of a customer of the tenant from t.From to t.To and records the change, in a single
+// statement. When the customer is not found or no longer has status t.From, nothing changes and sql.ErrNoRows
+// is returned.
+func (s transition) Create(ctx *gofr.Context, t models.Transition) (models.Transition, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return models.Transition{}, err
+	}
+
+	query := "WITH moved AS (UPDATE customer SET status=?,updated_at=now() " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL AND status=? RETURNING id) " +
+		"INSERT INTO customer_transition (customer_id,from_status,to_status,reason,caller) " +
+		"SELECT id,?,?,?,? FROM moved RETURNING id, created_at"
+
+	ctx, span := tracing.Start(ctx, "store.Transition.Create", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(t.CustomerID))
+	defer span.End()
+
+	t.Caller = middleware.Caller(ctx)
+
+	err = ctx.DB().QueryRowContext(ctx, query, t.To, t.CustomerID, tenant, t.From, t.From, t.To, t.Reason, t.Caller).
+		Scan(&t.ID, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.Transition{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return models.Transition{}, dbError(ctx, "Transition.Create", err)
+	}
+	return t, nil
+}
+
+// List returns the transitions of a customer of the tenant, oldest first.
+func (s transition) List(ctx *gofr.Context, customerID int) ([]models.Transition, error) {
+	tenant, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + transitionColumns + " FROM customer_transition WHERE customer_id=? AND " + ownedByTenant + " ORDER BY id"
+
+	ctx, span := tracing.Start(ctx, "store.Transition.List", semconv.DBStatementKey.String(query), tracing.CustomerIDKey.Int(customerID))
+	defer span.End()
+
+	rows, err := ctx.DB().QueryContext(ctx, query, customerID, tenant)
+	if err != nil {
+		return nil, dbError(ctx, "Transition.List", err)
+	}
+	defer rows.Close()
+
+	var res []models.Transition
+
+	for rows.Next() {
+		var t models.Transition
+
+		err := rows.Scan(&t.ID, &t.CustomerID, &t.From, &t.To, &t.Reason, &t.Caller, &t.CreatedAt)
+		if err != nil {
+			return nil, errors.Error("scan error")
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
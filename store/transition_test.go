@@ -0,0 +1,87 @@
+package store
+
+import (
+	"customer/models"
+	"database/sql"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func TestTransition_Create(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewTransition()
+	query := "WITH moved AS (UPDATE customer SET status=?,updated_at=now() " +
+		"WHERE id=? AND tenant_id=? AND deleted_at IS NULL AND status=? RETURNING id) " +
+		"INSERT INTO customer_transition (customer_id,from_status,to_status,reason,caller) " +
+		"SELECT id,?,?,?,? FROM moved RETURNING id, created_at"
+
+	input := models.Transition{CustomerID: 1, From: models.StatusProspect, To: models.StatusActive, Reason: "signed"}
+	expected := input
+	expected.ID, expected.CreatedAt = 1, &created
+
+	tests := []struct {
+		desc     string
+		expected models.Transition
+		err      error
+		mock     interface{}
+	}{
+		{"success", expected, nil,
+			mock.ExpectQuery(query).WithArgs("active", 1, tenant, "prospect", "prospect", "active", "signed", "").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, created))},
+		{"status changed or customer not found", models.Transition{}, sql.ErrNoRows,
+			mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)},
+		{"internal server error", models.Transition{}, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.Create(ctx, input)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestTransition_List(t *testing.T) {
+	db, mock, ctx, _ := InitializeDb()
+	defer db.Close()
+
+	store := NewTransition()
+	query := "SELECT " + transitionColumns + " FROM customer_transition WHERE customer_id=? AND " + ownedByTenant + " ORDER BY id"
+	transitions := []models.Transition{{ID: 1, CustomerID: 1, From: models.StatusProspect, To: models.StatusActive,
+		Reason: "signed", Caller: "key:1234abcd", CreatedAt: &created}}
+
+	tests := []struct {
+		desc     string
+		expected []models.Transition
+		err      error
+		mock     interface{}
+	}{
+		{"success", transitions, nil, mock.ExpectQuery(query).WithArgs(1, tenant).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "customer_id", "from_status", "to_status", "reason", "caller", "created_at"}).
+				AddRow(1, 1, "prospect", "active", "signed", "key:1234abcd", created))},
+		{"internal server error", nil, errors.DB{Err: errors.Error("db error")},
+			mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := store.List(ctx, 1)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.err, err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("TEST[%d] Expected %v\nGot %v", i+1, tc.expected, res)
+			}
+		})
+	}
+}
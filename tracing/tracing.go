@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentation = "customer"
+
+// CustomerIDKey is the span attribute holding the id of the customer a request works on.
+const CustomerIDKey = attribute.Key("customer.id")
+
+// Init installs the W3C trace context propagator and a tracer provider that exports over OTLP/gRPC
+// to endpoint. Without an endpoint spans are still propagated but never exported.
+func Init(ctx context.Context, serviceName, endpoint string) (shutdown func(context.Context) error, err error) {
+	var exporter sdktrace.SpanExporter
+
+	if endpoint != "" {
+		exporter, err = otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tp := NewProvider(serviceName, exporter)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown, nil
+}
+
+// NewProvider returns a tracer provider batching spans to exporter. Tests pass a tracetest.InMemoryExporter.
+func NewProvider(serviceName string, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(serviceName))),
+	}
+
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// Tracer returns the tracer used for every span of the service.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Start starts a child of the span in ctx. It returns a copy of ctx in which the span is current, to be handed
+// to the layers that nest their spans under it. ctx itself is left alone, as it may be shared by concurrent
+// callers, e.g. the resolvers of one GraphQL query.
+func Start(ctx *gofr.Context, name string, attrs ...attribute.KeyValue) (*gofr.Context, trace.Span) {
+	parent := ctx.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	spanCtx, span := Tracer().Start(parent, name, trace.WithAttributes(attrs...))
+
+	c := *ctx
+	c.Context = spanCtx
+
+	return &c, span
+}
+
+// Fail marks the current span of ctx as failed.
+func Fail(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// SetCustomerID records the id of the customer the current span of ctx works on.
+func SetCustomerID(ctx context.Context, id int) {
+	trace.SpanFromContext(ctx).SetAttributes(CustomerIDKey.Int(id))
+}
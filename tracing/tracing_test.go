@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStart(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := NewProvider("customer", exporter)
+	otel.SetTracerProvider(tp)
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.Background()
+
+	handlerCtx, outer := Start(ctx, "handler.GetByID")
+	SetCustomerID(handlerCtx, 7)
+
+	storeCtx, inner := Start(handlerCtx, "store.GetByID", CustomerIDKey.Int(7))
+	Fail(storeCtx, errors.New("db error"))
+	inner.End()
+	outer.End()
+
+	if ctx.Context != context.Background() || handlerCtx.Context == storeCtx.Context {
+		t.Errorf("Expected every span to be current on its own copy of the context only")
+	}
+
+	_ = tp.ForceFlush(context.Background())
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans\nGot %v", len(spans))
+	}
+
+	store, handler := spans[0], spans[1]
+
+	if store.Parent.SpanID() != handler.SpanContext.SpanID() {
+		t.Errorf("Expected %v to be the child of %v", store.Name, handler.Name)
+	}
+
+	if store.Status.Code != codes.Error || handler.Status.Code == codes.Error {
+		t.Errorf("Expected only the store span to fail\nGot %v and %v", store.Status.Code, handler.Status.Code)
+	}
+
+	for _, s := range spans {
+		found := false
+
+		for _, a := range s.Attributes {
+			if a.Key == CustomerIDKey && a.Value.AsInt64() == 7 {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("Expected %v to carry %v", s.Name, CustomerIDKey)
+		}
+	}
+}